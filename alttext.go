@@ -0,0 +1,24 @@
+package wallpapers
+
+import (
+	"context"
+	"strings"
+)
+
+// MetadataAltText holds a description of a wallpaper for screen readers
+// and embeds.
+const MetadataAltText = "alt_text"
+
+// SetAltText sets the alt text of a file. An empty text removes it, so the
+// file name is used again.
+func SetAltText(ctx context.Context, filename, text string) error {
+	return updateFile(ctx, filename, ObjectUpdate{Metadata: map[string]string{MetadataAltText: strings.TrimSpace(text)}})
+}
+
+// Alt returns f's alt text, falling back to its name.
+func (f *File) Alt() string {
+	if f.AltText != "" {
+		return f.AltText
+	}
+	return f.Name
+}
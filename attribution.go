@@ -0,0 +1,54 @@
+package wallpapers
+
+import (
+	"context"
+)
+
+// Object metadata keys used to store attribution.
+const (
+	MetadataSourceURL = "source_url"
+	MetadataAuthor    = "author"
+	MetadataLicense   = "license"
+)
+
+// Attribution records where a wallpaper came from and who made it.
+type Attribution struct {
+	SourceURL string `json:"source_url,omitempty"`
+	Author    string `json:"author,omitempty"`
+	License   string `json:"license,omitempty"`
+}
+
+func attributionFromMetadata(md map[string]string) Attribution {
+	return Attribution{
+		SourceURL: md[MetadataSourceURL],
+		Author:    md[MetadataAuthor],
+		License:   md[MetadataLicense],
+	}
+}
+
+// metadata returns the non-empty attribution fields as object metadata.
+func (a Attribution) metadata() map[string]string {
+	md := map[string]string{}
+	for k, v := range map[string]string{
+		MetadataSourceURL: a.SourceURL,
+		MetadataAuthor:    a.Author,
+		MetadataLicense:   a.License,
+	} {
+		if v != "" {
+			md[k] = v
+		}
+	}
+
+	return md
+}
+
+// WithAttribution stores attribution as object metadata.
+func WithAttribution(a Attribution) UploadOption {
+	return WithMetadata(a.metadata())
+}
+
+// SetAttribution updates the attribution of an existing file. Empty fields are
+// left unchanged.
+func SetAttribution(ctx context.Context, filename string, a Attribution) error {
+	return updateFile(ctx, filename, ObjectUpdate{Metadata: a.metadata()})
+}
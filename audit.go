@@ -0,0 +1,303 @@
+package wallpapers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"os"
+	"os/user"
+	"strconv"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/storage"
+)
+
+// Audit actions.
+const (
+	AuditUpload     = "upload"
+	AuditDelete     = "delete"
+	AuditRename     = "rename"
+	AuditUpdate     = "update"
+	AuditQuarantine = "quarantine"
+	AuditRestore    = "restore"
+)
+
+// metadataAuditName records which file an audit entry is about, so
+// listings can be filtered without reading every entry.
+const metadataAuditName = "audit_name"
+
+// auditPageSize is how many audit entries are listed at once.
+const auditPageSize = 1000
+
+// AuditEntry records one change to a collection. Before and After hold the
+// attributes that changed, such as size, checksum, name and metadata.
+type AuditEntry struct {
+	Time   time.Time         `json:"time"`
+	Actor  string            `json:"actor"`
+	Action string            `json:"action"`
+	Name   string            `json:"name"`
+	Before map[string]string `json:"before,omitempty"`
+	After  map[string]string `json:"after,omitempty"`
+}
+
+type actorKey struct{}
+
+// WithActor returns a context whose changes are attributed to actor in the
+// audit log.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// defaultActor is the local user and host, for the command line tools.
+var defaultActor = sync.OnceValue(func() string {
+	name := "unknown"
+	if u, err := user.Current(); err == nil {
+		name = u.Username
+	}
+	if host, err := os.Hostname(); err == nil {
+		name += "@" + host
+	}
+	return name
+})
+
+func actorFor(ctx context.Context) string {
+	if a, ok := ctx.Value(actorKey{}).(string); ok && a != "" {
+		return a
+	}
+	return defaultActor()
+}
+
+// auditAttrs returns the attributes of f that the audit log records.
+func auditAttrs(f *File) map[string]string {
+	if f == nil {
+		return nil
+	}
+
+	attrs := maps.Clone(f.Metadata)
+	if attrs == nil {
+		attrs = map[string]string{}
+	}
+	attrs["size"] = strconv.FormatInt(f.Size, 10)
+	attrs["crc32c"] = strconv.FormatUint(uint64(f.CRC32C), 10)
+	if f.StorageClass != "" {
+		attrs["storage_class"] = f.StorageClass
+	}
+	if !f.CustomTime.IsZero() {
+		attrs["custom_time"] = f.CustomTime.UTC().Format(time.RFC3339)
+	}
+	return attrs
+}
+
+// changed returns the attributes that differ between before and after, as
+// they were and as they are.
+func changed(before, after map[string]string) (map[string]string, map[string]string) {
+	b, a := map[string]string{}, map[string]string{}
+	for k, v := range before {
+		if after[k] != v {
+			b[k] = v
+			if av, ok := after[k]; ok {
+				a[k] = av
+			}
+		}
+	}
+	for k, v := range after {
+		if _, ok := before[k]; !ok {
+			a[k] = v
+		}
+	}
+	return b, a
+}
+
+// updated returns a copy of f with u applied, as Store.Update would.
+func updated(f *File, u ObjectUpdate) *File {
+	c := *f
+	if !u.CustomTime.IsZero() {
+		c.CustomTime = u.CustomTime
+	}
+	c.Metadata = maps.Clone(f.Metadata)
+	for k, v := range u.Metadata {
+		if c.Metadata == nil {
+			c.Metadata = map[string]string{}
+		}
+		if v == "" {
+			delete(c.Metadata, k)
+			continue
+		}
+		c.Metadata[k] = v
+	}
+	return &c
+}
+
+// attrsIfExists returns the attributes of a file, or nil if it does not
+// exist.
+func attrsIfExists(ctx context.Context, filename string) (*File, error) {
+	f, err := GetFile(ctx, filename)
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		return nil, nil
+	}
+	return f, err
+}
+
+// recordAudit appends an entry to the audit log of ctx's store. Stores
+// without sub stores keep no audit log.
+//
+// It is called once a change has been made, so failing to record it is
+// logged rather than returned: the change happened either way, and callers
+// such as the uploader must not treat it as failed.
+func recordAudit(ctx context.Context, e AuditEntry) {
+	if err := writeAudit(ctx, e); err != nil {
+		LoggerFor(ctx).Errorw("could not record audit entry", "action", e.Action, "name", e.Name, "error", err)
+	}
+}
+
+func writeAudit(ctx context.Context, e AuditEntry) error {
+	s, err := subStore(ctx, AuditStore)
+	if errors.Is(err, ErrNoSubStore) {
+		LoggerFor(ctx).Debugw("store keeps no audit log", "action", e.Action, "name", e.Name)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("could not record audit entry: %w", err)
+	}
+
+	e.Time = time.Now().UTC()
+	e.Actor = actorFor(ctx)
+	buf, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	// Entries are named so that the store lists the newest first:
+	// math.MaxInt64 minus the Unix time in nanoseconds as 19 digits, then
+	// a checksum of the entry.
+	name := fmt.Sprintf("%019d-%08x.json", math.MaxInt64-e.Time.UnixNano(), GetFileCRC(buf))
+	u := ObjectUpdate{ContentType: "application/json", Metadata: map[string]string{metadataAuditName: e.Name}}
+	wc, err := s.NewWriter(ctx, name, GetChecksums(buf), u)
+	if err != nil {
+		return fmt.Errorf("could not record audit entry: %w", err)
+	}
+	if _, err := wc.Write(buf); err != nil {
+		return fmt.Errorf("could not record audit entry: %w", err)
+	}
+	if err := wc.Close(); err != nil {
+		return fmt.Errorf("could not record audit entry: %w", err)
+	}
+
+	return nil
+}
+
+// updateFile changes the attributes of a file and records the change.
+func updateFile(ctx context.Context, filename string, u ObjectUpdate) error {
+	before, err := GetFile(ctx, filename)
+	if err != nil {
+		return err
+	}
+
+	if err := StoreFor(ctx).Update(ctx, filename, u); err != nil {
+		return err
+	}
+
+	b, a := changed(auditAttrs(before), auditAttrs(updated(before, u)))
+	if len(b) == 0 && len(a) == 0 {
+		return nil
+	}
+	recordAudit(ctx, AuditEntry{Action: AuditUpdate, Name: filename, Before: b, After: a})
+	return nil
+}
+
+// AuditQuery selects audit entries. Zero fields select everything.
+type AuditQuery struct {
+	// Name only selects changes to this file.
+	Name string
+	// Since only selects changes made at or after this time.
+	Since time.Time
+	// Limit is the most entries to return.
+	Limit int
+}
+
+// AuditEntries returns the entries of the audit log of ctx's store that
+// match q, newest first. The log is listed a page at a time, and only the
+// entries returned are read, so a small limit stays cheap however long the
+// log grows.
+func AuditEntries(ctx context.Context, q AuditQuery) ([]*AuditEntry, error) {
+	s, err := subStore(ctx, AuditStore)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []*AuditEntry{}
+	// add reads the entry in f if it matches q, and reports whether more
+	// entries are wanted.
+	add := func(f *File) (bool, error) {
+		t, ok := auditEntryTime(f.Name)
+		if !ok {
+			return true, nil
+		}
+		if t.Before(q.Since) {
+			return false, nil
+		}
+		if name, ok := f.Metadata[metadataAuditName]; ok && q.Name != "" && name != q.Name {
+			return true, nil
+		}
+
+		e, err := readAuditEntry(ctx, s, f.Name)
+		if err != nil {
+			return false, err
+		}
+		if q.Name != "" && e.Name != q.Name {
+			return true, nil
+		}
+		entries = append(entries, e)
+		return q.Limit <= 0 || len(entries) < q.Limit, nil
+	}
+
+	token := ""
+	for {
+		files, next, err := s.Page(ctx, token, auditPageSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range files {
+			more, err := add(f)
+			if err != nil || !more {
+				return entries, err
+			}
+		}
+		if next == "" {
+			return entries, nil
+		}
+		token = next
+	}
+}
+
+// auditEntryTime returns when the entry named name was recorded.
+func auditEntryTime(name string) (time.Time, bool) {
+	if len(name) < 19 {
+		return time.Time{}, false
+	}
+	n, err := strconv.ParseInt(name[:19], 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(0, math.MaxInt64-n).UTC(), true
+}
+
+func readAuditEntry(ctx context.Context, s Store, name string) (*AuditEntry, error) {
+	rc, err := s.NewReader(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	var e AuditEntry
+	if err := json.NewDecoder(io.LimitReader(rc, 1<<20)).Decode(&e); err != nil {
+		return nil, fmt.Errorf("could not read audit entry %s: %w", name, err)
+	}
+
+	return &e, nil
+}
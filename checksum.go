@@ -0,0 +1,46 @@
+package wallpapers
+
+import (
+	"bytes"
+	"crypto/md5" // #nosec G501 -- GCS uses MD5 for integrity, not security
+	"errors"
+	"fmt"
+)
+
+// ErrCorrupt is returned when a file's content does not match the checksums
+// stored with it.
+var ErrCorrupt = errors.New("content does not match stored checksums")
+
+// Checksums are the checksums GCS keeps for an object.
+type Checksums struct {
+	CRC32C uint32
+	MD5    []byte
+}
+
+// GetChecksums returns the checksums of content.
+func GetChecksums(content []byte) Checksums {
+	sum := md5.Sum(content) // #nosec G401 -- see import
+	return Checksums{
+		CRC32C: GetFileCRC(content),
+		MD5:    sum[:],
+	}
+}
+
+// Verify checks content against the size and checksums stored for f,
+// returning an error wrapping ErrCorrupt if they differ. Composite objects
+// have no MD5, so it is only checked when f has one.
+func Verify(f *File, content []byte) error {
+	if int64(len(content)) != f.Size {
+		return fmt.Errorf("%w: %q is %d bytes, expected %d", ErrCorrupt, f.Name, len(content), f.Size)
+	}
+
+	sums := GetChecksums(content)
+	if sums.CRC32C != f.CRC32C {
+		return fmt.Errorf("%w: %q has crc32c %08x, expected %08x", ErrCorrupt, f.Name, sums.CRC32C, f.CRC32C)
+	}
+	if len(f.MD5) > 0 && !bytes.Equal(sums.MD5, f.MD5) {
+		return fmt.Errorf("%w: %q has md5 %x, expected %x", ErrCorrupt, f.Name, sums.MD5, f.MD5)
+	}
+
+	return nil
+}
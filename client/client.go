@@ -0,0 +1,368 @@
+// Package client talks to a wallpapers server over HTTP. It is built on the
+// generated wallpapersclient, adding paging, plain Go types and resumable
+// uploads.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/icco/wallpapers/wallpapersclient"
+)
+
+const (
+	// DefaultBaseURL is the public wallpapers server.
+	DefaultBaseURL = wallpapersclient.DefaultBaseURL
+
+	// maxPageSize is the largest page the server returns.
+	maxPageSize = 1000
+
+	// uploadChunkSize is how much of an upload is sent per request. A
+	// request that fails only loses what it was sending.
+	uploadChunkSize = 8 << 20
+
+	// uploadAttempts is how many times each part of an upload is sent
+	// before giving up.
+	uploadAttempts = 3
+)
+
+// Client is a wallpapers API client.
+type Client struct {
+	api *wallpapersclient.ClientWithResponses
+}
+
+// Image is a wallpaper as listed by the server's /v1 API.
+type Image struct {
+	Key          string    `json:"key"`
+	Type         string    `json:"type"`
+	Etag         string    `json:"etag"`
+	CDN          string    `json:"cdn"`
+	Thumbnail    string    `json:"thumbnail"`
+	Bucket       string    `json:"bucket,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	Video        *Video    `json:"video,omitempty"`
+	ColorProfile string    `json:"color_profile,omitempty"`
+	VariantOf    string    `json:"variant_of,omitempty"`
+	AltText      string    `json:"alt_text,omitempty"`
+	SourceURL    string    `json:"source_url,omitempty"`
+	Author       string    `json:"author,omitempty"`
+	License      string    `json:"license,omitempty"`
+}
+
+// Video describes a video wallpaper.
+type Video struct {
+	Width    int     `json:"width,omitempty"`
+	Height   int     `json:"height,omitempty"`
+	Duration float64 `json:"duration,omitempty"`
+}
+
+// Stats is a summary of a collection.
+type Stats struct {
+	Count     int            `json:"count"`
+	TotalSize int64          `json:"total_size"`
+	Formats   map[string]int `json:"formats"`
+}
+
+// Error is a non-successful response from the server.
+type Error = wallpapersclient.ResponseError
+
+// New returns a Client for the server at baseURL. token, if set, is sent
+// as a bearer token on every request; Upload and Delete need it. Redirects
+// are not followed, so that image URLs can be returned to the caller.
+func New(baseURL, token string) (*Client, error) {
+	api, err := wallpapersclient.New(baseURL, token)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{api: api}, nil
+}
+
+// ImagesPage returns up to limit wallpapers, newest first, starting at
+// cursor, and the cursor of the next page. An empty cursor starts at the
+// beginning, and an empty next cursor means there are no more pages. A
+// limit of 0 uses the server's default.
+func (c *Client) ImagesPage(ctx context.Context, cursor string, limit int) ([]*Image, string, error) {
+	return c.page(ctx, "", cursor, limit)
+}
+
+// Images lazily iterates over every wallpaper, newest first, a page at a
+// time. Iteration stops after the first error.
+func (c *Client) Images(ctx context.Context) iter.Seq2[*Image, error] {
+	return c.pages(ctx, "")
+}
+
+// ListImages returns every wallpaper, newest first.
+func (c *Client) ListImages(ctx context.Context) ([]*Image, error) {
+	return collect(c.Images(ctx))
+}
+
+// Search returns every wallpaper matching query, newest first, as the
+// server's other search APIs match them.
+func (c *Client) Search(ctx context.Context, query string) ([]*Image, error) {
+	if query == "" {
+		return nil, errors.New("wallpapers: search query is empty")
+	}
+	return collect(c.pages(ctx, query))
+}
+
+// Stats returns a summary of the collection.
+func (c *Client) Stats(ctx context.Context) (*Stats, error) {
+	resp, err := c.api.V1GetStatsWithResponse(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := wallpapersclient.CheckResponse(resp.HTTPResponse, resp.Body); err != nil {
+		return nil, err
+	}
+	if resp.JSON200 == nil {
+		return nil, fmt.Errorf("could not decode response: %s", resp.Status())
+	}
+
+	s := resp.JSON200.Data
+	stats := &Stats{Count: deref(s.Count), TotalSize: deref(s.TotalSize), Formats: deref(s.Formats)}
+	return stats, nil
+}
+
+// Fit returns the URL of name cropped to fit a w by h display.
+func (c *Client) Fit(ctx context.Context, name string, w, h int) (string, error) {
+	return redirect(c.api.FitImage(ctx, name, &wallpapersclient.FitImageParams{W: w, H: h}))
+}
+
+// Random returns the URL of a random wallpaper cropped to fit a w by h display.
+func (c *Client) Random(ctx context.Context, w, h int) (string, error) {
+	return redirect(c.api.FitRandomImage(ctx, &wallpapersclient.FitRandomImageParams{W: w, H: h}))
+}
+
+// Archive writes a zip of the named wallpapers' original files to w.
+func (c *Client) Archive(ctx context.Context, names []string, w io.Writer) error {
+	resp, err := c.api.PostArchive(ctx, wallpapersclient.PostArchiveJSONRequestBody{Names: names})
+	if err := check(resp, err); err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	_, err = io.Copy(w, resp.Body)
+	return err
+}
+
+// Upload adds content to the default collection as name, and returns the
+// name it was stored as, which differs if name is taken. It is sent in
+// parts over the tus resumable upload protocol, and a part that fails is
+// sent again from wherever the server got to. Content already in the
+// collection is refused with a 409 *Error.
+func (c *Client) Upload(ctx context.Context, name string, content []byte) (string, error) {
+	created, err := c.api.CreateUploadWithResponse(ctx, &wallpapersclient.CreateUploadParams{
+		TusResumable:   wallpapersclient.CreateUploadParamsTusResumableN100,
+		UploadLength:   int64(len(content)),
+		UploadMetadata: "filename " + base64.StdEncoding.EncodeToString([]byte(name)),
+	})
+	if err != nil {
+		return "", err
+	}
+	if err := wallpapersclient.CheckResponse(created.HTTPResponse, created.Body); err != nil {
+		return "", err
+	}
+	id := path.Base(created.HTTPResponse.Header.Get("Location"))
+
+	offset, failures := int64(0), 0
+	for {
+		part := content[offset:min(offset+uploadChunkSize, int64(len(content)))]
+		resp, err := c.api.AppendUploadWithBodyWithResponse(ctx, id, &wallpapersclient.AppendUploadParams{
+			TusResumable: wallpapersclient.AppendUploadParamsTusResumableN100,
+			UploadOffset: offset,
+		}, "application/offset+octet-stream", bytes.NewReader(part))
+		if err == nil {
+			err = wallpapersclient.CheckResponse(resp.HTTPResponse, resp.Body)
+		}
+
+		var apiErr *Error
+		switch {
+		case err == nil:
+			failures = 0
+			if key := resp.HTTPResponse.Header.Get("Wallpaper-Key"); key != "" {
+				return key, nil
+			}
+			if offset, err = uploadOffset(resp.HTTPResponse); err != nil {
+				return "", err
+			}
+		case errors.As(err, &apiErr) && apiErr.StatusCode != http.StatusConflict:
+			return "", err
+		case ctx.Err() != nil:
+			return "", ctx.Err()
+		default:
+			// The part was cut off, or the server had more of the upload
+			// than we thought: carry on from wherever it got to.
+			if failures++; failures >= uploadAttempts {
+				return "", fmt.Errorf("could not upload %s: %w", name, err)
+			}
+			head, herr := c.api.UploadOffsetWithResponse(ctx, id, &wallpapersclient.UploadOffsetParams{
+				TusResumable: wallpapersclient.UploadOffsetParamsTusResumableN100,
+			})
+			if herr == nil {
+				herr = wallpapersclient.CheckResponse(head.HTTPResponse, head.Body)
+			}
+			if herr != nil {
+				// A 409 for content already stored also ends the upload,
+				// so the HEAD finds nothing; report the first error.
+				return "", err
+			}
+			if offset, err = uploadOffset(head.HTTPResponse); err != nil {
+				return "", err
+			}
+		}
+	}
+}
+
+// Delete removes name from the default collection.
+func (c *Client) Delete(ctx context.Context, name string) error {
+	resp, err := c.api.V1DeleteImageWithResponse(ctx, name)
+	if err != nil {
+		return err
+	}
+	return wallpapersclient.CheckResponse(resp.HTTPResponse, resp.Body)
+}
+
+// page returns a page of wallpapers matching query, or of every wallpaper
+// if query is empty.
+func (c *Client) page(ctx context.Context, query, cursor string, limit int) ([]*Image, string, error) {
+	params := &wallpapersclient.V1ListImagesParams{}
+	if query != "" {
+		params.Q = &query
+	}
+	if cursor != "" {
+		params.Cursor = &cursor
+	}
+	if limit > 0 {
+		params.Limit = &limit
+	}
+
+	resp, err := c.api.V1ListImagesWithResponse(ctx, params)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := wallpapersclient.CheckResponse(resp.HTTPResponse, resp.Body); err != nil {
+		return nil, "", err
+	}
+	if resp.JSON200 == nil {
+		return nil, "", fmt.Errorf("could not decode response: %s", resp.Status())
+	}
+
+	images := make([]*Image, 0, len(resp.JSON200.Data))
+	for _, f := range resp.JSON200.Data {
+		images = append(images, newImage(f))
+	}
+	return images, deref(resp.JSON200.NextCursor), nil
+}
+
+// pages iterates over every page of wallpapers matching query.
+func (c *Client) pages(ctx context.Context, query string) iter.Seq2[*Image, error] {
+	return func(yield func(*Image, error) bool) {
+		cursor := ""
+		for {
+			images, next, err := c.page(ctx, query, cursor, maxPageSize)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			for _, img := range images {
+				if !yield(img, nil) {
+					return
+				}
+			}
+			if next == "" {
+				return
+			}
+			cursor = next
+		}
+	}
+}
+
+func collect(images iter.Seq2[*Image, error]) ([]*Image, error) {
+	all := []*Image{}
+	for img, err := range images {
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, img)
+	}
+	return all, nil
+}
+
+func newImage(f wallpapersclient.File) *Image {
+	img := &Image{
+		Key:          f.Key,
+		Type:         string(f.Type),
+		Etag:         f.Etag,
+		CDN:          f.Cdn,
+		Thumbnail:    f.Thumbnail,
+		Bucket:       deref(f.Bucket),
+		CreatedAt:    f.CreatedAt,
+		UpdatedAt:    f.UpdatedAt,
+		ColorProfile: deref(f.ColorProfile),
+		VariantOf:    deref(f.VariantOf),
+		AltText:      deref(f.AltText),
+		SourceURL:    deref(f.SourceUrl),
+		Author:       deref(f.Author),
+		License:      deref(f.License),
+	}
+	if v := f.Video; v != nil {
+		img.Video = &Video{Width: deref(v.Width), Height: deref(v.Height), Duration: float64(deref(v.Duration))}
+	}
+	return img
+}
+
+func deref[T any](p *T) T {
+	var zero T
+	if p == nil {
+		return zero
+	}
+	return *p
+}
+
+// check returns the error of a raw operation, or a *Error if its response
+// was neither successful nor a redirect, closing the body in that case.
+func check(resp *http.Response, err error) error {
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < http.StatusBadRequest {
+		return nil
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	return wallpapersclient.CheckResponse(resp, body)
+}
+
+func redirect(resp *http.Response, err error) (string, error) {
+	if err := check(resp, err); err != nil {
+		return "", err
+	}
+	resp.Body.Close()
+
+	loc := resp.Header.Get("Location")
+	if loc == "" {
+		return "", errors.New("wallpapers: response did not redirect")
+	}
+	return loc, nil
+}
+
+func uploadOffset(resp *http.Response) (int64, error) {
+	offset, err := strconv.ParseInt(resp.Header.Get("Upload-Offset"), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("wallpapers: invalid Upload-Offset %q", resp.Header.Get("Upload-Offset"))
+	}
+	return offset, nil
+}
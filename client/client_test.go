@@ -0,0 +1,288 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// newTestClient serves handler and returns a client for it with the token
+// "secret".
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	c, err := New(srv.URL, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func writeJSON(w http.ResponseWriter, status int, body string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	fmt.Fprint(w, body)
+}
+
+func TestListImages(t *testing.T) {
+	pages := map[string]string{
+		"":   `{"data":[{"key":"c.jpg","type":"image"},{"key":"b.jpg","type":"image"}],"next_cursor":"p2"}`,
+		"p2": `{"data":[{"key":"a.mp4","type":"video","video":{"width":1920,"height":1080}}]}`,
+	}
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/images" {
+			http.NotFound(w, r)
+			return
+		}
+		if got := r.URL.Query().Get("limit"); got != fmt.Sprint(maxPageSize) {
+			t.Errorf("limit = %q, want %d", got, maxPageSize)
+		}
+		body, ok := pages[r.URL.Query().Get("cursor")]
+		if !ok {
+			writeJSON(w, http.StatusBadRequest, `{"data":null,"error":{"code":"bad_request","message":"invalid cursor","param":"cursor"}}`)
+			return
+		}
+		writeJSON(w, http.StatusOK, body)
+	})
+
+	images, err := c.ListImages(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var keys []string
+	for _, img := range images {
+		keys = append(keys, img.Key)
+	}
+	if fmt.Sprint(keys) != "[c.jpg b.jpg a.mp4]" {
+		t.Errorf("ListImages keys = %v, want [c.jpg b.jpg a.mp4]", keys)
+	}
+	if v := images[2].Video; v == nil || v.Width != 1920 || v.Height != 1080 {
+		t.Errorf("video = %+v, want 1920x1080", v)
+	}
+
+	_, _, err = c.ImagesPage(context.Background(), "forged", maxPageSize)
+	var apiErr *Error
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest || apiErr.Message != "invalid cursor" {
+		t.Errorf("ImagesPage(forged) error = %v, want 400 invalid cursor", err)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if q := r.URL.Query().Get("q"); r.URL.Path != "/v1/images" || q != "lake" {
+			t.Errorf("request = %s, want a search for lake", r.URL)
+		}
+		writeJSON(w, http.StatusOK, `{"data":[{"key":"lake.jpg","type":"image","author":"nat"}]}`)
+	})
+
+	images, err := c.Search(context.Background(), "lake")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(images) != 1 || images[0].Key != "lake.jpg" || images[0].Author != "nat" {
+		t.Errorf("Search = %+v, want lake.jpg by nat", images)
+	}
+
+	if _, err := c.Search(context.Background(), ""); err == nil {
+		t.Error("Search with no query succeeded")
+	}
+}
+
+func TestStats(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/stats" {
+			http.NotFound(w, r)
+			return
+		}
+		writeJSON(w, http.StatusOK, `{"data":{"count":2,"total_size":30,"formats":{"jpg":2}}}`)
+	})
+
+	stats, err := c.Stats(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Count != 2 || stats.TotalSize != 30 || stats.Formats["jpg"] != 2 {
+		t.Errorf("Stats = %+v, want 2 jpgs of 30 bytes", stats)
+	}
+}
+
+func TestErrorMessage(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		body string
+		want string
+	}{
+		{name: "legacy", body: `{"error":"not found","code":"not_found"}`, want: "not found"},
+		{name: "v1", body: `{"data":null,"error":{"code":"not_found","message":"not found"}}`, want: "not found"},
+		{name: "not json", body: `oops`, want: ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+				fmt.Fprint(w, tc.body)
+			})
+
+			_, err := c.Stats(context.Background())
+			var apiErr *Error
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("Stats error = %v, want *Error", err)
+			}
+			if apiErr.StatusCode != http.StatusNotFound || apiErr.Message != tc.want {
+				t.Errorf("Stats error = %d %q, want 404 %q", apiErr.StatusCode, apiErr.Message, tc.want)
+			}
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		key        string
+		wantStatus int
+	}{
+		{name: "delete", key: "a.jpg"},
+		{name: "missing", key: "z.jpg", wantStatus: http.StatusNotFound},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodDelete || r.Header.Get("Authorization") != "Bearer secret" {
+					t.Errorf("request = %s with %q, want an authorized DELETE", r.Method, r.Header.Get("Authorization"))
+				}
+				if r.URL.Path != "/v1/images/a.jpg" {
+					writeJSON(w, http.StatusNotFound, `{"data":null,"error":{"code":"not_found","message":"not found"}}`)
+					return
+				}
+				w.WriteHeader(http.StatusNoContent)
+			})
+
+			err := c.Delete(context.Background(), tc.key)
+			var apiErr *Error
+			switch {
+			case tc.wantStatus == 0 && err != nil:
+				t.Errorf("Delete error = %v", err)
+			case tc.wantStatus != 0 && (!errors.As(err, &apiErr) || apiErr.StatusCode != tc.wantStatus):
+				t.Errorf("Delete error = %v, want %d", err, tc.wantStatus)
+			}
+		})
+	}
+}
+
+// tusServer is an in memory tus server holding one upload.
+type tusServer struct {
+	t  *testing.T
+	mu sync.Mutex
+	// got is what has been received, and length what will be.
+	got    []byte
+	length int
+	// dropFirst cuts the connection half way through the first PATCH.
+	dropFirst bool
+	// duplicate refuses the finished upload as already stored.
+	duplicate bool
+	done      bool
+}
+
+func (s *tusServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if r.Header.Get("Tus-Resumable") != "1.0.0" || r.Header.Get("Authorization") != "Bearer secret" {
+		s.t.Errorf("%s %s without the tus version or token", r.Method, r.URL.Path)
+	}
+
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/upload":
+		s.length, _ = strconv.Atoi(r.Header.Get("Upload-Length"))
+		if got := r.Header.Get("Upload-Metadata"); got != "filename bmV3LnBuZw==" {
+			s.t.Errorf("Upload-Metadata = %q, want new.png", got)
+		}
+		w.Header().Set("Location", "/upload/abc")
+		w.WriteHeader(http.StatusCreated)
+	case s.done || r.URL.Path != "/upload/abc":
+		writeJSON(w, http.StatusNotFound, `{"error":"no such upload"}`)
+	case r.Method == http.MethodHead:
+		w.Header().Set("Upload-Offset", strconv.Itoa(len(s.got)))
+		w.WriteHeader(http.StatusOK)
+	case r.Method == http.MethodPatch:
+		if off := r.Header.Get("Upload-Offset"); off != strconv.Itoa(len(s.got)) {
+			w.Header().Set("Upload-Offset", strconv.Itoa(len(s.got)))
+			writeJSON(w, http.StatusConflict, `{"error":"offset mismatch","code":"offset_mismatch"}`)
+			return
+		}
+		if s.dropFirst {
+			s.dropFirst = false
+			part := make([]byte, (s.length-len(s.got))/2)
+			n, _ := io.ReadFull(r.Body, part)
+			s.got = append(s.got, part[:n]...)
+			conn, _, err := http.NewResponseController(w).Hijack()
+			if err != nil {
+				s.t.Fatal(err)
+			}
+			conn.Close()
+			return
+		}
+		part, _ := io.ReadAll(r.Body)
+		s.got = append(s.got, part...)
+		w.Header().Set("Upload-Offset", strconv.Itoa(len(s.got)))
+		if len(s.got) < s.length {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		s.done = true
+		if s.duplicate {
+			writeJSON(w, http.StatusConflict, `{"error":"the same content is already stored as \"old.png\"","code":"duplicate"}`)
+			return
+		}
+		w.Header().Set("Wallpaper-Key", "new-1234.png")
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func TestUpload(t *testing.T) {
+	content := bytes.Repeat([]byte("wallpaper"), 1000)
+
+	for _, tc := range []struct {
+		name       string
+		dropFirst  bool
+		duplicate  bool
+		wantKey    string
+		wantStatus int
+	}{
+		{name: "upload", wantKey: "new-1234.png"},
+		{name: "resumed after the connection drops", dropFirst: true, wantKey: "new-1234.png"},
+		{name: "stored content", duplicate: true, wantStatus: http.StatusConflict},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := &tusServer{t: t, dropFirst: tc.dropFirst, duplicate: tc.duplicate}
+			c := newTestClient(t, srv.ServeHTTP)
+
+			key, err := c.Upload(context.Background(), "new.png", content)
+			if tc.wantStatus != 0 {
+				var apiErr *Error
+				if !errors.As(err, &apiErr) || apiErr.StatusCode != tc.wantStatus {
+					t.Fatalf("Upload error = %v, want %d", err, tc.wantStatus)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if key != tc.wantKey {
+				t.Errorf("Upload = %q, want %q", key, tc.wantKey)
+			}
+			if !bytes.Equal(srv.got, content) {
+				t.Errorf("server got %d bytes, want the %d sent", len(srv.got), len(content))
+			}
+		})
+	}
+}
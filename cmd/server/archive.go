@@ -0,0 +1,141 @@
+package main
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"github.com/icco/wallpapers"
+	"go.uber.org/zap"
+)
+
+const (
+	maxArchiveFiles = 200
+	maxArchiveBytes = 4 << 30
+	archiveTimeout  = 30 * time.Minute
+)
+
+var archiveErrorCodes = map[int]string{
+	http.StatusBadRequest:            "bad_request",
+	http.StatusNotFound:              "not_found",
+	http.StatusRequestEntityTooLarge: "too_large",
+	http.StatusInternalServerError:   "internal",
+}
+
+type archiveRequest struct {
+	Names []string `json:"names"`
+}
+
+// archiveHandler streams a zip of the requested wallpapers' original files.
+// Names come from repeated name query parameters, or a JSON body of the form
+// {"names": [...]} when POSTed.
+func archiveHandler(w http.ResponseWriter, r *http.Request) {
+	names := r.URL.Query()["name"]
+	if r.Method == http.MethodPost {
+		var req archiveRequest
+		if err := json.NewDecoder(io.LimitReader(r.Body, 1<<20)).Decode(&req); err != nil {
+			renderError(w, r, http.StatusBadRequest, "bad_request", "invalid request body")
+			return
+		}
+		names = append(names, req.Names...)
+	}
+
+	files, status, err := archiveFiles(r, names)
+	if err != nil {
+		renderError(w, r, status, archiveErrorCodes[status], err.Error())
+		return
+	}
+
+	// The server's write timeout is far too short for a large archive.
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Now().Add(archiveTimeout)); err != nil {
+		reqLog(r).Warnw("could not extend archive write deadline", zap.Error(err))
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", `attachment; filename="wallpapers.zip"`)
+	w.WriteHeader(http.StatusOK)
+
+	zw := zip.NewWriter(w)
+	for _, f := range files {
+		if err := writeArchiveFile(r, zw, f); err != nil {
+			reqLog(r).Errorw("error writing archive", "name", f.Name, zap.Error(err))
+			return
+		}
+
+		if err := http.NewResponseController(w).Flush(); err != nil {
+			reqLog(r).Debugw("could not flush archive", zap.Error(err))
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		reqLog(r).Errorw("error closing archive", zap.Error(err))
+	}
+
+	reqLog(r).Infow("sent archive", "files", len(files))
+}
+
+// archiveFiles looks up and validates the requested files, returning an HTTP
+// status to use on error.
+func archiveFiles(r *http.Request, names []string) ([]*wallpapers.File, int, error) {
+	if len(names) == 0 {
+		return nil, http.StatusBadRequest, errors.New("no names requested")
+	}
+
+	seen := map[string]bool{}
+	var files []*wallpapers.File
+	var total int64
+	for _, name := range names {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		if len(seen) > maxArchiveFiles {
+			return nil, http.StatusRequestEntityTooLarge, fmt.Errorf("at most %d files can be archived", maxArchiveFiles)
+		}
+
+		f, err := wallpapers.GetFile(r.Context(), name)
+		if errors.Is(err, storage.ErrObjectNotExist) {
+			return nil, http.StatusNotFound, fmt.Errorf("%q not found", name)
+		}
+		if err != nil {
+			reqLog(r).Errorw("error during archive get file", "name", name, zap.Error(err))
+			return nil, http.StatusInternalServerError, errors.New("retrieval error")
+		}
+
+		total += f.Size
+		if total > maxArchiveBytes {
+			return nil, http.StatusRequestEntityTooLarge, fmt.Errorf("archive would be larger than %d bytes", maxArchiveBytes)
+		}
+
+		files = append(files, f)
+	}
+
+	return files, http.StatusOK, nil
+}
+
+func writeArchiveFile(r *http.Request, zw *zip.Writer, f *wallpapers.File) error {
+	rc, err := wallpapers.OpenFile(r.Context(), f.Name)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	// Images are already compressed, so store them as is.
+	fw, err := zw.CreateHeader(&zip.FileHeader{
+		Name:     f.Name,
+		Method:   zip.Store,
+		Modified: f.Added(),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(fw, rc)
+	return err
+}
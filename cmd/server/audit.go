@@ -0,0 +1,95 @@
+package main
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/icco/wallpapers"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultAuditLimit = 100
+	maxAuditLimit     = 1000
+)
+
+// apiToken is the bearer token requireToken accepts. Empty refuses every
+// request.
+var apiToken string
+
+// tokenActor names the holder of the API token calling from addr, so that
+// changes made through the API are attributed in the audit log. The token
+// is shared, so the caller's address is all that tells them apart.
+func tokenActor(addr string) string {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		addr = host
+	}
+	return "api-token@" + addr
+}
+
+// requireToken only lets through requests carrying apiToken as a bearer
+// token, and attributes their changes to tokenActor.
+func requireToken(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || apiToken == "" || subtle.ConstantTimeCompare([]byte(got), []byte(apiToken)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="wallpapers"`)
+			renderError(w, r, http.StatusUnauthorized, "unauthorized", "a valid API token is required")
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(wallpapers.WithActor(r.Context(), tokenActor(r.RemoteAddr))))
+	})
+}
+
+// auditHandler lists audit entries newest first, optionally for one file
+// and only since a time.
+func auditHandler(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	name := q.Get("name")
+
+	limit := defaultAuditLimit
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			renderBadRequest(w, r, "invalid_limit", invalidParam("limit", "limit must be a positive integer"))
+			return
+		}
+		limit = min(n, maxAuditLimit)
+	}
+
+	var since time.Time
+	if v := q.Get("since"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			renderBadRequest(w, r, "invalid_since", invalidParam("since", "since must be an RFC 3339 time"))
+			return
+		}
+		since = t
+	}
+
+	// Reading entries can outlast the server's write timeout when the
+	// filters match little of a long log.
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Now().Add(time.Minute)); err != nil {
+		reqLog(r).Debugw("could not extend audit write deadline", zap.Error(err))
+	}
+
+	entries, err := wallpapers.AuditEntries(r.Context(), wallpapers.AuditQuery{Name: name, Since: since, Limit: limit})
+	if errors.Is(err, wallpapers.ErrNoSubStore) {
+		entries, err = []*wallpapers.AuditEntry{}, nil
+	}
+	if err != nil {
+		reqLog(r).Errorw("error during audit list", zap.Error(err))
+		renderError(w, r, http.StatusInternalServerError, "internal", "retrieval error")
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	if err := Renderer.JSON(w, http.StatusOK, entries); err != nil {
+		reqLog(r).Errorw("error during audit render", zap.Error(err))
+	}
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/icco/wallpapers"
+)
+
+// listingMaxAge is how long clients may reuse a listing without revalidating.
+const listingMaxAge = time.Minute
+
+// versionState tracks a collection's listing version. The newest Updated
+// time alone misses deletions, so when the number of files changes without
+// a newer Updated time, the version moves to when the change was first
+// seen.
+type versionState struct {
+	count   int
+	last    time.Time
+	version time.Time
+}
+
+var (
+	versionsMu sync.Mutex
+	versions   = map[wallpapers.Store]*versionState{}
+)
+
+// collectionVersion returns the version of the listing of s, given its
+// current files.
+func collectionVersion(s wallpapers.Store, files []*wallpapers.File) time.Time {
+	last := wallpapers.LastModified(files)
+
+	versionsMu.Lock()
+	defer versionsMu.Unlock()
+
+	v, ok := versions[s]
+	if !ok {
+		v = &versionState{}
+		versions[s] = v
+	}
+
+	switch {
+	case v.version.IsZero():
+		v.version = last
+	case len(files) != v.count || !last.Equal(v.last):
+		v.version = last
+		if len(files) != v.count && !last.After(v.last) {
+			v.version = time.Now()
+		}
+	}
+	v.count = len(files)
+	v.last = last
+
+	return v.version
+}
+
+// revision identifies the build, so that responses rendered by a new
+// release get new ETags even if the files have not changed.
+var revision = sync.OnceValue(func() string {
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, s := range info.Settings {
+			if s.Key == "vcs.revision" {
+				return s.Value
+			}
+		}
+	}
+	return ""
+})
+
+// listingETag returns a weak ETag for a response to r built from files. It
+// hashes each file's name, etag, generation and update time rather than the
+// rendered body, so it changes when a file is added, removed, replaced or
+// has its metadata changed. The query is included because it selects what
+// the response holds.
+func listingETag(r *http.Request, files []*wallpapers.File) string {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%s\n%s?%s\n", revision(), r.URL.Path, r.URL.RawQuery)
+	for _, f := range files {
+		fmt.Fprintf(h, "%s %s %d %d\n", f.Name, f.Etag, f.Generation, f.Updated.UnixNano())
+	}
+	return fmt.Sprintf(`W/"%016x"`, h.Sum64())
+}
+
+// etagMatches reports whether an If-None-Match header lists etag. Weak
+// comparison is used, as RFC 9110 asks for If-None-Match.
+func etagMatches(header, etag string) bool {
+	for _, t := range strings.Split(header, ",") {
+		t = strings.TrimSpace(t)
+		if t == "*" || strings.TrimPrefix(t, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
+
+// notModified sets ETag, Last-Modified and Cache-Control for a response
+// built from files. If the request's If-None-Match or If-Modified-Since
+// shows the client already has the current version, it writes a 304 and
+// returns true so the caller can skip rendering.
+func notModified(w http.ResponseWriter, r *http.Request, files []*wallpapers.File) bool {
+	etag := listingETag(r, files)
+	version := collectionVersion(wallpapers.StoreFor(r.Context()), files)
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(listingMaxAge.Seconds())))
+	if !version.IsZero() {
+		w.Header().Set("Last-Modified", version.UTC().Format(http.TimeFormat))
+	}
+
+	// If-Modified-Since is ignored when If-None-Match is sent.
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		if !etagMatches(inm, etag) {
+			return false
+		}
+		w.WriteHeader(http.StatusNotModified)
+		return true
+	}
+
+	if version.IsZero() {
+		return false
+	}
+
+	since, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+
+	// HTTP dates only have second precision.
+	if version.Truncate(time.Second).After(since) {
+		return false
+	}
+
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
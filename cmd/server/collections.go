@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/icco/wallpapers"
+	"github.com/icco/wallpapers/config"
+)
+
+// collections are the public collections the server can list, by name.
+// Private collections are never served.
+var collections = map[string]wallpapers.Store{}
+
+// openCollections opens every public collection. The first is the default
+// store, so that it shares its listing and events with requests that do not
+// name a collection. As the default store is served to every request that
+// does not name a collection, it must be public.
+func openCollections(cfg *config.Config) error {
+	if def := cfg.Collections[0]; !def.Public() {
+		return fmt.Errorf("the default collection %q is private and cannot be served, list a public collection first", def.Name)
+	}
+
+	for i, c := range cfg.Collections {
+		if !c.Public() {
+			continue
+		}
+		if i == 0 {
+			collections[c.Name] = wallpapers.DefaultStore()
+			continue
+		}
+
+		s, err := cfg.Open(i)
+		if err != nil {
+			return err
+		}
+		collections[c.Name] = s
+	}
+
+	return nil
+}
+
+// collectionName returns the name s is served under, or "" for the default
+// store.
+func collectionName(s wallpapers.Store) string {
+	if s == wallpapers.DefaultStore() {
+		return ""
+	}
+	for name, c := range collections {
+		if c == s {
+			return name
+		}
+	}
+	return ""
+}
+
+// collectionMiddleware switches the request to the collection named by the
+// collection query parameter, if there is one.
+func collectionMiddleware(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := r.URL.Query().Get("collection")
+		if name == "" {
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		s, ok := collections[name]
+		if !ok {
+			renderError(w, r, http.StatusNotFound, "not_found", "unknown collection")
+			return
+		}
+
+		h.ServeHTTP(w, r.WithContext(wallpapers.ContextWithStore(r.Context(), s)))
+	})
+}
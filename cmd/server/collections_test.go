@@ -0,0 +1,67 @@
+package main
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/icco/wallpapers"
+	"github.com/icco/wallpapers/config"
+)
+
+func TestOpenCollections(t *testing.T) {
+	public := func(name string) wallpapers.Collection {
+		return wallpapers.Collection{Name: name, Bucket: name, ImgixHost: name + ".imgix.net"}
+	}
+	private := func(name string) wallpapers.Collection {
+		return wallpapers.Collection{Name: name, Bucket: name}
+	}
+
+	for _, tc := range []struct {
+		name        string
+		collections []wallpapers.Collection
+		wantErr     string
+		wantServed  []string
+	}{
+		{
+			name:        "public default",
+			collections: []wallpapers.Collection{public("walls"), private("drafts"), public("shots")},
+			wantServed:  []string{"shots", "walls"},
+		},
+		{
+			name:        "private default",
+			collections: []wallpapers.Collection{private("drafts"), public("walls")},
+			wantErr:     `default collection "drafts" is private`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Cleanup(func() { clear(collections) })
+			cfg := config.New()
+			cfg.Backend = "local"
+			cfg.Dir = t.TempDir()
+			cfg.Collections = tc.collections
+
+			err := openCollections(cfg)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("openCollections error = %v, want %q", err, tc.wantErr)
+				}
+				if len(collections) != 0 {
+					t.Errorf("served %v after refusing to start", slices.Sorted(maps.Keys(collections)))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := slices.Sorted(maps.Keys(collections)); !slices.Equal(got, tc.wantServed) {
+				t.Errorf("served %v, want %v", got, tc.wantServed)
+			}
+			if collections["walls"] != wallpapers.DefaultStore() {
+				t.Error("default collection is not served from the default store")
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestParseSchedule(t *testing.T) {
+	for _, tc := range []struct {
+		expr    string
+		wantErr bool
+	}{
+		{expr: "* * * * *"},
+		{expr: "*/15 * * * *"},
+		{expr: "0 9-17 * * 1-5"},
+		{expr: "0,30 * 1,15 * *"},
+		{expr: "0-30/10 * * * *"},
+		{expr: "5/20 * * * *"},
+		{expr: "0 0 29 2 *"},
+		{expr: "* * * *", wantErr: true},
+		{expr: "* * * * * *", wantErr: true},
+		{expr: "60 * * * *", wantErr: true},
+		{expr: "* 24 * * *", wantErr: true},
+		{expr: "* * 0 * *", wantErr: true},
+		{expr: "* * * 13 *", wantErr: true},
+		{expr: "* * * * 7", wantErr: true},
+		{expr: "*/0 * * * *", wantErr: true},
+		{expr: "10-5 * * * *", wantErr: true},
+		{expr: "a * * * *", wantErr: true},
+		{expr: "1-b * * * *", wantErr: true},
+	} {
+		t.Run(tc.expr, func(t *testing.T) {
+			_, err := parseSchedule(tc.expr)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("parseSchedule(%q) error = %v, want error %v", tc.expr, err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseCronField(t *testing.T) {
+	bits := func(vs ...int) uint64 {
+		var set uint64
+		for _, v := range vs {
+			set |= 1 << uint(v)
+		}
+		return set
+	}
+
+	for _, tc := range []struct {
+		field  string
+		lo, hi int
+		want   uint64
+	}{
+		{field: "5", lo: 0, hi: 59, want: bits(5)},
+		{field: "1-3", lo: 0, hi: 59, want: bits(1, 2, 3)},
+		{field: "1,3,5", lo: 0, hi: 59, want: bits(1, 3, 5)},
+		{field: "*/20", lo: 0, hi: 59, want: bits(0, 20, 40)},
+		{field: "0-30/10", lo: 0, hi: 59, want: bits(0, 10, 20, 30)},
+		{field: "50/5", lo: 0, hi: 59, want: bits(50, 55)},
+		{field: "*/4", lo: 1, hi: 12, want: bits(1, 5, 9)},
+		{field: "1-2,10-11", lo: 1, hi: 31, want: bits(1, 2, 10, 11)},
+	} {
+		t.Run(tc.field, func(t *testing.T) {
+			got, err := parseCronField(tc.field, tc.lo, tc.hi)
+			if err != nil {
+				t.Fatalf("parseCronField(%q) error: %v", tc.field, err)
+			}
+			if got != tc.want {
+				t.Errorf("parseCronField(%q) = %b, want %b", tc.field, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestScheduleNext(t *testing.T) {
+	at := func(s string) time.Time {
+		t.Helper()
+		v, err := time.Parse("2006-01-02 15:04", s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+
+	for _, tc := range []struct {
+		name string
+		expr string
+		from string
+		want string
+	}{
+		{name: "every minute", expr: "* * * * *", from: "2024-03-10 10:00", want: "2024-03-10 10:01"},
+		{name: "step", expr: "*/15 * * * *", from: "2024-03-10 10:07", want: "2024-03-10 10:15"},
+		{name: "step on a match moves on", expr: "*/15 * * * *", from: "2024-03-10 10:15", want: "2024-03-10 10:30"},
+		{name: "step wraps the hour", expr: "*/15 * * * *", from: "2024-03-10 10:50", want: "2024-03-10 11:00"},
+		{name: "hour range", expr: "0 9-17 * * *", from: "2024-03-10 17:30", want: "2024-03-11 09:00"},
+		{name: "day rollover", expr: "30 2 * * *", from: "2024-03-10 03:00", want: "2024-03-11 02:30"},
+		{name: "month rollover", expr: "0 0 1 * *", from: "2024-01-31 12:00", want: "2024-02-01 00:00"},
+		{name: "year rollover", expr: "0 0 1 1 *", from: "2024-06-01 00:00", want: "2025-01-01 00:00"},
+		{name: "short month skipped", expr: "0 0 31 * *", from: "2024-04-01 00:00", want: "2024-05-31 00:00"},
+		{name: "leap day", expr: "0 0 29 2 *", from: "2024-03-01 00:00", want: "2028-02-29 00:00"},
+		{name: "day of week", expr: "0 8 * * 1", from: "2024-03-10 10:00", want: "2024-03-11 08:00"},
+		{name: "weekdays skip the weekend", expr: "0 8 * * 1-5", from: "2024-03-08 09:00", want: "2024-03-11 08:00"},
+		// 2024-03-13 is a Wednesday and 2024-03-15 the 15th: with both
+		// day fields restricted, either one matches.
+		{name: "day of month or week", expr: "0 0 15 * 3", from: "2024-03-10 00:00", want: "2024-03-13 00:00"},
+		{name: "day of month or week, month day first", expr: "0 0 12 * 3", from: "2024-03-10 00:00", want: "2024-03-12 00:00"},
+		{name: "never", expr: "0 0 31 2 *", from: "2024-01-01 00:00", want: ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s, err := parseSchedule(tc.expr)
+			if err != nil {
+				t.Fatalf("parseSchedule(%q) error: %v", tc.expr, err)
+			}
+
+			// Seconds past the minute are dropped.
+			got := s.next(at(tc.from).Add(42 * time.Second))
+			if tc.want == "" {
+				if !got.IsZero() {
+					t.Errorf("next(%s) = %s, want never", tc.from, got)
+				}
+				return
+			}
+			if want := at(tc.want); !got.Equal(want) {
+				t.Errorf("next(%s) = %s, want %s", tc.from, got.Format("2006-01-02 15:04 Mon"), want.Format("2006-01-02 15:04 Mon"))
+			}
+		})
+	}
+}
+
+func TestJobSkipsOverlappingRuns(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	calls := 0
+	j := &job{name: "test", run: func(context.Context) error {
+		calls++
+		close(started)
+		<-release
+		return nil
+	}}
+
+	done := make(chan struct{})
+	go func() {
+		j.runOnce(context.Background())
+		close(done)
+	}()
+	<-started
+
+	// A run due while the first is still going is skipped.
+	j.runOnce(context.Background())
+	if !j.status().Running {
+		t.Error("status().Running = false during a run")
+	}
+
+	close(release)
+	<-done
+	if calls != 1 {
+		t.Errorf("job ran %d times, want 1", calls)
+	}
+	if st := j.status(); st.Running || st.Runs != 1 {
+		t.Errorf("status() = running %v, runs %d, want false, 1", st.Running, st.Runs)
+	}
+}
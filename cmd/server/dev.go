@@ -0,0 +1,163 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const devPollInterval = 500 * time.Millisecond
+
+// reloadScript is added to HTML pages in development so they reload when
+// a template or static file changes.
+const reloadScript = `<script>new EventSource("/dev/reload").onmessage = () => location.reload();</script>`
+
+// devDir is the cmd/server source directory when templates and static
+// files are served from disk instead of the binary.
+var devDir string
+
+// lastChange returns the latest modification time of the files under dir.
+func lastChange(dir string) (time.Time, error) {
+	var latest time.Time
+	err := filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+		return nil
+	})
+	return latest, err
+}
+
+// devReloadHandler sends one event once a template or static file changes
+// after the request started, then ends the stream.
+func devReloadHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	rc := http.NewResponseController(w)
+
+	dirs := []string{filepath.Join(devDir, "static"), filepath.Join(devDir, "templates")}
+	changed := func() time.Time {
+		var latest time.Time
+		for _, dir := range dirs {
+			t, err := lastChange(dir)
+			if err != nil {
+				reqLog(r).Warnw("could not check for changes", "dir", dir, zap.Error(err))
+				continue
+			}
+			if t.After(latest) {
+				latest = t
+			}
+		}
+		return latest
+	}
+	start := changed()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	ticker := time.NewTicker(devPollInterval)
+	defer ticker.Stop()
+	for {
+		msg := ": waiting\n\n"
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if changed().After(start) {
+				msg = "data: reload\n\n"
+			}
+		}
+
+		if err := rc.SetWriteDeadline(time.Now().Add(devPollInterval * 4)); err != nil {
+			reqLog(r).Debugw("could not extend reload write deadline", zap.Error(err))
+		}
+		if _, err := fmt.Fprint(w, msg); err != nil {
+			return
+		}
+		if err := rc.Flush(); err != nil || strings.HasPrefix(msg, "data:") {
+			return
+		}
+	}
+}
+
+// liveReload adds reloadScript to HTML responses.
+func liveReload(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rw := &reloadWriter{ResponseWriter: w}
+		h.ServeHTTP(rw, r)
+		if !rw.html {
+			return
+		}
+
+		body := rw.buf.Bytes()
+		if i := bytes.LastIndex(body, []byte("</body>")); i >= 0 {
+			body = append(body[:i:i], append([]byte(reloadScript), body[i:]...)...)
+		} else {
+			body = append(body, reloadScript...)
+		}
+		w.WriteHeader(rw.status)
+		if _, err := w.Write(body); err != nil {
+			reqLog(r).Errorw("error writing page", zap.Error(err))
+		}
+	})
+}
+
+// reloadWriter buffers HTML responses so liveReload can change them, and
+// passes everything else through.
+type reloadWriter struct {
+	http.ResponseWriter
+	buf         bytes.Buffer
+	html        bool
+	status      int
+	wroteHeader bool
+}
+
+func (w *reloadWriter) WriteHeader(code int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+
+	w.html = code == http.StatusOK && strings.HasPrefix(w.Header().Get("Content-Type"), "text/html")
+	if w.html {
+		w.status = code
+		w.Header().Del("Content-Length")
+		w.Header().Del("ETag")
+		return
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *reloadWriter) Write(p []byte) (int, error) {
+	if !w.wroteHeader {
+		if w.Header().Get("Content-Type") == "" {
+			w.Header().Set("Content-Type", http.DetectContentType(p))
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.html {
+		return w.buf.Write(p)
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+// Unwrap lets http.ResponseController flush streamed responses.
+func (w *reloadWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/storage"
+	chi "github.com/go-chi/chi/v5"
+	"github.com/icco/wallpapers"
+	"go.uber.org/zap"
+)
+
+const downloadTimeout = 30 * time.Minute
+
+// Ranged reads start small, so a browser scrubbing through a video only
+// fetches a little past each position, and double up to a limit while a
+// client keeps reading.
+const (
+	minReadWindow = 1 << 20
+	maxReadWindow = 64 << 20
+)
+
+// objectSeeker lets http.ServeContent read a stored file. Seeking only
+// moves the offset; the next Read opens a ranged reader there, so only the
+// requested part of the file is fetched.
+type objectSeeker struct {
+	ctx    context.Context
+	name   string
+	size   int64
+	offset int64
+
+	rc     io.ReadCloser
+	window int64 // size of the current ranged read
+	end    int64 // offset where the current ranged read ends
+}
+
+func (s *objectSeeker) Read(p []byte) (int, error) {
+	if s.offset >= s.size {
+		return 0, io.EOF
+	}
+
+	if s.rc != nil && s.offset >= s.end {
+		s.Close()
+		s.window = min(s.window*2, maxReadWindow)
+	}
+	if s.rc == nil {
+		s.window = max(s.window, minReadWindow)
+		length := min(s.window, s.size-s.offset)
+		rc, err := wallpapers.OpenRange(s.ctx, s.name, s.offset, length)
+		if err != nil {
+			return 0, err
+		}
+		s.rc = rc
+		s.end = s.offset + length
+	}
+
+	n, err := s.rc.Read(p[:min(int64(len(p)), s.end-s.offset)])
+	s.offset += int64(n)
+	// The end of a window is not the end of the file.
+	if errors.Is(err, io.EOF) && s.offset < s.size {
+		if s.offset < s.end {
+			return n, io.ErrUnexpectedEOF
+		}
+		err = nil
+	}
+	return n, err
+}
+
+func (s *objectSeeker) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekCurrent:
+		offset += s.offset
+	case io.SeekEnd:
+		offset += s.size
+	}
+	if offset < 0 {
+		return 0, errors.New("seek before start of file")
+	}
+
+	if offset != s.offset {
+		s.Close()
+		s.offset = offset
+		s.window = 0
+	}
+	return offset, nil
+}
+
+func (s *objectSeeker) Close() error {
+	if s.rc == nil {
+		return nil
+	}
+	err := s.rc.Close()
+	s.rc = nil
+	return err
+}
+
+// downloadHandler streams a wallpaper's original file as an attachment, so
+// clients do not depend on GCS media links. Range and conditional requests
+// are supported.
+func downloadHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	name := chi.URLParam(r, "name")
+	f, err := wallpapers.GetFile(ctx, name)
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		renderError(w, r, http.StatusNotFound, "not_found", "not found")
+		return
+	}
+	if err != nil {
+		reqLog(r).Errorw("error during download get file", "name", name, zap.Error(err))
+		renderError(w, r, http.StatusInternalServerError, "internal", "retrieval error")
+		return
+	}
+
+	// Requests for later ranges of a file resume a download and are not
+	// counted.
+	rng := r.Header.Get("Range")
+	if r.Method == http.MethodGet && (rng == "" || strings.HasPrefix(rng, "bytes=0-")) {
+		recordDownload(f.Name)
+	}
+
+	serveObject(w, r, f, "attachment", "public, max-age=86400")
+}
+
+// serveObject streams a stored file with the given Content-Disposition type
+// and Cache-Control. Range and conditional requests are supported.
+func serveObject(w http.ResponseWriter, r *http.Request, f *wallpapers.File, disposition, cacheControl string) {
+	// The server's write timeout is far too short for a large original.
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Now().Add(downloadTimeout)); err != nil {
+		reqLog(r).Warnw("could not extend download write deadline", zap.Error(err))
+	}
+
+	contentType := mime.TypeByExtension(filepath.Ext(f.Name))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": f.Name}))
+	w.Header().Set("Cache-Control", cacheControl)
+	if f.Etag != "" {
+		w.Header().Set("ETag", fmt.Sprintf("%q", f.Etag))
+	}
+
+	content := &objectSeeker{ctx: r.Context(), name: f.Name, size: f.Size}
+	defer content.Close()
+	http.ServeContent(w, r, f.Name, f.Updated, content)
+}
+
+// downloadsHandler reports how many times each file has been downloaded,
+// counted as /stats/usage.json counts downloads.
+func downloadsHandler(w http.ResponseWriter, r *http.Request) {
+	counts, _ := popularity()
+
+	w.Header().Set("Cache-Control", "no-store")
+	if err := Renderer.JSON(w, http.StatusOK, counts); err != nil {
+		reqLog(r).Errorw("error during downloads render", zap.Error(err))
+	}
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"cloud.google.com/go/storage"
+	chi "github.com/go-chi/chi/v5"
+	"github.com/icco/wallpapers"
+	"go.uber.org/zap"
+)
+
+const (
+	// maxEinkDimension is lower than maxFitDimension because renditions
+	// are made in memory when imgix is not available.
+	maxEinkDimension  = 4096
+	defaultEinkLevels = 2
+	// einkTimeout bounds downloading and dithering an original that has
+	// no cached rendition yet.
+	einkTimeout = 30 * time.Second
+)
+
+// einkPanels are the sizes the server dithers to, those of common e-ink
+// panels in landscape. Rendering and caching every requested size would
+// let anyone fill the variants store, so other sizes are redirected to the
+// smallest panel that covers them.
+var einkPanels = []wallpapers.Size{
+	{Width: 296, Height: 128},
+	{Width: 400, Height: 300},
+	{Width: 600, Height: 448},
+	{Width: 640, Height: 384},
+	{Width: 800, Height: 480},
+	{Width: 800, Height: 600},
+	{Width: 1024, Height: 758},
+	{Width: 1200, Height: 825},
+	{Width: 1448, Height: 1072},
+	{Width: 1600, Height: 1200},
+	{Width: 1872, Height: 1404},
+	{Width: 2200, Height: 1650},
+	{Width: 3200, Height: 1800},
+}
+
+// einkLevels are the numbers of gray levels the server dithers to: 1, 2
+// and 4 bits per pixel.
+var einkLevels = []int{2, 4, 16}
+
+// snapEink returns the panel size and levels the server renders for a
+// request of w by h with levels: the smallest panel, turned to match the
+// request, that is at least w by h, or the largest if none is, and the most
+// levels that do not exceed the request.
+func snapEink(w, h, levels int) (int, int, int) {
+	portrait := h > w
+	if portrait {
+		w, h = h, w
+	}
+
+	panel := einkPanels[len(einkPanels)-1]
+	for _, p := range einkPanels {
+		if p.Width >= w && p.Height >= h && p.Width*p.Height < panel.Width*panel.Height {
+			panel = p
+		}
+	}
+	w, h = panel.Width, panel.Height
+	if portrait {
+		w, h = h, w
+	}
+
+	snapped := einkLevels[0]
+	for _, l := range einkLevels {
+		if l <= levels {
+			snapped = l
+		}
+	}
+
+	return w, h, snapped
+}
+
+// einkParams reads and validates the w, h and levels query parameters.
+func einkParams(r *http.Request) (int, int, int, error) {
+	q := r.URL.Query()
+
+	w, err := strconv.Atoi(q.Get("w"))
+	if err != nil || w < 1 || w > maxEinkDimension {
+		return 0, 0, 0, invalidParam("w", "w must be an integer between 1 and %d", maxEinkDimension)
+	}
+
+	h, err := strconv.Atoi(q.Get("h"))
+	if err != nil || h < 1 || h > maxEinkDimension {
+		return 0, 0, 0, invalidParam("h", "h must be an integer between 1 and %d", maxEinkDimension)
+	}
+
+	levels := defaultEinkLevels
+	if v := q.Get("levels"); v != "" {
+		levels, err = strconv.Atoi(v)
+		if err != nil || levels < 2 || levels > 256 {
+			return 0, 0, 0, invalidParam("levels", "levels must be an integer between 2 and 256")
+		}
+	}
+
+	return w, h, levels, nil
+}
+
+// einkHandler serves a grayscale version of a wallpaper for e-ink displays.
+// Collections behind imgix are redirected to an imgix rendition. Otherwise
+// the image is dithered here, at one of einkPanels and einkLevels, and the
+// result cached in the store's variants.
+func einkHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	width, height, levels, err := einkParams(r)
+	if err != nil {
+		renderBadRequest(w, r, "bad_request", err)
+		return
+	}
+
+	name := chi.URLParam(r, "name")
+	file, err := wallpapers.GetFile(ctx, name)
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		renderError(w, r, http.StatusNotFound, "not_found", "not found")
+		return
+	}
+	if err != nil {
+		reqLog(r).Errorw("error during eink get file", "name", name, zap.Error(err))
+		renderError(w, r, http.StatusInternalServerError, "internal", "retrieval error")
+		return
+	}
+	if file.Type != wallpapers.TypeImage {
+		renderError(w, r, http.StatusBadRequest, "bad_request", "only images have e-ink versions")
+		return
+	}
+
+	if u, ok := wallpapers.EinkURL(ctx, file.Name, width, height, levels); ok {
+		http.Redirect(w, r, u, http.StatusFound)
+		return
+	}
+
+	if sw, sh, sl := snapEink(width, height, levels); sw != width || sh != height || sl != levels {
+		q := r.URL.Query()
+		q.Set("w", strconv.Itoa(sw))
+		q.Set("h", strconv.Itoa(sh))
+		q.Set("levels", strconv.Itoa(sl))
+		u := *r.URL
+		u.RawQuery = q.Encode()
+		http.Redirect(w, r, u.RequestURI(), http.StatusFound)
+		return
+	}
+
+	variant := wallpapers.EinkName(file, width, height, levels)
+	content, err := wallpapers.LoadVariant(ctx, variant)
+	if err != nil {
+		if !errors.Is(err, storage.ErrObjectNotExist) {
+			reqLog(r).Warnw("could not read cached eink version", "name", variant, zap.Error(err))
+		}
+
+		// The server's write timeout is too short to download and dither
+		// a large original.
+		if err := http.NewResponseController(w).SetWriteDeadline(time.Now().Add(einkTimeout)); err != nil {
+			reqLog(r).Warnw("could not extend eink write deadline", zap.Error(err))
+		}
+
+		original, err := wallpapers.DownloadFile(ctx, file.Name)
+		if err != nil {
+			reqLog(r).Errorw("error during eink download", "name", name, zap.Error(err))
+			renderError(w, r, http.StatusInternalServerError, "internal", "retrieval error")
+			return
+		}
+
+		content, err = wallpapers.Dither(original, width, height, levels)
+		if err != nil {
+			reqLog(r).Errorw("error during eink dither", "name", name, zap.Error(err))
+			renderError(w, r, http.StatusInternalServerError, "internal", "render error")
+			return
+		}
+
+		if err := wallpapers.SaveVariant(ctx, variant, content); err != nil {
+			reqLog(r).Warnw("could not cache eink version", "name", variant, zap.Error(err))
+		}
+	}
+
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("Cache-Control", "public, max-age=86400")
+	if _, err := w.Write(content); err != nil {
+		reqLog(r).Errorw("error writing eink", zap.Error(err))
+	}
+}
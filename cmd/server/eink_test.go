@@ -0,0 +1,27 @@
+package main
+
+import "testing"
+
+func TestSnapEink(t *testing.T) {
+	for _, tc := range []struct {
+		name                string
+		w, h, levels        int
+		wantW, wantH, wantL int
+	}{
+		{name: "panel", w: 800, h: 480, levels: 2, wantW: 800, wantH: 480, wantL: 2},
+		{name: "portrait panel", w: 480, h: 800, levels: 4, wantW: 480, wantH: 800, wantL: 4},
+		{name: "covered by next panel", w: 800, h: 481, levels: 2, wantW: 800, wantH: 600, wantL: 2},
+		{name: "smallest covering panel", w: 500, h: 400, levels: 2, wantW: 600, wantH: 448, wantL: 2},
+		{name: "tiny", w: 1, h: 1, levels: 2, wantW: 296, wantH: 128, wantL: 2},
+		{name: "larger than every panel", w: 4096, h: 4096, levels: 2, wantW: 3200, wantH: 1800, wantL: 2},
+		{name: "levels round down", w: 800, h: 480, levels: 8, wantW: 800, wantH: 480, wantL: 4},
+		{name: "levels capped", w: 800, h: 480, levels: 256, wantW: 800, wantH: 480, wantL: 16},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			w, h, l := snapEink(tc.w, tc.h, tc.levels)
+			if w != tc.wantW || h != tc.wantH || l != tc.wantL {
+				t.Errorf("snapEink(%d, %d, %d) = %d, %d, %d, want %d, %d, %d", tc.w, tc.h, tc.levels, w, h, l, tc.wantW, tc.wantH, tc.wantL)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"go.uber.org/zap"
+)
+
+// apiError describes a failed request. RequestID matches the request-id field
+// of the server's logs for the request. Param names the query parameter
+// that was rejected, if any.
+type apiError struct {
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	Param     string `json:"param,omitempty"`
+	RequestID string `json:"request_id,omitempty"`
+}
+
+func newAPIError(r *http.Request, code, msg string) apiError {
+	return apiError{
+		Code:      code,
+		Message:   msg,
+		RequestID: middleware.GetReqID(r.Context()),
+	}
+}
+
+// legacyError is the error shape of routes outside /v1. Error duplicates
+// Message for clients written against the old {"error": message} responses.
+type legacyError struct {
+	Error string `json:"error"`
+	apiError
+}
+
+// renderError writes an error response. /v1 routes get an envelope, legacy
+// routes get the same fields at the top level.
+func renderError(w http.ResponseWriter, r *http.Request, status int, code, msg string) {
+	writeError(w, r, status, newAPIError(r, code, msg))
+}
+
+func writeError(w http.ResponseWriter, r *http.Request, status int, e apiError) {
+	var body any = legacyError{Error: e.Message, apiError: e}
+	if strings.HasPrefix(r.URL.Path, "/v1/") {
+		body = envelope{Error: &e}
+	}
+
+	if err := Renderer.JSON(w, status, body); err != nil {
+		reqLog(r).Errorw("error during error render", "path", r.URL.Path, zap.Error(err))
+	}
+}
+
+// reqLog returns the server's logger annotated with the request's ID, so
+// handler logs can be matched with request logs and error responses.
+func reqLog(r *http.Request) *zap.SugaredLogger {
+	return ctxLog(r.Context())
+}
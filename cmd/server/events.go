@@ -0,0 +1,239 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/icco/wallpapers"
+	"go.uber.org/zap"
+)
+
+const (
+	eventsPollInterval = time.Minute
+	eventsKeepAlive    = 30 * time.Second
+)
+
+// event is a change to a collection sent to /events subscribers.
+type event struct {
+	Type string           `json:"type"`
+	File *wallpapers.File `json:"file"`
+
+	// store is the collection that changed.
+	store wallpapers.Store
+}
+
+// changes publishes the changes of every served collection.
+var changes = newBroker()
+
+// broker fans collection changes out to server-sent event subscribers. It
+// finds changes by diffing each cached listing of a collection with the one
+// before it, as listings are refreshed by requests, by the server's own
+// changes, from other replicas through Redis, or by watch.
+type broker struct {
+	mu   sync.Mutex
+	subs map[chan event]wallpapers.Store
+
+	// knownMu orders diffs, so that each listing is compared with the
+	// one before it.
+	knownMu sync.Mutex
+	known   map[wallpapers.Store]map[string]*wallpapers.File
+	// read is when the listing in known was read.
+	read map[wallpapers.Store]time.Time
+}
+
+func newBroker() *broker {
+	return &broker{
+		subs:  map[chan event]wallpapers.Store{},
+		known: map[wallpapers.Store]map[string]*wallpapers.File{},
+		read:  map[wallpapers.Store]time.Time{},
+	}
+}
+
+// subscribe returns a channel receiving the changes of s.
+func (b *broker) subscribe(s wallpapers.Store) chan event {
+	ch := make(chan event, 16)
+	b.mu.Lock()
+	b.subs[ch] = s
+	b.mu.Unlock()
+	return ch
+}
+
+func (b *broker) unsubscribe(ch chan event) {
+	b.mu.Lock()
+	delete(b.subs, ch)
+	b.mu.Unlock()
+}
+
+func (b *broker) subscribers() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.subs)
+}
+
+// publish sends an event to every subscriber of its collection, dropping it
+// for subscribers that are not keeping up.
+func (b *broker) publish(e event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch, s := range b.subs {
+		if s != e.store {
+			continue
+		}
+		select {
+		case ch <- e:
+		default:
+			log.Warnw("dropping event for slow subscriber", "type", e.Type, "name", e.File.Name)
+		}
+	}
+}
+
+// servedStores returns the default store and every public collection.
+func servedStores() []wallpapers.Store {
+	stores := []wallpapers.Store{wallpapers.DefaultStore()}
+	for _, name := range slices.Sorted(maps.Keys(collections)) {
+		if s := collections[name]; !slices.Contains(stores, s) {
+			stores = append(stores, s)
+		}
+	}
+	return stores
+}
+
+// watch reads the cached listing of every served collection until ctx is
+// done, so that expired listings are refreshed and their changes published
+// even when no requests come in. Listings are only read through the cache,
+// which rereads a bucket once its listing expires. Reads are skipped while
+// nobody is subscribed; the next refresh then reports everything that
+// changed in the meantime.
+func (b *broker) watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		for _, s := range servedStores() {
+			if b.isKnown(s) && b.subscribers() == 0 {
+				continue
+			}
+
+			if _, err := listFiles(wallpapers.ContextWithStore(ctx, s)); err != nil {
+				log.Errorw("error polling for events", "collection", collectionName(s), zap.Error(err))
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// isKnown reports whether s has been listed.
+func (b *broker) isKnown(s wallpapers.Store) bool {
+	b.knownMu.Lock()
+	defer b.knownMu.Unlock()
+	_, ok := b.known[s]
+	return ok
+}
+
+// update publishes the differences between files, a listing of s read at
+// read, and the previous listing. Listings read before the previous one
+// are ignored, so a slow poll cannot undo a change already published.
+func (b *broker) update(s wallpapers.Store, files []*wallpapers.File, read time.Time) {
+	b.knownMu.Lock()
+	defer b.knownMu.Unlock()
+
+	if read.Before(b.read[s]) {
+		return
+	}
+
+	current := make(map[string]*wallpapers.File, len(files))
+	for _, f := range files {
+		current[f.Name] = f
+	}
+	if known, ok := b.known[s]; ok {
+		b.diff(s, known, current)
+	}
+	b.known[s] = current
+	b.read[s] = read
+}
+
+func (b *broker) diff(s wallpapers.Store, before, after map[string]*wallpapers.File) {
+	for name, f := range after {
+		old, ok := before[name]
+		switch {
+		case !ok:
+			b.publish(event{Type: "add", File: f, store: s})
+		case old.Etag != f.Etag:
+			b.publish(event{Type: "update", File: f, store: s})
+		}
+	}
+
+	for name, f := range before {
+		if _, ok := after[name]; !ok {
+			b.publish(event{Type: "delete", File: f, store: s})
+		}
+	}
+}
+
+// handler streams the changes of the request's collection as server-sent
+// events.
+func (b *broker) handler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	rc := http.NewResponseController(w)
+
+	ch := b.subscribe(wallpapers.StoreFor(ctx))
+	defer b.unsubscribe(ch)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	write := func(msg string) error {
+		// The server's write timeout is meant for normal requests, so push it
+		// out before each write to keep the stream open.
+		if err := rc.SetWriteDeadline(time.Now().Add(eventsKeepAlive * 2)); err != nil {
+			reqLog(r).Debugw("could not extend events write deadline", zap.Error(err))
+		}
+		if _, err := fmt.Fprint(w, msg); err != nil {
+			return err
+		}
+		return rc.Flush()
+	}
+
+	if err := write(": connected\n\n"); err != nil {
+		reqLog(r).Errorw("error writing events", zap.Error(err))
+		return
+	}
+
+	keepAlive := time.NewTicker(eventsKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		var msg string
+		select {
+		case <-ctx.Done():
+			return
+		case <-keepAlive.C:
+			msg = ": keep-alive\n\n"
+		case e := <-ch:
+			data, err := json.Marshal(e)
+			if err != nil {
+				reqLog(r).Errorw("error encoding event", zap.Error(err))
+				continue
+			}
+			msg = fmt.Sprintf("event: %s\ndata: %s\n\n", e.Type, data)
+		}
+
+		if err := write(msg); err != nil {
+			reqLog(r).Debugw("events client went away", zap.Error(err))
+			return
+		}
+	}
+}
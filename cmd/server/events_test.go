@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/icco/wallpapers"
+)
+
+func TestBrokerUpdate(t *testing.T) {
+	a, b := wallpapers.NewMemoryStore(), wallpapers.NewMemoryStore()
+	file := func(name, etag string) *wallpapers.File {
+		return &wallpapers.File{Name: name, Etag: etag}
+	}
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	br := newBroker()
+	subA, subB := br.subscribe(a), br.subscribe(b)
+
+	// received returns the events waiting on ch as type:name.
+	received := func(ch chan event) []string {
+		var got []string
+		for {
+			select {
+			case e := <-ch:
+				got = append(got, e.Type+":"+e.File.Name)
+			default:
+				return got
+			}
+		}
+	}
+
+	for _, step := range []struct {
+		name  string
+		store wallpapers.Store
+		files []*wallpapers.File
+		read  time.Time
+		wantA []string
+		wantB []string
+	}{
+		{
+			name:  "first listing is only recorded",
+			store: a,
+			files: []*wallpapers.File{file("x.png", "1")},
+			read:  base,
+		},
+		{
+			name:  "changes go to the collection's subscribers",
+			store: a,
+			files: []*wallpapers.File{file("x.png", "2"), file("y.png", "1")},
+			read:  base.Add(time.Minute),
+			wantA: []string{"add:y.png", "update:x.png"},
+		},
+		{
+			name:  "listings read before the last one are ignored",
+			store: a,
+			files: []*wallpapers.File{file("x.png", "1")},
+			read:  base.Add(time.Second),
+		},
+		{
+			name:  "deletes",
+			store: a,
+			files: []*wallpapers.File{file("x.png", "2")},
+			read:  base.Add(2 * time.Minute),
+			wantA: []string{"delete:y.png"},
+		},
+		{
+			name:  "other collections are tracked separately",
+			store: b,
+			files: nil,
+			read:  base,
+		},
+		{
+			name:  "other collections have their own subscribers",
+			store: b,
+			files: []*wallpapers.File{file("z.png", "1")},
+			read:  base.Add(time.Minute),
+			wantB: []string{"add:z.png"},
+		},
+	} {
+		br.update(step.store, step.files, step.read)
+
+		gotA, gotB := received(subA), received(subB)
+		slices.Sort(gotA)
+		if !slices.Equal(gotA, step.wantA) || !slices.Equal(gotB, step.wantB) {
+			t.Errorf("%s: events = %q and %q, want %q and %q", step.name, gotA, gotB, step.wantA, step.wantB)
+		}
+	}
+}
+
+func TestWatchReadsCachedListing(t *testing.T) {
+	s := wallpapers.NewMemoryStore()
+	old := wallpapers.DefaultStore()
+	wallpapers.SetStore(s)
+	sub := changes.subscribe(s)
+	t.Cleanup(func() {
+		changes.unsubscribe(sub)
+		wallpapers.SetStore(old)
+		listingsMu.Lock()
+		delete(listings, s)
+		listingsMu.Unlock()
+	})
+
+	// poll runs watch once, as it returns after its first round when ctx
+	// is already done, and returns the events it published.
+	poll := func() []string {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		changes.watch(ctx, time.Hour)
+		var got []string
+		for {
+			select {
+			case e := <-sub:
+				got = append(got, e.Type+":"+e.File.Name)
+			default:
+				return got
+			}
+		}
+	}
+
+	putFile(t, s, "a.jpg")
+	if got := poll(); got != nil {
+		t.Errorf("first poll published %q, want nothing", got)
+	}
+
+	putFile(t, s, "b.jpg")
+	if got := poll(); got != nil {
+		t.Errorf("poll of a fresh listing published %q, want nothing as the bucket is not reread", got)
+	}
+
+	listingsMu.Lock()
+	l := *listings[s]
+	l.fetched = l.fetched.Add(-listingTTL)
+	listings[s] = &l
+	listingsMu.Unlock()
+	if got, want := poll(), []string{"add:b.jpg"}; !slices.Equal(got, want) {
+		t.Errorf("poll of an expired listing published %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/storage"
+	chi "github.com/go-chi/chi/v5"
+	"github.com/icco/wallpapers"
+	"go.uber.org/zap"
+)
+
+const (
+	maxFitDimension = 8192
+	maxFitDPR       = 4
+
+	// dailyTTL is how long a day's pick is kept in Redis, long enough to
+	// outlast the day in every time zone.
+	dailyTTL = 48 * time.Hour
+)
+
+var (
+	dailyMu sync.Mutex
+	// dailies holds each collection's pick of the day when Redis is not
+	// set, keyed by the pick's Redis key.
+	dailies = map[string]string{}
+)
+
+// fitParams reads and validates the w, h and dpr query parameters.
+func fitParams(r *http.Request) (int, int, float64, error) {
+	q := r.URL.Query()
+
+	w, err := strconv.Atoi(q.Get("w"))
+	if err != nil || w < 1 || w > maxFitDimension {
+		return 0, 0, 0, invalidParam("w", "w must be an integer between 1 and %d", maxFitDimension)
+	}
+
+	h, err := strconv.Atoi(q.Get("h"))
+	if err != nil || h < 1 || h > maxFitDimension {
+		return 0, 0, 0, invalidParam("h", "h must be an integer between 1 and %d", maxFitDimension)
+	}
+
+	dpr := 1.0
+	if v := q.Get("dpr"); v != "" {
+		dpr, err = strconv.ParseFloat(v, 64)
+		if err != nil || dpr < 1 || dpr > maxFitDPR {
+			return 0, 0, 0, invalidParam("dpr", "dpr must be a number between 1 and %d", maxFitDPR)
+		}
+	}
+
+	return w, h, dpr, nil
+}
+
+func fitHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	width, height, dpr, err := fitParams(r)
+	if err != nil {
+		renderBadRequest(w, r, "bad_request", err)
+		return
+	}
+
+	name := chi.URLParam(r, "name")
+	file, err := wallpapers.GetFile(ctx, name)
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		renderError(w, r, http.StatusNotFound, "not_found", "not found")
+		return
+	}
+	if err != nil {
+		reqLog(r).Errorw("error during fit get file", "name", name, zap.Error(err))
+		renderError(w, r, http.StatusInternalServerError, "internal", "retrieval error")
+		return
+	}
+
+	http.Redirect(w, r, wallpapers.FitURL(ctx, file.Name, width, height, dpr), http.StatusFound)
+}
+
+func fitRandomHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	width, height, dpr, err := fitParams(r)
+	if err != nil {
+		renderBadRequest(w, r, "bad_request", err)
+		return
+	}
+
+	images, err := listFiles(ctx)
+	if err != nil {
+		reqLog(r).Errorw("error during fit random get all", zap.Error(err))
+		renderError(w, r, http.StatusInternalServerError, "internal", "retrieval error")
+		return
+	}
+
+	images = slices.DeleteFunc(images, func(f *wallpapers.File) bool {
+		return f.Type != wallpapers.TypeImage || f.VariantOf != ""
+	})
+	if len(images) == 0 {
+		renderError(w, r, http.StatusNotFound, "not_found", "no images in collection")
+		return
+	}
+
+	file := images[rand.IntN(len(images))]
+	w.Header().Set("Cache-Control", "no-store")
+	http.Redirect(w, r, wallpapers.FitURL(ctx, file.Name, width, height, dpr), http.StatusFound)
+}
+
+func fitDailyHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	width, height, dpr, err := fitParams(r)
+	if err != nil {
+		renderBadRequest(w, r, "bad_request", err)
+		return
+	}
+
+	images, err := listFiles(ctx)
+	if err != nil {
+		reqLog(r).Errorw("error during fit daily get all", zap.Error(err))
+		renderError(w, r, http.StatusInternalServerError, "internal", "retrieval error")
+		return
+	}
+
+	images = slices.DeleteFunc(images, func(f *wallpapers.File) bool {
+		return f.Type != wallpapers.TypeImage || f.VariantOf != ""
+	})
+	if len(images) == 0 {
+		renderError(w, r, http.StatusNotFound, "not_found", "no images in collection")
+		return
+	}
+
+	now := time.Now().UTC()
+	name := dailyPick(r, images, now)
+	midnight := now.Truncate(24 * time.Hour).Add(24 * time.Hour)
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(midnight.Sub(now).Seconds())))
+	http.Redirect(w, r, wallpapers.FitURL(ctx, name, width, height, dpr), http.StatusFound)
+}
+
+// dailyPick returns the name of the wallpaper of the day for the request's
+// collection. The first pick of a day is kept, in Redis when it is set so
+// every replica agrees, so that wallpapers added during the day do not
+// change it.
+func dailyPick(r *http.Request, images []*wallpapers.File, now time.Time) string {
+	ctx := r.Context()
+	suffix := ":daily:" + now.Format(time.DateOnly)
+	key := listingKey(wallpapers.StoreFor(ctx)) + suffix
+	has := func(name string) bool {
+		return slices.ContainsFunc(images, func(f *wallpapers.File) bool { return f.Name == name })
+	}
+
+	// Sort by name so the pick only depends on the day, as walls set -daily
+	// does.
+	slices.SortFunc(images, func(a, b *wallpapers.File) int { return strings.Compare(a.Name, b.Name) })
+	day := now.Unix() / int64((24 * time.Hour).Seconds())
+	pick := images[day%int64(len(images))].Name
+
+	if sharedCache == nil {
+		dailyMu.Lock()
+		defer dailyMu.Unlock()
+		if name, ok := dailies[key]; ok && has(name) {
+			return name
+		}
+		// Only the current day's picks are kept.
+		maps.DeleteFunc(dailies, func(k, _ string) bool { return !strings.HasSuffix(k, suffix) })
+		dailies[key] = pick
+		return pick
+	}
+
+	name, err := sharedCache.Get(ctx, key)
+	switch {
+	case err == nil && has(string(name)):
+		return string(name)
+	case err == nil:
+		// The day's pick has been deleted since, so replace it.
+		if err := sharedCache.Set(ctx, key, []byte(pick), dailyTTL); err != nil {
+			reqLog(r).Warnw("could not write shared daily pick", zap.Error(err))
+		}
+		return pick
+	case !errors.Is(err, errCacheMiss):
+		reqLog(r).Warnw("could not read shared daily pick", zap.Error(err))
+		return pick
+	}
+
+	ok, err := sharedCache.SetNX(ctx, key, []byte(pick), dailyTTL)
+	if err != nil {
+		reqLog(r).Warnw("could not write shared daily pick", zap.Error(err))
+		return pick
+	}
+	if !ok {
+		// Another replica picked first.
+		if name, err := sharedCache.Get(ctx, key); err == nil && has(string(name)) {
+			return string(name)
+		}
+	}
+	return pick
+}
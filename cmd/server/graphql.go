@@ -0,0 +1,410 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"github.com/graph-gophers/graphql-go"
+	"github.com/icco/wallpapers"
+	"go.uber.org/zap"
+)
+
+// graphQLSchema is the schema served at /graphql. Lists are paged with the
+// same signed cursors as /v1, and accept the same sort, order, type and
+// variants values as the listing endpoints' query parameters.
+const graphQLSchema = `
+type Query {
+  images(first: Int = 100, after: String, sort: String = "added", order: String, type: String, variants: Boolean = false, collection: String): ImageConnection!
+  search(query: String!, first: Int = 100, after: String, collection: String): ImageConnection!
+  image(key: String!, collection: String): Image
+  collections: [Collection!]!
+  collection(name: String!): Collection
+  stats(collection: String): Stats!
+}
+
+type ImageConnection {
+  nodes: [Image!]!
+  nextCursor: String
+  totalCount: Int!
+}
+
+type Image {
+  key: String!
+  type: String!
+  etag: String!
+  cdn: String!
+  thumbnail: String!
+  size: Int!
+  createdAt: String!
+  updatedAt: String!
+  addedAt: String!
+  video: Video
+  colorProfile: String
+  variantOf: String
+  altText: String
+  sourceURL: String
+  author: String
+  license: String
+}
+
+type Video {
+  width: Int
+  height: Int
+  duration: Float
+}
+
+type Collection {
+  name: String!
+  images(first: Int = 100, after: String, sort: String = "added", order: String, type: String, variants: Boolean = false): ImageConnection!
+  stats: Stats!
+}
+
+type Stats {
+  count: Int!
+  totalSize: Int!
+  formats: [FormatCount!]!
+}
+
+type FormatCount {
+  format: String!
+  count: Int!
+}
+`
+
+var graphQL = graphql.MustParseSchema(graphQLSchema, &gqlQuery{})
+
+// gqlQuery resolves the Query type.
+type gqlQuery struct{}
+
+// gqlListArgs are the arguments of every field that lists images.
+type gqlListArgs struct {
+	First    int32
+	After    *string
+	Sort     string
+	Order    *string
+	Type     *string
+	Variants bool
+}
+
+func (*gqlQuery) Images(ctx context.Context, args struct {
+	gqlListArgs
+	Collection *string
+}) (*gqlConnection, error) {
+	return gqlImages(ctx, gqlValue(args.Collection), args.gqlListArgs, "")
+}
+
+func (*gqlQuery) Search(ctx context.Context, args struct {
+	Query      string
+	First      int32
+	After      *string
+	Collection *string
+}) (*gqlConnection, error) {
+	list := gqlListArgs{First: args.First, After: args.After}
+	return gqlImages(ctx, gqlValue(args.Collection), list, args.Query)
+}
+
+func (*gqlQuery) Image(ctx context.Context, args struct {
+	Key        string
+	Collection *string
+}) (*gqlImage, error) {
+	collection := gqlValue(args.Collection)
+	ctx, err := gqlCollection(ctx, collection)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := wallpapers.GetFile(ctx, args.Key)
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		ctxLog(ctx).Errorw("error during graphql image", "name", args.Key, zap.Error(err))
+		return nil, errors.New("retrieval error")
+	}
+	return &gqlImage{withImageURLs(f, collection)}, nil
+}
+
+func (*gqlQuery) Collections() []*gqlCollectionType {
+	var all []*gqlCollectionType
+	for _, name := range slices.Sorted(maps.Keys(collections)) {
+		all = append(all, &gqlCollectionType{name: name})
+	}
+	return all
+}
+
+func (*gqlQuery) Collection(args struct{ Name string }) *gqlCollectionType {
+	if _, ok := collections[args.Name]; !ok {
+		return nil
+	}
+	return &gqlCollectionType{name: args.Name}
+}
+
+func (*gqlQuery) Stats(ctx context.Context, args struct{ Collection *string }) (*gqlStats, error) {
+	return gqlCollectionStats(ctx, gqlValue(args.Collection))
+}
+
+// gqlConnection is a page of images.
+type gqlConnection struct {
+	nodes      []*gqlImage
+	nextCursor string
+	totalCount int
+}
+
+func (c *gqlConnection) Nodes() []*gqlImage  { return c.nodes }
+func (c *gqlConnection) NextCursor() *string { return gqlOptional(c.nextCursor) }
+func (c *gqlConnection) TotalCount() int32   { return int32(c.totalCount) }
+
+// gqlImage resolves the Image type.
+type gqlImage struct {
+	f *wallpapers.File
+}
+
+func (i *gqlImage) Key() string           { return i.f.Name }
+func (i *gqlImage) Type() string          { return i.f.Type }
+func (i *gqlImage) Etag() string          { return i.f.Etag }
+func (i *gqlImage) CDN() string           { return i.f.FullRezURL }
+func (i *gqlImage) Thumbnail() string     { return i.f.ThumbnailURL }
+func (i *gqlImage) Size() gqlInt          { return gqlInt(i.f.Size) }
+func (i *gqlImage) CreatedAt() string     { return gqlTime(i.f.Created) }
+func (i *gqlImage) UpdatedAt() string     { return gqlTime(i.f.Updated) }
+func (i *gqlImage) AddedAt() string       { return gqlTime(i.f.Added()) }
+func (i *gqlImage) ColorProfile() *string { return gqlOptional(i.f.ColorProfile) }
+func (i *gqlImage) VariantOf() *string    { return gqlOptional(i.f.VariantOf) }
+func (i *gqlImage) AltText() *string      { return gqlOptional(i.f.AltText) }
+func (i *gqlImage) SourceURL() *string    { return gqlOptional(i.f.SourceURL) }
+func (i *gqlImage) Author() *string       { return gqlOptional(i.f.Author) }
+func (i *gqlImage) License() *string      { return gqlOptional(i.f.License) }
+
+func (i *gqlImage) Video() *gqlVideo {
+	if i.f.Video == nil {
+		return nil
+	}
+	return &gqlVideo{i.f.Video}
+}
+
+// gqlVideo resolves the Video type. Unknown dimensions are null.
+type gqlVideo struct {
+	v *wallpapers.VideoInfo
+}
+
+func (v *gqlVideo) Width() *int32  { return gqlOptionalInt(v.v.Width) }
+func (v *gqlVideo) Height() *int32 { return gqlOptionalInt(v.v.Height) }
+
+func (v *gqlVideo) Duration() *float64 {
+	if v.v.Duration == 0 {
+		return nil
+	}
+	return &v.v.Duration
+}
+
+// gqlCollectionType resolves the Collection type.
+type gqlCollectionType struct {
+	name string
+}
+
+func (c *gqlCollectionType) Name() string { return c.name }
+
+func (c *gqlCollectionType) Images(ctx context.Context, args gqlListArgs) (*gqlConnection, error) {
+	return gqlImages(ctx, c.name, args, "")
+}
+
+func (c *gqlCollectionType) Stats(ctx context.Context) (*gqlStats, error) {
+	return gqlCollectionStats(ctx, c.name)
+}
+
+// gqlStats resolves the Stats type.
+type gqlStats struct {
+	s *wallpapers.Stats
+}
+
+func (s *gqlStats) Count() int32      { return int32(s.s.Count) }
+func (s *gqlStats) TotalSize() gqlInt { return gqlInt(s.s.TotalSize) }
+
+func (s *gqlStats) Formats() []*gqlFormatCount {
+	formats := []*gqlFormatCount{}
+	for _, format := range slices.Sorted(maps.Keys(s.s.Formats)) {
+		formats = append(formats, &gqlFormatCount{format: format, count: s.s.Formats[format]})
+	}
+	return formats
+}
+
+// gqlFormatCount is one entry of Stats.formats.
+type gqlFormatCount struct {
+	format string
+	count  int
+}
+
+func (f *gqlFormatCount) Format() string { return f.format }
+func (f *gqlFormatCount) Count() int32   { return int32(f.count) }
+
+// gqlInt is an Int that can be larger than the 32 bits GraphQL promises,
+// such as a size in bytes. It is only ever returned, never an argument.
+type gqlInt int64
+
+func (gqlInt) ImplementsGraphQLType(name string) bool { return name == "Int" }
+
+func (n *gqlInt) UnmarshalGraphQL(input any) error {
+	return fmt.Errorf("gqlInt cannot be used as an argument, got %v", input)
+}
+
+// gqlOptional returns nil for an empty string, so that unset fields are
+// null rather than "".
+func gqlOptional(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+func gqlOptionalInt(n int) *int32 {
+	if n == 0 {
+		return nil
+	}
+	v := int32(n)
+	return &v
+}
+
+func gqlValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// gqlTime formats t as encoding/json does.
+func gqlTime(t time.Time) string {
+	return t.Format(time.RFC3339Nano)
+}
+
+// gqlCollection returns ctx switched to the named collection, as
+// collectionMiddleware does for the collection query parameter.
+func gqlCollection(ctx context.Context, name string) (context.Context, error) {
+	if name == "" {
+		return ctx, nil
+	}
+	s, ok := collections[name]
+	if !ok {
+		return nil, errors.New("unknown collection")
+	}
+	return wallpapers.ContextWithStore(ctx, s), nil
+}
+
+// gqlImages returns a page of the collection's images, keeping only those
+// matching query if it is set.
+func gqlImages(ctx context.Context, collection string, args gqlListArgs, query string) (*gqlConnection, error) {
+	ctx, err := gqlCollection(ctx, collection)
+	if err != nil {
+		return nil, err
+	}
+
+	first := int(args.First)
+	if first < 1 || first > maxPageSize {
+		return nil, invalidParam("first", "first must be between 1 and %d", maxPageSize)
+	}
+	offset := 0
+	if after := gqlValue(args.After); after != "" {
+		if offset, err = decodeCursor(after); err != nil {
+			return nil, err
+		}
+	}
+
+	q := url.Values{}
+	for name, v := range map[string]string{"sort": args.Sort, "order": gqlValue(args.Order), "type": gqlValue(args.Type)} {
+		if v != "" {
+			q.Set(name, v)
+		}
+	}
+	if args.Variants {
+		q.Set("variants", "all")
+	}
+	opts, err := listOptionsFrom(q)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := listFiles(ctx)
+	if err != nil {
+		ctxLog(ctx).Errorw("error during graphql images", zap.Error(err))
+		return nil, errors.New("retrieval error")
+	}
+	if files, err = opts.sorted(files); err != nil {
+		return nil, err
+	}
+	if query != "" {
+		files = searchFiles(ctx, files, query)
+	}
+
+	page, next := paginate(files, first, offset)
+	nodes := make([]*gqlImage, 0, len(page))
+	for _, f := range page {
+		nodes = append(nodes, &gqlImage{withImageURLs(f, collection)})
+	}
+	return &gqlConnection{nodes: nodes, nextCursor: next, totalCount: len(files)}, nil
+}
+
+func gqlCollectionStats(ctx context.Context, collection string) (*gqlStats, error) {
+	ctx, err := gqlCollection(ctx, collection)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := listFiles(ctx)
+	if err != nil {
+		ctxLog(ctx).Errorw("error during graphql stats", zap.Error(err))
+		return nil, errors.New("retrieval error")
+	}
+	return &gqlStats{wallpapers.Summarize(files)}, nil
+}
+
+// gqlRequest is a GraphQL request, as sent in a POST body.
+type gqlRequest struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+// graphqlHandler runs a GraphQL query, given as query, operationName and
+// variables query parameters or as a JSON body with the same fields.
+// Requests that fail before anything is resolved, such as invalid queries,
+// are a 400.
+func graphqlHandler(w http.ResponseWriter, r *http.Request) {
+	var req gqlRequest
+	if r.Method == http.MethodPost {
+		if err := json.NewDecoder(io.LimitReader(r.Body, 1<<20)).Decode(&req); err != nil {
+			renderError(w, r, http.StatusBadRequest, "bad_request", "invalid request body")
+			return
+		}
+	} else {
+		q := r.URL.Query()
+		req.Query = q.Get("query")
+		req.OperationName = q.Get("operationName")
+		if v := q.Get("variables"); v != "" {
+			if err := json.Unmarshal([]byte(v), &req.Variables); err != nil {
+				renderBadRequest(w, r, "bad_request", invalidParam("variables", "variables must be a JSON object"))
+				return
+			}
+		}
+	}
+	if req.Query == "" {
+		renderBadRequest(w, r, "bad_request", invalidParam("query", "query is required"))
+		return
+	}
+
+	res := graphQL.Exec(r.Context(), req.Query, req.OperationName, req.Variables)
+	status := http.StatusOK
+	if res.Data == nil {
+		status = http.StatusBadRequest
+	}
+	if err := Renderer.JSON(w, status, res); err != nil {
+		reqLog(r).Errorw("error during graphql render", zap.Error(err))
+	}
+}
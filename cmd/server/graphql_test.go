@@ -0,0 +1,222 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/icco/wallpapers"
+)
+
+func TestGraphQL(t *testing.T) {
+	s := wallpapers.NewMemoryStore()
+	shots := wallpapers.NewMemoryStore()
+	ctx := wallpapers.ContextWithStore(context.Background(), s)
+	for _, name := range []string{"a.jpg", "b.jpg", "c.png"} {
+		putFile(t, s, name)
+	}
+	putFile(t, shots, "d.jpg")
+
+	collections["shots"] = shots
+	t.Cleanup(func() {
+		delete(collections, "shots")
+		listingsMu.Lock()
+		delete(listings, s)
+		delete(listings, shots)
+		listingsMu.Unlock()
+	})
+
+	for _, tc := range []struct {
+		name  string
+		query string
+		vars  map[string]any
+		want  string
+	}{
+		{
+			name:  "fields in selection order",
+			query: `{ images(sort: "name", order: "asc", first: 2) { totalCount nodes { type key } } }`,
+			want:  `{"data":{"images":{"totalCount":3,"nodes":[{"type":"image","key":"a.jpg"},{"type":"image","key":"b.jpg"}]}}}`,
+		},
+		{
+			name:  "aliases, fragments and __typename",
+			query: `{ first: image(key: "a.jpg") { ...F } second: image(key: "b.jpg") { ... on Image { key } } } fragment F on Image { __typename key }`,
+			want:  `{"data":{"first":{"__typename":"Image","key":"a.jpg"},"second":{"key":"b.jpg"}}}`,
+		},
+		{
+			name:  "variables",
+			query: `query Get($key: String!, $after: String) { image(key: $key) { key } images(sort: "name", order: "asc", after: $after) { nodes { key } } }`,
+			vars:  map[string]any{"key": "c.png", "after": encodeCursor(2)},
+			want:  `{"data":{"image":{"key":"c.png"},"images":{"nodes":[{"key":"c.png"}]}}}`,
+		},
+		{
+			name:  "missing image is null",
+			query: `{ image(key: "z.jpg") { key } }`,
+			want:  `{"data":{"image":null}}`,
+		},
+		{
+			name:  "skip and include",
+			query: `query($yes: Boolean = true) { image(key: "a.jpg") { key @skip(if: $yes) etag @include(if: false) altText } }`,
+			want:  `{"data":{"image":{"altText":null}}}`,
+		},
+		{
+			name:  "search",
+			query: `{ search(query: "b") { totalCount nodes { key } } }`,
+			want:  `{"data":{"search":{"totalCount":1,"nodes":[{"key":"b.jpg"}]}}}`,
+		},
+		{
+			name:  "stats",
+			query: `{ stats { count formats { format count } } }`,
+			want:  `{"data":{"stats":{"count":3,"formats":[{"format":"jpg","count":2},{"format":"png","count":1}]}}}`,
+		},
+		{
+			name:  "collections",
+			query: `{ collections { name images { nodes { key } } stats { count } } }`,
+			want:  `{"data":{"collections":[{"name":"shots","images":{"nodes":[{"key":"d.jpg"}]},"stats":{"count":1}}]}}`,
+		},
+		{
+			name:  "collection argument",
+			query: `{ image(key: "d.jpg", collection: "shots") { key } }`,
+			want:  `{"data":{"image":{"key":"d.jpg"}}}`,
+		},
+		{
+			name:  "introspection",
+			query: `{ __schema { queryType { name } } __type(name: "FormatCount") { fields { name type { kind ofType { name } } } } }`,
+			want:  `{"data":{"__schema":{"queryType":{"name":"Query"}},"__type":{"fields":[{"name":"format","type":{"kind":"NON_NULL","ofType":{"name":"String"}}},{"name":"count","type":{"kind":"NON_NULL","ofType":{"name":"Int"}}}]}}}`,
+		},
+		{
+			name:  "error on nullable field",
+			query: `{ image(key: "a.jpg", collection: "nope") { key } }`,
+			want:  `{"errors":[{"message":"unknown collection","path":["image"]}],"data":{"image":null}}`,
+		},
+		{
+			name:  "error on non-null field nulls its parent",
+			query: `{ images(first: 0) { totalCount } }`,
+			want:  `{"errors":[{"message":"first must be between 1 and 1000","path":["images"]}],"data":null}`,
+		},
+		{
+			name:  "invalid cursor",
+			query: `{ images(after: "bogus") { totalCount } }`,
+			want:  `{"errors":[{"message":"invalid cursor","path":["images"]}],"data":null}`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := json.Marshal(graphQL.Exec(ctx, tc.query, "", tc.vars))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tc.want {
+				t.Errorf("Exec =\n%s\nwant\n%s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestGraphQLRequestErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		query string
+		op    string
+		want  string
+	}{
+		{name: "syntax", query: `{ images { nodes { key } }`, want: `syntax error: unexpected "", expecting Ident`},
+		{name: "unterminated string", query: `{ image(key: "a) { key } }`, want: "syntax error: literal not terminated"},
+		{name: "unknown field", query: `{ images { count } }`, want: `Cannot query field "count" on type "ImageConnection".`},
+		{name: "missing selection", query: `{ images }`, want: `Field "images" of type "ImageConnection!" must have a selection of subfields. Did you mean "images { ... }"?`},
+		{name: "selection on scalar", query: `{ stats { count { x } } }`, want: `Field "count" must not have a selection since type "Int!" has no subfields.`},
+		{name: "unknown argument", query: `{ images(limit: 1) { totalCount } }`, want: `Unknown argument "limit" on field "images" of type "Query".`},
+		{name: "missing argument", query: `{ image { key } }`, want: `Field "image" argument "key" of type "String!" is required but not provided.`},
+		{name: "undefined variable", query: `{ image(key: $key) { key } }`, want: `Variable "$key" is not defined.`},
+		{name: "missing variable", query: `query($key: String!) { image(key: $key) { key } }`, want: "Variable \"key\" has invalid value null.\nExpected type \"String!\", found null."},
+		{name: "unknown fragment", query: `{ ...F }`, want: `Unknown fragment "F".`},
+		{name: "fragment cycle", query: `{ stats { ...A } } fragment A on Stats { ...B } fragment B on Stats { ...A }`, want: `Cannot spread fragment "A" within itself via B.`},
+		{name: "wrong fragment type", query: `{ stats { ...F } } fragment F on Image { key }`, want: `Fragment "F" cannot be spread here as objects of type "Stats" can never be of type "Image".`},
+		{name: "mutation", query: `mutation { deleteImage }`, want: "no mutations are offered by the schema"},
+		{name: "ambiguous operation", query: `query A { stats { count } } query B { stats { count } }`, want: "more than one operation in query document and no operation name given"},
+		{name: "unknown operation", query: `query A { stats { count } }`, op: "B", want: `no operation with name "B"`},
+		{name: "unknown directive", query: `{ stats @cached { count } }`, want: `Unknown directive "cached".`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			res := graphQL.Exec(context.Background(), tc.query, tc.op, nil)
+			if res.Data != nil || len(res.Errors) == 0 {
+				t.Fatalf("Exec = %s, %v, want a request error", res.Data, res.Errors)
+			}
+			if res.Errors[0].Message != tc.want {
+				t.Errorf("error = %q, want %q", res.Errors[0].Message, tc.want)
+			}
+		})
+	}
+}
+
+func TestGraphQLHandler(t *testing.T) {
+	query := url.Values{"query": {`query($k: String!) { image(key: $k) { key } }`}, "variables": {`{"k": "a.jpg"}`}}
+	for _, tc := range []struct {
+		name       string
+		method     string
+		target     string
+		body       string
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "get",
+			method:     http.MethodGet,
+			target:     "/graphql?" + query.Encode(),
+			wantStatus: http.StatusOK,
+			wantBody:   `{"data":{"image":null}}`,
+		},
+		{
+			name:       "post",
+			method:     http.MethodPost,
+			target:     "/graphql",
+			body:       `{"query": "{ __typename }"}`,
+			wantStatus: http.StatusOK,
+			wantBody:   `{"data":{"__typename":"Query"}}`,
+		},
+		{
+			name:       "invalid query",
+			method:     http.MethodPost,
+			target:     "/graphql",
+			body:       `{"query": "{ nope }"}`,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"errors":[{"message":"Cannot query field \"nope\" on type \"Query\".","locations":[{"line":1,"column":3}]}]}`,
+		},
+		{
+			name:       "missing query",
+			method:     http.MethodGet,
+			target:     "/graphql",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "invalid variables",
+			method:     http.MethodGet,
+			target:     "/graphql?query=%7B__typename%7D&variables=x",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "invalid body",
+			method:     http.MethodPost,
+			target:     "/graphql",
+			body:       `{`,
+			wantStatus: http.StatusBadRequest,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s := wallpapers.NewMemoryStore()
+			r := httptest.NewRequest(tc.method, tc.target, strings.NewReader(tc.body))
+			r = r.WithContext(wallpapers.ContextWithStore(r.Context(), s))
+			w := httptest.NewRecorder()
+			graphqlHandler(w, r)
+
+			if w.Code != tc.wantStatus {
+				t.Errorf("status = %d, want %d: %s", w.Code, tc.wantStatus, w.Body)
+			}
+			if got := strings.TrimSpace(w.Body.String()); tc.wantBody != "" && got != tc.wantBody {
+				t.Errorf("body = %s, want %s", got, tc.wantBody)
+			}
+		})
+	}
+}
@@ -0,0 +1,338 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"github.com/icco/wallpapers"
+	"github.com/icco/wallpapers/config"
+	wallpapersv1 "github.com/icco/wallpapers/proto/wallpapers/v1"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// grpcServer serves the wallpapers.v1 service from the same listings and
+// stores as the HTTP API.
+type grpcServer struct {
+	wallpapersv1.UnimplementedWallpapersServiceServer
+}
+
+// newGRPCServer returns a gRPC server with the wallpapers.v1 service
+// registered.
+func newGRPCServer(opts ...grpc.ServerOption) *grpc.Server {
+	s := grpc.NewServer(append(opts,
+		grpc.ChainUnaryInterceptor(grpcUnaryLogging),
+		grpc.ChainStreamInterceptor(grpcStreamLogging),
+	)...)
+	wallpapersv1.RegisterWallpapersServiceServer(s, &grpcServer{})
+	return s
+}
+
+// grpcTransport returns the address the gRPC service listens on and the
+// options that secure it. Upload and Delete take the API token, so without
+// a TLS certificate the service is only reachable from the same host.
+func grpcTransport(cfg config.Server) (string, []grpc.ServerOption, error) {
+	if cfg.GRPCTLSCert == "" {
+		return net.JoinHostPort("localhost", cfg.GRPCPort), nil, nil
+	}
+
+	creds, err := credentials.NewServerTLSFromFile(cfg.GRPCTLSCert, cfg.GRPCTLSKey)
+	if err != nil {
+		return "", nil, fmt.Errorf("could not load gRPC TLS certificate: %w", err)
+	}
+	return ":" + cfg.GRPCPort, []grpc.ServerOption{grpc.Creds(creds)}, nil
+}
+
+// grpcUnaryLogging gives unary calls a logger, as loggingMiddleware does
+// for HTTP requests, and logs each call.
+func grpcUnaryLogging(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	start := time.Now()
+	resp, err := handler(grpcLogContext(ctx, info.FullMethod), req)
+	logGRPCCall(info.FullMethod, start, err)
+	return resp, err
+}
+
+// grpcStreamLogging is grpcUnaryLogging for streaming calls.
+func grpcStreamLogging(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	err := handler(srv, &loggedStream{ServerStream: ss, ctx: grpcLogContext(ss.Context(), info.FullMethod)})
+	logGRPCCall(info.FullMethod, start, err)
+	return err
+}
+
+// loggedStream replaces the context of a stream.
+type loggedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *loggedStream) Context() context.Context { return s.ctx }
+
+func grpcLogContext(ctx context.Context, method string) context.Context {
+	l := log.With("grpc-method", method)
+	ctx = context.WithValue(ctx, logKey{}, l)
+	return wallpapers.ContextWithLogger(ctx, l)
+}
+
+func logGRPCCall(method string, start time.Time, err error) {
+	log.Infow("grpc call", "grpc-method", method, "code", status.Code(err).String(), "latency", time.Since(start))
+}
+
+// grpcError converts err to a status. Parameter errors are invalid
+// arguments, and anything else is logged and reported as internal with msg.
+func grpcError(ctx context.Context, err error, msg string) error {
+	var pe *paramError
+	if errors.As(err, &pe) {
+		return status.Error(codes.InvalidArgument, pe.msg)
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	ctxLog(ctx).Errorw("error during grpc "+msg, zap.Error(err))
+	return status.Error(codes.Internal, msg)
+}
+
+// grpcCollection returns ctx switched to the named public collection, as
+// collectionMiddleware does for the collection query parameter.
+func grpcCollection(ctx context.Context, name string) (context.Context, error) {
+	if name == "" {
+		return ctx, nil
+	}
+	s, ok := collections[name]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "unknown collection")
+	}
+	return wallpapers.ContextWithStore(ctx, s), nil
+}
+
+// requireGRPCToken checks for apiToken as a bearer token in the call's
+// authorization metadata, as requireToken does for HTTP, and returns ctx
+// with the call's changes attributed to the caller.
+func requireGRPCToken(ctx context.Context) (context.Context, error) {
+	var got string
+	if vals := metadata.ValueFromIncomingContext(ctx, "authorization"); len(vals) > 0 {
+		got = vals[0]
+	}
+	got, ok := strings.CutPrefix(got, "Bearer ")
+	if !ok || apiToken == "" || subtle.ConstantTimeCompare([]byte(got), []byte(apiToken)) != 1 {
+		return nil, status.Error(codes.Unauthenticated, "a valid API token is required")
+	}
+
+	addr := "unknown"
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		addr = p.Addr.String()
+	}
+	return wallpapers.WithActor(ctx, tokenActor(addr)), nil
+}
+
+// checkKey rejects keys that are not a file at the top of a collection,
+// such as audit entries.
+func checkKey(key string) error {
+	if key == "" || strings.ContainsAny(key, `/\`) {
+		return status.Error(codes.InvalidArgument, "key must name a file, without path separators")
+	}
+	return nil
+}
+
+// newGRPCImage converts f like newV1Image does for /v1.
+func newGRPCImage(f *wallpapers.File) *wallpapersv1.Image {
+	img := &wallpapersv1.Image{
+		Key:          f.Name,
+		Type:         f.Type,
+		Etag:         f.Etag,
+		Cdn:          f.FullRezURL,
+		Thumbnail:    f.ThumbnailURL,
+		Bucket:       f.Bucket,
+		CreatedAt:    timestamppb.New(f.Created),
+		UpdatedAt:    timestamppb.New(f.Updated),
+		ColorProfile: f.ColorProfile,
+		VariantOf:    f.VariantOf,
+		AltText:      f.AltText,
+		SourceUrl:    f.SourceURL,
+		Author:       f.Author,
+		License:      f.License,
+	}
+	if f.Video != nil {
+		img.Video = &wallpapersv1.Video{Width: int32(f.Video.Width), Height: int32(f.Video.Height), Duration: f.Video.Duration}
+	}
+	return img
+}
+
+// sendImages streams the images of the collection named in opts, sorted
+// and filtered like /v1/images, keeping only those matching query if it is
+// set.
+func sendImages(ctx context.Context, opts *wallpapersv1.ListOptions, query string, send func(*wallpapersv1.Image) error) error {
+	ctx, err := grpcCollection(ctx, opts.GetCollection())
+	if err != nil {
+		return err
+	}
+
+	q := url.Values{}
+	q.Set("sort", opts.GetSort())
+	q.Set("order", opts.GetOrder())
+	q.Set("type", opts.GetType())
+	if opts.GetAllVariants() {
+		q.Set("variants", "all")
+	}
+	lo, err := listOptionsFrom(q)
+	if err != nil {
+		return grpcError(ctx, err, "invalid options")
+	}
+
+	files, err := listFiles(ctx)
+	if err != nil {
+		return grpcError(ctx, err, "retrieval error")
+	}
+	if files, err = lo.sorted(files); err != nil {
+		return grpcError(ctx, err, "retrieval error")
+	}
+	if query != "" {
+		files = searchFiles(ctx, files, query)
+	}
+
+	for _, f := range files {
+		if err := send(newGRPCImage(f)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (*grpcServer) List(req *wallpapersv1.ListRequest, stream grpc.ServerStreamingServer[wallpapersv1.Image]) error {
+	return sendImages(stream.Context(), req.GetOptions(), "", stream.Send)
+}
+
+func (*grpcServer) Search(req *wallpapersv1.SearchRequest, stream grpc.ServerStreamingServer[wallpapersv1.Image]) error {
+	if strings.TrimSpace(req.GetQuery()) == "" {
+		return status.Error(codes.InvalidArgument, "query is required")
+	}
+	return sendImages(stream.Context(), req.GetOptions(), req.GetQuery(), stream.Send)
+}
+
+func (*grpcServer) Get(ctx context.Context, req *wallpapersv1.GetRequest) (*wallpapersv1.Image, error) {
+	if err := checkKey(req.GetKey()); err != nil {
+		return nil, err
+	}
+	ctx, err := grpcCollection(ctx, req.GetCollection())
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := wallpapers.GetFile(ctx, req.GetKey())
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		return nil, status.Error(codes.NotFound, "not found")
+	}
+	if err != nil {
+		return nil, grpcError(ctx, err, "retrieval error")
+	}
+	return newGRPCImage(withImageURLs(f, req.GetCollection())), nil
+}
+
+func (*grpcServer) Upload(stream grpc.ClientStreamingServer[wallpapersv1.UploadRequest, wallpapersv1.Image]) error {
+	ctx, err := requireGRPCToken(stream.Context())
+	if err != nil {
+		return err
+	}
+
+	first, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	info := first.GetInfo()
+	if info.GetName() == "" {
+		return status.Error(codes.InvalidArgument, "the first message must be the upload's info and name it")
+	}
+	name, err := formatUploadName(info.GetName())
+	if err != nil {
+		return grpcError(ctx, err, "upload error")
+	}
+
+	// Uploads over the limit would be refused or quarantined anyway, so
+	// stop reading rather than hold them in memory.
+	limit := uploadLimit()
+	var buf bytes.Buffer
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if req.GetInfo() != nil {
+			return status.Error(codes.InvalidArgument, "only the first message may be the upload's info")
+		}
+		if int64(buf.Len()+len(req.GetChunk())) > limit {
+			return status.Error(codes.ResourceExhausted, fmt.Sprintf("uploads are limited to %d bytes", limit))
+		}
+		buf.Write(req.GetChunk())
+	}
+
+	var opts []wallpapers.UploadOption
+	if info.GetAddedAt() != nil {
+		opts = append(opts, wallpapers.WithCustomTime(info.GetAddedAt().AsTime()))
+	}
+	name, err = storeUpload(ctx, name, buf.Bytes(), opts...)
+	var dup *wallpapers.DuplicateError
+	if errors.As(err, &dup) {
+		return status.Error(codes.AlreadyExists, fmt.Sprintf("the same content is already stored as %q", dup.Existing))
+	}
+	var rejected *wallpapers.RejectedError
+	if errors.As(err, &rejected) {
+		return status.Error(codes.InvalidArgument, rejected.Error())
+	}
+	if err != nil {
+		return grpcError(ctx, err, "upload error")
+	}
+
+	f, err := wallpapers.GetFile(ctx, name)
+	if err != nil {
+		return grpcError(ctx, err, "retrieval error")
+	}
+	return stream.SendAndClose(newGRPCImage(withImageURLs(f, "")))
+}
+
+func (*grpcServer) Delete(ctx context.Context, req *wallpapersv1.DeleteRequest) (*wallpapersv1.DeleteResponse, error) {
+	ctx, err := requireGRPCToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkKey(req.GetKey()); err != nil {
+		return nil, err
+	}
+
+	err = wallpapers.DeleteFile(ctx, req.GetKey())
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		return nil, status.Error(codes.NotFound, "not found")
+	}
+	if err != nil {
+		return nil, grpcError(ctx, err, "delete error")
+	}
+	refreshAfterChange(ctx)
+
+	return &wallpapersv1.DeleteResponse{}, nil
+}
+
+// refreshAfterChange rereads the listing of the collection on ctx, so that
+// List and the HTTP API show an upload or delete straight away, and
+// /events subscribers hear of it without waiting for the listing to expire.
+func refreshAfterChange(ctx context.Context) {
+	if _, err := refreshListing(ctx, wallpapers.StoreFor(ctx)); err != nil {
+		ctxLog(ctx).Warnw("could not refresh listing", zap.Error(err))
+	}
+}
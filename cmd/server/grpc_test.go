@@ -0,0 +1,510 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"image"
+	"image/png"
+	"io"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/icco/wallpapers"
+	"github.com/icco/wallpapers/config"
+	wallpapersv1 "github.com/icco/wallpapers/proto/wallpapers/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// dialGRPC serves the gRPC service over an in memory connection, with s as
+// the default store, and returns a client for it.
+func dialGRPC(t *testing.T, s wallpapers.Store) wallpapersv1.WallpapersServiceClient {
+	t.Helper()
+	old := wallpapers.DefaultStore()
+	wallpapers.SetStore(s)
+	t.Cleanup(func() {
+		wallpapers.SetStore(old)
+		listingsMu.Lock()
+		delete(listings, s)
+		listingsMu.Unlock()
+	})
+
+	lis := bufconn.Listen(1 << 20)
+	srv := newGRPCServer()
+	// Serve returns once the server is stopped at the end of the test.
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return wallpapersv1.NewWallpapersServiceClient(conn)
+}
+
+// recvKeys reads a stream of images to the end and returns their keys.
+func recvKeys(stream grpc.ServerStreamingClient[wallpapersv1.Image], err error) ([]string, error) {
+	if err != nil {
+		return nil, err
+	}
+	var keys []string
+	for {
+		img, err := stream.Recv()
+		if err == io.EOF {
+			return keys, nil
+		}
+		if err != nil {
+			return keys, err
+		}
+		keys = append(keys, img.GetKey())
+	}
+}
+
+func TestGRPCRead(t *testing.T) {
+	s := wallpapers.NewMemoryStore()
+	shots := wallpapers.NewMemoryStore()
+	for _, name := range []string{"a.jpg", "b.jpg", "c.png"} {
+		putFile(t, s, name)
+	}
+	putFile(t, shots, "d.jpg")
+	collections["shots"] = shots
+	t.Cleanup(func() {
+		delete(collections, "shots")
+		listingsMu.Lock()
+		delete(listings, shots)
+		listingsMu.Unlock()
+	})
+	c := dialGRPC(t, s)
+
+	byName := &wallpapersv1.ListOptions{Sort: "name", Order: "asc"}
+	for _, tc := range []struct {
+		name     string
+		call     func(context.Context) ([]string, error)
+		want     []string
+		wantCode codes.Code
+	}{
+		{
+			name: "list",
+			call: func(ctx context.Context) ([]string, error) {
+				return recvKeys(c.List(ctx, &wallpapersv1.ListRequest{Options: byName}))
+			},
+			want: []string{"a.jpg", "b.jpg", "c.png"},
+		},
+		{
+			name: "list descending",
+			call: func(ctx context.Context) ([]string, error) {
+				return recvKeys(c.List(ctx, &wallpapersv1.ListRequest{Options: &wallpapersv1.ListOptions{Sort: "name", Order: "desc"}}))
+			},
+			want: []string{"c.png", "b.jpg", "a.jpg"},
+		},
+		{
+			name: "list collection",
+			call: func(ctx context.Context) ([]string, error) {
+				return recvKeys(c.List(ctx, &wallpapersv1.ListRequest{Options: &wallpapersv1.ListOptions{Collection: "shots"}}))
+			},
+			want: []string{"d.jpg"},
+		},
+		{
+			name: "list unknown collection",
+			call: func(ctx context.Context) ([]string, error) {
+				return recvKeys(c.List(ctx, &wallpapersv1.ListRequest{Options: &wallpapersv1.ListOptions{Collection: "nope"}}))
+			},
+			wantCode: codes.NotFound,
+		},
+		{
+			name: "list invalid sort",
+			call: func(ctx context.Context) ([]string, error) {
+				return recvKeys(c.List(ctx, &wallpapersv1.ListRequest{Options: &wallpapersv1.ListOptions{Sort: "color"}}))
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "search",
+			call: func(ctx context.Context) ([]string, error) {
+				return recvKeys(c.Search(ctx, &wallpapersv1.SearchRequest{Query: "b", Options: byName}))
+			},
+			want: []string{"b.jpg"},
+		},
+		{
+			name: "search without query",
+			call: func(ctx context.Context) ([]string, error) {
+				return recvKeys(c.Search(ctx, &wallpapersv1.SearchRequest{Query: " "}))
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "get",
+			call: func(ctx context.Context) ([]string, error) {
+				img, err := c.Get(ctx, &wallpapersv1.GetRequest{Key: "c.png"})
+				return []string{img.GetKey()}, err
+			},
+			want: []string{"c.png"},
+		},
+		{
+			name: "get from collection",
+			call: func(ctx context.Context) ([]string, error) {
+				img, err := c.Get(ctx, &wallpapersv1.GetRequest{Key: "d.jpg", Collection: "shots"})
+				return []string{img.GetKey()}, err
+			},
+			want: []string{"d.jpg"},
+		},
+		{
+			name: "get audit entry",
+			call: func(ctx context.Context) ([]string, error) {
+				_, err := c.Get(ctx, &wallpapersv1.GetRequest{Key: "audit/0001.json"})
+				return nil, err
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "get missing",
+			call: func(ctx context.Context) ([]string, error) {
+				_, err := c.Get(ctx, &wallpapersv1.GetRequest{Key: "z.jpg"})
+				return nil, err
+			},
+			wantCode: codes.NotFound,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tc.call(context.Background())
+			if code := status.Code(err); code != tc.wantCode {
+				t.Fatalf("code = %s, want %s: %v", code, tc.wantCode, err)
+			}
+			if tc.wantCode == codes.OK && !slices.Equal(got, tc.want) {
+				t.Errorf("keys = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestGRPCWrite(t *testing.T) {
+	old := apiToken
+	apiToken = "secret"
+	t.Cleanup(func() { apiToken = old })
+
+	img := image.NewGray(image.Rect(0, 0, wallpapers.DefaultValidation.MinWidth, wallpapers.DefaultValidation.MinHeight))
+	var valid bytes.Buffer
+	if err := png.Encode(&valid, img); err != nil {
+		t.Fatal(err)
+	}
+
+	authed := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer secret")
+	wrongToken := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer nope")
+	upload := func(c wallpapersv1.WallpapersServiceClient, ctx context.Context, reqs ...*wallpapersv1.UploadRequest) error {
+		stream, err := c.Upload(ctx)
+		if err != nil {
+			return err
+		}
+		for _, req := range reqs {
+			if err := stream.Send(req); err != nil {
+				break
+			}
+		}
+		_, err = stream.CloseAndRecv()
+		return err
+	}
+	info := func(name string) *wallpapersv1.UploadRequest {
+		return &wallpapersv1.UploadRequest{Data: &wallpapersv1.UploadRequest_Info{Info: &wallpapersv1.UploadInfo{Name: name}}}
+	}
+	chunks := func(b []byte) []*wallpapersv1.UploadRequest {
+		var reqs []*wallpapersv1.UploadRequest
+		for chunk := range slices.Chunk(b, 1<<10) {
+			reqs = append(reqs, &wallpapersv1.UploadRequest{Data: &wallpapersv1.UploadRequest_Chunk{Chunk: chunk}})
+		}
+		return reqs
+	}
+
+	hashed := wallpapers.HashedName("a.jpg", valid.Bytes())
+
+	for _, tc := range []struct {
+		name     string
+		call     func(wallpapersv1.WallpapersServiceClient) error
+		want     []string
+		wantCode codes.Code
+		// mirrored are the files copied to the mirror after the call.
+		mirrored []string
+		// wantActor, if set, is who the last audit entry names.
+		wantActor string
+	}{
+		{
+			name: "upload",
+			call: func(c wallpapersv1.WallpapersServiceClient) error {
+				return upload(c, authed, append([]*wallpapersv1.UploadRequest{info("new.png")}, chunks(valid.Bytes())...)...)
+			},
+			want:      []string{"a.jpg", "new.png"},
+			mirrored:  []string{"new.png"},
+			wantActor: "api-token@bufconn",
+		},
+		{
+			name: "upload formats the name",
+			call: func(c wallpapersv1.WallpapersServiceClient) error {
+				return upload(c, authed, append([]*wallpapersv1.UploadRequest{info("My New.PNG")}, chunks(valid.Bytes())...)...)
+			},
+			want:     []string{"a.jpg", "mynew.png"},
+			mirrored: []string{"mynew.png"},
+		},
+		{
+			name: "upload with a taken name is hashed",
+			call: func(c wallpapersv1.WallpapersServiceClient) error {
+				return upload(c, authed, append([]*wallpapersv1.UploadRequest{info("a.jpg")}, chunks(valid.Bytes())...)...)
+			},
+			want:     []string{"a.jpg", hashed},
+			mirrored: []string{hashed},
+		},
+		{
+			name: "upload of stored content",
+			call: func(c wallpapersv1.WallpapersServiceClient) error {
+				return upload(c, authed, append([]*wallpapersv1.UploadRequest{info("b.jpg")}, chunks([]byte("a.jpg"))...)...)
+			},
+			want:     []string{"a.jpg"},
+			wantCode: codes.AlreadyExists,
+		},
+		{
+			name: "upload with a path",
+			call: func(c wallpapersv1.WallpapersServiceClient) error {
+				return upload(c, authed, append([]*wallpapersv1.UploadRequest{info("../new.png")}, chunks(valid.Bytes())...)...)
+			},
+			want:     []string{"a.jpg"},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "upload without a usable name",
+			call: func(c wallpapersv1.WallpapersServiceClient) error {
+				return upload(c, authed, append([]*wallpapersv1.UploadRequest{info("?!.png")}, chunks(valid.Bytes())...)...)
+			},
+			want:     []string{"a.jpg"},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "upload without token",
+			call: func(c wallpapersv1.WallpapersServiceClient) error {
+				return upload(c, context.Background(), append([]*wallpapersv1.UploadRequest{info("new.png")}, chunks(valid.Bytes())...)...)
+			},
+			want:     []string{"a.jpg"},
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name: "upload with wrong token",
+			call: func(c wallpapersv1.WallpapersServiceClient) error {
+				return upload(c, wrongToken, info("new.png"))
+			},
+			want:     []string{"a.jpg"},
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name: "upload without info",
+			call: func(c wallpapersv1.WallpapersServiceClient) error {
+				return upload(c, authed, chunks(valid.Bytes())...)
+			},
+			want:     []string{"a.jpg"},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "upload with info twice",
+			call: func(c wallpapersv1.WallpapersServiceClient) error {
+				return upload(c, authed, info("new.png"), info("other.png"))
+			},
+			want:     []string{"a.jpg"},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "upload over the limit",
+			call: func(c wallpapersv1.WallpapersServiceClient) error {
+				old := maxUploadSize
+				maxUploadSize = 1 << 10
+				defer func() { maxUploadSize = old }()
+				return upload(c, authed, append([]*wallpapersv1.UploadRequest{info("new.png")}, chunks(valid.Bytes())...)...)
+			},
+			want:     []string{"a.jpg"},
+			wantCode: codes.ResourceExhausted,
+		},
+		{
+			name: "upload rejected",
+			call: func(c wallpapersv1.WallpapersServiceClient) error {
+				return upload(c, authed, append([]*wallpapersv1.UploadRequest{info("new.png")}, chunks([]byte("not a png"))...)...)
+			},
+			want:     []string{"a.jpg"},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "delete",
+			call: func(c wallpapersv1.WallpapersServiceClient) error {
+				_, err := c.Delete(authed, &wallpapersv1.DeleteRequest{Key: "a.jpg"})
+				return err
+			},
+			want:      nil,
+			wantActor: "api-token@bufconn",
+		},
+		{
+			name: "delete without token",
+			call: func(c wallpapersv1.WallpapersServiceClient) error {
+				_, err := c.Delete(context.Background(), &wallpapersv1.DeleteRequest{Key: "a.jpg"})
+				return err
+			},
+			want:     []string{"a.jpg"},
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name: "delete audit entry",
+			call: func(c wallpapersv1.WallpapersServiceClient) error {
+				_, err := c.Delete(authed, &wallpapersv1.DeleteRequest{Key: "audit/0001.json"})
+				return err
+			},
+			want:     []string{"a.jpg"},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "delete missing",
+			call: func(c wallpapersv1.WallpapersServiceClient) error {
+				_, err := c.Delete(authed, &wallpapersv1.DeleteRequest{Key: "z.jpg"})
+				return err
+			},
+			want:     []string{"a.jpg"},
+			wantCode: codes.NotFound,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s := wallpapers.NewMemoryStore()
+			putFile(t, s, "a.jpg")
+			c := dialGRPC(t, s)
+			mirror := wallpapers.NewMemoryStore()
+			mirrorTo = mirror
+			t.Cleanup(func() { mirrorTo = nil })
+
+			err := tc.call(c)
+			if code := status.Code(err); code != tc.wantCode {
+				t.Fatalf("code = %s, want %s: %v", code, tc.wantCode, err)
+			}
+
+			afterUploads.Wait()
+			var mirrored []string
+			for f, err := range mirror.List(context.Background()) {
+				if err != nil {
+					t.Fatal(err)
+				}
+				mirrored = append(mirrored, f.Name)
+			}
+			if !slices.Equal(mirrored, tc.mirrored) {
+				t.Errorf("mirrored = %q, want %q", mirrored, tc.mirrored)
+			}
+
+			if tc.wantActor != "" {
+				entries, err := wallpapers.AuditEntries(wallpapers.ContextWithStore(context.Background(), s), wallpapers.AuditQuery{Limit: 1})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(entries) != 1 || entries[0].Actor != tc.wantActor {
+					t.Errorf("last audit entry = %+v, want actor %q", entries, tc.wantActor)
+				}
+			}
+
+			// The listing is refreshed after a change, so List shows it
+			// straight away.
+			got, err := recvKeys(c.List(context.Background(), &wallpapersv1.ListRequest{Options: &wallpapersv1.ListOptions{Sort: "name", Order: "asc"}}))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(got, tc.want) {
+				t.Errorf("keys = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestGRPCTransport(t *testing.T) {
+	dir := t.TempDir()
+	cert, key := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	writeTestCert(t, cert, key)
+
+	for _, tc := range []struct {
+		name     string
+		cfg      config.Server
+		wantAddr string
+		wantTLS  bool
+		wantErr  bool
+	}{
+		{
+			name:     "without tls only listens on localhost",
+			cfg:      config.Server{GRPCPort: "9000"},
+			wantAddr: "localhost:9000",
+		},
+		{
+			name:     "with tls listens everywhere",
+			cfg:      config.Server{GRPCPort: "9000", GRPCTLSCert: cert, GRPCTLSKey: key},
+			wantAddr: ":9000",
+			wantTLS:  true,
+		},
+		{
+			name:    "missing certificate",
+			cfg:     config.Server{GRPCPort: "9000", GRPCTLSCert: filepath.Join(dir, "nope.pem"), GRPCTLSKey: key},
+			wantErr: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			addr, opts, err := grpcTransport(tc.cfg)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("grpcTransport error = %v, want error %v", err, tc.wantErr)
+			}
+			if addr != tc.wantAddr {
+				t.Errorf("addr = %q, want %q", addr, tc.wantAddr)
+			}
+			if got := len(opts) > 0; got != tc.wantTLS {
+				t.Errorf("tls = %v, want %v", got, tc.wantTLS)
+			}
+		})
+	}
+}
+
+// writeTestCert writes a self-signed certificate for localhost and its key
+// as PEM files.
+func writeTestCert(t *testing.T, certFile, keyFile string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for file, block := range map[string]*pem.Block{
+		certFile: {Type: "CERTIFICATE", Bytes: der},
+		keyFile:  {Type: "EC PRIVATE KEY", Bytes: keyDER},
+	} {
+		if err := os.WriteFile(file, pem.EncodeToMemory(block), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
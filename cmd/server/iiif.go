@@ -0,0 +1,277 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/storage"
+	chi "github.com/go-chi/chi/v5"
+	"github.com/icco/wallpapers"
+	"go.uber.org/zap"
+)
+
+const (
+	iiifContext = "http://iiif.io/api/image/3/context.json"
+
+	// iiifTileSize is the tile size advertised to deep-zoom viewers.
+	iiifTileSize = 512
+
+	// maxIIIFArea limits renditions made in memory when imgix is not
+	// available. imgix renditions share the limit of /fit.
+	maxIIIFArea      = maxEinkDimension * maxEinkDimension
+	maxIIIFImgixArea = maxFitDimension * maxFitDimension
+
+	// iiifTimeout bounds downloading and rendering an original that has no
+	// cached rendition yet.
+	iiifTimeout = 30 * time.Second
+)
+
+// iiifSizes caches the dimensions of originals by etag, since reading them
+// means opening the file.
+var iiifSizes sync.Map
+
+type iiifSize struct {
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+type iiifTiles struct {
+	Width        int   `json:"width"`
+	ScaleFactors []int `json:"scaleFactors"`
+}
+
+type iiifInfo struct {
+	Context        string      `json:"@context"`
+	ID             string      `json:"id"`
+	Type           string      `json:"type"`
+	Protocol       string      `json:"protocol"`
+	Profile        string      `json:"profile"`
+	Width          int         `json:"width"`
+	Height         int         `json:"height"`
+	MaxArea        int         `json:"maxArea"`
+	Tiles          []iiifTiles `json:"tiles"`
+	ExtraQualities []string    `json:"extraQualities"`
+	ExtraFormats   []string    `json:"extraFormats"`
+	ExtraFeatures  []string    `json:"extraFeatures"`
+}
+
+// iiifFile returns an image and its dimensions, rendering an error if it
+// cannot.
+func iiifFile(w http.ResponseWriter, r *http.Request) (*wallpapers.File, *iiifSize, bool) {
+	ctx := r.Context()
+	name := chi.URLParam(r, "name")
+	file, err := wallpapers.GetFile(ctx, name)
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		renderError(w, r, http.StatusNotFound, "not_found", "not found")
+		return nil, nil, false
+	}
+	if err != nil {
+		reqLog(r).Errorw("error during iiif get file", "name", name, zap.Error(err))
+		renderError(w, r, http.StatusInternalServerError, "internal", "retrieval error")
+		return nil, nil, false
+	}
+	if file.Type != wallpapers.TypeImage {
+		renderError(w, r, http.StatusNotFound, "not_found", "only images are available through IIIF")
+		return nil, nil, false
+	}
+
+	if s, ok := iiifSizes.Load(file.Etag); ok {
+		return file, s.(*iiifSize), true
+	}
+
+	width, height, err := wallpapers.ImageSize(ctx, file.Name)
+	if err != nil {
+		reqLog(r).Errorw("error during iiif image size", "name", name, zap.Error(err))
+		renderError(w, r, http.StatusInternalServerError, "internal", "retrieval error")
+		return nil, nil, false
+	}
+
+	s := &iiifSize{Width: width, Height: height}
+	iiifSizes.Store(file.Etag, s)
+	return file, s, true
+}
+
+// iiifMaxArea returns the largest rendition the store in r's context can
+// make.
+func iiifMaxArea(r *http.Request) (int, bool) {
+	if wallpapers.UsesImgix(r.Context()) {
+		return maxIIIFImgixArea, true
+	}
+	return maxIIIFArea, false
+}
+
+// iiifScales returns the scale factors advertised for tiles of an image of
+// size: powers of two, down to where the image is about one tile.
+func iiifScales(size *iiifSize) []int {
+	var scales []int
+	for s := 1; s == 1 || (size.Width/s >= iiifTileSize/2 && size.Height/s >= iiifTileSize/2); s *= 2 {
+		scales = append(scales, s)
+	}
+	return scales
+}
+
+// iiifIsTile reports whether req is one of the tiles info.json advertises
+// for an image of size: a cell of the tile grid at one of the scale
+// factors, scaled down by that factor. Only tiles are cached, since the
+// regions and sizes a client can ask for are endless.
+func iiifIsTile(size *iiifSize, req *wallpapers.IIIFRequest) bool {
+	reg := req.Region
+	for _, s := range iiifScales(size) {
+		step := iiifTileSize * s
+		if reg.Min.X%step != 0 || reg.Min.Y%step != 0 {
+			continue
+		}
+		if reg.Dx() != min(step, size.Width-reg.Min.X) || reg.Dy() != min(step, size.Height-reg.Min.Y) {
+			continue
+		}
+
+		// Viewers ask for the width and let the height follow, which
+		// may round either way.
+		w, h := (reg.Dx()+s-1)/s, (reg.Dy()+s-1)/s
+		if req.Width == w && req.Height >= h-1 && req.Height <= h {
+			return true
+		}
+	}
+	return false
+}
+
+// iiifBaseHandler redirects the base URI of an image to its info.json, as
+// the IIIF Image API asks.
+func iiifBaseHandler(w http.ResponseWriter, r *http.Request) {
+	http.Redirect(w, r, r.URL.Path+"/info.json", http.StatusSeeOther)
+}
+
+// iiifInfoHandler describes an image for IIIF viewers such as OpenSeadragon.
+func iiifInfoHandler(w http.ResponseWriter, r *http.Request) {
+	file, size, ok := iiifFile(w, r)
+	if !ok {
+		return
+	}
+
+	maxArea, imgix := iiifMaxArea(r)
+	formats := []string{"png", "gif"}
+	if imgix {
+		formats = append(formats, "webp")
+	}
+
+	info := iiifInfo{
+		Context:        iiifContext,
+		ID:             siteURL + "/iiif/" + url.PathEscape(file.Name),
+		Type:           "ImageService3",
+		Protocol:       "http://iiif.io/api/image",
+		Profile:        "level2",
+		Width:          size.Width,
+		Height:         size.Height,
+		MaxArea:        maxArea,
+		Tiles:          []iiifTiles{{Width: iiifTileSize, ScaleFactors: iiifScales(size)}},
+		ExtraQualities: []string{wallpapers.IIIFColor, wallpapers.IIIFGray, wallpapers.IIIFBitonal},
+		ExtraFormats:   formats,
+		ExtraFeatures:  []string{"mirroring", "rotationBy90s", "sizeUpscaling"},
+	}
+
+	buf, err := json.Marshal(info)
+	if err != nil {
+		reqLog(r).Errorw("error during iiif info marshal", zap.Error(err))
+		renderError(w, r, http.StatusInternalServerError, "internal", "render error")
+		return
+	}
+
+	w.Header().Set("Content-Type", `application/ld+json;profile="`+iiifContext+`"`)
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Cache-Control", "public, max-age=86400")
+	if _, err := w.Write(buf); err != nil {
+		reqLog(r).Errorw("error writing iiif info", zap.Error(err))
+	}
+}
+
+// iiifImageHandler serves a region of an image. Collections behind imgix are
+// redirected to an imgix rendition. Otherwise the image is rendered here, and
+// tiles are cached in the store's variants, like /eink.
+func iiifImageHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	quality, format, ok := strings.Cut(chi.URLParam(r, "file"), ".")
+	if !ok {
+		renderError(w, r, http.StatusBadRequest, "bad_request", "missing format")
+		return
+	}
+
+	file, size, ok := iiifFile(w, r)
+	if !ok {
+		return
+	}
+
+	maxArea, _ := iiifMaxArea(r)
+	req, err := wallpapers.ParseIIIF(size.Width, size.Height,
+		chi.URLParam(r, "region"), chi.URLParam(r, "size"), chi.URLParam(r, "rotation"), quality, format, maxArea)
+	if err != nil {
+		renderError(w, r, http.StatusBadRequest, "bad_request", err.Error())
+		return
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	if u, ok := wallpapers.IIIFURL(ctx, file.Name, req); ok {
+		http.Redirect(w, r, u, http.StatusFound)
+		return
+	}
+
+	tile := iiifIsTile(size, req)
+	variant := wallpapers.IIIFName(file, req)
+	var content []byte
+	err = storage.ErrObjectNotExist
+	if tile {
+		content, err = wallpapers.LoadVariant(ctx, variant)
+	}
+	if err != nil {
+		if !errors.Is(err, storage.ErrObjectNotExist) {
+			reqLog(r).Warnw("could not read cached iiif version", "name", variant, zap.Error(err))
+		}
+
+		// The server's write timeout is too short to download and render
+		// a large original.
+		if err := http.NewResponseController(w).SetWriteDeadline(time.Now().Add(iiifTimeout)); err != nil {
+			reqLog(r).Warnw("could not extend iiif write deadline", zap.Error(err))
+		}
+
+		original, err := wallpapers.DownloadFile(ctx, file.Name)
+		if err != nil {
+			reqLog(r).Errorw("error during iiif download", "name", file.Name, zap.Error(err))
+			renderError(w, r, http.StatusInternalServerError, "internal", "retrieval error")
+			return
+		}
+
+		content, err = wallpapers.RenderIIIF(original, req)
+		if errors.Is(err, wallpapers.ErrIIIF) {
+			renderError(w, r, http.StatusBadRequest, "bad_request", err.Error())
+			return
+		}
+		if err != nil {
+			reqLog(r).Errorw("error during iiif render", "name", file.Name, zap.Error(err))
+			renderError(w, r, http.StatusInternalServerError, "internal", "render error")
+			return
+		}
+
+		if tile {
+			if err := wallpapers.SaveVariant(ctx, variant, content); err != nil {
+				reqLog(r).Warnw("could not cache iiif version", "name", variant, zap.Error(err))
+			}
+		}
+	}
+
+	w.Header().Set("Content-Type", iiifContentTypes[req.Format])
+	w.Header().Set("Cache-Control", "public, max-age=86400")
+	if _, err := w.Write(content); err != nil {
+		reqLog(r).Errorw("error writing iiif", zap.Error(err))
+	}
+}
+
+var iiifContentTypes = map[string]string{
+	"jpg":  "image/jpeg",
+	"png":  "image/png",
+	"gif":  "image/gif",
+	"webp": "image/webp",
+}
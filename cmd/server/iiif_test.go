@@ -0,0 +1,40 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/icco/wallpapers"
+)
+
+func TestIIIFIsTile(t *testing.T) {
+	size := &iiifSize{Width: 3000, Height: 2000}
+	for _, tc := range []struct {
+		path string
+		want bool
+	}{
+		{path: "0,0,512,512/512,", want: true},
+		{path: "512,1024,512,512/512,", want: true},
+		{path: "0,0,1024,1024/512,", want: true},
+		{path: "2048,0,952,1024/476,", want: true},
+		{path: "2560,1536,440,464/440,", want: true},
+		{path: "0,0,512,512/512,512", want: true},
+		{path: "1,0,512,512/512,", want: false},
+		{path: "0,0,512,512/256,", want: false},
+		{path: "0,0,600,512/600,", want: false},
+		{path: "2560,1536,440,400/440,", want: false},
+		{path: "full/max", want: false},
+		{path: "square/300,", want: false},
+	} {
+		t.Run(tc.path, func(t *testing.T) {
+			region, sz, _ := strings.Cut(tc.path, "/")
+			req, err := wallpapers.ParseIIIF(size.Width, size.Height, region, sz, "0", "default", "jpg", maxIIIFArea)
+			if err != nil {
+				t.Fatalf("ParseIIIF(%q) error: %v", tc.path, err)
+			}
+			if got := iiifIsTile(size, req); got != tc.want {
+				t.Errorf("iiifIsTile(%q) = %v, want %v", tc.path, got, tc.want)
+			}
+		})
+	}
+}
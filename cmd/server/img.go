@@ -0,0 +1,170 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"net/http"
+	"net/url"
+	"time"
+
+	"cloud.google.com/go/storage"
+	chi "github.com/go-chi/chi/v5"
+	"github.com/icco/wallpapers"
+	"go.uber.org/zap"
+)
+
+const (
+	// immutableCache lets browsers and CDNs keep /img responses forever,
+	// since their URLs change whenever the content does.
+	immutableCache = "public, max-age=31536000, immutable"
+
+	imgThumbWidth  = 800
+	imgThumbHeight = 450
+)
+
+// serveImages makes file URLs point at /img on this server instead of imgix
+// and storage.googleapis.com.
+var serveImages bool
+
+// contentHash is the part of /img URLs that changes with a file's content.
+func contentHash(f *wallpapers.File) string {
+	return fmt.Sprintf("%08x", f.CRC32C)
+}
+
+// imgPath is the /img path of f, or of its thumbnail. Files of collections
+// other than the default carry the collection parameter.
+func imgPath(f *wallpapers.File, thumb bool, collection string) string {
+	p := "/img/" + contentHash(f) + "/"
+	if thumb {
+		p += "thumb/"
+	}
+	p += url.PathEscape(f.Name)
+	if collection != "" {
+		p += "?collection=" + url.QueryEscape(collection)
+	}
+	return p
+}
+
+// withImageURLs returns f, or a copy pointing at /img if serveImages is set.
+func withImageURLs(f *wallpapers.File, collection string) *wallpapers.File {
+	if !serveImages {
+		return f
+	}
+
+	c := *f
+	c.FullRezURL = siteURL + imgPath(f, false, collection)
+	c.ThumbnailURL = c.FullRezURL
+	if f.Type == wallpapers.TypeImage {
+		c.ThumbnailURL = siteURL + imgPath(f, true, collection)
+	}
+	return &c
+}
+
+// imgFile returns the file named in the request, redirecting to its
+// current URL if the hash in the path is stale.
+func imgFile(w http.ResponseWriter, r *http.Request, thumb bool) (*wallpapers.File, bool) {
+	name := chi.URLParam(r, "name")
+	f, err := wallpapers.GetFile(r.Context(), name)
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		renderError(w, r, http.StatusNotFound, "not_found", "not found")
+		return nil, false
+	}
+	if err != nil {
+		reqLog(r).Errorw("error during img get file", "name", name, zap.Error(err))
+		renderError(w, r, http.StatusInternalServerError, "internal", "retrieval error")
+		return nil, false
+	}
+
+	if chi.URLParam(r, "hash") != contentHash(f) {
+		w.Header().Set("Cache-Control", "no-cache")
+		http.Redirect(w, r, imgPath(f, thumb, r.URL.Query().Get("collection")), http.StatusFound)
+		return nil, false
+	}
+
+	return f, true
+}
+
+// imgHandler serves an original under a URL that includes its content
+// hash, so it can be cached as immutable.
+func imgHandler(w http.ResponseWriter, r *http.Request) {
+	f, ok := imgFile(w, r, false)
+	if !ok {
+		return
+	}
+
+	serveObject(w, r, f, "inline", immutableCache)
+}
+
+// imgThumbHandler serves an 800x450 crop of an image, rendered here and
+// cached in the store's variants like the IIIF renditions.
+func imgThumbHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	f, ok := imgFile(w, r, true)
+	if !ok {
+		return
+	}
+	if f.Type != wallpapers.TypeImage {
+		renderError(w, r, http.StatusNotFound, "not_found", "only images have thumbnails")
+		return
+	}
+
+	_, size, ok := iiifFile(w, r)
+	if !ok {
+		return
+	}
+
+	// Crop the center to the thumbnail's aspect ratio, then scale it down.
+	cw, ch := size.Width, size.Height
+	if cw*imgThumbHeight > ch*imgThumbWidth {
+		cw = ch * imgThumbWidth / imgThumbHeight
+	} else {
+		ch = cw * imgThumbHeight / imgThumbWidth
+	}
+	x, y := (size.Width-cw)/2, (size.Height-ch)/2
+	req := &wallpapers.IIIFRequest{
+		Region:  image.Rect(x, y, x+cw, y+ch),
+		Width:   min(imgThumbWidth, cw),
+		Height:  min(imgThumbHeight, ch),
+		Quality: wallpapers.IIIFDefault,
+		Format:  "jpg",
+	}
+
+	variant := wallpapers.IIIFName(f, req)
+	content, err := wallpapers.LoadVariant(ctx, variant)
+	if err != nil {
+		if !errors.Is(err, storage.ErrObjectNotExist) {
+			reqLog(r).Warnw("could not read cached thumbnail", "name", variant, zap.Error(err))
+		}
+
+		// The server's write timeout is too short to download and render
+		// a large original.
+		if err := http.NewResponseController(w).SetWriteDeadline(time.Now().Add(iiifTimeout)); err != nil {
+			reqLog(r).Warnw("could not extend thumbnail write deadline", zap.Error(err))
+		}
+
+		original, err := wallpapers.DownloadFile(ctx, f.Name)
+		if err != nil {
+			reqLog(r).Errorw("error during thumbnail download", "name", f.Name, zap.Error(err))
+			renderError(w, r, http.StatusInternalServerError, "internal", "retrieval error")
+			return
+		}
+
+		content, err = wallpapers.RenderIIIF(original, req)
+		if err != nil {
+			reqLog(r).Errorw("error during thumbnail render", "name", f.Name, zap.Error(err))
+			renderError(w, r, http.StatusInternalServerError, "internal", "render error")
+			return
+		}
+
+		if err := wallpapers.SaveVariant(ctx, variant, content); err != nil {
+			reqLog(r).Warnw("could not cache thumbnail", "name", variant, zap.Error(err))
+		}
+	}
+
+	w.Header().Set("Content-Type", "image/jpeg")
+	w.Header().Set("Cache-Control", immutableCache)
+	if _, err := w.Write(content); err != nil {
+		reqLog(r).Errorw("error writing thumbnail", zap.Error(err))
+	}
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// job is a function run in the background on a cron schedule.
+type job struct {
+	name string
+	spec string
+	run  func(context.Context) error
+
+	mu       sync.Mutex
+	schedule *schedule
+	running  bool
+	runs     int
+	lastRun  time.Time
+	lastTook time.Duration
+	lastErr  error
+	next     time.Time
+}
+
+// jobs are the background jobs run by the server, with their default
+// schedules.
+var jobs = []*job{
+	{name: "cache-refresh", spec: "*/2 * * * *", run: refreshListings},
+	{name: "readiness", spec: "*/5 * * * *", run: checkReadiness},
+	{name: "usage-flush", spec: "* * * * *", run: flushUsage},
+	{name: "upload-cleanup", spec: "17 * * * *", run: cleanupUploads},
+}
+
+// checkReadiness runs the /readyz checks so failures are logged even when
+// nothing is polling the endpoint.
+func checkReadiness(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, readyTimeout)
+	defer cancel()
+
+	for name, fn := range readyChecks {
+		if err := fn(ctx); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// startJobs parses every job's schedule, applying the overrides in
+// schedules, and starts running them until ctx is done. A schedule of "off"
+// disables a job.
+func startJobs(ctx context.Context, schedules map[string]string) error {
+	for _, j := range jobs {
+		if v := schedules[j.name]; v != "" {
+			j.spec = v
+		}
+		if j.spec == "off" {
+			continue
+		}
+
+		s, err := parseSchedule(j.spec)
+		if err != nil {
+			return fmt.Errorf("job %s: %w", j.name, err)
+		}
+		j.schedule = s
+
+		go j.loop(ctx)
+	}
+
+	return nil
+}
+
+// loop runs j at every time matching its schedule. A run that is still going
+// when the next one is due makes that one be skipped.
+func (j *job) loop(ctx context.Context) {
+	for {
+		next := j.schedule.next(time.Now())
+		if next.IsZero() {
+			log.Warnw("job will never run again", "job", j.name, "spec", j.spec)
+			return
+		}
+
+		j.mu.Lock()
+		j.next = next
+		j.mu.Unlock()
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		go j.runOnce(ctx)
+	}
+}
+
+func (j *job) runOnce(ctx context.Context) {
+	j.mu.Lock()
+	if j.running {
+		j.mu.Unlock()
+		log.Warnw("skipping job that is still running", "job", j.name)
+		return
+	}
+	j.running = true
+	j.mu.Unlock()
+
+	ctx = context.WithValue(ctx, logKey{}, log.With("job", j.name))
+	start := time.Now()
+	err := j.run(ctx)
+	took := time.Since(start)
+	if err != nil {
+		ctxLog(ctx).Errorw("error during job", zap.Error(err))
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.running = false
+	j.runs++
+	j.lastRun = start
+	j.lastTook = took
+	j.lastErr = err
+}
+
+// jobStatus is the state of one job as reported by /jobs.
+type jobStatus struct {
+	Name     string     `json:"name"`
+	Schedule string     `json:"schedule"`
+	Enabled  bool       `json:"enabled"`
+	Running  bool       `json:"running"`
+	Runs     int        `json:"runs"`
+	LastRun  *time.Time `json:"last_run,omitempty"`
+	Duration string     `json:"duration,omitempty"`
+	Result   string     `json:"result,omitempty"`
+	Error    string     `json:"error,omitempty"`
+	NextRun  *time.Time `json:"next_run,omitempty"`
+}
+
+func (j *job) status() jobStatus {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	s := jobStatus{
+		Name:     j.name,
+		Schedule: j.spec,
+		Enabled:  j.schedule != nil,
+		Running:  j.running,
+		Runs:     j.runs,
+	}
+	if j.runs > 0 {
+		last := j.lastRun
+		s.LastRun = &last
+		s.Duration = j.lastTook.String()
+		s.Result = "ok"
+		if j.lastErr != nil {
+			s.Result = "error"
+			s.Error = j.lastErr.Error()
+		}
+	}
+	if !j.next.IsZero() {
+		next := j.next
+		s.NextRun = &next
+	}
+
+	return s
+}
+
+// jobsHandler reports when each background job last ran and how it went.
+func jobsHandler(w http.ResponseWriter, r *http.Request) {
+	resp := make([]jobStatus, 0, len(jobs))
+	for _, j := range jobs {
+		resp = append(resp, j.status())
+	}
+	sort.Slice(resp, func(a, b int) bool { return resp[a].Name < resp[b].Name })
+
+	w.Header().Set("Cache-Control", "no-store")
+	if err := Renderer.JSON(w, http.StatusOK, resp); err != nil {
+		reqLog(r).Errorw("error during jobs render", zap.Error(err))
+	}
+}
@@ -0,0 +1,246 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/icco/wallpapers"
+	"go.uber.org/zap"
+)
+
+// fileFields are the JSON fields of a wallpapers.File, and of a v1Image,
+// that can be selected.
+var fileFields = []string{"key", "type", "etag", "cdn", "thumbnail", "created_at", "updated_at", "video", "color_profile", "variant_of", "alt_text", "source_url", "author", "license"}
+
+// sortKeys are the sort values listings accept: the keys of
+// wallpapers.SortFiles, and popular, which orders by downloads and views.
+var sortKeys = append(slices.Clone(wallpapers.SortKeys), "popular")
+
+// listOptions are the sort, order, type, variants and fields query
+// parameters accepted by the listing endpoints.
+type listOptions struct {
+	sort   string
+	desc   bool
+	typ    string
+	fields []string
+
+	// allVariants keeps lower resolution copies, which are hidden unless
+	// variants=all.
+	allVariants bool
+}
+
+func parseListOptions(r *http.Request) (*listOptions, error) {
+	return listOptionsFrom(r.URL.Query())
+}
+
+// listOptionsFrom reads listOptions from query parameters, or from GraphQL
+// arguments of the same names.
+func listOptionsFrom(q url.Values) (*listOptions, error) {
+	o := &listOptions{sort: "added", desc: true}
+
+	if v := q.Get("sort"); v != "" {
+		if !slices.Contains(sortKeys, v) {
+			return nil, invalidParam("sort", "sort must be one of %s", strings.Join(sortKeys, ", "))
+		}
+		o.sort = v
+		o.desc = v != "name"
+	}
+
+	switch q.Get("order") {
+	case "":
+	case "asc":
+		o.desc = false
+	case "desc":
+		o.desc = true
+	default:
+		return nil, invalidParam("order", "order must be asc or desc")
+	}
+
+	if v := q.Get("type"); v != "" {
+		if !slices.Contains(wallpapers.MediaTypes, v) {
+			return nil, invalidParam("type", "type must be one of %s", strings.Join(wallpapers.MediaTypes, ", "))
+		}
+		o.typ = v
+	}
+
+	switch q.Get("variants") {
+	case "":
+	case "all":
+		o.allVariants = true
+	default:
+		return nil, invalidParam("variants", "variants must be all")
+	}
+
+	if v := q.Get("fields"); v != "" {
+		for _, f := range strings.Split(v, ",") {
+			f = strings.TrimSpace(f)
+			if !slices.Contains(fileFields, f) {
+				return nil, invalidParam("fields", "unknown field %q, must be one of %s", f, strings.Join(fileFields, ", "))
+			}
+			o.fields = append(o.fields, f)
+		}
+	}
+
+	return o, nil
+}
+
+// matchQuery reports whether every word of query appears in the file's
+// name or author.
+func matchQuery(f *wallpapers.File, query string) bool {
+	text := strings.ToLower(f.Name + " " + f.Author)
+	for _, term := range strings.Fields(strings.ToLower(query)) {
+		// Wallhaven style tag searches (+tag, -tag, @user, id:) are not
+		// supported, so only plain words filter.
+		term = strings.TrimPrefix(term, "+")
+		if strings.HasPrefix(term, "-") || strings.HasPrefix(term, "@") || strings.Contains(term, ":") {
+			continue
+		}
+		if !strings.Contains(text, term) {
+			return false
+		}
+	}
+	return true
+}
+
+// sorted returns a sorted copy of files, keeping only the requested type
+// and, unless all variants are asked for, the highest resolution copy of
+// each artwork.
+func (o *listOptions) sorted(files []*wallpapers.File) ([]*wallpapers.File, error) {
+	files = slices.Clone(files)
+	files = slices.DeleteFunc(files, func(f *wallpapers.File) bool {
+		return (o.typ != "" && f.Type != o.typ) || (!o.allVariants && f.VariantOf != "")
+	})
+	if o.sort == "popular" {
+		sortPopular(files, o.desc)
+		return files, nil
+	}
+	if err := wallpapers.SortFiles(files, o.sort, o.desc); err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
+
+// cacheable reports whether the order of a listing only changes when the
+// listing does, so that responses can be revalidated against it. Popular
+// listings change as wallpapers are viewed and downloaded.
+func (o *listOptions) cacheable() bool {
+	return o.sort != "popular"
+}
+
+// project returns images as is, or reduced to the selected fields.
+func (o *listOptions) project(images []*v1Image) (any, error) {
+	if len(o.fields) == 0 {
+		return images, nil
+	}
+
+	ret := make([]any, 0, len(images))
+	for _, img := range images {
+		v, err := o.projectFile(img)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, v)
+	}
+
+	return ret, nil
+}
+
+// projectFile returns f, a file or image, as is, or reduced to the selected
+// fields.
+func (o *listOptions) projectFile(f any) (any, error) {
+	if len(o.fields) == 0 {
+		return f, nil
+	}
+
+	buf, err := json.Marshal(f)
+	if err != nil {
+		return nil, err
+	}
+
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(buf, &all); err != nil {
+		return nil, err
+	}
+
+	picked := make(map[string]json.RawMessage, len(o.fields))
+	for _, field := range o.fields {
+		if v, ok := all[field]; ok {
+			picked[field] = v
+		}
+	}
+
+	return picked, nil
+}
+
+// streamFiles writes files as a JSON array one element at a time, so the
+// whole body is never held in memory. Once the first byte is written the
+// status cannot change, so a failure part way is logged and the response
+// cut short.
+func streamFiles(w http.ResponseWriter, r *http.Request, files []*wallpapers.File, opts *listOptions) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.WriteHeader(http.StatusOK)
+
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	if _, err := bw.WriteString("["); err != nil {
+		reqLog(r).Errorw("error during stream write", zap.Error(err))
+		return
+	}
+	for i, f := range files {
+		if i > 0 {
+			if err := bw.WriteByte(','); err != nil {
+				reqLog(r).Errorw("error during stream write", zap.Error(err))
+				return
+			}
+		}
+
+		v, err := opts.projectFile(f)
+		if err != nil {
+			reqLog(r).Errorw("error during stream projection", "name", f.Name, zap.Error(err))
+			return
+		}
+		if err := enc.Encode(v); err != nil {
+			reqLog(r).Errorw("error during stream encode", "name", f.Name, zap.Error(err))
+			return
+		}
+	}
+	if _, err := bw.WriteString("]\n"); err != nil {
+		reqLog(r).Errorw("error during stream write", zap.Error(err))
+		return
+	}
+	if err := bw.Flush(); err != nil {
+		reqLog(r).Errorw("error during stream flush", zap.Error(err))
+	}
+}
+
+func allHandler(w http.ResponseWriter, r *http.Request) {
+	opts, err := parseListOptions(r)
+	if err != nil {
+		renderBadRequest(w, r, "bad_request", err)
+		return
+	}
+
+	images, err := listFiles(r.Context())
+	if err != nil {
+		reqLog(r).Errorw("error during get all", zap.Error(err))
+		renderError(w, r, http.StatusInternalServerError, "internal", "retrieval error")
+		return
+	}
+
+	if opts.cacheable() && notModified(w, r, images) {
+		return
+	}
+
+	images, err = opts.sorted(images)
+	if err != nil {
+		renderBadRequest(w, r, "bad_request", err)
+		return
+	}
+
+	streamFiles(w, r, images, opts)
+}
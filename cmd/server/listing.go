@@ -0,0 +1,283 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"errors"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/icco/wallpapers"
+	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	// listingTTL is how long a cached listing is served before the next
+	// request reads the bucket again. The cache-refresh job normally
+	// refreshes listings before they expire.
+	listingTTL = 5 * time.Minute
+	// listingCheckInterval is how often a replica asks Redis whether the
+	// shared listing has changed since it last read it.
+	listingCheckInterval = 10 * time.Second
+	// listingRefreshTimeout bounds reading the bucket for a listing.
+	listingRefreshTimeout = time.Minute
+)
+
+type listing struct {
+	files   []*wallpapers.File
+	fetched time.Time
+	// version names the shared copy files came from, and checked is when
+	// it was last compared with Redis.
+	version string
+	checked time.Time
+}
+
+// sharedListing is a listing as stored in Redis. Its version is also
+// stored under a key of its own, so replicas can tell whether their copy
+// is current without reading the whole listing.
+type sharedListing struct {
+	Version string
+	Fetched time.Time
+	Files   []*wallpapers.File
+}
+
+var (
+	listingsMu sync.Mutex
+	listings   = map[wallpapers.Store]*listing{}
+
+	// refreshes lets concurrent requests for a listing that has to be read
+	// from the bucket share one read.
+	refreshes singleflight.Group
+
+	// sharedCache, if set, is where replicas share listings. Each
+	// replica keeps its own copy in listings, and falls back to it when
+	// Redis cannot be reached.
+	sharedCache *redisCache
+)
+
+// listingKey returns the shared cache key of s's listing.
+func listingKey(s wallpapers.Store) string {
+	for _, name := range slices.Sorted(maps.Keys(collections)) {
+		if collections[name] == s {
+			return "wallpapers:listing:" + name
+		}
+	}
+	return "wallpapers:listing"
+}
+
+// listingVersionKey returns the shared cache key of the version of s's
+// listing.
+func listingVersionKey(s wallpapers.Store) string {
+	return listingKey(s) + ":version"
+}
+
+// listFiles returns every file in the request's collection, most recently
+// added first, from the cache if it is fresh enough. The returned slice may
+// be modified by the caller, but the files must not be.
+func listFiles(ctx context.Context) ([]*wallpapers.File, error) {
+	s := wallpapers.StoreFor(ctx)
+
+	listingsMu.Lock()
+	l := listings[s]
+	listingsMu.Unlock()
+
+	fresh := l != nil && time.Since(l.fetched) < listingTTL
+	if sharedCache == nil && fresh {
+		return slices.Clone(l.files), nil
+	}
+
+	if sharedCache != nil {
+		if fresh && time.Since(l.checked) < listingCheckInterval {
+			return slices.Clone(l.files), nil
+		}
+
+		files, err := readSharedListing(ctx, s, l)
+		if err == nil {
+			return slices.Clone(files), nil
+		}
+		if !errors.Is(err, errCacheMiss) {
+			ctxLog(ctx).Warnw("could not read shared listing", zap.Error(err))
+			// Keep serving this replica's copy rather than sending every
+			// request to the bucket while Redis is unreachable.
+			if fresh {
+				markChecked(s, l)
+				return slices.Clone(l.files), nil
+			}
+		}
+	}
+
+	v, err, _ := refreshes.Do(listingKey(s), func() (any, error) {
+		// Every request waiting on the read shares its result, so it must
+		// not be cancelled when the request that started it goes away.
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), listingRefreshTimeout)
+		defer cancel()
+		return refreshListing(ctx, s)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return slices.Clone(v.([]*wallpapers.File)), nil
+}
+
+// markChecked records that l, the cached listing of s, was just compared
+// with the shared copy.
+func markChecked(s wallpapers.Store, l *listing) {
+	listingsMu.Lock()
+	defer listingsMu.Unlock()
+	if listings[s] == l {
+		c := *l
+		c.checked = time.Now()
+		listings[s] = &c
+	}
+}
+
+// refreshListing reads the whole of s and caches the result. The returned
+// slice must not be modified.
+func refreshListing(ctx context.Context, s wallpapers.Store) ([]*wallpapers.File, error) {
+	read := time.Now()
+	files, err := wallpapers.GetAll(wallpapers.ContextWithStore(ctx, s))
+	if err != nil {
+		return nil, err
+	}
+	if serveImages {
+		name := collectionName(s)
+		for i, f := range files {
+			files[i] = withImageURLs(f, name)
+		}
+	}
+
+	now := time.Now()
+	l := &listing{files: files, fetched: now, version: strconv.FormatInt(now.UnixNano(), 36), checked: now}
+	if sharedCache != nil {
+		if err := writeSharedListing(ctx, s, l); err != nil {
+			ctxLog(ctx).Warnw("could not write shared listing", zap.Error(err))
+		}
+	}
+
+	listingsMu.Lock()
+	listings[s] = l
+	listingsMu.Unlock()
+	changes.update(s, files, read)
+
+	return files, nil
+}
+
+// writeSharedListing stores l as s's listing in Redis. The version is
+// written last, so replicas that see it can read the listing it names.
+func writeSharedListing(ctx context.Context, s wallpapers.Store, l *listing) error {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(sharedListing{Version: l.version, Fetched: l.fetched, Files: l.files}); err != nil {
+		return err
+	}
+	if err := sharedCache.Set(ctx, listingKey(s), buf.Bytes(), listingTTL); err != nil {
+		return err
+	}
+	return sharedCache.Set(ctx, listingVersionKey(s), []byte(l.version), listingTTL)
+}
+
+// readSharedListing returns s's listing from Redis. If l, this replica's
+// copy, is the current version, only the version is read.
+func readSharedListing(ctx context.Context, s wallpapers.Store, l *listing) ([]*wallpapers.File, error) {
+	version, err := sharedCache.Get(ctx, listingVersionKey(s))
+	if err != nil {
+		return nil, err
+	}
+	if l != nil && l.version == string(version) {
+		markChecked(s, l)
+		return l.files, nil
+	}
+
+	buf, err := sharedCache.Get(ctx, listingKey(s))
+	if err != nil {
+		return nil, err
+	}
+	var shared sharedListing
+	if err := gob.NewDecoder(bytes.NewReader(buf)).Decode(&shared); err != nil {
+		return nil, err
+	}
+
+	listingsMu.Lock()
+	listings[s] = &listing{files: shared.Files, fetched: shared.Fetched, version: shared.Version, checked: time.Now()}
+	listingsMu.Unlock()
+	changes.update(s, shared.Files, shared.Fetched)
+
+	return shared.Files, nil
+}
+
+// refreshListings refreshes the cached listing of the default store and of
+// every public collection.
+func refreshListings(ctx context.Context) error {
+	stores := []wallpapers.Store{wallpapers.DefaultStore()}
+	for _, s := range collections {
+		if !slices.Contains(stores, s) {
+			stores = append(stores, s)
+		}
+	}
+
+	for _, s := range stores {
+		if _, err := refreshListing(ctx, s); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// searchTTL is how long the names matching a search are kept in Redis.
+// Entries are keyed by listing version, so a new listing never sees stale
+// results.
+const searchTTL = listingTTL
+
+// searchFiles returns the files, a subset of the request's collection, that
+// match query. When Redis is set, the names matching query across the whole
+// listing are cached there, so replicas share the work of each search.
+func searchFiles(ctx context.Context, files []*wallpapers.File, query string) []*wallpapers.File {
+	match := func(f *wallpapers.File) bool { return !matchQuery(f, query) }
+	if sharedCache == nil || strings.TrimSpace(query) == "" {
+		return slices.DeleteFunc(files, match)
+	}
+
+	s := wallpapers.StoreFor(ctx)
+	listingsMu.Lock()
+	l := listings[s]
+	listingsMu.Unlock()
+	if l == nil {
+		return slices.DeleteFunc(files, match)
+	}
+
+	sum := sha256.Sum256([]byte(strings.ToLower(query)))
+	key := listingKey(s) + ":search:" + l.version + ":" + hex.EncodeToString(sum[:8])
+	var names []string
+	buf, err := sharedCache.Get(ctx, key)
+	switch {
+	case err == nil:
+		names = strings.Split(string(buf), "\n")
+	case errors.Is(err, errCacheMiss):
+		for _, f := range l.files {
+			if matchQuery(f, query) {
+				names = append(names, f.Name)
+			}
+		}
+		if err := sharedCache.Set(ctx, key, []byte(strings.Join(names, "\n")), searchTTL); err != nil {
+			ctxLog(ctx).Warnw("could not write shared search", zap.Error(err))
+		}
+	default:
+		ctxLog(ctx).Warnw("could not read shared search", zap.Error(err))
+		return slices.DeleteFunc(files, match)
+	}
+
+	found := make(map[string]bool, len(names))
+	for _, name := range names {
+		found[name] = true
+	}
+	return slices.DeleteFunc(files, func(f *wallpapers.File) bool { return !found[f.Name] })
+}
@@ -0,0 +1,246 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/icco/wallpapers"
+)
+
+func putFile(t *testing.T, s wallpapers.Store, name string) {
+	t.Helper()
+	content := []byte(name)
+	wc, err := s.NewWriter(context.Background(), name, wallpapers.GetChecksums(content), wallpapers.ObjectUpdate{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := wc.Write(content); err != nil {
+		t.Fatal(err)
+	}
+	if err := wc.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSharedListing(t *testing.T) {
+	s := wallpapers.NewMemoryStore()
+	ctx := wallpapers.ContextWithStore(context.Background(), s)
+	putFile(t, s, "a.jpg")
+
+	f := newTestRedis(t)
+	sharedCache = f.cache
+	t.Cleanup(func() {
+		sharedCache = nil
+		listingsMu.Lock()
+		delete(listings, s)
+		listingsMu.Unlock()
+	})
+
+	// expire makes the next request check Redis again.
+	expire := func() {
+		listingsMu.Lock()
+		defer listingsMu.Unlock()
+		l := *listings[s]
+		l.checked = l.checked.Add(-listingCheckInterval)
+		listings[s] = &l
+	}
+
+	count := func(want int) {
+		t.Helper()
+		files, err := listFiles(ctx)
+		if err != nil {
+			t.Fatalf("listFiles error: %v", err)
+		}
+		if len(files) != want {
+			t.Errorf("listFiles returned %d files, want %d", len(files), want)
+		}
+	}
+
+	for _, step := range []struct {
+		name  string
+		setup func()
+		files int
+		want  []string
+	}{
+		{
+			name:  "miss reads the bucket and shares it",
+			files: 1,
+			want:  []string{"GET wallpapers:listing:version", "SET wallpapers:listing", "SET wallpapers:listing:version"},
+		},
+		{
+			name:  "recently checked copy is served without Redis",
+			files: 1,
+		},
+		{
+			name:  "current copy only reads the version",
+			setup: expire,
+			files: 1,
+			want:  []string{"GET wallpapers:listing:version"},
+		},
+		{
+			// The bucket now has two files, but another replica's shared
+			// listing is read rather than the bucket.
+			name: "new replica reads the shared listing",
+			setup: func() {
+				putFile(t, s, "b.jpg")
+				listingsMu.Lock()
+				delete(listings, s)
+				listingsMu.Unlock()
+			},
+			files: 1,
+			want:  []string{"GET wallpapers:listing:version", "GET wallpapers:listing"},
+		},
+		{
+			name: "outage serves the local copy",
+			setup: func() {
+				f.close()
+				expire()
+			},
+			files: 1,
+			want:  []string{"GET wallpapers:listing:version"},
+		},
+	} {
+		t.Run(step.name, func(t *testing.T) {
+			if step.setup != nil {
+				step.setup()
+			}
+			count(step.files)
+			if got := f.seen(); strings.Join(got, ",") != strings.Join(step.want, ",") {
+				t.Errorf("commands = %q, want %q", got, step.want)
+			}
+		})
+	}
+}
+
+// TestListFilesDetached checks that the shared bucket read is not tied to
+// the request that started it, so others waiting on it are not failed when
+// that request goes away.
+func TestListFilesDetached(t *testing.T) {
+	s := wallpapers.NewMemoryStore()
+	putFile(t, s, "a.jpg")
+	t.Cleanup(func() {
+		listingsMu.Lock()
+		delete(listings, s)
+		listingsMu.Unlock()
+	})
+
+	ctx, cancel := context.WithCancel(wallpapers.ContextWithStore(context.Background(), s))
+	cancel()
+	files, err := listFiles(ctx)
+	if err != nil {
+		t.Fatalf("listFiles error: %v", err)
+	}
+	if len(files) != 1 {
+		t.Errorf("listFiles returned %d files, want 1", len(files))
+	}
+}
+
+func TestSearchFiles(t *testing.T) {
+	s := wallpapers.NewMemoryStore()
+	ctx := wallpapers.ContextWithStore(context.Background(), s)
+	for _, name := range []string{"forest.jpg", "forest-night.jpg", "sea.jpg"} {
+		putFile(t, s, name)
+	}
+
+	f := newTestRedis(t)
+	sharedCache = f.cache
+	t.Cleanup(func() {
+		sharedCache = nil
+		listingsMu.Lock()
+		delete(listings, s)
+		listingsMu.Unlock()
+	})
+
+	files, err := listFiles(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	forest := slices.DeleteFunc(slices.Clone(files), func(f *wallpapers.File) bool { return f.Name != "forest.jpg" })
+	f.seen()
+
+	search := func(files []*wallpapers.File, query string) []string {
+		var names []string
+		for _, f := range searchFiles(ctx, files, query) {
+			names = append(names, f.Name)
+		}
+		slices.Sort(names)
+		return names
+	}
+
+	for _, step := range []struct {
+		name  string
+		files []*wallpapers.File
+		query string
+		want  []string
+		cmds  int
+	}{
+		{name: "miss matches the listing and shares it", files: files, query: "forest", want: []string{"forest-night.jpg", "forest.jpg"}, cmds: 2},
+		{name: "hit is read from Redis", files: files, query: "Forest", want: []string{"forest-night.jpg", "forest.jpg"}, cmds: 1},
+		{name: "hit keeps only the files given", files: forest, query: "forest", want: []string{"forest.jpg"}, cmds: 1},
+		{name: "no matches", files: files, query: "desert", cmds: 2},
+		{name: "empty query matches everything without Redis", files: files, query: " ", want: []string{"forest-night.jpg", "forest.jpg", "sea.jpg"}},
+	} {
+		t.Run(step.name, func(t *testing.T) {
+			f.seen()
+			got := search(slices.Clone(step.files), step.query)
+			if !slices.Equal(got, step.want) {
+				t.Errorf("searchFiles(%q) = %q, want %q", step.query, got, step.want)
+			}
+			if cmds := f.seen(); len(cmds) != step.cmds {
+				t.Errorf("sent %q, want %d commands", cmds, step.cmds)
+			}
+		})
+	}
+}
+
+func TestDailyPick(t *testing.T) {
+	s := wallpapers.NewMemoryStore()
+	r := httptest.NewRequest(http.MethodGet, "/fit/daily", nil)
+	r = r.WithContext(wallpapers.ContextWithStore(r.Context(), s))
+	t.Cleanup(func() { clear(dailies) })
+
+	files := func(names ...string) []*wallpapers.File {
+		var fs []*wallpapers.File
+		for _, name := range names {
+			fs = append(fs, &wallpapers.File{Name: name})
+		}
+		return fs
+	}
+	day := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	for _, shared := range []bool{false, true} {
+		t.Run(fmt.Sprint("shared=", shared), func(t *testing.T) {
+			if shared {
+				sharedCache = newTestRedis(t).cache
+				t.Cleanup(func() { sharedCache = nil })
+			}
+
+			first := dailyPick(r, files("a.jpg", "b.jpg", "c.jpg"), day)
+			// Adding wallpapers does not change the day's pick.
+			if got := dailyPick(r, files("0.jpg", "a.jpg", "b.jpg", "c.jpg"), day.Add(time.Hour)); got != first {
+				t.Errorf("pick after adding = %q, want %q", got, first)
+			}
+			// Deleting it does.
+			rest := slices.DeleteFunc(files("a.jpg", "b.jpg", "c.jpg"), func(f *wallpapers.File) bool { return f.Name == first })
+			again := dailyPick(r, rest, day)
+			if again == first {
+				t.Errorf("pick after deleting = %q, want another", again)
+			}
+			if got := dailyPick(r, files("a.jpg", "b.jpg", "c.jpg"), day); got != again {
+				t.Errorf("pick after replacing = %q, want %q", got, again)
+			}
+			// The next day picks afresh, by the day's number.
+			tomorrow := day.Add(24 * time.Hour)
+			want := files("a.jpg", "b.jpg", "c.jpg")[tomorrow.Unix()/int64((24*time.Hour).Seconds())%3].Name
+			if got := dailyPick(r, files("c.jpg", "b.jpg", "a.jpg"), tomorrow); got != want {
+				t.Errorf("next day's pick = %q, want %q", got, want)
+			}
+		})
+	}
+}
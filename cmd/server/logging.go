@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/icco/gutil/logging"
+	"github.com/icco/wallpapers"
+	"go.uber.org/zap"
+)
+
+type logKey struct{}
+
+// sampleRule logs rate of the requests whose path starts with prefix.
+type sampleRule struct {
+	prefix string
+	rate   float64
+}
+
+// parseSampling parses sampling rules: a comma separated list of path
+// prefixes and the fraction of their requests to log, e.g.
+// "/healthz=0,/fit/=0.1". The longest matching prefix wins and other paths
+// are always logged.
+func parseSampling(v string) ([]sampleRule, error) {
+	var rules []sampleRule
+	for _, part := range strings.Split(v, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		prefix, rateStr, ok := strings.Cut(part, "=")
+		rate, err := strconv.ParseFloat(rateStr, 64)
+		if !ok || !strings.HasPrefix(prefix, "/") || err != nil || rate < 0 || rate > 1 {
+			return nil, fmt.Errorf("invalid log sampling rule %q, want /prefix=rate with rate between 0 and 1", part)
+		}
+		rules = append(rules, sampleRule{prefix: prefix, rate: rate})
+	}
+
+	return rules, nil
+}
+
+// sampled reports whether a request to path should be logged.
+func sampled(rules []sampleRule, path string) bool {
+	best := -1
+	for i, rule := range rules {
+		if strings.HasPrefix(path, rule.prefix) && (best < 0 || len(rule.prefix) > len(rules[best].prefix)) {
+			best = i
+		}
+	}
+	if best < 0 {
+		return true
+	}
+
+	return rand.Float64() < rules[best].rate
+}
+
+// loggingMiddleware assigns every request an ID and writes request logs
+// like logging.Middleware, sampled by the given rules. Only the request log
+// is sampled: what handlers log, including every error, is kept. The ID is
+// returned in the X-Request-Id header, and handlers log through reqLog so
+// their lines carry it too.
+func loggingMiddleware(sampling string) (func(http.Handler) http.Handler, error) {
+	rules, err := parseSampling(sampling)
+	if err != nil {
+		return nil, err
+	}
+
+	requestLog := logging.Handler(log.Desugar(), project)
+	withLogger := func(next http.Handler) http.Handler {
+		logged := requestLog(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := middleware.GetReqID(r.Context())
+			if id != "" {
+				w.Header().Set("X-Request-Id", id)
+			}
+			l := log.With("request-id", id)
+			ctx := context.WithValue(r.Context(), logKey{}, l)
+			r = r.WithContext(wallpapers.ContextWithLogger(ctx, l))
+
+			if sampled(rules, r.URL.Path) {
+				logged.ServeHTTP(w, r)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+
+	return chi.Chain(
+		middleware.RequestID,
+		withLogger,
+		middleware.Recoverer,
+	).Handler, nil
+}
+
+// ctxLog returns the logger of the request ctx belongs to, or the server's
+// logger outside of requests.
+func ctxLog(ctx context.Context) *zap.SugaredLogger {
+	if l, ok := ctx.Value(logKey{}).(*zap.SugaredLogger); ok {
+		return l
+	}
+	return log
+}
@@ -1,12 +1,18 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
 	"html/template"
+	"io"
+	"net"
 	"net/http"
 	"os"
+	"path/filepath"
 	"time"
 
+	"github.com/andybalholm/brotli"
 	chi "github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/cors"
@@ -14,6 +20,8 @@ import (
 	"github.com/icco/gutil/logging"
 	"github.com/icco/wallpapers"
 	"github.com/icco/wallpapers/cmd/server/static"
+	"github.com/icco/wallpapers/cmd/server/templates"
+	"github.com/icco/wallpapers/config"
 	"github.com/unrolled/render"
 	"github.com/unrolled/secure"
 	"go.uber.org/zap"
@@ -31,24 +39,87 @@ var (
 	// See:
 	//  - https://github.com/unrolled/render/blob/v1/README.md
 	//  - https://godoc.org/gopkg.in/unrolled/render.v1
-	Renderer = render.New(render.Options{
+	Renderer = newRenderer(".", render.FS(templates.Templates), false)
+)
+
+// newRenderer returns a renderer for the templates in dir of fsys. In
+// development, templates are recompiled when they change.
+func newRenderer(dir string, fsys render.FileSystem, dev bool) *render.Render {
+	return render.New(render.Options{
 		Charset:                   "UTF-8",
 		DisableHTTPErrorRendering: false,
+		Directory:                 dir,
+		FileSystem:                fsys,
 		Extensions:                []string{".tmpl", ".html"},
 		IndentJSON:                false,
 		IndentXML:                 true,
 		RequirePartials:           false,
+		IsDevelopment:             dev,
 		Funcs:                     []template.FuncMap{template.FuncMap{}},
 	})
-)
+}
 
 func main() {
-	port := "8080"
-	if fromEnv := os.Getenv("PORT"); fromEnv != "" {
-		port = fromEnv
+	cfg := config.New()
+	cfg.RegisterFlags(flag.CommandLine)
+	flag.StringVar(&devDir, "dev", "", "serve templates and static files from this cmd/server directory and reload pages when they change")
+	flag.Parse()
+	if err := cfg.Load(); err != nil {
+		log.Fatalw("could not load config", zap.Error(err))
 	}
+
+	// In development, templates and static files are read from disk so
+	// changes show up without a rebuild.
+	assets := http.FS(static.Assets)
+	if devDir != "" {
+		Renderer = newRenderer(filepath.Join(devDir, "templates"), render.LocalFileSystem{}, true)
+		assets = http.Dir(filepath.Join(devDir, "static"))
+		log.Infow("serving templates and static files from disk", "dir", devDir)
+	}
+
+	port := cfg.Server.Port
 	log.Infow("Starting up", "host", fmt.Sprintf("http://localhost:%s", port))
 
+	store, err := cfg.DefaultStore()
+	if err != nil {
+		log.Fatalw("could not configure store", zap.Error(err))
+	}
+	wallpapers.SetStore(store)
+	wallpapers.SetLogger(log)
+
+	if err := openCollections(cfg); err != nil {
+		log.Fatalw("could not configure collections", zap.Error(err))
+	}
+
+	if cfg.Server.Redis != "" {
+		if sharedCache, err = newRedisCache(cfg.Server.Redis); err != nil {
+			log.Fatalw("could not configure redis", zap.Error(err))
+		}
+	}
+
+	if mirrorTo, err = cfg.MirrorStore(); err != nil {
+		log.Fatalw("could not configure mirror", zap.Error(err))
+	}
+	notifier = cfg.Notifier()
+	warmSizes = cfg.WarmSizes
+
+	serveImages = cfg.Server.ServeImages
+	apiToken = cfg.Server.APIToken
+	switch {
+	case cfg.Server.CursorSecret != "":
+		cursorKey = []byte(cfg.Server.CursorSecret)
+	case cfg.Server.Redis != "" || os.Getenv("K_SERVICE") != "":
+		// Shared listings and Cloud Run both mean several instances behind
+		// one address, where a random per process key would reject cursors
+		// issued by the others.
+		log.Fatalw("a cursor secret is required when running more than one instance", "env", config.CursorSecretEnv)
+	}
+
+	requestLogging, err := loggingMiddleware(cfg.Server.LogSampling)
+	if err != nil {
+		log.Fatalw("could not configure logging", zap.Error(err))
+	}
+
 	secureMiddleware := secure.New(secure.Options{
 		SSLRedirect:        false,
 		SSLProxyHeaders:    map[string]string{"X-Forwarded-Proto": "https"},
@@ -59,10 +130,37 @@ func main() {
 		FeaturePolicy:      "geolocation 'none'; midi 'none'; sync-xhr 'none'; microphone 'none'; camera 'none'; magnetometer 'none'; gyroscope 'none'; fullscreen 'none'; payment 'none'; usb 'none'",
 	})
 
+	// Compress text responses, preferring brotli. Images, zips and event
+	// streams are left alone.
+	compressor := middleware.NewCompressor(5,
+		"text/html",
+		"text/css",
+		"text/plain",
+		"text/javascript",
+		"application/javascript",
+		"application/json",
+		"image/svg+xml",
+	)
+	compressor.SetEncoder("br", func(w io.Writer, level int) io.Writer {
+		return brotli.NewWriterLevel(w, level)
+	})
+
 	r := chi.NewRouter()
-	r.Use(etag.Handler(false))
 	r.Use(middleware.RealIP)
-	r.Use(logging.Middleware(log.Desugar(), project))
+	r.Use(func(h http.Handler) http.Handler {
+		compressed := compressor.Handler(h)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Byte ranges refer to the stored bytes, so ranged responses
+			// are never content encoded.
+			if r.Header.Get("Range") != "" {
+				h.ServeHTTP(w, r)
+				return
+			}
+			compressed.ServeHTTP(w, r)
+		})
+	})
+	r.Use(requestLogging)
+	r.Use(usageMiddleware)
 	r.Use(secureMiddleware.Handler)
 
 	crs := cors.New(cors.Options{
@@ -85,29 +183,33 @@ func main() {
 		})
 	})
 
-	r.Get("/healthz", func(w http.ResponseWriter, r *http.Request) {
-		if _, err := w.Write([]byte("hi.")); err != nil {
-			log.Errorw("error writing healthz", zap.Error(err))
-		}
-	})
+	if devDir != "" {
+		r.Use(liveReload)
+		r.Get("/dev/reload", devReloadHandler)
+	}
+
+	if err := startJobs(context.Background(), cfg.Server.Jobs); err != nil {
+		log.Fatalw("could not start jobs", zap.Error(err))
+	}
+
+	go changes.watch(context.Background(), eventsPollInterval)
 
-	r.Mount("/", http.FileServer(http.FS(static.Assets)))
+	routes(r, store, assets, changes)
 
-	r.Get("/all.json", func(w http.ResponseWriter, r *http.Request) {
-		ctx := r.Context()
-		images, err := wallpapers.GetAll(ctx)
+	if cfg.Server.GRPCPort != "" {
+		addr, opts, err := grpcTransport(cfg.Server)
 		if err != nil {
-			log.Errorw("error during get all", zap.Error(err))
-			if err := Renderer.JSON(w, 500, map[string]string{"error": "retrieval error"}); err != nil {
-				log.Errorw("error during get all render", zap.Error(err))
-			}
-			return
+			log.Fatalw("could not configure grpc", zap.Error(err))
 		}
-
-		if err := Renderer.JSON(w, http.StatusOK, images); err != nil {
-			log.Errorw("error during get all success render", zap.Error(err))
+		lis, err := net.Listen("tcp", addr)
+		if err != nil {
+			log.Fatalw("could not listen for grpc", zap.Error(err))
 		}
-	})
+		log.Infow("Serving gRPC", "addr", addr, "tls", len(opts) > 0)
+		go func() {
+			log.Fatal(newGRPCServer(opts...).Serve(lis))
+		}()
+	}
 
 	srv := &http.Server{
 		Addr:         ":" + port,
@@ -119,3 +221,121 @@ func main() {
 
 	log.Fatal(srv.ListenAndServe())
 }
+
+// routes registers the server's endpoints on r. Files of a local store are
+// served directly, and static files come from assets.
+func routes(r chi.Router, store wallpapers.Store, assets http.FileSystem, events *broker) {
+	// Listings set their own ETags from the versions of the files they are
+	// built from, so they skip buffering and hashing the response.
+	r.Group(func(r chi.Router) {
+		r.Use(collectionMiddleware)
+
+		r.Get("/all.json", allHandler)
+		r.Get("/stats.json", func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			images, err := listFiles(ctx)
+			if err != nil {
+				reqLog(r).Errorw("error during get stats", zap.Error(err))
+				renderError(w, r, http.StatusInternalServerError, "internal", "retrieval error")
+				return
+			}
+
+			if notModified(w, r, images) {
+				return
+			}
+
+			if err := Renderer.JSON(w, http.StatusOK, wallpapers.Summarize(images)); err != nil {
+				reqLog(r).Errorw("error during get stats success render", zap.Error(err))
+			}
+		})
+		r.Get("/sitemap.xml", sitemapHandler)
+		r.Get("/v1/images", v1ImagesHandler)
+		r.Get("/v1/stats", v1StatsHandler)
+		r.Get("/playlist", playlistHandler)
+	})
+
+	// Responses are buffered and hashed for etags, so streaming routes are
+	// registered outside this group.
+	r.Group(func(r chi.Router) {
+		r.Use(etag.Handler(false))
+		r.Use(collectionMiddleware)
+
+		r.Get("/healthz", func(w http.ResponseWriter, r *http.Request) {
+			if _, err := w.Write([]byte("hi.")); err != nil {
+				reqLog(r).Errorw("error writing healthz", zap.Error(err))
+			}
+		})
+
+		r.Mount("/", http.FileServer(assets))
+
+		// The local backend has no imgix in front of it, so serve its files
+		// directly.
+		if ls, ok := store.(*wallpapers.LocalStore); ok {
+			r.Handle(wallpapers.LocalURLPrefix+"*", http.StripPrefix(wallpapers.LocalURLPrefix, http.FileServer(http.Dir(ls.Dir))))
+		}
+
+		r.Get("/fit/random", fitRandomHandler)
+		r.Get("/fit/daily", fitDailyHandler)
+		r.Get("/fit/{name}", fitHandler)
+
+		r.Get("/iiif/{name}", iiifBaseHandler)
+		r.Get("/iiif/{name}/info.json", iiifInfoHandler)
+
+		r.Get("/image/{name}", imageHandler)
+		r.Get("/oembed", oembedHandler)
+
+		r.Get("/v1/fit/random", fitRandomHandler)
+		r.Get("/v1/fit/daily", fitDailyHandler)
+		r.Get("/v1/fit/{name}", fitHandler)
+
+		r.Get("/api/v1/search", wallhavenSearchHandler)
+		r.Get("/api/v1/w/{id}", wallhavenWallpaperHandler)
+	})
+
+	// The audit log names who changed what, so it is only served to
+	// holders of the API token. Reading it can outlast the write timeout,
+	// which the etag group's buffered writer cannot extend.
+	r.With(requireToken, collectionMiddleware).Get("/audit", auditHandler)
+
+	// Like gRPC and browser uploads, deletes change the default collection.
+	r.With(requireToken).Delete("/v1/images/{name}", v1DeleteImageHandler)
+
+	r.Get("/readyz", readyzHandler)
+
+	// Browser uploads are resumable, using tus, and a part can take longer
+	// than the write timeout, so they are in neither group. Like gRPC
+	// uploads, they go to the default collection.
+	r.Options("/upload", tusOptionsHandler)
+	r.With(requireTus, requireToken).Post("/upload", tusCreateHandler)
+	r.With(requireTus, requireToken).Head("/upload/{id}", tusHeadHandler)
+	r.With(requireTus, requireToken).Patch("/upload/{id}", tusPatchHandler)
+	r.With(requireTus, requireToken).Delete("/upload/{id}", tusDeleteHandler)
+
+	// GraphQL queries name their collection in arguments, and are POSTed,
+	// so they are neither in the collection nor the etag group.
+	r.Get("/graphql", graphqlHandler)
+	r.Post("/graphql", graphqlHandler)
+	r.Get("/jobs", jobsHandler)
+
+	// Previews, e-ink versions and IIIF regions are drawn on demand, which
+	// takes longer than the write timeout that the etag group's buffered
+	// writer cannot extend.
+	r.With(collectionMiddleware).Get("/eink/{name}", einkHandler)
+	r.With(collectionMiddleware).Get("/iiif/{name}/{region}/{size}/{rotation}/{file}", iiifImageHandler)
+	r.With(collectionMiddleware).Get("/preview/{name}", previewHandler)
+
+	// Downloads stream large files, so they are not buffered for etags.
+	r.With(collectionMiddleware).Get("/download/{name}", downloadHandler)
+	r.With(collectionMiddleware).Head("/download/{name}", downloadHandler)
+	r.With(collectionMiddleware).Get("/img/{hash}/{name}", imgHandler)
+	r.With(collectionMiddleware).Head("/img/{hash}/{name}", imgHandler)
+	r.With(collectionMiddleware).Get("/img/{hash}/thumb/{name}", imgThumbHandler)
+	r.Get("/downloads.json", downloadsHandler)
+	r.Get("/stats/usage.json", usageHandler)
+
+	for _, prefix := range []string{"", "/v1"} {
+		r.With(collectionMiddleware).Get(prefix+"/archive", archiveHandler)
+		r.With(collectionMiddleware).Post(prefix+"/archive", archiveHandler)
+		r.With(collectionMiddleware).Get(prefix+"/events", events.handler)
+	}
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"cloud.google.com/go/storage"
+	"github.com/icco/wallpapers"
+	"go.uber.org/zap"
+)
+
+const (
+	oembedWidth  = 1920
+	oembedHeight = 1080
+)
+
+// oembedResponse is an oEmbed photo response. See https://oembed.com.
+type oembedResponse struct {
+	Version      string `json:"version"`
+	Type         string `json:"type"`
+	Title        string `json:"title"`
+	URL          string `json:"url"`
+	Width        int    `json:"width"`
+	Height       int    `json:"height"`
+	ProviderName string `json:"provider_name"`
+	ProviderURL  string `json:"provider_url"`
+	AuthorName   string `json:"author_name,omitempty"`
+	AuthorURL    string `json:"author_url,omitempty"`
+}
+
+// oembedURL returns the oEmbed endpoint for a page URL, for discovery links.
+func oembedURL(pageURL string) string {
+	return siteURL + "/oembed?url=" + url.QueryEscape(pageURL)
+}
+
+// oembedName returns the wallpaper name from an image page URL on this site.
+func oembedName(raw string) (string, bool) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", false
+	}
+
+	site, _ := url.Parse(siteURL)
+	if !strings.EqualFold(u.Hostname(), site.Hostname()) {
+		return "", false
+	}
+
+	name, ok := strings.CutPrefix(u.Path, "/image/")
+	name = strings.TrimSuffix(name, "/")
+	if !ok || name == "" || strings.Contains(name, "/") {
+		return "", false
+	}
+
+	return name, true
+}
+
+// oembedSize scales the default size down to fit maxwidth and maxheight,
+// keeping its aspect ratio.
+func oembedSize(q url.Values) (int, int, error) {
+	w, h := oembedWidth, oembedHeight
+	if v := q.Get("maxwidth"); v != "" {
+		mw, err := strconv.Atoi(v)
+		if err != nil || mw < 1 {
+			return 0, 0, invalidParam("maxwidth", "maxwidth must be a positive integer")
+		}
+		if mw < w {
+			h = h * mw / w
+			w = mw
+		}
+	}
+
+	if v := q.Get("maxheight"); v != "" {
+		mh, err := strconv.Atoi(v)
+		if err != nil || mh < 1 {
+			return 0, 0, invalidParam("maxheight", "maxheight must be a positive integer")
+		}
+		if mh < h {
+			w = w * mh / h
+			h = mh
+		}
+	}
+
+	return max(w, 1), max(h, 1), nil
+}
+
+func oembedHandler(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if f := q.Get("format"); f != "" && f != "json" {
+		renderError(w, r, http.StatusNotImplemented, "not_implemented", "only json is supported")
+		return
+	}
+
+	name, ok := oembedName(q.Get("url"))
+	if !ok {
+		renderError(w, r, http.StatusNotFound, "not_found", "url is not a wallpaper page")
+		return
+	}
+
+	width, height, err := oembedSize(q)
+	if err != nil {
+		renderBadRequest(w, r, "bad_request", err)
+		return
+	}
+
+	file, err := wallpapers.GetFile(r.Context(), name)
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		renderError(w, r, http.StatusNotFound, "not_found", "not found")
+		return
+	}
+	if err != nil {
+		reqLog(r).Errorw("error during oembed get file", "name", name, zap.Error(err))
+		renderError(w, r, http.StatusInternalServerError, "internal", "retrieval error")
+		return
+	}
+
+	resp := oembedResponse{
+		Version:      "1.0",
+		Type:         "photo",
+		Title:        file.Name,
+		URL:          wallpapers.FitURL(r.Context(), file.Name, width, height, 1),
+		Width:        width,
+		Height:       height,
+		ProviderName: "Wallpapers",
+		ProviderURL:  siteURL,
+		AuthorName:   file.Author,
+		AuthorURL:    file.SourceURL,
+	}
+	if err := Renderer.JSON(w, http.StatusOK, resp); err != nil {
+		reqLog(r).Errorw("error during oembed success render", zap.Error(err))
+	}
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"net/url"
+
+	"cloud.google.com/go/storage"
+	chi "github.com/go-chi/chi/v5"
+	"github.com/icco/wallpapers"
+	"github.com/icco/wallpapers/cmd/server/templates"
+	"go.uber.org/zap"
+)
+
+const (
+	// siteURL is the canonical address of the gallery.
+	siteURL = "https://walls.natwelch.com"
+
+	ogWidth  = 1200
+	ogHeight = 630
+)
+
+// imageURL returns the canonical URL of a wallpaper's page.
+func imageURL(name string) string {
+	return siteURL + "/image/" + url.PathEscape(name)
+}
+
+func imageHandler(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+	file, err := wallpapers.GetFile(r.Context(), name)
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		if err := Renderer.Text(w, http.StatusNotFound, "not found"); err != nil {
+			reqLog(r).Errorw("error during image render", zap.Error(err))
+		}
+		return
+	}
+	if err != nil {
+		reqLog(r).Errorw("error during image get file", "name", name, zap.Error(err))
+		if err := Renderer.Text(w, http.StatusInternalServerError, "retrieval error"); err != nil {
+			reqLog(r).Errorw("error during image render", zap.Error(err))
+		}
+		return
+	}
+
+	recordView(file.Name)
+
+	page := templates.ImagePage{
+		File:     withImageURLs(file, r.URL.Query().Get("collection")),
+		Root:     "/",
+		URL:      imageURL(file.Name),
+		OEmbed:   oembedURL(imageURL(file.Name)),
+		Download: "/download/" + url.PathEscape(file.Name),
+		OGImage:  wallpapers.FitURL(r.Context(), file.Name, ogWidth, ogHeight, 1),
+		OGWidth:  ogWidth,
+		OGHeight: ogHeight,
+	}
+	if err := Renderer.HTML(w, http.StatusOK, "image", page); err != nil {
+		reqLog(r).Errorw("error during image success render", zap.Error(err))
+	}
+}
+
+// sitemap is a sitemap with the image extension. encoding/xml does not
+// support namespace prefixes, so the prefixed names are spelled out.
+type sitemap struct {
+	XMLName xml.Name     `xml:"urlset"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	Image   string       `xml:"xmlns:image,attr"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+type sitemapURL struct {
+	Loc     string         `xml:"loc"`
+	LastMod string         `xml:"lastmod"`
+	Images  []sitemapImage `xml:"image:image"`
+}
+
+type sitemapImage struct {
+	Loc string `xml:"image:loc"`
+}
+
+func sitemapHandler(w http.ResponseWriter, r *http.Request) {
+	images, err := listFiles(r.Context())
+	if err != nil {
+		reqLog(r).Errorw("error during sitemap get all", zap.Error(err))
+		if err := Renderer.Text(w, http.StatusInternalServerError, "retrieval error"); err != nil {
+			reqLog(r).Errorw("error during sitemap render", zap.Error(err))
+		}
+		return
+	}
+
+	if notModified(w, r, images) {
+		return
+	}
+
+	sm := sitemap{
+		XMLNS: "http://www.sitemaps.org/schemas/sitemap/0.9",
+		Image: "http://www.google.com/schemas/sitemap-image/1.1",
+		URLs: []sitemapURL{
+			{Loc: siteURL + "/", LastMod: wallpapers.LastModified(images).UTC().Format("2006-01-02")},
+		},
+	}
+	for _, f := range images {
+		sm.URLs = append(sm.URLs, sitemapURL{
+			Loc:     imageURL(f.Name),
+			LastMod: f.Updated.UTC().Format("2006-01-02"),
+			Images: []sitemapImage{
+				{Loc: f.FullRezURL},
+				{Loc: f.ThumbnailURL},
+			},
+		})
+	}
+
+	if err := Renderer.XML(w, http.StatusOK, sm); err != nil {
+		reqLog(r).Errorw("error during sitemap success render", zap.Error(err))
+	}
+}
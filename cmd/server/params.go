@@ -0,0 +1,33 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// paramError is a query parameter that failed validation. Handlers check
+// parameters before listing the bucket or redirecting to imgix, so
+// malformed requests are turned away cheaply.
+type paramError struct {
+	param string
+	msg   string
+}
+
+func (e *paramError) Error() string { return e.msg }
+
+// invalidParam returns a paramError for param.
+func invalidParam(param, format string, args ...any) error {
+	return &paramError{param: param, msg: fmt.Sprintf(format, args...)}
+}
+
+// renderBadRequest writes a 400 for err, naming the parameter at fault if
+// err is a paramError.
+func renderBadRequest(w http.ResponseWriter, r *http.Request, code string, err error) {
+	e := newAPIError(r, code, err.Error())
+	var pe *paramError
+	if errors.As(err, &pe) {
+		e.Param = pe.param
+	}
+	writeError(w, r, http.StatusBadRequest, e)
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/icco/wallpapers"
+	"go.uber.org/zap"
+)
+
+// playlistFormats are the formats /playlist can be written in, by the
+// format query parameter.
+var playlistFormats = map[string]string{
+	"json": "application/json; charset=UTF-8",
+	"m3u":  "audio/x-mpegurl; charset=UTF-8",
+	"txt":  "text/plain; charset=UTF-8",
+}
+
+// playlistHandler serves /playlist: the URLs of the images matching q, in
+// the order given by sort and order, for wallpaper rotators that read a
+// list of URLs. With w and h, each URL is a crop of that size; otherwise
+// it is the full resolution rendition.
+func playlistHandler(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	format := q.Get("format")
+	if format == "" {
+		format = "txt"
+	}
+	contentType, ok := playlistFormats[format]
+	if !ok {
+		renderBadRequest(w, r, "bad_request", invalidParam("format", "format must be json, m3u or txt"))
+		return
+	}
+
+	sized := q.Get("w") != "" || q.Get("h") != ""
+	width, height, dpr, err := fitParams(r)
+	if sized && err != nil {
+		renderBadRequest(w, r, "bad_request", err)
+		return
+	}
+
+	opts, err := parseListOptions(r)
+	if err != nil {
+		renderBadRequest(w, r, "bad_request", err)
+		return
+	}
+	opts.typ = wallpapers.TypeImage
+
+	images, err := listFiles(r.Context())
+	if err != nil {
+		reqLog(r).Errorw("error during playlist get all", zap.Error(err))
+		renderError(w, r, http.StatusInternalServerError, "internal", "retrieval error")
+		return
+	}
+
+	if opts.cacheable() && notModified(w, r, images) {
+		return
+	}
+
+	images, err = opts.sorted(images)
+	if err != nil {
+		renderBadRequest(w, r, "bad_request", err)
+		return
+	}
+	query := q.Get("q")
+	images = searchFiles(r.Context(), images, query)
+
+	urls := make([]string, 0, len(images))
+	for _, f := range images {
+		u := f.FullRezURL
+		if sized {
+			u = wallpapers.FitURL(r.Context(), f.Name, width, height, dpr)
+		}
+		urls = append(urls, u)
+	}
+
+	if format == "json" {
+		if err := Renderer.JSON(w, http.StatusOK, urls); err != nil {
+			reqLog(r).Errorw("error during playlist render", zap.Error(err))
+		}
+		return
+	}
+
+	var buf bytes.Buffer
+	if format == "m3u" {
+		buf.WriteString("#EXTM3U\n")
+	}
+	for i, u := range urls {
+		if format == "m3u" {
+			fmt.Fprintf(&buf, "#EXTINF:-1,%s\n", strings.Join(strings.Fields(images[i].Alt()), " "))
+		}
+		buf.WriteString(u + "\n")
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		reqLog(r).Errorw("error writing playlist", zap.Error(err))
+	}
+}
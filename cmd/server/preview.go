@@ -0,0 +1,234 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/storage"
+	chi "github.com/go-chi/chi/v5"
+	"github.com/icco/wallpapers"
+	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
+)
+
+const defaultPreviewDevice = "macbook"
+
+const (
+	// previewTimeout bounds downloading and drawing a preview that is not
+	// cached yet.
+	previewTimeout = 30 * time.Second
+	// previewConcurrency is how many previews are drawn at once. Decoding a
+	// large original takes hundreds of megabytes, so further requests wait
+	// for a slot.
+	previewConcurrency = 2
+	// previewCacheSize is how many drawn previews are kept in memory.
+	previewCacheSize = 32
+)
+
+// previewKey is a preview of one store.
+type previewKey struct {
+	store wallpapers.Store
+	name  string
+}
+
+var (
+	previewSlots = make(chan struct{}, previewConcurrency)
+	// previewRenders lets concurrent requests for a preview that has to be
+	// drawn share one drawing.
+	previewRenders singleflight.Group
+
+	previewMu sync.Mutex
+	// previews holds recently drawn previews, oldest first in previewOrder.
+	previews     = map[previewKey][]byte{}
+	previewOrder []previewKey
+	// frames holds each device's frame, by device name, and savedFrames
+	// records the frames known to be in each store's variants, for imgix
+	// to draw previews with.
+	frames      = map[string][]byte{}
+	savedFrames = map[previewKey]bool{}
+)
+
+// previewHandler serves a wallpaper drawn on a device mockup, chosen by
+// the device query parameter. Collections behind imgix are redirected to
+// an imgix rendition that lays a frame drawn here over the wallpaper.
+// Otherwise previews are drawn here and cached in memory and in the
+// store's variants.
+func previewHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	device := r.URL.Query().Get("device")
+	if device == "" {
+		device = defaultPreviewDevice
+	}
+	d, ok := wallpapers.Devices[device]
+	if !ok {
+		names := slices.Sorted(maps.Keys(wallpapers.Devices))
+		renderBadRequest(w, r, "bad_request", invalidParam("device", "device must be one of %s", strings.Join(names, ", ")))
+		return
+	}
+
+	name := chi.URLParam(r, "name")
+	file, err := wallpapers.GetFile(ctx, name)
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		renderError(w, r, http.StatusNotFound, "not_found", "not found")
+		return
+	}
+	if err != nil {
+		reqLog(r).Errorw("error during preview get file", "name", name, zap.Error(err))
+		renderError(w, r, http.StatusInternalServerError, "internal", "retrieval error")
+		return
+	}
+	if file.Type != wallpapers.TypeImage {
+		renderError(w, r, http.StatusBadRequest, "bad_request", "only images have previews")
+		return
+	}
+
+	if wallpapers.UsesImgix(ctx) {
+		frame, err := previewFrame(ctx, device, d)
+		if err == nil {
+			u, _ := wallpapers.PreviewURL(ctx, file.Name, d, frame)
+			http.Redirect(w, r, u, http.StatusFound)
+			return
+		}
+		reqLog(r).Warnw("could not store preview frame, drawing preview", "device", device, zap.Error(err))
+	}
+
+	// The server's write timeout is too short to download and draw a large
+	// original.
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Now().Add(previewTimeout)); err != nil {
+		reqLog(r).Warnw("could not extend preview write deadline", zap.Error(err))
+	}
+
+	content, err := drawPreview(ctx, file, device, d)
+	if err != nil {
+		reqLog(r).Errorw("error during preview render", "name", name, zap.Error(err))
+		renderError(w, r, http.StatusInternalServerError, "internal", "render error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("Cache-Control", "public, max-age=86400")
+	if _, err := w.Write(content); err != nil {
+		reqLog(r).Errorw("error writing preview", zap.Error(err))
+	}
+}
+
+// previewFrame stores d's frame in the variants of the store in ctx, if it
+// is not there yet, and returns its name.
+func previewFrame(ctx context.Context, device string, d wallpapers.Device) (string, error) {
+	previewMu.Lock()
+	frame, ok := frames[device]
+	previewMu.Unlock()
+	if !ok {
+		var err error
+		if frame, err = wallpapers.PreviewFrame(d); err != nil {
+			return "", err
+		}
+		previewMu.Lock()
+		frames[device] = frame
+		previewMu.Unlock()
+	}
+	name := wallpapers.PreviewFrameName(device, frame)
+	key := previewKey{wallpapers.StoreFor(ctx), name}
+
+	previewMu.Lock()
+	saved := savedFrames[key]
+	previewMu.Unlock()
+	if saved {
+		return name, nil
+	}
+
+	variants, err := wallpapers.Variants(ctx)
+	if err != nil {
+		return "", err
+	}
+	_, err = variants.Attrs(ctx, name)
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		err = wallpapers.SaveVariant(ctx, name, frame)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	previewMu.Lock()
+	savedFrames[key] = true
+	previewMu.Unlock()
+	return name, nil
+}
+
+// drawPreview returns the preview of file on d, from memory, the store's
+// variants or, failing both, by drawing it. Requests for the same preview
+// share one drawing, and at most previewConcurrency are drawn at once.
+func drawPreview(ctx context.Context, file *wallpapers.File, device string, d wallpapers.Device) ([]byte, error) {
+	key := previewKey{wallpapers.StoreFor(ctx), wallpapers.PreviewName(file, device)}
+
+	previewMu.Lock()
+	content, ok := previews[key]
+	previewMu.Unlock()
+	if ok {
+		return content, nil
+	}
+
+	v, err, _ := previewRenders.Do(listingKey(key.store)+":"+key.name, func() (any, error) {
+		// Every request waiting on the drawing shares its result, so it
+		// must not be cancelled when the request that started it goes
+		// away.
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), previewTimeout)
+		defer cancel()
+
+		content, err := wallpapers.LoadVariant(ctx, key.name)
+		if err != nil {
+			if !errors.Is(err, storage.ErrObjectNotExist) {
+				ctxLog(ctx).Warnw("could not read cached preview", "name", key.name, zap.Error(err))
+			}
+
+			select {
+			case previewSlots <- struct{}{}:
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+			defer func() { <-previewSlots }()
+
+			original, err := wallpapers.DownloadFile(ctx, file.Name)
+			if err != nil {
+				return nil, err
+			}
+			content, err = wallpapers.Preview(original, d)
+			if err != nil {
+				return nil, err
+			}
+
+			if err := wallpapers.SaveVariant(ctx, key.name, content); err != nil {
+				ctxLog(ctx).Warnw("could not cache preview", "name", key.name, zap.Error(err))
+			}
+		}
+
+		cachePreview(key, content)
+		return content, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]byte), nil
+}
+
+// cachePreview keeps content in memory, forgetting the oldest preview if
+// there are more than previewCacheSize.
+func cachePreview(key previewKey, content []byte) {
+	previewMu.Lock()
+	defer previewMu.Unlock()
+	if _, ok := previews[key]; ok {
+		return
+	}
+	previews[key] = content
+	previewOrder = append(previewOrder, key)
+	if len(previewOrder) > previewCacheSize {
+		delete(previews, previewOrder[0])
+		previewOrder = previewOrder[1:]
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/png"
+	"sync"
+	"testing"
+
+	"github.com/icco/wallpapers"
+)
+
+func TestDrawPreview(t *testing.T) {
+	s := wallpapers.NewMemoryStore()
+	ctx := wallpapers.ContextWithStore(context.Background(), s)
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 64, 40))); err != nil {
+		t.Fatal(err)
+	}
+	wc, err := s.NewWriter(ctx, "a.png", wallpapers.GetChecksums(buf.Bytes()), wallpapers.ObjectUpdate{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := wc.Write(buf.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	if err := wc.Close(); err != nil {
+		t.Fatal(err)
+	}
+	file, err := s.Attrs(ctx, "a.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := wallpapers.Devices[defaultPreviewDevice]
+	key := previewKey{s, wallpapers.PreviewName(file, defaultPreviewDevice)}
+	t.Cleanup(func() {
+		previewMu.Lock()
+		delete(previews, key)
+		previewOrder = nil
+		previewMu.Unlock()
+	})
+
+	// Concurrent requests share one drawing.
+	var wg sync.WaitGroup
+	got := make([][]byte, 4)
+	for i := range got {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			content, err := drawPreview(ctx, file, defaultPreviewDevice, d)
+			if err != nil {
+				t.Errorf("drawPreview error: %v", err)
+			}
+			got[i] = content
+		}()
+	}
+	wg.Wait()
+	for _, content := range got[1:] {
+		if !bytes.Equal(content, got[0]) {
+			t.Fatal("concurrent previews differ")
+		}
+	}
+
+	cached, err := wallpapers.LoadVariant(ctx, key.name)
+	if err != nil {
+		t.Fatalf("preview not stored in variants: %v", err)
+	}
+	if !bytes.Equal(cached, got[0]) {
+		t.Error("stored preview differs from the drawn one")
+	}
+
+	// With the original and the stored preview gone, the preview is still
+	// served from memory.
+	variants, err := wallpapers.Variants(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, del := range []func() error{
+		func() error { return s.Delete(ctx, "a.png") },
+		func() error { return variants.Delete(ctx, key.name) },
+	} {
+		if err := del(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	content, err := drawPreview(ctx, file, defaultPreviewDevice, d)
+	if err != nil {
+		t.Fatalf("drawPreview from memory error: %v", err)
+	}
+	if !bytes.Equal(content, got[0]) {
+		t.Error("preview from memory differs from the drawn one")
+	}
+}
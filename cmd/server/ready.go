@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/icco/wallpapers"
+	"go.uber.org/zap"
+)
+
+const readyTimeout = 800 * time.Millisecond
+
+// check is the result of checking one dependency.
+type check struct {
+	Status  string `json:"status"`
+	Latency string `json:"latency"`
+	Error   string `json:"error,omitempty"`
+}
+
+// readiness is the response of /readyz.
+type readiness struct {
+	Status string           `json:"status"`
+	Checks map[string]check `json:"checks"`
+}
+
+// readyChecks are the dependencies checked by /readyz, by name.
+var readyChecks = map[string]func(context.Context) error{
+	"gcs": wallpapers.CheckBucket,
+}
+
+// readyzHandler checks that every dependency is reachable, so monitoring
+// notices expired credentials. It responds 503 if any check fails.
+func readyzHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), readyTimeout)
+	defer cancel()
+
+	resp := readiness{Status: "ok", Checks: map[string]check{}}
+	status := http.StatusOK
+	for name, fn := range readyChecks {
+		start := time.Now()
+		err := fn(ctx)
+		c := check{Status: "ok", Latency: time.Since(start).String()}
+		if err != nil {
+			reqLog(r).Errorw("readiness check failed", "check", name, zap.Error(err))
+			c.Status = "error"
+			c.Error = err.Error()
+			resp.Status = "error"
+			status = http.StatusServiceUnavailable
+		}
+		resp.Checks[name] = c
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	if err := Renderer.JSON(w, status, resp); err != nil {
+		reqLog(r).Errorw("error during readyz render", zap.Error(err))
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	redisPoolSize    = 4
+	redisDialTimeout = 2 * time.Second
+	// redisTimeout bounds reading and writing each command.
+	redisTimeout = 2 * time.Second
+)
+
+// errCacheMiss is returned by redisCache.Get for keys that are not set.
+var errCacheMiss = errors.New("cache miss")
+
+// redisCache shares caches between replicas through Redis.
+type redisCache struct {
+	client *redis.Client
+}
+
+// newRedisCache returns a cache for a redis:// or rediss:// URL of the
+// form redis://[user:password@]host[:port][/db]. Connections are made when
+// they are first needed.
+func newRedisCache(rawURL string) (*redisCache, error) {
+	opts, err := redis.ParseURL(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid redis url: %w", err)
+	}
+	opts.PoolSize = redisPoolSize
+	opts.DialTimeout = redisDialTimeout
+	opts.ReadTimeout = redisTimeout
+	opts.WriteTimeout = redisTimeout
+	// Managed Redis services often refuse CLIENT SETINFO.
+	opts.DisableIdentity = true
+
+	return &redisCache{client: redis.NewClient(opts)}, nil
+}
+
+// Get returns the value of key, or errCacheMiss if it is not set.
+func (c *redisCache) Get(ctx context.Context, key string) ([]byte, error) {
+	v, err := c.client.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, errCacheMiss
+	}
+	return v, err
+}
+
+// Set sets key to value, expiring after ttl.
+func (c *redisCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return c.client.Set(ctx, key, value, ttl).Err()
+}
+
+// SetNX sets key to value, expiring after ttl, unless it is already set,
+// and reports whether it was set.
+func (c *redisCache) SetNX(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	return c.client.SetNX(ctx, key, value, ttl).Result()
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"encoding/json"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+
+	chi "github.com/go-chi/chi/v5"
+	"github.com/icco/wallpapers"
+	"github.com/icco/wallpapers/cmd/server/static"
+)
+
+// specPaths maps chi patterns to the OpenAPI paths describing them, where
+// they differ.
+var specPaths = map[string]string{
+	"/iiif/{name}/{region}/{size}/{rotation}/{file}": "/iiif/{name}/{region}/{size}/{rotation}/{quality}.{format}",
+}
+
+// TestOpenAPIMatchesRoutes checks that openapi.json describes exactly the
+// routes the server registers.
+func TestOpenAPIMatchesRoutes(t *testing.T) {
+	r := chi.NewRouter()
+	routes(r, wallpapers.NewMemoryStore(), http.FS(static.Assets), newBroker())
+
+	registered := map[string]bool{}
+	err := chi.Walk(r, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		// Static files are served from the root.
+		if strings.HasSuffix(route, "/*") {
+			return nil
+		}
+		if p, ok := specPaths[route]; ok {
+			route = p
+		}
+		registered[strings.ToLower(method)+" "+route] = true
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf, err := static.Assets.ReadFile("openapi.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var spec struct {
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal(buf, &spec); err != nil {
+		t.Fatal(err)
+	}
+	documented := map[string]bool{}
+	for path, ops := range spec.Paths {
+		for method := range ops {
+			documented[method+" "+path] = true
+		}
+	}
+
+	for _, op := range slices.Sorted(maps.Keys(registered)) {
+		if !documented[op] {
+			t.Errorf("%s is registered but not in openapi.json", op)
+		}
+	}
+	for _, op := range slices.Sorted(maps.Keys(documented)) {
+		if !registered[op] {
+			t.Errorf("%s is in openapi.json but not registered", op)
+		}
+	}
+}
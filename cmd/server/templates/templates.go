@@ -0,0 +1,30 @@
+package templates
+
+import (
+	"embed"
+
+	"github.com/icco/wallpapers"
+)
+
+// Templates are our HTML templates, rendered by the server's Renderer.
+//
+//go:embed *.tmpl
+var Templates embed.FS
+
+// ImagePage is what image.tmpl is rendered with, by the server and by walls
+// export. Fields left empty are omitted from the page.
+type ImagePage struct {
+	File *wallpapers.File
+	// Root is the path of the gallery relative to the page, ending in a
+	// slash.
+	Root string
+	// URL is the page's canonical URL.
+	URL    string
+	OEmbed string
+	// Download is the URL of the original file.
+	Download string
+
+	OGImage  string
+	OGWidth  int
+	OGHeight int
+}
@@ -0,0 +1,77 @@
+package templates
+
+import (
+	"html/template"
+	"strings"
+	"testing"
+
+	"github.com/icco/wallpapers"
+)
+
+func TestImagePage(t *testing.T) {
+	tmpl := template.Must(template.ParseFS(Templates, "image.tmpl"))
+	f := &wallpapers.File{
+		Name:         "lake.jpg",
+		FullRezURL:   "https://example.com/lake.jpg",
+		ThumbnailURL: "https://example.com/lake.jpg?w=800",
+		Attribution:  wallpapers.Attribution{Author: "nat"},
+	}
+
+	for _, tc := range []struct {
+		name    string
+		page    ImagePage
+		want    []string
+		notWant []string
+	}{
+		{
+			name: "served",
+			page: ImagePage{
+				File:     f,
+				Root:     "/",
+				URL:      "https://walls.example.com/image/lake.jpg",
+				OEmbed:   "https://walls.example.com/oembed?url=x",
+				Download: "/download/lake.jpg",
+				OGImage:  "https://example.com/lake.jpg?w=1200",
+				OGWidth:  1200,
+				OGHeight: 630,
+			},
+			want: []string{
+				`<link rel="canonical" href="https://walls.example.com/image/lake.jpg">`,
+				`<link rel="stylesheet" type="text/css" href="/css/tachyons.min.css">`,
+				`<meta property="og:image" content="https://example.com/lake.jpg?w=1200">`,
+				`<meta property="og:image:width" content="1200">`,
+				`<a href="/download/lake.jpg">Download original</a>`,
+				`<meta name="author" content="nat">`,
+				` &middot; by nat`,
+			},
+		},
+		{
+			name: "exported",
+			page: ImagePage{File: f, Root: "../../"},
+			want: []string{
+				`<link rel="stylesheet" type="text/css" href="../../css/tachyons.min.css">`,
+				`<h1 class="man pan"><a href="../../">Wallpapers</a></h1>`,
+				`<a href="https://example.com/lake.jpg">Full resolution</a>`,
+				` &middot; by nat`,
+			},
+			notWant: []string{"canonical", "oembed", "og:", "Download original"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var b strings.Builder
+			if err := tmpl.Execute(&b, tc.page); err != nil {
+				t.Fatal(err)
+			}
+			for _, s := range tc.want {
+				if !strings.Contains(b.String(), s) {
+					t.Errorf("page does not contain %q:\n%s", s, b.String())
+				}
+			}
+			for _, s := range tc.notWant {
+				if strings.Contains(b.String(), s) {
+					t.Errorf("page contains %q:\n%s", s, b.String())
+				}
+			}
+		})
+	}
+}
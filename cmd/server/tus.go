@@ -0,0 +1,420 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"github.com/go-chi/chi/v5"
+	"github.com/icco/wallpapers"
+	"go.uber.org/zap"
+)
+
+// Browsers upload through a subset of the tus resumable upload protocol,
+// https://tus.io/protocols/resumable-upload: the core protocol with the
+// creation and termination extensions. Each PATCH is kept as a part in the
+// default collection's uploads sub store, next to a session listing the
+// parts, so an upload cut off part way can be resumed from any replica, and
+// no replica holds it in memory until the last part arrives. The parts are
+// then joined and stored with storeUpload, as gRPC uploads are.
+const (
+	tusVersion    = "1.0.0"
+	tusExtensions = "creation,termination"
+	// tusContentType is the only content type PATCH requests may have.
+	tusContentType = "application/offset+octet-stream"
+	// tusSessionTTL is how long an upload may go untouched before the
+	// upload-cleanup job discards it.
+	tusSessionTTL = 24 * time.Hour
+	// tusPartTimeout bounds receiving one PATCH, which on a slow
+	// connection takes far longer than the server's read timeout.
+	tusPartTimeout = 10 * time.Minute
+)
+
+// tusSession is what is known of an upload being received. It is stored as
+// JSON under the upload's ID, and its parts under the ID and their index.
+type tusSession struct {
+	Name   string `json:"name"`
+	Length int64  `json:"length"`
+	Offset int64  `json:"offset"`
+	Parts  int    `json:"parts"`
+}
+
+// tusLocks serialises the requests of each upload within a replica.
+var tusLocks sync.Map
+
+func tusLock(id string) func() {
+	mu, _ := tusLocks.LoadOrStore(id, &sync.Mutex{})
+	mu.(*sync.Mutex).Lock()
+	return mu.(*sync.Mutex).Unlock
+}
+
+func tusSessionName(id string) string { return id + ".json" }
+
+func tusPartName(id string, part int) string { return id + "." + strconv.Itoa(part) }
+
+// tusHeaders sets the headers every tus response carries.
+func tusHeaders(w http.ResponseWriter) {
+	w.Header().Set("Tus-Resumable", tusVersion)
+	w.Header().Set("Cache-Control", "no-store")
+}
+
+// tusOptionsHandler describes what the server supports. It is not
+// authenticated, so clients can check before asking for a token.
+func tusOptionsHandler(w http.ResponseWriter, r *http.Request) {
+	tusHeaders(w)
+	w.Header().Set("Tus-Version", tusVersion)
+	w.Header().Set("Tus-Extension", tusExtensions)
+	w.Header().Set("Tus-Max-Size", strconv.FormatInt(uploadLimit(), 10))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// requireTus refuses requests for another version of the protocol.
+func requireTus(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tusHeaders(w)
+		if v := r.Header.Get("Tus-Resumable"); v != tusVersion {
+			w.Header().Set("Tus-Version", tusVersion)
+			renderError(w, r, http.StatusPreconditionFailed, "unsupported_version", "Tus-Resumable must be "+tusVersion)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// tusCreateHandler starts an upload of Upload-Length bytes, named by the
+// filename in Upload-Metadata.
+func tusCreateHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	length, err := strconv.ParseInt(r.Header.Get("Upload-Length"), 10, 64)
+	if err != nil || length < 1 {
+		renderBadRequest(w, r, "invalid_length", invalidParam("Upload-Length", "Upload-Length must be a positive number of bytes"))
+		return
+	}
+	if limit := uploadLimit(); length > limit {
+		renderError(w, r, http.StatusRequestEntityTooLarge, "too_large", fmt.Sprintf("uploads are limited to %d bytes", limit))
+		return
+	}
+
+	meta, err := parseTusMetadata(r.Header.Get("Upload-Metadata"))
+	if err != nil {
+		renderBadRequest(w, r, "invalid_metadata", err)
+		return
+	}
+	name, err := formatUploadName(meta["filename"])
+	if err != nil {
+		renderBadRequest(w, r, "invalid_name", err)
+		return
+	}
+
+	uploads, err := wallpapers.PendingUploads(ctx)
+	if err != nil {
+		reqLog(r).Errorw("could not open pending uploads", zap.Error(err))
+		renderError(w, r, http.StatusServiceUnavailable, "unavailable", "this collection cannot keep uploads")
+		return
+	}
+	id, err := newUploadID()
+	if err != nil {
+		reqLog(r).Errorw("could not create upload ID", zap.Error(err))
+		renderError(w, r, http.StatusInternalServerError, "internal", "upload error")
+		return
+	}
+	if err := putTusObject(ctx, uploads, tusSessionName(id), &tusSession{Name: name, Length: length}); err != nil {
+		reqLog(r).Errorw("could not start upload", zap.Error(err))
+		renderError(w, r, http.StatusInternalServerError, "internal", "upload error")
+		return
+	}
+
+	w.Header().Set("Location", "/upload/"+id)
+	w.WriteHeader(http.StatusCreated)
+}
+
+// tusHeadHandler reports how much of an upload has been received, so a
+// client can resume from there.
+func tusHeadHandler(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	_, sess, ok := loadTusSession(w, r, id)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Upload-Offset", strconv.FormatInt(sess.Offset, 10))
+	w.Header().Set("Upload-Length", strconv.FormatInt(sess.Length, 10))
+	w.WriteHeader(http.StatusOK)
+}
+
+// tusPatchHandler appends the body to an upload at Upload-Offset. Whatever
+// arrives before a connection drops is kept. Once the whole upload is in,
+// it is stored and its name returned in Wallpaper-Key.
+func tusPatchHandler(w http.ResponseWriter, r *http.Request) {
+	// What arrived before a client went away is still stored, so writes
+	// outlive the request.
+	ctx := context.WithoutCancel(r.Context())
+	id := chi.URLParam(r, "id")
+	if ct := r.Header.Get("Content-Type"); ct != tusContentType {
+		renderError(w, r, http.StatusUnsupportedMediaType, "unsupported_media_type", "Content-Type must be "+tusContentType)
+		return
+	}
+	offset, err := strconv.ParseInt(r.Header.Get("Upload-Offset"), 10, 64)
+	if err != nil || offset < 0 {
+		renderBadRequest(w, r, "invalid_offset", invalidParam("Upload-Offset", "Upload-Offset must be a number of bytes"))
+		return
+	}
+
+	rc := http.NewResponseController(w)
+	deadline := time.Now().Add(tusPartTimeout)
+	if err := errors.Join(rc.SetReadDeadline(deadline), rc.SetWriteDeadline(deadline)); err != nil {
+		reqLog(r).Warnw("could not extend upload deadlines", zap.Error(err))
+	}
+
+	defer tusLock(id)()
+	uploads, sess, ok := loadTusSession(w, r, id)
+	if !ok {
+		return
+	}
+	if offset != sess.Offset {
+		w.Header().Set("Upload-Offset", strconv.FormatInt(sess.Offset, 10))
+		renderError(w, r, http.StatusConflict, "offset_mismatch", fmt.Sprintf("the upload is at offset %d", sess.Offset))
+		return
+	}
+
+	// Read one byte past the end, to tell a body that is too long from one
+	// that ends exactly there.
+	part, readErr := io.ReadAll(io.LimitReader(r.Body, sess.Length-sess.Offset+1))
+	if int64(len(part)) > sess.Length-sess.Offset {
+		renderError(w, r, http.StatusRequestEntityTooLarge, "too_large", "the body runs past Upload-Length")
+		return
+	}
+	if len(part) > 0 {
+		if err := putTusPart(ctx, uploads, tusPartName(id, sess.Parts), part); err != nil {
+			reqLog(r).Errorw("could not store upload part", "id", id, zap.Error(err))
+			renderError(w, r, http.StatusInternalServerError, "internal", "upload error")
+			return
+		}
+		sess.Parts++
+		sess.Offset += int64(len(part))
+		if err := putTusObject(ctx, uploads, tusSessionName(id), sess); err != nil {
+			reqLog(r).Errorw("could not update upload", "id", id, zap.Error(err))
+			renderError(w, r, http.StatusInternalServerError, "internal", "upload error")
+			return
+		}
+	}
+	if readErr != nil {
+		// The client went away; it resumes from the new offset.
+		reqLog(r).Infow("upload cut short", "id", id, "offset", sess.Offset, zap.Error(readErr))
+		return
+	}
+
+	w.Header().Set("Upload-Offset", strconv.FormatInt(sess.Offset, 10))
+	if sess.Offset < sess.Length {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	name, err := finishTusUpload(ctx, uploads, id, sess)
+	var dup *wallpapers.DuplicateError
+	var rejected *wallpapers.RejectedError
+	var pe *paramError
+	switch {
+	case errors.As(err, &dup):
+		renderError(w, r, http.StatusConflict, "duplicate", fmt.Sprintf("the same content is already stored as %q", dup.Existing))
+	case errors.As(err, &rejected):
+		renderError(w, r, http.StatusUnprocessableEntity, "rejected", rejected.Error())
+	case errors.As(err, &pe):
+		renderBadRequest(w, r, "invalid_upload", err)
+	case err != nil:
+		reqLog(r).Errorw("error during upload", "id", id, zap.Error(err))
+		renderError(w, r, http.StatusInternalServerError, "internal", "upload error")
+	default:
+		w.Header().Set("Wallpaper-Key", name)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// tusDeleteHandler abandons an upload.
+func tusDeleteHandler(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	defer tusLock(id)()
+	uploads, sess, ok := loadTusSession(w, r, id)
+	if !ok {
+		return
+	}
+
+	if err := deleteTusUpload(r.Context(), uploads, id, sess); err != nil {
+		reqLog(r).Errorw("could not delete upload", "id", id, zap.Error(err))
+		renderError(w, r, http.StatusInternalServerError, "internal", "delete error")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// finishTusUpload joins the parts of a complete upload and stores them.
+// The upload is discarded whether or not the content is accepted, as
+// sending it again would not change the outcome.
+func finishTusUpload(ctx context.Context, uploads wallpapers.Store, id string, sess *tusSession) (string, error) {
+	var content bytes.Buffer
+	content.Grow(int(sess.Length))
+	for i := range sess.Parts {
+		part, err := wallpapers.DownloadFile(wallpapers.ContextWithStore(ctx, uploads), tusPartName(id, i))
+		if err != nil {
+			return "", fmt.Errorf("could not read part %d of upload %s: %w", i, id, err)
+		}
+		content.Write(part)
+	}
+
+	name, err := storeUpload(ctx, sess.Name, content.Bytes())
+	if err := deleteTusUpload(context.WithoutCancel(ctx), uploads, id, sess); err != nil {
+		ctxLog(ctx).Warnw("could not delete finished upload", "id", id, zap.Error(err))
+	}
+	return name, err
+}
+
+// deleteTusUpload removes an upload's parts, then its session.
+func deleteTusUpload(ctx context.Context, uploads wallpapers.Store, id string, sess *tusSession) error {
+	for i := range sess.Parts {
+		if err := uploads.Delete(ctx, tusPartName(id, i)); err != nil && !errors.Is(err, storage.ErrObjectNotExist) {
+			return err
+		}
+	}
+	if err := uploads.Delete(ctx, tusSessionName(id)); err != nil {
+		return err
+	}
+	tusLocks.Delete(id)
+	return nil
+}
+
+// cleanupUploads discards uploads untouched for longer than tusSessionTTL.
+func cleanupUploads(ctx context.Context) error {
+	uploads, err := wallpapers.PendingUploads(wallpapers.ContextWithStore(ctx, wallpapers.DefaultStore()))
+	if errors.Is(err, wallpapers.ErrNoSubStore) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var stale []string
+	for f, err := range uploads.List(ctx) {
+		if err != nil {
+			return err
+		}
+		id, ok := strings.CutSuffix(f.Name, ".json")
+		if ok && time.Since(f.Updated) > tusSessionTTL {
+			stale = append(stale, id)
+		}
+	}
+
+	for _, id := range stale {
+		sess, err := readTusSession(ctx, uploads, id)
+		if err != nil {
+			return err
+		}
+		if err := deleteTusUpload(ctx, uploads, id, sess); err != nil {
+			return err
+		}
+		ctxLog(ctx).Infow("discarded stale upload", "id", id, "name", sess.Name)
+	}
+	return nil
+}
+
+// loadTusSession reads the session of the upload id, writing an error
+// response if it cannot.
+func loadTusSession(w http.ResponseWriter, r *http.Request, id string) (wallpapers.Store, *tusSession, bool) {
+	uploads, err := wallpapers.PendingUploads(r.Context())
+	if err == nil {
+		var sess *tusSession
+		sess, err = readTusSession(r.Context(), uploads, id)
+		if err == nil {
+			return uploads, sess, true
+		}
+	}
+
+	if errors.Is(err, storage.ErrObjectNotExist) || errors.Is(err, wallpapers.ErrNoSubStore) {
+		renderError(w, r, http.StatusNotFound, "not_found", "no such upload")
+		return nil, nil, false
+	}
+	reqLog(r).Errorw("could not read upload", "id", id, zap.Error(err))
+	renderError(w, r, http.StatusInternalServerError, "internal", "retrieval error")
+	return nil, nil, false
+}
+
+func readTusSession(ctx context.Context, uploads wallpapers.Store, id string) (*tusSession, error) {
+	if !isUploadID(id) {
+		return nil, fmt.Errorf("invalid upload ID %q: %w", id, storage.ErrObjectNotExist)
+	}
+	dat, err := wallpapers.DownloadFile(wallpapers.ContextWithStore(ctx, uploads), tusSessionName(id))
+	if err != nil {
+		return nil, err
+	}
+	var sess tusSession
+	if err := json.Unmarshal(dat, &sess); err != nil {
+		return nil, fmt.Errorf("could not parse upload %s: %w", id, err)
+	}
+	return &sess, nil
+}
+
+func putTusObject(ctx context.Context, uploads wallpapers.Store, name string, v any) error {
+	dat, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return putTusPart(ctx, uploads, name, dat)
+}
+
+func putTusPart(ctx context.Context, uploads wallpapers.Store, name string, content []byte) error {
+	wc, err := uploads.NewWriter(ctx, name, wallpapers.GetChecksums(content), wallpapers.ObjectUpdate{})
+	if err != nil {
+		return err
+	}
+	if _, err := wc.Write(content); err != nil {
+		wc.Close()
+		return err
+	}
+	return wc.Close()
+}
+
+// newUploadID returns a random upload ID.
+func newUploadID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// isUploadID reports whether id could have come from newUploadID, so
+// that IDs never name other files in the sub store.
+func isUploadID(id string) bool {
+	_, err := hex.DecodeString(id)
+	return err == nil && len(id) == 32
+}
+
+// parseTusMetadata parses an Upload-Metadata header: comma separated keys,
+// each followed by a space and its base64 encoded value.
+func parseTusMetadata(v string) (map[string]string, error) {
+	meta := map[string]string{}
+	for _, pair := range strings.Split(v, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		key, enc, _ := strings.Cut(pair, " ")
+		val, err := base64.StdEncoding.DecodeString(enc)
+		if err != nil {
+			return nil, invalidParam("Upload-Metadata", "the value of %q is not base64", key)
+		}
+		meta[key] = string(val)
+	}
+	return meta, nil
+}
@@ -0,0 +1,308 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"image"
+	"image/png"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/icco/wallpapers"
+	"github.com/icco/wallpapers/cmd/server/static"
+)
+
+// serveRoutes serves the routes with s as the default store and the API token
+// "secret", and returns the server's URL.
+func serveRoutes(t *testing.T, s wallpapers.Store) string {
+	t.Helper()
+	oldStore, oldToken := wallpapers.DefaultStore(), apiToken
+	wallpapers.SetStore(s)
+	apiToken = "secret"
+	t.Cleanup(func() {
+		afterUploads.Wait()
+		wallpapers.SetStore(oldStore)
+		apiToken = oldToken
+		listingsMu.Lock()
+		delete(listings, s)
+		listingsMu.Unlock()
+	})
+
+	r := chi.NewRouter()
+	routes(r, s, http.FS(static.Assets), newBroker())
+	srv := httptest.NewServer(r)
+	t.Cleanup(srv.Close)
+	return srv.URL
+}
+
+// tusDo sends a tus request with the API token and returns the response,
+// with its body read and closed.
+func tusDo(t *testing.T, method, url string, headers map[string]string, body []byte) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(method, url, bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer secret")
+	req.Header.Set("Tus-Resumable", tusVersion)
+	if body != nil {
+		req.Header.Set("Content-Type", tusContentType)
+	}
+	for k, v := range headers {
+		if v == "" {
+			req.Header.Del(k)
+			continue
+		}
+		req.Header.Set(k, v)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	return resp
+}
+
+func tusFilename(name string) string {
+	return "filename " + base64.StdEncoding.EncodeToString([]byte(name))
+}
+
+// createTus starts an upload of content named name and returns its URL.
+func createTus(t *testing.T, base, name string, length int) string {
+	t.Helper()
+	resp := tusDo(t, http.MethodPost, base+"/upload", map[string]string{
+		"Upload-Length":   strconv.Itoa(length),
+		"Upload-Metadata": tusFilename(name),
+	}, nil)
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("create status = %d, want %d", resp.StatusCode, http.StatusCreated)
+	}
+	return base + resp.Header.Get("Location")
+}
+
+func validPNG(t *testing.T) []byte {
+	t.Helper()
+	img := image.NewGray(image.Rect(0, 0, wallpapers.DefaultValidation.MinWidth, wallpapers.DefaultValidation.MinHeight))
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestTusUpload(t *testing.T) {
+	s := wallpapers.NewMemoryStore()
+	putFile(t, s, "a.jpg")
+	base := serveRoutes(t, s)
+	content := validPNG(t)
+	half := len(content) / 2
+
+	resp := tusDo(t, http.MethodOptions, base+"/upload", map[string]string{"Authorization": "", "Tus-Resumable": ""}, nil)
+	if resp.StatusCode != http.StatusNoContent || resp.Header.Get("Tus-Version") != tusVersion || resp.Header.Get("Tus-Max-Size") == "" {
+		t.Fatalf("OPTIONS = %d %v", resp.StatusCode, resp.Header)
+	}
+
+	url := createTus(t, base, "My New.PNG", len(content))
+	offset := func() string {
+		t.Helper()
+		resp := tusDo(t, http.MethodHead, url, nil, nil)
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("HEAD status = %d", resp.StatusCode)
+		}
+		return resp.Header.Get("Upload-Offset")
+	}
+	if got := offset(); got != "0" {
+		t.Errorf("offset of a new upload = %s, want 0", got)
+	}
+
+	resp = tusDo(t, http.MethodPatch, url, map[string]string{"Upload-Offset": "0"}, content[:half])
+	if resp.StatusCode != http.StatusNoContent || resp.Header.Get("Upload-Offset") != strconv.Itoa(half) {
+		t.Fatalf("first PATCH = %d at %s", resp.StatusCode, resp.Header.Get("Upload-Offset"))
+	}
+	if got := offset(); got != strconv.Itoa(half) {
+		t.Errorf("offset after the first part = %s, want %d", got, half)
+	}
+
+	// A client that lost the response sends the first part again.
+	resp = tusDo(t, http.MethodPatch, url, map[string]string{"Upload-Offset": "0"}, content[:half])
+	if resp.StatusCode != http.StatusConflict || resp.Header.Get("Upload-Offset") != strconv.Itoa(half) {
+		t.Errorf("resent PATCH = %d at %s, want %d at %d", resp.StatusCode, resp.Header.Get("Upload-Offset"), http.StatusConflict, half)
+	}
+
+	resp = tusDo(t, http.MethodPatch, url, map[string]string{"Upload-Offset": strconv.Itoa(half)}, content[half:])
+	if resp.StatusCode != http.StatusNoContent || resp.Header.Get("Wallpaper-Key") != "mynew.png" {
+		t.Fatalf("last PATCH = %d, key %q", resp.StatusCode, resp.Header.Get("Wallpaper-Key"))
+	}
+
+	ctx := wallpapers.ContextWithStore(context.Background(), s)
+	got, err := wallpapers.DownloadFile(ctx, "mynew.png")
+	if err != nil || !bytes.Equal(got, content) {
+		t.Fatalf("stored %d bytes, %v; want the %d uploaded", len(got), err, len(content))
+	}
+	entries, err := wallpapers.AuditEntries(ctx, wallpapers.AuditQuery{Limit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Actor != "api-token@127.0.0.1" {
+		t.Errorf("last audit entry = %+v, want actor api-token@127.0.0.1", entries)
+	}
+
+	if resp := tusDo(t, http.MethodHead, url, nil, nil); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("HEAD of a finished upload = %d, want %d", resp.StatusCode, http.StatusNotFound)
+	}
+	uploads, err := wallpapers.PendingUploads(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for f := range uploads.List(ctx) {
+		t.Errorf("%s left in the uploads store", f.Name)
+	}
+}
+
+func TestTusRequests(t *testing.T) {
+	content := validPNG(t)
+
+	for _, tc := range []struct {
+		name string
+		// send makes the requests, given the server's URL, and returns the
+		// last response.
+		send       func(t *testing.T, base string) *http.Response
+		wantStatus int
+	}{
+		{
+			name: "create without token",
+			send: func(t *testing.T, base string) *http.Response {
+				return tusDo(t, http.MethodPost, base+"/upload", map[string]string{"Authorization": "", "Upload-Length": "10", "Upload-Metadata": tusFilename("new.png")}, nil)
+			},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name: "create another version",
+			send: func(t *testing.T, base string) *http.Response {
+				return tusDo(t, http.MethodPost, base+"/upload", map[string]string{"Tus-Resumable": "0.2.2", "Upload-Length": "10", "Upload-Metadata": tusFilename("new.png")}, nil)
+			},
+			wantStatus: http.StatusPreconditionFailed,
+		},
+		{
+			name: "create without length",
+			send: func(t *testing.T, base string) *http.Response {
+				return tusDo(t, http.MethodPost, base+"/upload", map[string]string{"Upload-Metadata": tusFilename("new.png")}, nil)
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "create over the limit",
+			send: func(t *testing.T, base string) *http.Response {
+				return tusDo(t, http.MethodPost, base+"/upload", map[string]string{"Upload-Length": strconv.FormatInt(uploadLimit()+1, 10), "Upload-Metadata": tusFilename("new.png")}, nil)
+			},
+			wantStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name: "create with a path",
+			send: func(t *testing.T, base string) *http.Response {
+				return tusDo(t, http.MethodPost, base+"/upload", map[string]string{"Upload-Length": "10", "Upload-Metadata": tusFilename("../new.png")}, nil)
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "create with metadata that is not base64",
+			send: func(t *testing.T, base string) *http.Response {
+				return tusDo(t, http.MethodPost, base+"/upload", map[string]string{"Upload-Length": "10", "Upload-Metadata": "filename new.png"}, nil)
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "patch unknown upload",
+			send: func(t *testing.T, base string) *http.Response {
+				return tusDo(t, http.MethodPatch, base+"/upload/0123456789abcdef0123456789abcdef", map[string]string{"Upload-Offset": "0"}, content)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name: "patch another file in the uploads store",
+			send: func(t *testing.T, base string) *http.Response {
+				return tusDo(t, http.MethodPatch, base+"/upload/a.jpg", map[string]string{"Upload-Offset": "0"}, content)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name: "patch with another content type",
+			send: func(t *testing.T, base string) *http.Response {
+				url := createTus(t, base, "new.png", len(content))
+				return tusDo(t, http.MethodPatch, url, map[string]string{"Upload-Offset": "0", "Content-Type": "image/png"}, content)
+			},
+			wantStatus: http.StatusUnsupportedMediaType,
+		},
+		{
+			name: "patch past the length",
+			send: func(t *testing.T, base string) *http.Response {
+				url := createTus(t, base, "new.png", 10)
+				return tusDo(t, http.MethodPatch, url, map[string]string{"Upload-Offset": "0"}, content)
+			},
+			wantStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name: "upload of stored content",
+			send: func(t *testing.T, base string) *http.Response {
+				url := createTus(t, base, "b.jpg", len("a.jpg"))
+				return tusDo(t, http.MethodPatch, url, map[string]string{"Upload-Offset": "0"}, []byte("a.jpg"))
+			},
+			wantStatus: http.StatusConflict,
+		},
+		{
+			name: "upload rejected",
+			send: func(t *testing.T, base string) *http.Response {
+				url := createTus(t, base, "new.png", len("not a png"))
+				return tusDo(t, http.MethodPatch, url, map[string]string{"Upload-Offset": "0"}, []byte("not a png"))
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "delete",
+			send: func(t *testing.T, base string) *http.Response {
+				url := createTus(t, base, "new.png", len(content))
+				tusDo(t, http.MethodPatch, url, map[string]string{"Upload-Offset": "0"}, content[:10])
+				if resp := tusDo(t, http.MethodDelete, url, nil, nil); resp.StatusCode != http.StatusNoContent {
+					t.Errorf("DELETE status = %d, want %d", resp.StatusCode, http.StatusNoContent)
+				}
+				return tusDo(t, http.MethodHead, url, nil, nil)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s := wallpapers.NewMemoryStore()
+			putFile(t, s, "a.jpg")
+			base := serveRoutes(t, s)
+
+			if resp := tc.send(t, base); resp.StatusCode != tc.wantStatus {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tc.wantStatus)
+			}
+
+			ctx := wallpapers.ContextWithStore(context.Background(), s)
+			files, err := wallpapers.GetAll(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(files) != 1 || files[0].Name != "a.jpg" {
+				t.Errorf("files = %v, want only a.jpg", files)
+			}
+			if tc.wantStatus == http.StatusConflict || tc.wantStatus == http.StatusUnprocessableEntity {
+				uploads, err := wallpapers.PendingUploads(ctx)
+				if err != nil {
+					t.Fatal(err)
+				}
+				for f := range uploads.List(ctx) {
+					t.Errorf("%s left in the uploads store after the upload finished", f.Name)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/icco/wallpapers"
+	"github.com/icco/wallpapers/notify"
+	"go.uber.org/zap"
+)
+
+// afterUploadTimeout bounds the work done in the background after an
+// upload.
+const afterUploadTimeout = 5 * time.Minute
+
+// maxUploadSize caps uploads through the API, which are held in memory
+// until they are stored. Larger files can be added with the uploader.
+var maxUploadSize int64 = 64 << 20
+
+// uploadLimit is the largest upload accepted through the API.
+func uploadLimit() int64 {
+	return min(maxUploadSize, wallpapers.DefaultValidation.MaxSize)
+}
+
+var (
+	// notifier, warmSizes and mirrorTo are where uploads are announced,
+	// which renditions are requested for them and where the default
+	// collection is copied, as configured for the uploader.
+	notifier  *notify.Notifier
+	warmSizes []wallpapers.Size
+	mirrorTo  wallpapers.Store
+
+	// afterUploads tracks the background work of afterUpload.
+	afterUploads sync.WaitGroup
+)
+
+// afterUpload does what the uploader does once a new wallpaper is in the
+// default collection: it announces it, requests its renditions and copies
+// it to the mirror. This happens in the background, and failures are only
+// logged.
+func afterUpload(ctx context.Context, name string) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), afterUploadTimeout)
+	afterUploads.Add(1)
+	go func() {
+		defer afterUploads.Done()
+		defer cancel()
+
+		if err := notifier.NewWallpaper(ctx, name); err != nil {
+			ctxLog(ctx).Warnw("could not send notification", "file", name, zap.Error(err))
+		}
+		if err := wallpapers.Warm(ctx, name, warmSizes); err != nil {
+			ctxLog(ctx).Warnw("could not warm renditions", "file", name, zap.Error(err))
+		}
+		if mirrorTo != nil {
+			if err := wallpapers.MirrorFile(ctx, wallpapers.StoreFor(ctx), mirrorTo, name); err != nil {
+				ctxLog(ctx).Warnw("could not mirror file", "file", name, zap.Error(err))
+			}
+		}
+	}()
+}
+
+// formatUploadName formats the name of an upload as the uploader formats
+// local files, refusing names that are not a file at the top of a
+// collection.
+func formatUploadName(raw string) (string, error) {
+	if raw == "" || strings.ContainsAny(raw, `/\`) {
+		return "", invalidParam("name", "name must name a file, without path separators")
+	}
+	name := wallpapers.FormatName(raw)
+	if strings.TrimSuffix(name, filepath.Ext(name)) == "" {
+		return "", invalidParam("name", "name must have letters or digits")
+	}
+	return name, nil
+}
+
+// storeUpload adds content to the collection on ctx as the uploader would:
+// content already in the collection is refused, a different picture with
+// the same name gets a hash added to its name, and the new wallpaper is
+// announced, warmed and mirrored. It returns the name content was stored
+// under.
+func storeUpload(ctx context.Context, name string, content []byte, opts ...wallpapers.UploadOption) (string, error) {
+	// Dedupe against the cached listing rather than listing the bucket for
+	// every upload.
+	files, err := listFiles(ctx)
+	if err != nil {
+		return "", err
+	}
+	opts = append(opts, wallpapers.WithIndex(wallpapers.IndexFiles(files)))
+	name, err = wallpapers.UploadNew(ctx, name, content, opts...)
+	if err != nil {
+		return "", err
+	}
+
+	refreshAfterChange(ctx)
+	afterUpload(ctx, name)
+	return name, nil
+}
@@ -0,0 +1,391 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	chi "github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/icco/wallpapers"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// maxUsageReferers and maxUsageImages cap how many referer hosts and files
+// are counted separately, in each map of counts by file, so a crawler sending made up referers or names
+// cannot grow the counts without bound.
+const (
+	maxUsageReferers = 1000
+	maxUsageImages   = 10000
+
+	// usageKey is the Redis hash holding the shared request count and when
+	// counting started. Each map of counts is a hash of its own, named
+	// usageKey, a colon and the map's JSON name.
+	usageKey = "wallpapers:usage"
+)
+
+// usageStats is the rollup served at /stats/usage.json. Nothing that
+// identifies a client is kept: referers are reduced to their host and user
+// agents to a class.
+type usageStats struct {
+	Since     time.Time        `json:"since"`
+	Requests  int64            `json:"requests"`
+	Endpoints map[string]int64 `json:"endpoints"`
+	Referers  map[string]int64 `json:"referers"`
+	Agents    map[string]int64 `json:"agents"`
+	Images    map[string]int64 `json:"images"`
+
+	// Views counts the image pages and wallhaven details shown for each
+	// file, and Downloads its original file downloads.
+	Views     map[string]int64 `json:"views"`
+	Downloads map[string]int64 `json:"downloads"`
+}
+
+// usage counts requests by route since the server started. When Redis is
+// set, pending holds the counts not yet added to the shared ones, which are
+// what /stats/usage.json serves, so they survive restarts and cover every
+// replica. Without Redis the counts are this process's alone.
+var usage = struct {
+	sync.Mutex
+	usageStats
+	pending usageStats
+
+	// shared holds every replica's views and downloads as of the last
+	// flush, once there has been one.
+	shared *usageStats
+}{usageStats: newUsageStats(), pending: newUsageStats()}
+
+func newUsageStats() usageStats {
+	return usageStats{
+		Since:     time.Now(),
+		Endpoints: map[string]int64{},
+		Referers:  map[string]int64{},
+		Agents:    map[string]int64{},
+		Images:    map[string]int64{},
+		Views:     map[string]int64{},
+		Downloads: map[string]int64{},
+	}
+}
+
+// usageMiddleware counts each routed request once it has been handled,
+// when chi knows which route it matched. Files are only counted when the
+// request succeeded, so requests for names that do not exist are not.
+func usageMiddleware(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		h.ServeHTTP(ww, r)
+
+		rctx := chi.RouteContext(r.Context())
+		if rctx == nil || rctx.RoutePattern() == "" {
+			return
+		}
+		image := ""
+		if status := ww.Status(); status >= 200 && status < 400 {
+			image = rctx.URLParam("name")
+			if image == "" {
+				image = rctx.URLParam("id")
+			}
+		}
+		recordUsage(rctx.RoutePattern(), refererHost(r.Referer()), agentClass(r.UserAgent()), image)
+	})
+}
+
+func recordUsage(endpoint, referer, agent, image string) {
+	usage.Lock()
+	defer usage.Unlock()
+
+	if referer != "" {
+		if _, ok := usage.Referers[referer]; !ok && len(usage.Referers) >= maxUsageReferers {
+			referer = "other"
+		}
+	}
+	if image != "" {
+		if _, ok := usage.Images[image]; !ok && len(usage.Images) >= maxUsageImages {
+			image = "other"
+		}
+	}
+
+	for _, u := range []*usageStats{&usage.usageStats, &usage.pending} {
+		u.Requests++
+		u.Endpoints[endpoint]++
+		u.Agents[agent]++
+		if referer != "" {
+			u.Referers[referer]++
+		}
+		if image != "" {
+			u.Images[image]++
+		}
+	}
+}
+
+// recordView counts a view of the named file.
+func recordView(name string) {
+	recordFileUse(name, func(u *usageStats) map[string]int64 { return u.Views })
+}
+
+// recordDownload counts a download of the named file.
+func recordDownload(name string) {
+	recordFileUse(name, func(u *usageStats) map[string]int64 { return u.Downloads })
+}
+
+func recordFileUse(name string, counts func(*usageStats) map[string]int64) {
+	usage.Lock()
+	defer usage.Unlock()
+
+	if _, ok := counts(&usage.usageStats)[name]; !ok && len(counts(&usage.usageStats)) >= maxUsageImages {
+		name = "other"
+	}
+	for _, u := range []*usageStats{&usage.usageStats, &usage.pending} {
+		counts(u)[name]++
+	}
+}
+
+// usageHashes returns the Redis hash of each map of counts in u, keyed by
+// the name of the hash.
+func usageHashes(u *usageStats) map[string]map[string]int64 {
+	return map[string]map[string]int64{
+		usageKey + ":endpoints": u.Endpoints,
+		usageKey + ":referers":  u.Referers,
+		usageKey + ":agents":    u.Agents,
+		usageKey + ":images":    u.Images,
+		usageKey + ":views":     u.Views,
+		usageKey + ":downloads": u.Downloads,
+	}
+}
+
+// flushUsage adds the pending counts to the shared ones in Redis. Referers
+// and files the shared counts have no room for are counted as other, as
+// recordUsage does. Counts that cannot be written are kept for the next
+// flush. The shared views and downloads are then read back, for sorting by
+// popularity.
+func flushUsage(ctx context.Context) error {
+	if sharedCache == nil {
+		return nil
+	}
+
+	usage.Lock()
+	pending := usage.pending
+	usage.pending = newUsageStats()
+	usage.Unlock()
+	if pending.Requests == 0 && len(pending.Views) == 0 && len(pending.Downloads) == 0 {
+		return loadPopularity(ctx)
+	}
+
+	capped := map[string]int{
+		usageKey + ":referers":  maxUsageReferers,
+		usageKey + ":images":    maxUsageImages,
+		usageKey + ":views":     maxUsageImages,
+		usageKey + ":downloads": maxUsageImages,
+	}
+	hashes := usageHashes(&pending)
+
+	// Find out which capped fields the shared counts already have before
+	// adding new ones.
+	lens := map[string]*redis.IntCmd{}
+	exists := map[string]map[string]*redis.BoolCmd{}
+	_, err := sharedCache.client.Pipelined(ctx, func(p redis.Pipeliner) error {
+		for key := range capped {
+			lens[key] = p.HLen(ctx, key)
+			exists[key] = map[string]*redis.BoolCmd{}
+			for field := range hashes[key] {
+				exists[key][field] = p.HExists(ctx, key, field)
+			}
+		}
+		return nil
+	})
+	if err == nil {
+		for key, limit := range capped {
+			n := lens[key].Val()
+			counts := map[string]int64{}
+			for field, count := range hashes[key] {
+				if !exists[key][field].Val() {
+					if n >= int64(limit) {
+						field = "other"
+					} else {
+						n++
+					}
+				}
+				counts[field] += count
+			}
+			hashes[key] = counts
+		}
+
+		_, err = sharedCache.client.TxPipelined(ctx, func(p redis.Pipeliner) error {
+			p.HSetNX(ctx, usageKey, "since", pending.Since.UTC().Format(time.RFC3339))
+			p.HIncrBy(ctx, usageKey, "requests", pending.Requests)
+			for key, counts := range hashes {
+				for field, count := range counts {
+					p.HIncrBy(ctx, key, field, count)
+				}
+			}
+			return nil
+		})
+	}
+	if err != nil {
+		// Put the counts back, so they are added next time.
+		usage.Lock()
+		defer usage.Unlock()
+		usage.pending.Since = pending.Since
+		usage.pending.Requests += pending.Requests
+		for key, counts := range usageHashes(&pending) {
+			into := usageHashes(&usage.pending)[key]
+			for field, count := range counts {
+				into[field] += count
+			}
+		}
+		return err
+	}
+
+	return loadPopularity(ctx)
+}
+
+// loadPopularity reads the shared views and downloads from Redis.
+func loadPopularity(ctx context.Context) error {
+	var views, downloads *redis.MapStringStringCmd
+	_, err := sharedCache.client.Pipelined(ctx, func(p redis.Pipeliner) error {
+		views = p.HGetAll(ctx, usageKey+":views")
+		downloads = p.HGetAll(ctx, usageKey+":downloads")
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	shared := newUsageStats()
+	for field, v := range views.Val() {
+		shared.Views[field], _ = strconv.ParseInt(v, 10, 64)
+	}
+	for field, v := range downloads.Val() {
+		shared.Downloads[field], _ = strconv.ParseInt(v, 10, 64)
+	}
+
+	usage.Lock()
+	usage.shared = &shared
+	usage.Unlock()
+	return nil
+}
+
+// popularity returns how many times each file has been downloaded and
+// viewed: every replica's counts as of the last flush when Redis is set,
+// and otherwise this process's. The maps must not be changed.
+func popularity() (downloads, views map[string]int64) {
+	usage.Lock()
+	defer usage.Unlock()
+	if usage.shared != nil {
+		return usage.shared.Downloads, usage.shared.Views
+	}
+	return maps.Clone(usage.Downloads), maps.Clone(usage.Views)
+}
+
+// sortPopular sorts files in place by downloads and then views, most
+// popular first if desc is set. Ties are broken by name, as
+// wallpapers.SortFiles does.
+func sortPopular(files []*wallpapers.File, desc bool) {
+	downloads, views := popularity()
+	slices.SortStableFunc(files, func(a, b *wallpapers.File) int {
+		c := cmp.Or(
+			cmp.Compare(downloads[a.Name], downloads[b.Name]),
+			cmp.Compare(views[a.Name], views[b.Name]),
+			strings.Compare(a.Name, b.Name),
+		)
+		if desc {
+			return -c
+		}
+		return c
+	})
+}
+
+// sharedUsage reads the shared counts from Redis.
+func sharedUsage(ctx context.Context) (usageStats, error) {
+	stats := newUsageStats()
+	var totals *redis.MapStringStringCmd
+	hashes := map[string]*redis.MapStringStringCmd{}
+	_, err := sharedCache.client.Pipelined(ctx, func(p redis.Pipeliner) error {
+		totals = p.HGetAll(ctx, usageKey)
+		for key := range usageHashes(&stats) {
+			hashes[key] = p.HGetAll(ctx, key)
+		}
+		return nil
+	})
+	if err != nil {
+		return stats, err
+	}
+
+	if since, err := time.Parse(time.RFC3339, totals.Val()["since"]); err == nil {
+		stats.Since = since
+	}
+	stats.Requests, _ = strconv.ParseInt(totals.Val()["requests"], 10, 64)
+	for key, into := range usageHashes(&stats) {
+		for field, v := range hashes[key].Val() {
+			into[field], _ = strconv.ParseInt(v, 10, 64)
+		}
+	}
+	return stats, nil
+}
+
+// refererHost returns the host of a referer, or "" if there is none.
+func refererHost(referer string) string {
+	if referer == "" {
+		return ""
+	}
+	u, err := url.Parse(referer)
+	if err != nil || u.Host == "" {
+		return "invalid"
+	}
+	return strings.ToLower(u.Hostname())
+}
+
+// agentClass sorts user agents into browser, bot, cli, none and other.
+func agentClass(ua string) string {
+	l := strings.ToLower(ua)
+	switch {
+	case ua == "":
+		return "none"
+	case strings.Contains(l, "bot"), strings.Contains(l, "crawler"), strings.Contains(l, "spider"):
+		return "bot"
+	case strings.HasPrefix(l, "curl/"), strings.HasPrefix(l, "wget/"), strings.HasPrefix(l, "go-http-client/"), strings.HasPrefix(l, "python"):
+		return "cli"
+	case strings.HasPrefix(l, "mozilla/"):
+		return "browser"
+	default:
+		return "other"
+	}
+}
+
+// usageHandler serves the request counts, shared ones if Redis is set and
+// otherwise those since the server started.
+func usageHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "no-store")
+	if sharedCache != nil {
+		stats, err := sharedUsage(r.Context())
+		if err == nil {
+			if err := Renderer.JSON(w, http.StatusOK, stats); err != nil {
+				reqLog(r).Errorw("error during usage render", zap.Error(err))
+			}
+			return
+		}
+		reqLog(r).Warnw("could not read shared usage", zap.Error(err))
+	}
+
+	usage.Lock()
+	stats := usage.usageStats
+	stats.Endpoints = maps.Clone(usage.Endpoints)
+	stats.Referers = maps.Clone(usage.Referers)
+	stats.Agents = maps.Clone(usage.Agents)
+	stats.Images = maps.Clone(usage.Images)
+	stats.Views = maps.Clone(usage.Views)
+	stats.Downloads = maps.Clone(usage.Downloads)
+	usage.Unlock()
+
+	if err := Renderer.JSON(w, http.StatusOK, stats); err != nil {
+		reqLog(r).Errorw("error during usage render", zap.Error(err))
+	}
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net/url"
+	"testing"
+
+	"github.com/icco/wallpapers"
+)
+
+func TestFlushUsage(t *testing.T) {
+	ctx := context.Background()
+	f := newTestRedis(t)
+	sharedCache = f.cache
+	t.Cleanup(func() {
+		sharedCache = nil
+		usage.Lock()
+		usage.usageStats = newUsageStats()
+		usage.pending = newUsageStats()
+		usage.shared = nil
+		usage.Unlock()
+	})
+
+	// The shared referers are already full, apart from one known host.
+	for i := range maxUsageReferers - 1 {
+		f.srv.HSet(usageKey+":referers", fmt.Sprintf("host%d.example.com", i), "1")
+	}
+	f.srv.HSet(usageKey+":referers", "known.example.com", "5")
+
+	for _, step := range []struct {
+		name     string
+		record   [][4]string
+		down     bool
+		wantErr  bool
+		requests int64
+		endpoint int64
+		referers map[string]int64
+	}{
+		{
+			name: "counts are added to the shared ones",
+			record: [][4]string{
+				{"/fit/{name}", "known.example.com", "browser", "a.jpg"},
+				{"/fit/{name}", "new.example.com", "cli", "a.jpg"},
+			},
+			requests: 2,
+			endpoint: 2,
+			referers: map[string]int64{"known.example.com": 6, "other": 1},
+		},
+		{
+			name:     "nothing pending writes nothing",
+			requests: 2,
+			endpoint: 2,
+			referers: map[string]int64{"known.example.com": 6, "other": 1},
+		},
+		{
+			name:    "outage keeps the counts",
+			record:  [][4]string{{"/fit/{name}", "known.example.com", "browser", "a.jpg"}},
+			down:    true,
+			wantErr: true,
+		},
+		{
+			name:     "kept counts are added once Redis is back",
+			requests: 3,
+			endpoint: 3,
+			referers: map[string]int64{"known.example.com": 7, "other": 1},
+		},
+	} {
+		t.Run(step.name, func(t *testing.T) {
+			for _, r := range step.record {
+				recordUsage(r[0], r[1], r[2], r[3])
+			}
+			if step.down {
+				f.srv.SetError("LOADING")
+				t.Cleanup(func() { f.srv.SetError("") })
+			}
+
+			if err := flushUsage(ctx); (err != nil) != step.wantErr {
+				t.Fatalf("flushUsage error = %v, want error %v", err, step.wantErr)
+			}
+			if step.wantErr {
+				return
+			}
+
+			got, err := sharedUsage(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Requests != step.requests || got.Endpoints["/fit/{name}"] != step.endpoint || got.Images["a.jpg"] != step.requests {
+				t.Errorf("shared usage = %+v, want %d requests", got, step.requests)
+			}
+			referers := map[string]int64{}
+			for host := range step.referers {
+				referers[host] = got.Referers[host]
+			}
+			if !maps.Equal(referers, step.referers) || len(got.Referers) != maxUsageReferers+1 {
+				t.Errorf("shared referers = %v of %d, want %v of %d", referers, len(got.Referers), step.referers, maxUsageReferers+1)
+			}
+		})
+	}
+}
+
+func TestSortPopular(t *testing.T) {
+	t.Cleanup(func() {
+		sharedCache = nil
+		usage.Lock()
+		usage.usageStats = newUsageStats()
+		usage.pending = newUsageStats()
+		usage.shared = nil
+		usage.Unlock()
+	})
+
+	for _, name := range []string{"c.jpg", "c.jpg", "b.jpg"} {
+		recordDownload(name)
+	}
+	for _, name := range []string{"b.jpg", "d.jpg", "d.jpg", "d.jpg"} {
+		recordView(name)
+	}
+
+	for _, step := range []struct {
+		name   string
+		shared bool
+		order  string
+		want   string
+	}{
+		{name: "downloads then views", want: "[c.jpg b.jpg d.jpg a.jpg]"},
+		{name: "ascending", order: "asc", want: "[a.jpg d.jpg b.jpg c.jpg]"},
+		// Another replica has counted ten views of a.jpg.
+		{name: "shared counts", shared: true, want: "[c.jpg b.jpg a.jpg d.jpg]"},
+	} {
+		t.Run(step.name, func(t *testing.T) {
+			if step.shared {
+				f := newTestRedis(t)
+				sharedCache = f.cache
+				f.srv.HSet(usageKey+":views", "a.jpg", "10")
+				if err := flushUsage(context.Background()); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			opts, err := listOptionsFrom(url.Values{"sort": {"popular"}, "order": {step.order}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			files := []*wallpapers.File{{Name: "a.jpg"}, {Name: "b.jpg"}, {Name: "c.jpg"}, {Name: "d.jpg"}}
+			sorted, err := opts.sorted(files)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, f := range sorted {
+				got = append(got, f.Name)
+			}
+			if fmt.Sprint(got) != step.want {
+				t.Errorf("sorted = %v, want %s", got, step.want)
+			}
+		})
+	}
+}
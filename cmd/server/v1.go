@@ -0,0 +1,279 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"github.com/go-chi/chi/v5"
+	"github.com/icco/wallpapers"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultPageSize = 100
+	maxPageSize     = 1000
+
+	// cursorMACSize is how many bytes of the cursor's HMAC are kept.
+	cursorMACSize = 12
+)
+
+// cursorKey signs cursors so clients cannot forge offsets. It is random
+// unless the server is configured with a cursor secret.
+var cursorKey = func() []byte {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return b
+}()
+
+// envelope is the response shape of every /v1 JSON endpoint. Its fields are
+// frozen: new fields may be added, but existing ones are never renamed,
+// removed or change type.
+type envelope struct {
+	Data       any       `json:"data"`
+	NextCursor string    `json:"next_cursor,omitempty"`
+	Error      *apiError `json:"error,omitempty"`
+}
+
+// v1Image is a wallpaper as /v1 returns it. It is copied from
+// wallpapers.File field by field, so changes to the library cannot change
+// the API. Like envelope, its fields are frozen.
+type v1Image struct {
+	Key          string    `json:"key"`
+	Type         string    `json:"type"`
+	Etag         string    `json:"etag"`
+	CDN          string    `json:"cdn"`
+	Thumbnail    string    `json:"thumbnail"`
+	Bucket       string    `json:"bucket,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	Video        *v1Video  `json:"video,omitempty"`
+	ColorProfile string    `json:"color_profile,omitempty"`
+	VariantOf    string    `json:"variant_of,omitempty"`
+	AltText      string    `json:"alt_text,omitempty"`
+	SourceURL    string    `json:"source_url,omitempty"`
+	Author       string    `json:"author,omitempty"`
+	License      string    `json:"license,omitempty"`
+}
+
+// v1Video is the video information of a v1Image.
+type v1Video struct {
+	Width    int     `json:"width,omitempty"`
+	Height   int     `json:"height,omitempty"`
+	Duration float64 `json:"duration,omitempty"`
+}
+
+// v1Stats is a summary of a collection as /v1/stats returns it.
+type v1Stats struct {
+	Count     int            `json:"count"`
+	TotalSize int64          `json:"total_size"`
+	Formats   map[string]int `json:"formats"`
+}
+
+func newV1Image(f *wallpapers.File) *v1Image {
+	img := &v1Image{
+		Key:          f.Name,
+		Type:         f.Type,
+		Etag:         f.Etag,
+		CDN:          f.FullRezURL,
+		Thumbnail:    f.ThumbnailURL,
+		Bucket:       f.Bucket,
+		CreatedAt:    f.Created,
+		UpdatedAt:    f.Updated,
+		ColorProfile: f.ColorProfile,
+		VariantOf:    f.VariantOf,
+		AltText:      f.AltText,
+		SourceURL:    f.SourceURL,
+		Author:       f.Author,
+		License:      f.License,
+	}
+	if f.Video != nil {
+		img.Video = &v1Video{Width: f.Video.Width, Height: f.Video.Height, Duration: f.Video.Duration}
+	}
+	return img
+}
+
+func newV1Stats(s *wallpapers.Stats) *v1Stats {
+	return &v1Stats{Count: s.Count, TotalSize: s.TotalSize, Formats: s.Formats}
+}
+
+func renderData(w http.ResponseWriter, r *http.Request, data any, next string) {
+	if err := Renderer.JSON(w, http.StatusOK, envelope{Data: data, NextCursor: next}); err != nil {
+		reqLog(r).Errorw("error during success render", "path", r.URL.Path, zap.Error(err))
+	}
+}
+
+// pageParams reads the limit and cursor query parameters. Cursors are opaque
+// to clients and encode the offset of the next page.
+func pageParams(r *http.Request) (int, int, error) {
+	q := r.URL.Query()
+
+	limit := defaultPageSize
+	if v := q.Get("limit"); v != "" {
+		var err error
+		limit, err = strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > maxPageSize {
+			return 0, 0, invalidParam("limit", "limit must be an integer between 1 and %d", maxPageSize)
+		}
+	}
+
+	offset := 0
+	if v := q.Get("cursor"); v != "" {
+		var err error
+		if offset, err = decodeCursor(v); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	return limit, offset, nil
+}
+
+// encodeCursor returns the cursor for offset: "o:" and the offset, followed
+// by a truncated HMAC of both.
+func encodeCursor(offset int) string {
+	msg := []byte("o:" + strconv.Itoa(offset))
+	return base64.RawURLEncoding.EncodeToString(append(msg, cursorMAC(msg)...))
+}
+
+// decodeCursor returns the offset in a cursor made by encodeCursor.
+func decodeCursor(v string) (int, error) {
+	invalid := invalidParam("cursor", "invalid cursor")
+	dec, err := base64.RawURLEncoding.DecodeString(v)
+	if err != nil || len(dec) <= cursorMACSize {
+		return 0, invalid
+	}
+
+	msg, mac := dec[:len(dec)-cursorMACSize], dec[len(dec)-cursorMACSize:]
+	if !hmac.Equal(mac, cursorMAC(msg)) {
+		return 0, invalid
+	}
+	n, ok := strings.CutPrefix(string(msg), "o:")
+	offset, err := strconv.Atoi(n)
+	if !ok || err != nil || offset < 0 {
+		return 0, invalid
+	}
+
+	return offset, nil
+}
+
+func cursorMAC(msg []byte) []byte {
+	h := hmac.New(sha256.New, cursorKey)
+	h.Write(msg)
+	return h.Sum(nil)[:cursorMACSize]
+}
+
+// paginate returns the page of files starting at offset and the cursor for the
+// following page, if there is one.
+func paginate(files []*wallpapers.File, limit, offset int) ([]*wallpapers.File, string) {
+	if offset >= len(files) {
+		return []*wallpapers.File{}, ""
+	}
+
+	end := min(offset+limit, len(files))
+	next := ""
+	if end < len(files) {
+		next = encodeCursor(end)
+	}
+
+	return files[offset:end], next
+}
+
+func v1ImagesHandler(w http.ResponseWriter, r *http.Request) {
+	limit, offset, err := pageParams(r)
+	if err != nil {
+		renderBadRequest(w, r, "bad_request", err)
+		return
+	}
+
+	opts, err := parseListOptions(r)
+	if err != nil {
+		renderBadRequest(w, r, "bad_request", err)
+		return
+	}
+
+	images, err := listFiles(r.Context())
+	if err != nil {
+		reqLog(r).Errorw("error during v1 images", zap.Error(err))
+		renderError(w, r, http.StatusInternalServerError, "internal", "retrieval error")
+		return
+	}
+
+	if opts.cacheable() && notModified(w, r, images) {
+		return
+	}
+
+	images, err = opts.sorted(images)
+	if err != nil {
+		renderBadRequest(w, r, "bad_request", err)
+		return
+	}
+	if query := r.URL.Query().Get("q"); query != "" {
+		images = searchFiles(r.Context(), images, query)
+	}
+
+	page, next := paginate(images, limit, offset)
+	v1 := make([]*v1Image, 0, len(page))
+	for _, f := range page {
+		v1 = append(v1, newV1Image(f))
+	}
+	data, err := opts.project(v1)
+	if err != nil {
+		reqLog(r).Errorw("error during v1 images projection", zap.Error(err))
+		renderError(w, r, http.StatusInternalServerError, "internal", "render error")
+		return
+	}
+
+	renderData(w, r, data, next)
+}
+
+// v1DeleteImageHandler deletes a wallpaper from the default collection, as
+// the gRPC service's Delete does.
+func v1DeleteImageHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	// chi leaves the parameter escaped when the path has an escaped
+	// slash, which must not get past the check.
+	name, err := url.PathUnescape(chi.URLParam(r, "name"))
+	if err != nil || name == "" || strings.ContainsAny(name, `/\`) {
+		renderBadRequest(w, r, "invalid_name", invalidParam("name", "name must name a file, without path separators"))
+		return
+	}
+
+	err = wallpapers.DeleteFile(ctx, name)
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		renderError(w, r, http.StatusNotFound, "not_found", "not found")
+		return
+	}
+	if err != nil {
+		reqLog(r).Errorw("error during v1 delete", "name", name, zap.Error(err))
+		renderError(w, r, http.StatusInternalServerError, "internal", "delete error")
+		return
+	}
+	refreshAfterChange(ctx)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func v1StatsHandler(w http.ResponseWriter, r *http.Request) {
+	images, err := listFiles(r.Context())
+	if err != nil {
+		reqLog(r).Errorw("error during v1 stats", zap.Error(err))
+		renderError(w, r, http.StatusInternalServerError, "internal", "retrieval error")
+		return
+	}
+
+	if notModified(w, r, images) {
+		return
+	}
+
+	renderData(w, r, newV1Stats(wallpapers.Summarize(images)), "")
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/icco/wallpapers"
+)
+
+// TestV1ImageJSON pins the /v1 image schema, which clients rely on.
+func TestV1ImageJSON(t *testing.T) {
+	created := time.Date(2024, 3, 10, 10, 0, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		name string
+		file *wallpapers.File
+		want string
+	}{
+		{
+			name: "image",
+			file: &wallpapers.File{
+				Name:         "a.jpg",
+				Type:         wallpapers.TypeImage,
+				Etag:         "CJ+2",
+				FullRezURL:   "https://example.com/a.jpg",
+				ThumbnailURL: "https://example.com/a.jpg?w=800",
+				Created:      created,
+				Updated:      created.Add(time.Hour),
+				CRC32C:       1234,
+				Size:         5678,
+				Metadata:     map[string]string{"secret": "x"},
+				ColorProfile: "Display P3",
+				VariantOf:    "b.jpg",
+				AltText:      "A lake",
+				Attribution:  wallpapers.Attribution{SourceURL: "https://example.com", Author: "nat", License: "CC-BY"},
+			},
+			want: `{"key":"a.jpg","type":"image","etag":"CJ+2","cdn":"https://example.com/a.jpg","thumbnail":"https://example.com/a.jpg?w=800","created_at":"2024-03-10T10:00:00Z","updated_at":"2024-03-10T11:00:00Z","color_profile":"Display P3","variant_of":"b.jpg","alt_text":"A lake","source_url":"https://example.com","author":"nat","license":"CC-BY"}`,
+		},
+		{
+			name: "video",
+			file: &wallpapers.File{
+				Name:    "b.mp4",
+				Type:    wallpapers.TypeVideo,
+				Bucket:  "other",
+				Created: created,
+				Updated: created,
+				Video:   &wallpapers.VideoInfo{Width: 1920, Height: 1080, Duration: 12.5},
+			},
+			want: `{"key":"b.mp4","type":"video","etag":"","cdn":"","thumbnail":"","bucket":"other","created_at":"2024-03-10T10:00:00Z","updated_at":"2024-03-10T10:00:00Z","video":{"width":1920,"height":1080,"duration":12.5}}`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := json.Marshal(newV1Image(tc.file))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tc.want {
+				t.Errorf("json = %s\nwant   %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCursor(t *testing.T) {
+	for _, offset := range []int{0, 1, 100, 123456} {
+		got, err := decodeCursor(encodeCursor(offset))
+		if err != nil || got != offset {
+			t.Errorf("decodeCursor(encodeCursor(%d)) = %d, %v", offset, got, err)
+		}
+	}
+
+	forged := encodeCursor(100)
+	for _, v := range []string{"", "bm9wZQ", forged[:len(forged)-2] + "AA"} {
+		if _, err := decodeCursor(v); err == nil {
+			t.Errorf("decodeCursor(%q) succeeded", v)
+		}
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	files := make([]*wallpapers.File, 5)
+	for i := range files {
+		files[i] = &wallpapers.File{Name: string(rune('a' + i))}
+	}
+
+	for _, tc := range []struct {
+		limit, offset int
+		want          int
+		next          int
+	}{
+		{limit: 2, offset: 0, want: 2, next: 2},
+		{limit: 2, offset: 4, want: 1, next: -1},
+		{limit: 10, offset: 0, want: 5, next: -1},
+		{limit: 2, offset: 5, want: 0, next: -1},
+		{limit: 2, offset: 50, want: 0, next: -1},
+	} {
+		page, next := paginate(files, tc.limit, tc.offset)
+		if len(page) != tc.want {
+			t.Errorf("paginate(%d, %d) returned %d files, want %d", tc.limit, tc.offset, len(page), tc.want)
+		}
+		wantNext := ""
+		if tc.next >= 0 {
+			wantNext = encodeCursor(tc.next)
+		}
+		if next != wantNext {
+			t.Errorf("paginate(%d, %d) next = %q, want %q", tc.limit, tc.offset, next, wantNext)
+		}
+	}
+}
+
+func TestV1Search(t *testing.T) {
+	s := wallpapers.NewMemoryStore()
+	for _, name := range []string{"a.jpg", "b.jpg", "c.png"} {
+		putFile(t, s, name)
+	}
+	base := serveRoutes(t, s)
+
+	resp, err := http.Get(base + "/v1/images?q=b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var page struct {
+		Data []*v1Image `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Data) != 1 || page.Data[0].Key != "b.jpg" {
+		t.Errorf("search for b = %+v, want b.jpg", page.Data)
+	}
+}
+
+func TestV1Delete(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		key        string
+		token      string
+		wantStatus int
+		want       []string
+	}{
+		{name: "delete", key: "a.jpg", token: "secret", wantStatus: http.StatusNoContent},
+		{name: "without token", key: "a.jpg", wantStatus: http.StatusUnauthorized, want: []string{"a.jpg"}},
+		{name: "wrong token", key: "a.jpg", token: "nope", wantStatus: http.StatusUnauthorized, want: []string{"a.jpg"}},
+		{name: "missing", key: "z.jpg", token: "secret", wantStatus: http.StatusNotFound, want: []string{"a.jpg"}},
+		{name: "audit entry", key: "audit%2F0001.json", token: "secret", wantStatus: http.StatusBadRequest, want: []string{"a.jpg"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s := wallpapers.NewMemoryStore()
+			putFile(t, s, "a.jpg")
+			base := serveRoutes(t, s)
+
+			req, err := http.NewRequest(http.MethodDelete, base+"/v1/images/"+tc.key, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tc.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tc.token)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tc.wantStatus {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tc.wantStatus)
+			}
+
+			ctx := wallpapers.ContextWithStore(context.Background(), s)
+			files, err := wallpapers.GetAll(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, f := range files {
+				got = append(got, f.Name)
+			}
+			if !slices.Equal(got, tc.want) {
+				t.Errorf("files = %q, want %q", got, tc.want)
+			}
+			if tc.wantStatus == http.StatusNoContent {
+				entries, err := wallpapers.AuditEntries(ctx, wallpapers.AuditQuery{Limit: 1})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(entries) != 1 || entries[0].Actor != "api-token@127.0.0.1" {
+					t.Errorf("last audit entry = %+v, want actor api-token@127.0.0.1", entries)
+				}
+			}
+		})
+	}
+}
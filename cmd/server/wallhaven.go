@@ -0,0 +1,255 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math/rand/v2"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"cloud.google.com/go/storage"
+	chi "github.com/go-chi/chi/v5"
+	"github.com/icco/wallpapers"
+	"go.uber.org/zap"
+)
+
+// The wallhaven.cc API is spoken by many wallpaper changers. This is the
+// subset of it they use to find and download wallpapers, mapped onto the
+// collection, so they can use this server by changing their base URL.
+//
+// See https://wallhaven.cc/help/api.
+
+const (
+	wallhavenPerPage    = 24
+	wallhavenTimeFormat = "2006-01-02 15:04:05"
+	wallhavenSeedChars  = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+)
+
+type wallhavenThumbs struct {
+	Large    string `json:"large"`
+	Original string `json:"original"`
+	Small    string `json:"small"`
+}
+
+// wallhavenWallpaper is a wallpaper in wallhaven's format. We do not track
+// views, favorites, colors or tags, and everything is safe for work.
+// Dimensions are those of the full resolution rendition at path.
+type wallhavenWallpaper struct {
+	ID         string          `json:"id"`
+	URL        string          `json:"url"`
+	ShortURL   string          `json:"short_url"`
+	Views      int             `json:"views"`
+	Favorites  int             `json:"favorites"`
+	Source     string          `json:"source"`
+	Purity     string          `json:"purity"`
+	Category   string          `json:"category"`
+	DimensionX int             `json:"dimension_x"`
+	DimensionY int             `json:"dimension_y"`
+	Resolution string          `json:"resolution"`
+	Ratio      string          `json:"ratio"`
+	FileSize   int64           `json:"file_size"`
+	FileType   string          `json:"file_type"`
+	CreatedAt  string          `json:"created_at"`
+	Colors     []string        `json:"colors"`
+	Path       string          `json:"path"`
+	Thumbs     wallhavenThumbs `json:"thumbs"`
+	Tags       []string        `json:"tags"`
+}
+
+type wallhavenMeta struct {
+	CurrentPage int    `json:"current_page"`
+	LastPage    int    `json:"last_page"`
+	PerPage     int    `json:"per_page"`
+	Total       int    `json:"total"`
+	Query       string `json:"query"`
+	Seed        string `json:"seed,omitempty"`
+}
+
+// toWallhaven converts f, which is in the collection on ctx.
+func toWallhaven(ctx context.Context, f *wallpapers.File) wallhavenWallpaper {
+	w, h := wallpapers.FullRezWidth, wallpapers.FullRezHeight
+	return wallhavenWallpaper{
+		ID:         f.Name,
+		URL:        imageURL(f.Name),
+		ShortURL:   imageURL(f.Name),
+		Source:     f.SourceURL,
+		Purity:     "sfw",
+		Category:   "general",
+		DimensionX: w,
+		DimensionY: h,
+		Resolution: fmt.Sprintf("%dx%d", w, h),
+		Ratio:      strconv.FormatFloat(float64(w)/float64(h), 'f', 2, 64),
+		FileSize:   f.Size,
+		FileType:   mime.TypeByExtension(filepath.Ext(f.Name)),
+		CreatedAt:  f.Added().UTC().Format(wallhavenTimeFormat),
+		Colors:     []string{},
+		Path:       f.FullRezURL,
+		Thumbs: wallhavenThumbs{
+			Large:    f.ThumbnailURL,
+			Original: f.ThumbnailURL,
+			Small:    wallpapers.FitURL(ctx, f.Name, 300, 200, 1),
+		},
+		Tags: []string{},
+	}
+}
+
+// wallhavenWallpaperHandler serves /api/v1/w/{id}, where the id is the
+// file name.
+func wallhavenWallpaperHandler(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	f, err := wallpapers.GetFile(r.Context(), id)
+	if errors.Is(err, storage.ErrObjectNotExist) || (err == nil && f.Type != wallpapers.TypeImage) {
+		renderError(w, r, http.StatusNotFound, "not_found", "not found")
+		return
+	}
+	if err != nil {
+		reqLog(r).Errorw("error during wallhaven get file", "name", id, zap.Error(err))
+		renderError(w, r, http.StatusInternalServerError, "internal", "retrieval error")
+		return
+	}
+
+	recordView(f.Name)
+
+	if err := Renderer.JSON(w, http.StatusOK, map[string]any{"data": toWallhaven(r.Context(), withImageURLs(f, r.URL.Query().Get("collection")))}); err != nil {
+		reqLog(r).Errorw("error during wallhaven render", zap.Error(err))
+	}
+}
+
+// wallhavenSearchHandler serves /api/v1/search. The q parameter matches
+// file names and authors. Purity and categories are accepted and ignored.
+// Sorting by views, favorites, toplist or relevance falls back to
+// date_added, since we do not track them.
+func wallhavenSearchHandler(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	page := 1
+	if v := q.Get("page"); v != "" {
+		var err error
+		page, err = strconv.Atoi(v)
+		if err != nil || page < 1 {
+			renderBadRequest(w, r, "bad_request", invalidParam("page", "page must be a positive integer"))
+			return
+		}
+	}
+
+	fits, err := wallhavenFits(q.Get("atleast"), q.Get("resolutions"), q.Get("ratios"))
+	if err != nil {
+		renderBadRequest(w, r, "bad_request", err)
+		return
+	}
+
+	images, err := listFiles(r.Context())
+	if err != nil {
+		reqLog(r).Errorw("error during wallhaven search", zap.Error(err))
+		renderError(w, r, http.StatusInternalServerError, "internal", "retrieval error")
+		return
+	}
+
+	query := q.Get("q")
+	if !fits {
+		images = nil
+	}
+	allVariants := q.Get("variants") == "all"
+	images = slices.DeleteFunc(images, func(f *wallpapers.File) bool {
+		return f.Type != wallpapers.TypeImage || (f.VariantOf != "" && !allVariants)
+	})
+	images = searchFiles(r.Context(), images, query)
+
+	meta := wallhavenMeta{CurrentPage: page, PerPage: wallhavenPerPage, Total: len(images), Query: query}
+	switch q.Get("sorting") {
+	case "random":
+		meta.Seed = q.Get("seed")
+		if meta.Seed == "" {
+			meta.Seed = newSeed()
+		}
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(meta.Seed))
+		rnd := rand.New(rand.NewPCG(h.Sum64(), 0))
+		rnd.Shuffle(len(images), func(i, j int) { images[i], images[j] = images[j], images[i] })
+	default:
+		desc := q.Get("order") != "asc"
+		if err := wallpapers.SortFiles(images, "added", desc); err != nil {
+			reqLog(r).Errorw("error during wallhaven sort", zap.Error(err))
+			renderError(w, r, http.StatusInternalServerError, "internal", "retrieval error")
+			return
+		}
+	}
+
+	meta.LastPage = max((len(images)+wallhavenPerPage-1)/wallhavenPerPage, 1)
+	results, _ := paginate(images, wallhavenPerPage, (page-1)*wallhavenPerPage)
+	data := make([]wallhavenWallpaper, 0, len(results))
+	for _, f := range results {
+		data = append(data, toWallhaven(r.Context(), f))
+	}
+
+	if err := Renderer.JSON(w, http.StatusOK, map[string]any{"data": data, "meta": meta}); err != nil {
+		reqLog(r).Errorw("error during wallhaven search render", zap.Error(err))
+	}
+}
+
+// wallhavenFits reports whether the full resolution rendition satisfies the
+// atleast, resolutions and ratios filters. Sizes and ratios must be written
+// WIDTHxHEIGHT; ratios may also be landscape or portrait.
+func wallhavenFits(atleast, resolutions, ratios string) (bool, error) {
+	fw, fh := wallpapers.FullRezWidth, wallpapers.FullRezHeight
+	fits := true
+	if atleast != "" {
+		sz, err := wallpapers.ParseSize(atleast)
+		if err != nil {
+			return false, invalidParam("atleast", "atleast must be WIDTHxHEIGHT")
+		}
+		if sz.Width > fw || sz.Height > fh {
+			fits = false
+		}
+	}
+
+	if resolutions != "" {
+		ok := false
+		for _, v := range strings.Split(resolutions, ",") {
+			sz, err := wallpapers.ParseSize(v)
+			if err != nil {
+				return false, invalidParam("resolutions", "resolutions must be a comma separated list of WIDTHxHEIGHT")
+			}
+			if sz.Width == fw && sz.Height == fh {
+				ok = true
+			}
+		}
+		fits = fits && ok
+	}
+
+	if ratios != "" {
+		ok := false
+		for _, v := range strings.Split(ratios, ",") {
+			switch v = strings.TrimSpace(v); v {
+			case "landscape":
+				ok = true
+			case "portrait":
+			default:
+				sz, err := wallpapers.ParseSize(v)
+				if err != nil {
+					return false, invalidParam("ratios", "ratios must be a comma separated list of WIDTHxHEIGHT, landscape or portrait")
+				}
+				if sz.Width*fh == sz.Height*fw {
+					ok = true
+				}
+			}
+		}
+		fits = fits && ok
+	}
+
+	return fits, nil
+}
+
+func newSeed() string {
+	b := make([]byte, 6)
+	for i := range b {
+		b[i] = wallhavenSeedChars[rand.IntN(len(wallhavenSeedChars))]
+	}
+	return string(b)
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/icco/wallpapers"
+)
+
+// localPaths maps formatted names to the local file that has them.
+var localPaths map[string]string
+
+// resolveCollision returns the name to give the file at path, whose
+// formatted name is newName. If another file already has that name, a
+// different picture gets a content hash added to its name, and the same
+// picture returns "" so it can be skipped.
+func resolveCollision(path, newName string) (string, error) {
+	other, ok := localPaths[newName]
+	if !ok {
+		other = filepath.Join(filepath.Dir(path), newName)
+	}
+
+	otherInfo, err := os.Stat(other)
+	if errors.Is(err, fs.ErrNotExist) {
+		return newName, nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	// On case insensitive file systems the new name can be the file itself.
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	if os.SameFile(info, otherInfo) {
+		return newName, nil
+	}
+
+	dat, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("could not read file: %w", err)
+	}
+	otherDat, err := os.ReadFile(other)
+	if err != nil {
+		return "", fmt.Errorf("could not read file: %w", err)
+	}
+
+	if bytes.Equal(dat, otherDat) {
+		return "", nil
+	}
+
+	return wallpapers.HashedName(newName, dat), nil
+}
@@ -0,0 +1,20 @@
+//go:build darwin
+
+package main
+
+import (
+	"io/fs"
+	"syscall"
+	"time"
+)
+
+// getCreationTime returns the birth time of a file, falling back to the
+// modification time if it is unavailable.
+func getCreationTime(_ string, info fs.FileInfo) time.Time {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return info.ModTime()
+	}
+
+	return time.Unix(stat.Birthtimespec.Unix())
+}
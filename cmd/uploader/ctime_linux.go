@@ -0,0 +1,25 @@
+//go:build linux
+
+package main
+
+import (
+	"io/fs"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// getCreationTime returns the birth time of a file, falling back to the
+// modification time if the filesystem does not record it.
+func getCreationTime(path string, info fs.FileInfo) time.Time {
+	var stx unix.Statx_t
+	if err := unix.Statx(unix.AT_FDCWD, path, 0, unix.STATX_BTIME, &stx); err != nil {
+		return info.ModTime()
+	}
+
+	if stx.Mask&unix.STATX_BTIME == 0 {
+		return info.ModTime()
+	}
+
+	return time.Unix(stx.Btime.Sec, int64(stx.Btime.Nsec))
+}
@@ -0,0 +1,14 @@
+//go:build !darwin && !linux
+
+package main
+
+import (
+	"io/fs"
+	"time"
+)
+
+// getCreationTime returns the modification time of a file, as creation time
+// is not available on this platform.
+func getCreationTime(_ string, info fs.FileInfo) time.Time {
+	return info.ModTime()
+}
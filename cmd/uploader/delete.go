@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/icco/wallpapers"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+)
+
+const deleteConcurrency = 8
+
+// deleteFiles removes files from GCS concurrently. Individual failures are
+// logged and returned together once every file has been attempted.
+func deleteFiles(ctx context.Context, filenames []string) error {
+	var (
+		mu   sync.Mutex
+		errs []error
+	)
+
+	var g errgroup.Group
+	g.SetLimit(deleteConcurrency)
+	for _, filename := range filenames {
+		if ctx.Err() != nil {
+			break
+		}
+
+		g.Go(func() error {
+			if err := wallpapers.DeleteFile(ctx, filename); err != nil {
+				stats.failed.Add(1)
+				log.Errorw("could not delete", "file", filename, zap.Error(err))
+				mu.Lock()
+				errs = append(errs, fmt.Errorf("delete %q: %w", filename, err))
+				mu.Unlock()
+				return nil
+			}
+
+			stats.deleted.Add(1)
+			log.Infow("deleted", "file", filename)
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
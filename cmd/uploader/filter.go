@@ -0,0 +1,85 @@
+package main
+
+import (
+	"flag"
+	"io/fs"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	includes patterns
+	excludes patterns
+
+	minSize = flag.Int64("min-size", 0, "skip files smaller than this many bytes")
+	maxSize = flag.Int64("max-size", 0, "skip files larger than this many bytes, 0 for no limit")
+)
+
+func init() {
+	flag.Var(&includes, "include", "only sync files matching this glob, may be repeated")
+	flag.Var(&excludes, "exclude", "skip files or folders matching this glob, may be repeated")
+}
+
+// patterns is a repeatable flag of glob patterns.
+type patterns []string
+
+func (p *patterns) String() string {
+	return strings.Join(*p, ",")
+}
+
+func (p *patterns) Set(v string) error {
+	if _, err := filepath.Match(v, ""); err != nil {
+		return err
+	}
+
+	*p = append(*p, v)
+	return nil
+}
+
+// match reports whether a path relative to the wallpaper directory, its base
+// name, or any of its parent folders matches one of the patterns.
+func (p patterns) match(rel string) bool {
+	rel = filepath.ToSlash(rel)
+	candidates := []string{rel, filepath.Base(rel)}
+	for dir := filepath.Dir(rel); dir != "." && dir != "/"; dir = filepath.Dir(dir) {
+		candidates = append(candidates, dir)
+	}
+
+	for _, pattern := range p {
+		pattern = strings.TrimSuffix(pattern, "/")
+		for _, c := range candidates {
+			if ok, _ := filepath.Match(pattern, c); ok {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// skipFile reports whether a file should be left out of the sync based on the
+// include, exclude and size flags.
+func skipFile(root, path string, info fs.FileInfo) bool {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		rel = info.Name()
+	}
+
+	if len(includes) > 0 && !includes.match(rel) {
+		return true
+	}
+
+	if excludes.match(rel) {
+		return true
+	}
+
+	if info.Size() < *minSize {
+		return true
+	}
+
+	if *maxSize > 0 && info.Size() > *maxSize {
+		return true
+	}
+
+	return false
+}
@@ -2,63 +2,317 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"io/fs"
-	"log"
+	"maps"
 	"os"
+	"os/signal"
 	"os/user"
 	"path/filepath"
+	"slices"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/icco/wallpapers"
+	"github.com/icco/wallpapers/config"
+	"github.com/icco/wallpapers/notify"
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
 )
 
 const DropboxPath = "/Photos/Wallpapers/DesktopWallpapers"
 
 var (
-	knownLocalFiles map[string]bool
+	log *zap.SugaredLogger
+
+	knownLocalFiles  map[string]bool
+	knownRemoteFiles map[string]*wallpapers.File
+	knownContent     map[uint32][]*wallpapers.File
+	sameContent      map[string]string
+	localRoot        string
+	stats            summary
+	notifier         *notify.Notifier
+
+	jsonOutput      = flag.Bool("json", false, "write structured JSON logs")
+	timeout         = flag.Duration("timeout", wallpapers.OperationTimeout, "timeout for each GCS call other than transfers")
+	localDir        = flag.String("dir", "", "local wallpaper directory, defaults to nat's Dropbox")
+	verifyOnly      = flag.Bool("verify", false, "compare local files with GCS and report drift without changing anything")
+	recompressPNG   = flag.Bool("recompress-png", false, "losslessly recompress PNGs before uploading them, if that makes them smaller")
+	allowDuplicates = flag.Bool("allow-duplicates", false, "upload files even if the same content is already stored under another name")
+	pull            = flag.Bool("pull", false, "download remote files that are missing locally instead of deleting them")
+
+	maxBandwidth = flag.Int("max-bandwidth", 0, "limit uploads to this many bytes per second, 0 for no limit")
+	limiter      *rate.Limiter
+
+	minWidth  = flag.Int("min-width", wallpapers.DefaultValidation.MinWidth, "quarantine images narrower than this")
+	minHeight = flag.Int("min-height", wallpapers.DefaultValidation.MinHeight, "quarantine images shorter than this")
+
+	deleteRemote    = flag.Bool("delete", false, "delete remote files that are missing locally")
+	deleteThreshold = flag.Float64("delete-threshold", 10, "abort if more than this percent of remote files would be deleted")
+	deleteGrace     = flag.Duration("delete-grace", 7*24*time.Hour, "never delete remote files added less than this long ago, 0 to disable")
+
+	syncs = syncFlag{}
+	cfg   = config.New()
 )
 
+// syncFlag maps collection names to the local directories they sync with.
+type syncFlag map[string]string
+
+func (s syncFlag) String() string {
+	var pairs []string
+	for name, dir := range s {
+		pairs = append(pairs, name+"="+dir)
+	}
+	slices.Sort(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func (s syncFlag) Set(v string) error {
+	name, dir, ok := strings.Cut(v, "=")
+	if !ok || name == "" || dir == "" {
+		return fmt.Errorf("expected collection=dir, got %q", v)
+	}
+	s[name] = dir
+	return nil
+}
+
 func main() {
-	ctx := context.Background()
-	knownRemoteFiles, err := wallpapers.GetAll(ctx)
+	flag.Var(syncs, "sync", "sync a collection with a local directory, as collection=dir; may be repeated")
+	cfg.RegisterFlags(flag.CommandLine)
+	flag.Parse()
+
+	var err error
+	log, err = newLogger(*jsonOutput)
 	if err != nil {
-		log.Printf("error walking: %+v", err)
+		fmt.Fprintf(os.Stderr, "could not create logger: %+v\n", err)
+		os.Exit(1)
+	}
+	wallpapers.SetLogger(log)
+
+	if err := cfg.Load(); err != nil {
+		log.Errorw("could not load config", zap.Error(err))
+		_ = log.Sync()
 		os.Exit(1)
 	}
+
+	// Stop between files on Ctrl-C instead of leaving uploads half done.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+
+	wallpapers.OperationTimeout = *timeout
+
+	start := time.Now()
+	code := run(ctx)
+	_ = warming.Wait()
+	_ = mirroring.Wait()
+	stop()
+	if !*verifyOnly {
+		stats.log(time.Since(start))
+	}
+
+	_ = log.Sync()
+	os.Exit(code)
+}
+
+func newLogger(json bool) (*zap.SugaredLogger, error) {
+	cfg := zap.NewDevelopmentConfig()
+	if json {
+		cfg = zap.NewProductionConfig()
+	}
+	cfg.DisableStacktrace = true
+
+	l, err := cfg.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	return l.Sugar(), nil
+}
+
+func run(ctx context.Context) int {
+	collections := cfg.Collections
+	for name := range syncs {
+		if !slices.ContainsFunc(collections, func(c wallpapers.Collection) bool { return c.Name == name }) {
+			log.Errorw("unknown collection", "collection", name)
+			return 1
+		}
+	}
+
+	if *maxBandwidth > 0 {
+		limiter = rate.NewLimiter(rate.Limit(*maxBandwidth), *maxBandwidth)
+	}
+
+	mirrorStore, err := cfg.MirrorStore()
+	if err != nil {
+		log.Errorw("could not configure mirror", zap.Error(err))
+		return 1
+	}
+
+	code := 0
+	for i, c := range collections {
+		dir := syncs[c.Name]
+		if i == 0 && dir == "" {
+			dir = *localDir
+			if dir == "" {
+				u, err := user.Lookup("nat")
+				if err != nil {
+					log.Errorw("error getting nat", zap.Error(err))
+					return 1
+				}
+				dir = filepath.Join(u.HomeDir, "Dropbox", DropboxPath)
+			}
+		}
+		if dir == "" {
+			continue
+		}
+
+		store, err := cfg.Open(i)
+		if err != nil {
+			log.Errorw("could not configure store", "collection", c.Name, zap.Error(err))
+			return 1
+		}
+
+		// Only the default collection is mirrored.
+		mirrorTo = nil
+		if i == 0 {
+			mirrorTo = mirrorStore
+		}
+
+		// Only announce wallpapers anyone can see.
+		notifier = nil
+		if c.Public() {
+			notifier = cfg.Notifier()
+		}
+
+		log.Debugw("syncing collection", "collection", c.Name, "dir", dir)
+		if ret := syncCollection(wallpapers.ContextWithStore(ctx, store), dir); code == 0 {
+			code = ret
+		}
+	}
+
+	return code
+}
+
+// syncCollection syncs the store on ctx with a local directory.
+func syncCollection(ctx context.Context, localFiles string) int {
 	knownLocalFiles = map[string]bool{}
+	localPaths = map[string]string{}
+	knownRemoteFiles = map[string]*wallpapers.File{}
+	knownContent = map[uint32][]*wallpapers.File{}
+	sameContent = map[string]string{}
+	for file, err := range wallpapers.Files(ctx) {
+		if err != nil {
+			log.Errorw("error walking", zap.Error(err))
+			return 1
+		}
+		knownRemoteFiles[file.Name] = file
+		knownContent[file.OriginalCRC32C()] = append(knownContent[file.OriginalCRC32C()], file)
+	}
 
-	u, err := user.Lookup("nat")
+	localRoot = localFiles
+
+	if *verifyOnly {
+		drift, err := verify(localFiles)
+		if err != nil {
+			log.Errorw("error verifying", zap.Error(err))
+			return 1
+		}
+		if drift {
+			return 2
+		}
+		return 0
+	}
+
+	if *pull {
+		if err := os.MkdirAll(localFiles, 0750); err != nil {
+			log.Errorw("error creating dir", "dir", localFiles, zap.Error(err))
+			return 1
+		}
+	}
+
+	err := filepath.Walk(localFiles, func(path string, info fs.FileInfo, err error) error {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return walkFn(ctx, path, info, err)
+	})
 	if err != nil {
-		log.Printf("error getting nat: %+v", err)
-		os.Exit(1)
+		log.Errorw("error walking", zap.Error(err))
+		return 1
 	}
-	localFiles := filepath.Join(u.HomeDir, "Dropbox", DropboxPath)
 
-	if err := filepath.Walk(localFiles, walkFn); err != nil {
-		log.Printf("error walking: %+v", err)
-		os.Exit(1)
+	if err := moveRenamed(ctx); err != nil {
+		log.Errorw("error moving renamed files", zap.Error(err))
+		return 1
 	}
 
-	for _, file := range knownRemoteFiles {
-		filename := file.Name
-		if !knownLocalFiles[filename] {
-			if err := wallpapers.DeleteFile(ctx, filename); err != nil {
-				log.Printf("could not delete %q: %+v", filename, err)
-				os.Exit(1)
+	var toDelete []string
+	for filename, file := range knownRemoteFiles {
+		if knownLocalFiles[filename] {
+			continue
+		}
+
+		if *pull {
+			if err := pullFile(ctx, localFiles, file); err != nil {
+				log.Errorw("could not pull", "file", filename, zap.Error(err))
+				return 1
 			}
-			log.Printf("deleted %q", filename)
+			stats.pulled.Add(1)
+			stats.bytes.Add(file.Size)
+			log.Infow("pulled", "file", filename)
+			continue
+		}
+
+		// Files added with walls add or by another machine have never
+		// been in this directory, so give them time to be synced down
+		// before treating them as deleted. GCS resets the creation time
+		// whenever an object is rewritten, such as by a rename or a
+		// storage class change, so go by when the file was added.
+		if *deleteGrace > 0 && time.Since(file.Added()) < *deleteGrace {
+			stats.skipped.Add(1)
+			log.Infow("remote file is missing locally but recent, keeping", "file", filename, "added", file.Added())
+			continue
 		}
+
+		toDelete = append(toDelete, filename)
+	}
+
+	if len(toDelete) == 0 {
+		return 0
+	}
+
+	if !*deleteRemote {
+		log.Infow("remote files are missing locally, run with -delete to remove them", "count", len(toDelete))
+		return 0
+	}
+
+	percent := float64(len(toDelete)) / float64(len(knownRemoteFiles)) * 100
+	if percent > *deleteThreshold {
+		log.Errorw("refusing to delete remote files",
+			"count", len(toDelete),
+			"remote", len(knownRemoteFiles),
+			"percent", percent,
+			"threshold", *deleteThreshold)
+		return 1
+	}
+
+	if err := deleteFiles(ctx, toDelete); err != nil {
+		log.Errorw("error deleting", zap.Error(err))
+		return 1
 	}
+
+	return 0
 }
 
-func walkFn(path string, info fs.FileInfo, err error) error {
+func walkFn(ctx context.Context, path string, info fs.FileInfo, err error) error {
 	if err != nil {
 		return fmt.Errorf("prevent panic by handling failure accessing a path %q: %w", path, err)
 	}
 
 	if info.IsDir() {
-		log.Printf("found a dir: %q", info.Name())
+		log.Debugw("found a dir", "dir", info.Name())
 		return nil
 	}
 
@@ -67,23 +321,41 @@ func walkFn(path string, info fs.FileInfo, err error) error {
 		return nil
 	}
 
-	ctx := context.Background()
+	stats.scanned.Add(1)
+
+	// Skip filtered files, but remember them so they are not deleted remotely
+	if skipFile(localRoot, path, info) {
+		knownLocalFiles[wallpapers.FormatName(info.Name())] = true
+		stats.skipped.Add(1)
+		return nil
+	}
 
 	// Rename
 	folder := filepath.Dir(path)
 	oldName := info.Name()
 
-	newName := wallpapers.FormatName(info.Name())
+	newName, err := resolveCollision(path, wallpapers.FormatName(info.Name()))
+	if err != nil {
+		return fmt.Errorf("could not check for collisions: %w", err)
+	}
+	if newName == "" {
+		stats.skipped.Add(1)
+		log.Infow("same picture as another file, skipping", "file", path)
+		return nil
+	}
+
 	newPath := filepath.Join(folder, newName)
 	if newName != info.Name() {
 		if err := os.Rename(path, newPath); err != nil {
 			return fmt.Errorf("could not rename: %w", err)
 		}
-		log.Printf("renamed %q => %q", oldName, newName)
+		stats.renamed.Add(1)
+		log.Infow("renamed", "from", oldName, "to", newName)
 	}
 
 	// log existence
 	knownLocalFiles[newName] = true
+	localPaths[newName] = newPath
 
 	// Upload
 	dat, err := os.ReadFile(newPath)
@@ -91,20 +363,153 @@ func walkFn(path string, info fs.FileInfo, err error) error {
 		return fmt.Errorf("could not read file: %w", err)
 	}
 
-	gc, err := wallpapers.GetGoogleCRC(ctx, newName)
-	if err != nil {
-		return fmt.Errorf("could not get crc: %w", err)
+	// Recompressed uploads record the checksums of the file they came from.
+	sums := wallpapers.GetChecksums(dat)
+	created := getCreationTime(newPath, info)
+	remote, exists := knownRemoteFiles[newName]
+	if exists && remote.SameContent(dat) {
+		if remote.CustomTime.IsZero() {
+			if err := wallpapers.SetCustomTime(ctx, newName, created); err != nil {
+				return fmt.Errorf("could not set custom time: %w", err)
+			}
+			log.Infow("set custom time", "file", newName, "time", created)
+		}
+		stats.skipped.Add(1)
+		log.Debugw("unchanged, skipping", "file", newName)
+		return nil
 	}
-	lc := wallpapers.GetFileCRC(dat)
-	if gc == lc {
-		log.Printf("%q unchanged, skipping", newName)
+
+	// The content is already stored under another name. If that name is
+	// gone locally the file was renamed, otherwise it is a duplicate, which
+	// is only stored once. Which it is is only known after the walk.
+	if existing := findContent(dat, sums.CRC32C); existing != "" && !exists && !*allowDuplicates {
+		sameContent[newName] = existing
 		return nil
 	}
+	local := &wallpapers.File{Name: newName, Size: int64(len(dat)), CRC32C: sums.CRC32C, MD5: sums.MD5}
+
+	opts := []wallpapers.UploadOption{
+		// The listing already has the file being replaced, if any.
+		wallpapers.WithPrevious(remote),
+		wallpapers.WithCustomTime(created),
+		wallpapers.WithValidation(wallpapers.Validation{MaxSize: wallpapers.DefaultValidation.MaxSize, MinWidth: *minWidth, MinHeight: *minHeight}),
+	}
+	if limiter != nil {
+		opts = append(opts, wallpapers.WithRateLimiter(limiter))
+	}
+
+	if strings.EqualFold(filepath.Ext(newName), ".mp4") {
+		v, err := wallpapers.ProbeMP4(dat)
+		if err != nil {
+			log.Warnw("could not probe video", "file", newName, zap.Error(err))
+		} else {
+			opts = append(opts, wallpapers.WithVideoInfo(v))
+		}
+	}
+
+	if wallpapers.MediaType(newName) == wallpapers.TypeImage {
+		profile, err := wallpapers.ColorProfile(dat)
+		if err != nil {
+			log.Warnw("could not read color profile", "file", newName, zap.Error(err))
+		} else if profile != "" {
+			opts = append(opts, wallpapers.WithColorProfile(profile))
+		}
+	}
 
-	if err := wallpapers.UploadFile(ctx, newName, dat); err != nil {
+	if *recompressPNG {
+		smaller, err := wallpapers.RecompressPNG(dat)
+		if err != nil {
+			log.Warnw("could not recompress, uploading as is", "file", newName, zap.Error(err))
+		}
+		if smaller != nil {
+			log.Infow("recompressed", "file", newName, "from", len(dat), "to", len(smaller))
+			opts = append(opts, wallpapers.WithOriginal(dat))
+			dat = smaller
+		}
+	}
+
+	err = wallpapers.UploadFile(ctx, newName, dat, opts...)
+	var rejected *wallpapers.RejectedError
+	if errors.As(err, &rejected) {
+		stats.quarantined.Add(1)
+		log.Warnw("quarantined", "file", newName, "reason", rejected.Reason)
+		return nil
+	}
+	if err != nil {
 		return fmt.Errorf("cloud not upload file: %w", err)
 	}
 
-	log.Printf("uploaded file: %q", newName)
+	stats.uploaded.Add(1)
+	stats.bytes.Add(int64(len(dat)))
+	knownContent[sums.CRC32C] = append(knownContent[sums.CRC32C], local)
+	log.Infow("uploaded file", "file", newName)
+	warm(ctx, newName)
+	mirror(ctx, newName)
+
+	if !exists {
+		if err := notifier.NewWallpaper(ctx, newName); err != nil {
+			log.Warnw("could not send notification", "file", newName, zap.Error(err))
+		}
+	}
+
+	return nil
+}
+
+// findContent returns the name of a remote file made from dat, whose
+// CRC32C is crc, or "" if there is none. A matching checksum is only a
+// candidate: it is confirmed by size and MD5 before it counts.
+func findContent(dat []byte, crc uint32) string {
+	for _, f := range knownContent[crc] {
+		if f.SameContent(dat) {
+			return f.Name
+		}
+	}
+	return ""
+}
+
+// moveRenamed renames remote files whose content was found locally under a
+// new name, instead of uploading them again and deleting the old copy.
+func moveRenamed(ctx context.Context) error {
+	for _, newName := range slices.Sorted(maps.Keys(sameContent)) {
+		existing := sameContent[newName]
+		remote, ok := knownRemoteFiles[existing]
+		if !ok || knownLocalFiles[existing] {
+			stats.skipped.Add(1)
+			log.Infow("duplicate, skipping", "file", newName, "existing", existing)
+			continue
+		}
+
+		if err := wallpapers.RenameFile(ctx, existing, newName); err != nil {
+			return fmt.Errorf("could not move %q to %q: %w", existing, newName, err)
+		}
+
+		delete(knownRemoteFiles, existing)
+		remote.Name = newName
+		knownRemoteFiles[newName] = remote
+		stats.moved.Add(1)
+		log.Infow("moved", "from", existing, "to", newName)
+	}
+
+	return nil
+}
+
+// pullFile downloads a remote file into the local directory, preserving when
+// it was added to the collection as its modification time.
+func pullFile(ctx context.Context, dir string, file *wallpapers.File) error {
+	dat, err := wallpapers.DownloadFile(ctx, file.Name)
+	if err != nil {
+		return fmt.Errorf("could not download file: %w", err)
+	}
+
+	path := filepath.Join(dir, file.Name)
+	if err := os.WriteFile(path, dat, 0600); err != nil {
+		return fmt.Errorf("could not write file: %w", err)
+	}
+
+	added := file.Added()
+	if err := os.Chtimes(path, added, added); err != nil {
+		return fmt.Errorf("could not set times: %w", err)
+	}
+
 	return nil
 }
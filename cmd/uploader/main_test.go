@@ -0,0 +1,459 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/png"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/icco/wallpapers"
+	"go.uber.org/zap"
+)
+
+func init() {
+	log = zap.NewNop().Sugar()
+}
+
+// testImage returns a PNG large enough to pass validation, whose content
+// depends on shade.
+func testImage(t *testing.T, shade uint8) []byte {
+	t.Helper()
+	img := image.NewGray(image.Rect(0, 0, wallpapers.DefaultValidation.MinWidth, wallpapers.DefaultValidation.MinHeight))
+	for i := range img.Pix {
+		img.Pix[i] = shade
+	}
+	img.SetGray(0, 0, color.Gray{Y: shade + 1})
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// setFlag sets a flag for the length of a test.
+func setFlag[T any](t *testing.T, p *T, v T) {
+	t.Helper()
+	old := *p
+	*p = v
+	t.Cleanup(func() { *p = old })
+}
+
+// useMemoryStore makes the wallpapers package use an empty MemoryStore
+// for the length of a test.
+func useMemoryStore(t *testing.T) *wallpapers.MemoryStore {
+	t.Helper()
+	old := wallpapers.DefaultStore()
+	s := wallpapers.NewMemoryStore()
+	wallpapers.SetStore(s)
+	t.Cleanup(func() { wallpapers.SetStore(old) })
+	return s
+}
+
+func TestSyncCollection(t *testing.T) {
+	setFlag(t, deleteRemote, true)
+	setFlag(t, deleteThreshold, 100)
+
+	for _, tc := range []struct {
+		name string
+		// remote and local map file names to the shade of their content.
+		// Local names may be in subdirectories.
+		remote map[string]uint8
+		local  map[string]uint8
+		// junk are local files that are not images.
+		junk  []string
+		grace time.Duration
+		// written is how long ago the remote files were written, and
+		// added how long ago some of them were first added, as recorded
+		// in their custom time.
+		written time.Duration
+		added   map[string]time.Duration
+		want    map[string]uint8
+		// wantLocal, if set, are the local files after the sync.
+		wantLocal []string
+	}{
+		{
+			name:   "new file is uploaded",
+			remote: map[string]uint8{},
+			local:  map[string]uint8{"a.png": 1},
+			want:   map[string]uint8{"a.png": 1},
+		},
+		{
+			name:   "unchanged file is kept",
+			remote: map[string]uint8{"a.png": 1},
+			local:  map[string]uint8{"a.png": 1},
+			want:   map[string]uint8{"a.png": 1},
+		},
+		{
+			name:   "changed file is replaced",
+			remote: map[string]uint8{"a.png": 1},
+			local:  map[string]uint8{"a.png": 2},
+			want:   map[string]uint8{"a.png": 2},
+		},
+		{
+			name:   "file missing locally is deleted",
+			remote: map[string]uint8{"a.png": 1, "b.png": 2},
+			local:  map[string]uint8{"a.png": 1},
+			want:   map[string]uint8{"a.png": 1},
+		},
+		{
+			// The content only exists remotely under its old name, so it
+			// must be moved rather than skipped as a duplicate and then
+			// deleted.
+			name:   "renamed file is moved",
+			remote: map[string]uint8{"old.png": 1},
+			local:  map[string]uint8{"new.png": 1},
+			want:   map[string]uint8{"new.png": 1},
+		},
+		{
+			name:   "duplicate of a local file is skipped",
+			remote: map[string]uint8{"a.png": 1},
+			local:  map[string]uint8{"a.png": 1, "copy.png": 1},
+			want:   map[string]uint8{"a.png": 1},
+		},
+		{
+			name:   "duplicates of a renamed file are moved once",
+			remote: map[string]uint8{"old.png": 1},
+			local:  map[string]uint8{"new.png": 1, "newer.png": 1},
+			want:   map[string]uint8{"new.png": 1},
+		},
+		{
+			name:      "names are formatted before upload",
+			remote:    map[string]uint8{},
+			local:     map[string]uint8{"My Wall.PNG": 1},
+			want:      map[string]uint8{"mywall.png": 1},
+			wantLocal: []string{"mywall.png"},
+		},
+		{
+			name:   "subdirectories are walked",
+			remote: map[string]uint8{},
+			local:  map[string]uint8{"a.png": 1, "sub/b.png": 2},
+			want:   map[string]uint8{"a.png": 1, "b.png": 2},
+		},
+		{
+			name:      "hidden files are ignored",
+			remote:    map[string]uint8{},
+			local:     map[string]uint8{"a.png": 1, ".b.png": 2},
+			want:      map[string]uint8{"a.png": 1},
+			wantLocal: []string{".b.png", "a.png"},
+		},
+		{
+			name:   "invalid files are quarantined, not uploaded",
+			remote: map[string]uint8{},
+			local:  map[string]uint8{"a.png": 1},
+			junk:   []string{"b.png"},
+			want:   map[string]uint8{"a.png": 1},
+		},
+		{
+			name:   "recent remote files are kept",
+			remote: map[string]uint8{"a.png": 1, "b.png": 2},
+			local:  map[string]uint8{"a.png": 1},
+			grace:  time.Hour,
+			want:   map[string]uint8{"a.png": 1, "b.png": 2},
+		},
+		{
+			// Renames and storage class changes rewrite the object, which
+			// gives it a new creation time.
+			name:   "rewritten remote files are not recent",
+			remote: map[string]uint8{"a.png": 1, "b.png": 2},
+			local:  map[string]uint8{"a.png": 1},
+			grace:  time.Hour,
+			added:  map[string]time.Duration{"b.png": 30 * 24 * time.Hour},
+			want:   map[string]uint8{"a.png": 1},
+		},
+		{
+			name:    "recently added remote files are kept",
+			remote:  map[string]uint8{"a.png": 1, "b.png": 2},
+			local:   map[string]uint8{"a.png": 1},
+			grace:   time.Hour,
+			written: 30 * 24 * time.Hour,
+			added:   map[string]time.Duration{"b.png": time.Minute},
+			want:    map[string]uint8{"a.png": 1, "b.png": 2},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			setFlag(t, deleteGrace, tc.grace)
+			s := useMemoryStore(t)
+			ctx := context.Background()
+			s.Now = func() time.Time { return time.Now().Add(-tc.written) }
+			for name, shade := range tc.remote {
+				var opts []wallpapers.UploadOption
+				if d, ok := tc.added[name]; ok {
+					opts = append(opts, wallpapers.WithCustomTime(time.Now().Add(-d)))
+				}
+				if err := wallpapers.UploadFile(ctx, name, testImage(t, shade), opts...); err != nil {
+					t.Fatal(err)
+				}
+			}
+			s.Now = nil
+
+			dir := t.TempDir()
+			write := func(name string, content []byte) {
+				path := filepath.Join(dir, name)
+				if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, content, 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			for name, shade := range tc.local {
+				write(name, testImage(t, shade))
+			}
+			for _, name := range tc.junk {
+				write(name, []byte("not an image"))
+			}
+
+			if code := syncCollection(ctx, dir); code != 0 {
+				t.Fatalf("syncCollection returned %d", code)
+			}
+
+			got := map[string]uint32{}
+			for f, err := range s.List(ctx) {
+				if err != nil {
+					t.Fatal(err)
+				}
+				got[f.Name] = f.CRC32C
+			}
+			want := map[string]uint32{}
+			for name, shade := range tc.want {
+				want[name] = wallpapers.GetFileCRC(testImage(t, shade))
+			}
+			if !maps.Equal(got, want) {
+				t.Errorf("remote files = %v, want %v", slices.Sorted(maps.Keys(got)), slices.Sorted(maps.Keys(want)))
+			}
+
+			if tc.wantLocal != nil {
+				entries, err := os.ReadDir(dir)
+				if err != nil {
+					t.Fatal(err)
+				}
+				var local []string
+				for _, e := range entries {
+					local = append(local, e.Name())
+				}
+				if !slices.Equal(local, tc.wantLocal) {
+					t.Errorf("local files = %q, want %q", local, tc.wantLocal)
+				}
+			}
+		})
+	}
+}
+
+// withCRC appends four bytes to a PNG, which decoders ignore, so that its
+// CRC32C is crc.
+func withCRC(t *testing.T, content []byte, crc uint32) []byte {
+	t.Helper()
+	tab := crc32.MakeTable(crc32.Castagnoli)
+	// Each table entry has a different top byte, so the entries used to
+	// reach crc can be found working backwards from it.
+	var byTop [256]byte
+	for i, v := range tab {
+		byTop[v>>24] = byte(i)
+	}
+	var idx [4]byte
+	reg := ^crc
+	for i := 3; i >= 0; i-- {
+		idx[i] = byTop[reg>>24]
+		reg = (reg ^ tab[idx[i]]) << 8
+	}
+
+	out := slices.Clone(content)
+	reg = ^crc32.Checksum(content, tab)
+	for _, j := range idx {
+		out = append(out, byte(reg)^j)
+		reg = reg>>8 ^ tab[j]
+	}
+	if got := wallpapers.GetFileCRC(out); got != crc {
+		t.Fatalf("forged CRC32C = %08x, want %08x", got, crc)
+	}
+	return out
+}
+
+func TestSyncCollectionCRCCollision(t *testing.T) {
+	setFlag(t, deleteRemote, true)
+	setFlag(t, deleteThreshold, 100)
+	setFlag(t, deleteGrace, 0)
+
+	// Pad the images to the same size, so the forged one matches in
+	// both size and CRC32C.
+	a, b := testImage(t, 1), testImage(t, 2)
+	n := max(len(a), len(b))
+	a = append(a, make([]byte, n+4-len(a))...)
+	b = withCRC(t, append(b, make([]byte, n-len(b))...), wallpapers.GetFileCRC(a))
+	if len(a) != len(b) || bytes.Equal(a, b) {
+		t.Fatal("colliding files must be different and the same size")
+	}
+
+	for _, tc := range []struct {
+		name   string
+		remote map[string][]byte
+		local  map[string][]byte
+		want   map[string][]byte
+	}{
+		{
+			name:   "changed file is replaced",
+			remote: map[string][]byte{"a.png": a},
+			local:  map[string][]byte{"a.png": b},
+			want:   map[string][]byte{"a.png": b},
+		},
+		{
+			name:   "different file is not skipped as a duplicate",
+			remote: map[string][]byte{"a.png": a},
+			local:  map[string][]byte{"a.png": a, "b.png": b},
+			want:   map[string][]byte{"a.png": a, "b.png": b},
+		},
+		{
+			name:   "different file is not moved as a rename",
+			remote: map[string][]byte{"old.png": a},
+			local:  map[string][]byte{"new.png": b},
+			want:   map[string][]byte{"new.png": b},
+		},
+		{
+			name:   "same file is still moved as a rename",
+			remote: map[string][]byte{"old.png": a},
+			local:  map[string][]byte{"new.png": a},
+			want:   map[string][]byte{"new.png": a},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s := useMemoryStore(t)
+			ctx := context.Background()
+			for name, content := range tc.remote {
+				if err := wallpapers.UploadFile(ctx, name, content); err != nil {
+					t.Fatal(err)
+				}
+			}
+			dir := t.TempDir()
+			for name, content := range tc.local {
+				if err := os.WriteFile(filepath.Join(dir, name), content, 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if code := syncCollection(ctx, dir); code != 0 {
+				t.Fatalf("syncCollection returned %d", code)
+			}
+
+			got := map[string][16]byte{}
+			for f, err := range s.List(ctx) {
+				if err != nil {
+					t.Fatal(err)
+				}
+				got[f.Name] = [16]byte(f.MD5)
+			}
+			want := map[string][16]byte{}
+			for name, content := range tc.want {
+				want[name] = md5.Sum(content) // #nosec G401 -- matching the store's checksum
+			}
+			if !maps.Equal(got, want) {
+				t.Errorf("remote files = %x, want %x", got, want)
+			}
+		})
+	}
+}
+
+func TestCompareLocal(t *testing.T) {
+	setFlag(t, verifyOnly, true)
+	hashed := wallpapers.HashedName("mywall.png", testImage(t, 2))
+
+	for _, tc := range []struct {
+		name   string
+		remote map[string]uint8
+		local  map[string]uint8
+		want   drift
+	}{
+		{
+			name:   "unchanged",
+			remote: map[string]uint8{"a.png": 1},
+			local:  map[string]uint8{"a.png": 1},
+			want:   drift{local: 1},
+		},
+		{
+			name:   "changed file is a mismatch",
+			remote: map[string]uint8{"a.png": 1},
+			local:  map[string]uint8{"a.png": 2},
+			want:   drift{local: 1, mismatched: []string{"a.png"}},
+		},
+		{
+			name:   "collision is stored under its hashed name",
+			remote: map[string]uint8{"mywall.png": 1, hashed: 2},
+			local:  map[string]uint8{"mywall.png": 1, "My Wall.PNG": 2},
+			want:   drift{local: 2},
+		},
+		{
+			name:   "collision missing its hashed name",
+			remote: map[string]uint8{"mywall.png": 1},
+			local:  map[string]uint8{"mywall.png": 1, "My Wall.PNG": 2},
+			want:   drift{local: 2, missing: []string{hashed}},
+		},
+		{
+			name:   "same picture under a colliding name is deduped",
+			remote: map[string]uint8{"mywall.png": 1},
+			local:  map[string]uint8{"mywall.png": 1, "My Wall.PNG": 1},
+			want:   drift{local: 2, deduped: []string{"My Wall.PNG"}},
+		},
+		{
+			name:   "copy of a local file is deduped",
+			remote: map[string]uint8{"a.png": 1},
+			local:  map[string]uint8{"a.png": 1, "copy.png": 1},
+			want:   drift{local: 2, deduped: []string{"copy.png"}},
+		},
+		{
+			name:   "renamed file is drift",
+			remote: map[string]uint8{"old.png": 1},
+			local:  map[string]uint8{"new.png": 1},
+			want:   drift{local: 1, missing: []string{"new.png"}, orphaned: []string{"old.png"}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			useMemoryStore(t)
+			ctx := context.Background()
+			for name, shade := range tc.remote {
+				if err := wallpapers.UploadFile(ctx, name, testImage(t, shade)); err != nil {
+					t.Fatal(err)
+				}
+			}
+			dir := t.TempDir()
+			for name, shade := range tc.local {
+				if err := os.WriteFile(filepath.Join(dir, name), testImage(t, shade), 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			// The verify run indexes the remote files for compareLocal.
+			wantCode := 0
+			if len(tc.want.mismatched)+len(tc.want.missing)+len(tc.want.orphaned) > 0 {
+				wantCode = 2
+			}
+			if code := syncCollection(ctx, dir); code != wantCode {
+				t.Errorf("syncCollection returned %d, want %d", code, wantCode)
+			}
+
+			got, err := compareLocal(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i, path := range got.deduped {
+				got.deduped[i] = strings.TrimPrefix(path, dir+string(filepath.Separator))
+			}
+			if got.local != tc.want.local ||
+				!slices.Equal(got.mismatched, tc.want.mismatched) ||
+				!slices.Equal(got.missing, tc.want.missing) ||
+				!slices.Equal(got.orphaned, tc.want.orphaned) ||
+				!slices.Equal(got.deduped, tc.want.deduped) {
+				t.Errorf("compareLocal = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+
+	"github.com/icco/wallpapers"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+)
+
+// mirrorConcurrency is how many uploads are copied to the mirror at once.
+const mirrorConcurrency = 4
+
+// mirrorTo is the mirror of the collection being synced, if it has one.
+var mirrorTo wallpapers.Store
+
+// mirroring copies uploaded files to the mirror while the sync goes on.
+// main waits for it before exiting.
+var mirroring = func() *errgroup.Group {
+	g := &errgroup.Group{}
+	g.SetLimit(mirrorConcurrency)
+	return g
+}()
+
+// mirror copies name to the mirror in the background. Failures are only
+// logged; walls mirror sync copies whatever was missed.
+func mirror(ctx context.Context, name string) {
+	if mirrorTo == nil {
+		return
+	}
+
+	dst := mirrorTo
+	mirroring.Go(func() error {
+		if err := wallpapers.MirrorFile(ctx, wallpapers.StoreFor(ctx), dst, name); err != nil {
+			log.Warnw("could not mirror file", "file", name, zap.Error(err))
+		}
+		return nil
+	})
+}
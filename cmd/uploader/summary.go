@@ -0,0 +1,36 @@
+package main
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// summary counts what happened during a run so it can be reported at the end.
+type summary struct {
+	scanned  atomic.Int64
+	renamed  atomic.Int64
+	moved    atomic.Int64
+	uploaded atomic.Int64
+	skipped  atomic.Int64
+	deleted  atomic.Int64
+	pulled   atomic.Int64
+	failed   atomic.Int64
+	bytes    atomic.Int64
+
+	quarantined atomic.Int64
+}
+
+func (s *summary) log(d time.Duration) {
+	log.Infow("summary",
+		"scanned", s.scanned.Load(),
+		"renamed", s.renamed.Load(),
+		"moved", s.moved.Load(),
+		"uploaded", s.uploaded.Load(),
+		"skipped", s.skipped.Load(),
+		"deleted", s.deleted.Load(),
+		"pulled", s.pulled.Load(),
+		"failed", s.failed.Load(),
+		"quarantined", s.quarantined.Load(),
+		"bytes", s.bytes.Load(),
+		"duration", d.String())
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/icco/wallpapers"
+)
+
+// drift is how the local files and the copies in GCS differ.
+type drift struct {
+	local                         int
+	mismatched, missing, orphaned []string
+	// deduped are local files that are not uploaded because the same
+	// content is stored under the name of another local file.
+	deduped []string
+}
+
+// verify compares every local file with the copy in GCS without changing
+// anything, prints a summary and returns true if the two differ.
+func verify(root string) (bool, error) {
+	d, err := compareLocal(root)
+	if err != nil {
+		return false, err
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "STATUS\tFILE")
+	for _, group := range []struct {
+		status string
+		names  []string
+	}{
+		{"mismatch", d.mismatched},
+		{"missing", d.missing},
+		{"orphaned", d.orphaned},
+	} {
+		for _, name := range group.names {
+			fmt.Fprintf(tw, "%s\t%s\n", group.status, name)
+		}
+	}
+	fmt.Fprintln(tw)
+	fmt.Fprintf(tw, "local\t%d\n", d.local)
+	fmt.Fprintf(tw, "remote\t%d\n", len(knownRemoteFiles))
+	fmt.Fprintf(tw, "deduped\t%d\n", len(d.deduped))
+	fmt.Fprintf(tw, "mismatch\t%d\n", len(d.mismatched))
+	fmt.Fprintf(tw, "missing\t%d\n", len(d.missing))
+	fmt.Fprintf(tw, "orphaned\t%d\n", len(d.orphaned))
+	if err := tw.Flush(); err != nil {
+		return false, err
+	}
+
+	return len(d.mismatched)+len(d.missing)+len(d.orphaned) > 0, nil
+}
+
+// compareLocal names every local file as a sync would, collision hashes
+// included, and compares it with the copy in GCS. Files a sync would skip
+// as the same content as another local file are not drift.
+func compareLocal(root string) (drift, error) {
+	var d drift
+	localPaths = map[string]string{}
+	local := map[string]bool{}
+	ignored := map[string]bool{}
+	// sameAs maps local files missing remotely to the remote file with the
+	// same content.
+	sameAs := map[string]string{}
+	err := filepath.Walk(root, func(path string, info fs.FileInfo, err error) error {
+		if err != nil {
+			return fmt.Errorf("prevent panic by handling failure accessing a path %q: %w", path, err)
+		}
+
+		if info.IsDir() || strings.HasPrefix(info.Name(), ".") {
+			return nil
+		}
+
+		if skipFile(root, path, info) {
+			ignored[wallpapers.FormatName(info.Name())] = true
+			return nil
+		}
+		d.local++
+
+		name, err := resolveCollision(path, wallpapers.FormatName(info.Name()))
+		if err != nil {
+			return fmt.Errorf("could not check for collisions: %w", err)
+		}
+		if name == "" {
+			d.deduped = append(d.deduped, path)
+			return nil
+		}
+		local[name] = true
+		localPaths[name] = path
+
+		dat, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("could not read file: %w", err)
+		}
+
+		if remote, ok := knownRemoteFiles[name]; ok {
+			if !remote.SameContent(dat) {
+				d.mismatched = append(d.mismatched, name)
+			}
+			return nil
+		}
+		if existing := findContent(dat, wallpapers.GetFileCRC(dat)); existing != "" && !*allowDuplicates {
+			sameAs[name] = existing
+			return nil
+		}
+		d.missing = append(d.missing, name)
+		return nil
+	})
+	if err != nil {
+		return d, err
+	}
+
+	// Content stored under the name of another local file is a duplicate
+	// a sync leaves alone. If that name is gone locally, the file was
+	// renamed and a sync would move it.
+	for name, existing := range sameAs {
+		if local[existing] {
+			d.deduped = append(d.deduped, name)
+		} else {
+			d.missing = append(d.missing, name)
+		}
+	}
+
+	for name := range knownRemoteFiles {
+		if !local[name] && !ignored[name] {
+			d.orphaned = append(d.orphaned, name)
+		}
+	}
+
+	for _, names := range [][]string{d.mismatched, d.missing, d.orphaned, d.deduped} {
+		slices.Sort(names)
+	}
+	return d, nil
+}
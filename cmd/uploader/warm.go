@@ -0,0 +1,31 @@
+package main
+
+import (
+	"context"
+
+	"github.com/icco/wallpapers"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+)
+
+// warmConcurrency is how many uploads have their renditions requested at
+// once. Further uploads wait for a slot.
+const warmConcurrency = 4
+
+// warming requests imgix renditions of uploaded files while the sync goes
+// on. main waits for it before exiting.
+var warming = func() *errgroup.Group {
+	g := &errgroup.Group{}
+	g.SetLimit(warmConcurrency)
+	return g
+}()
+
+// warm requests the renditions of name in the background.
+func warm(ctx context.Context, name string) {
+	warming.Go(func() error {
+		if err := wallpapers.Warm(ctx, name, cfg.WarmSizes); err != nil {
+			log.Warnw("could not warm renditions", "file", name, zap.Error(err))
+		}
+		return nil
+	})
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+
+	"github.com/icco/wallpapers"
+	"go.uber.org/zap"
+)
+
+func add(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("add", flag.ExitOnError)
+	source := fs.String("source", "", "where the image came from, defaults to the url")
+	author := fs.String("author", "", "who made the image")
+	license := fs.String("license", "", "license the image is available under")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if fs.NArg() == 0 {
+		return errors.New("add requires at least one url")
+	}
+
+	notifier := cfg.Notifier()
+	for _, u := range fs.Args() {
+		attr := wallpapers.Attribution{
+			SourceURL: *source,
+			Author:    *author,
+			License:   *license,
+		}
+		if attr.SourceURL == "" {
+			attr.SourceURL = u
+		}
+
+		name, err := wallpapers.UploadFromURL(ctx, u, wallpapers.WithAttribution(attr))
+		var dup *wallpapers.DuplicateError
+		if errors.As(err, &dup) {
+			log.Infow("already have, skipping", "url", u, "file", dup.Existing)
+			continue
+		}
+		var rejected *wallpapers.RejectedError
+		if errors.As(err, &rejected) {
+			log.Warnw("quarantined", "url", u, "file", rejected.Name, "reason", rejected.Reason)
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("could not add %q: %w", u, err)
+		}
+		log.Infow("added", "url", u, "file", name, "cdn", wallpapers.FullRezURL(ctx, name))
+
+		if err := notifier.NewWallpaper(ctx, name); err != nil {
+			log.Warnw("could not send notification", "file", name, zap.Error(err))
+		}
+		if err := wallpapers.Warm(ctx, name, cfg.WarmSizes); err != nil {
+			log.Warnw("could not warm renditions", "file", name, zap.Error(err))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/icco/wallpapers"
+)
+
+// alt sets the alt text of a wallpaper, or clears it when no text is given.
+func alt(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: walls alt <file> [text]")
+	}
+
+	name, text := args[0], strings.Join(args[1:], " ")
+	if err := wallpapers.SetAltText(ctx, name, text); err != nil {
+		return err
+	}
+
+	if text == "" {
+		log.Infow("cleared alt text", "file", name)
+	} else {
+		log.Infow("updated alt text", "file", name)
+	}
+	return nil
+}
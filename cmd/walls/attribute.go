@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+
+	"github.com/icco/wallpapers"
+)
+
+func attribute(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("attribute", flag.ExitOnError)
+	source := fs.String("source", "", "where the image came from")
+	author := fs.String("author", "", "who made the image")
+	license := fs.String("license", "", "license the image is available under")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if fs.NArg() != 1 {
+		return errors.New("usage: walls attribute [flags] <file>")
+	}
+
+	attr := wallpapers.Attribution{
+		SourceURL: *source,
+		Author:    *author,
+		License:   *license,
+	}
+	if attr == (wallpapers.Attribution{}) {
+		return errors.New("nothing to set")
+	}
+
+	if err := wallpapers.SetAttribution(ctx, fs.Arg(0), attr); err != nil {
+		return err
+	}
+
+	log.Infow("updated attribution", "file", fs.Arg(0))
+	return nil
+}
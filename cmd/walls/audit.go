@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/icco/wallpapers"
+)
+
+// audit prints the audit log, oldest first.
+func audit(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("audit", flag.ExitOnError)
+	name := fs.String("name", "", "only show changes to this file")
+	since := fs.Duration("since", 0, "only show changes made within this long")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var cutoff time.Time
+	if *since > 0 {
+		cutoff = time.Now().Add(-*since)
+	}
+
+	entries, err := wallpapers.AuditEntries(ctx, wallpapers.AuditQuery{Name: *name, Since: cutoff})
+	if err != nil {
+		return err
+	}
+
+	for _, e := range slices.Backward(entries) {
+		fmt.Printf("%s\t%s\t%s\t%s\t%s\n", e.Time.Format(time.RFC3339), e.Actor, e.Action, e.Name, describeChange(e))
+	}
+
+	return nil
+}
+
+// describeChange summarizes an entry's attributes as key=before->after.
+func describeChange(e *wallpapers.AuditEntry) string {
+	keys := slices.Sorted(maps.Keys(e.Before))
+	for k := range e.After {
+		if _, ok := e.Before[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	slices.Sort(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, fmt.Sprintf("%s=%s->%s", k, e.Before[k], e.After[k]))
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,44 @@
+//go:build darwin
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var resolutionRegex = regexp.MustCompile(`Resolution: (\d+) x (\d+)`)
+
+// displaySize returns the resolution of the first display reported by
+// system_profiler.
+func displaySize(ctx context.Context) (int, int, error) {
+	out, err := exec.CommandContext(ctx, "system_profiler", "SPDisplaysDataType").Output()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	m := resolutionRegex.FindSubmatch(out)
+	if m == nil {
+		return 0, 0, errors.New("no resolution in system_profiler output")
+	}
+
+	w, _ := strconv.Atoi(string(m[1]))
+	h, _ := strconv.Atoi(string(m[2]))
+	return w, h, nil
+}
+
+// setDesktop sets path as the picture of every desktop.
+func setDesktop(ctx context.Context, path string) error {
+	script := fmt.Sprintf(`tell application "System Events" to tell every desktop to set picture to %q`, strings.ReplaceAll(path, `"`, ``))
+	// #nosec G204 -- the script only embeds our own cache path
+	if out, err := exec.CommandContext(ctx, "osascript", "-e", script).CombinedOutput(); err != nil {
+		return fmt.Errorf("osascript: %w: %s", err, out)
+	}
+
+	return nil
+}
@@ -0,0 +1,59 @@
+//go:build linux
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os/exec"
+	"regexp"
+	"strconv"
+)
+
+var resolutionRegex = regexp.MustCompile(`current (\d+) x (\d+)`)
+
+// displaySize returns the size of the X screen reported by xrandr.
+func displaySize(ctx context.Context) (int, int, error) {
+	out, err := exec.CommandContext(ctx, "xrandr", "--current").Output()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	m := resolutionRegex.FindSubmatch(out)
+	if m == nil {
+		return 0, 0, errors.New("no resolution in xrandr output")
+	}
+
+	w, _ := strconv.Atoi(string(m[1]))
+	h, _ := strconv.Atoi(string(m[2]))
+	return w, h, nil
+}
+
+// setDesktop sets path as the background with gsettings on GNOME, or with
+// feh on other window managers.
+func setDesktop(ctx context.Context, path string) error {
+	if _, err := exec.LookPath("gsettings"); err == nil {
+		uri := (&url.URL{Scheme: "file", Path: path}).String()
+		for _, key := range []string{"picture-uri", "picture-uri-dark"} {
+			// #nosec G204 -- the arguments are our own cache path
+			out, err := exec.CommandContext(ctx, "gsettings", "set", "org.gnome.desktop.background", key, uri).CombinedOutput()
+			// picture-uri-dark only exists on GNOME 42 and later.
+			if err != nil && key == "picture-uri" {
+				return fmt.Errorf("gsettings: %w: %s", err, out)
+			}
+		}
+		return nil
+	}
+
+	if _, err := exec.LookPath("feh"); err == nil {
+		// #nosec G204 -- the argument is our own cache path
+		if out, err := exec.CommandContext(ctx, "feh", "--bg-fill", path).CombinedOutput(); err != nil {
+			return fmt.Errorf("feh: %w: %s", err, out)
+		}
+		return nil
+	}
+
+	return errors.New("neither gsettings nor feh is installed")
+}
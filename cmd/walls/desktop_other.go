@@ -0,0 +1,18 @@
+//go:build !darwin && !linux
+
+package main
+
+import (
+	"context"
+	"errors"
+)
+
+var errDesktopUnsupported = errors.New("setting the desktop background is not supported on this platform")
+
+func displaySize(context.Context) (int, int, error) {
+	return 0, 0, errDesktopUnsupported
+}
+
+func setDesktop(context.Context, string) error {
+	return errDesktopUnsupported
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/icco/wallpapers"
+)
+
+// doctor reports names that collide once formatted, which would make one
+// wallpaper overwrite another.
+func doctor(ctx context.Context, args []string) error {
+	fset := flag.NewFlagSet("doctor", flag.ExitOnError)
+	dir := fset.String("dir", "", "also check a local wallpaper directory")
+	if err := fset.Parse(args); err != nil {
+		return err
+	}
+
+	problems := 0
+
+	// Objects the uploader did not name can be replaced by one it did.
+	for f, err := range wallpapers.Files(ctx) {
+		if err != nil {
+			return err
+		}
+
+		if want := wallpapers.FormatName(f.Name); want != f.Name {
+			problems++
+			log.Warnw("remote name is not formatted and may collide", "file", f.Name, "formatted", want)
+		}
+	}
+
+	if *dir != "" {
+		groups := map[string][]string{}
+		err := filepath.WalkDir(*dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() || strings.HasPrefix(d.Name(), ".") {
+				return nil
+			}
+
+			name := wallpapers.FormatName(d.Name())
+			groups[name] = append(groups[name], path)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, name := range slices.Sorted(maps.Keys(groups)) {
+			paths := groups[name]
+			if len(paths) < 2 {
+				continue
+			}
+
+			crcs := map[uint32]bool{}
+			for _, p := range paths {
+				dat, err := os.ReadFile(p)
+				if err != nil {
+					return fmt.Errorf("could not read file: %w", err)
+				}
+				crcs[wallpapers.GetFileCRC(dat)] = true
+			}
+
+			problems++
+			if len(crcs) == 1 {
+				log.Warnw("local files are copies of the same picture", "name", name, "files", paths)
+			} else {
+				log.Warnw("local files collide", "name", name, "files", paths, "pictures", len(crcs))
+			}
+		}
+	}
+
+	if problems > 0 {
+		return fmt.Errorf("found %d problems", problems)
+	}
+
+	log.Infow("no problems found")
+	return nil
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+
+	"github.com/icco/wallpapers"
+	"go.uber.org/zap"
+)
+
+// edit sets the attribution of every wallpaper matching a query.
+func edit(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("edit", flag.ExitOnError)
+	query := fs.String("query", "", "edit wallpapers whose name or author contains this")
+	source := fs.String("source", "", "where the images came from")
+	author := fs.String("author", "", "who made the images")
+	license := fs.String("license", "", "license the images are available under")
+	dryRun := fs.Bool("n", false, "list the wallpapers that would change without changing them")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *query == "" || fs.NArg() != 0 {
+		return errors.New("usage: walls edit -query q [flags]")
+	}
+
+	attr := wallpapers.Attribution{
+		SourceURL: *source,
+		Author:    *author,
+		License:   *license,
+	}
+	if attr == (wallpapers.Attribution{}) {
+		return errors.New("nothing to set")
+	}
+
+	// Collect the matches first, since editing an author can change what
+	// the query matches.
+	var files []*wallpapers.File
+	for f, err := range wallpapers.Files(ctx) {
+		if err != nil {
+			return err
+		}
+		if matchesQuery(f.Name, f.Author, *query) && f.Attribution != merge(f.Attribution, attr) {
+			files = append(files, f)
+		}
+	}
+
+	var failed int
+	for _, f := range files {
+		if *dryRun {
+			fmt.Println(f.Name)
+			continue
+		}
+
+		if err := wallpapers.SetAttribution(ctx, f.Name, attr); err != nil {
+			failed++
+			log.Errorw("could not update attribution", "file", f.Name, zap.Error(err))
+			continue
+		}
+		log.Infow("updated attribution", "file", f.Name)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d files failed", failed, len(files))
+	}
+
+	return nil
+}
+
+// merge returns a with the non-empty fields of b, as SetAttribution stores
+// them.
+func merge(a, b wallpapers.Attribution) wallpapers.Attribution {
+	if b.SourceURL != "" {
+		a.SourceURL = b.SourceURL
+	}
+	if b.Author != "" {
+		a.Author = b.Author
+	}
+	if b.License != "" {
+		a.License = b.License
+	}
+	return a
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/icco/wallpapers"
+	"github.com/icco/wallpapers/cmd/server/static"
+	"github.com/icco/wallpapers/cmd/server/templates"
+)
+
+// imagePage is the server's image page template, so exported pages look
+// the same as served ones.
+var imagePage = template.Must(template.ParseFS(templates.Templates, "image.tmpl"))
+
+// searchEntry is an element of the exported search index.
+type searchEntry struct {
+	Name      string `json:"key"`
+	Page      string `json:"page"`
+	Thumbnail string `json:"thumbnail"`
+	Author    string `json:"author,omitempty"`
+}
+
+func export(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	out := fs.String("out", "site", "directory to write the site to")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	files, err := wallpapers.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := copyAssets(*out); err != nil {
+		return fmt.Errorf("could not copy assets: %w", err)
+	}
+
+	if err := writeJSON(filepath.Join(*out, "all.json"), files); err != nil {
+		return err
+	}
+
+	index := make([]searchEntry, 0, len(files))
+	for _, f := range files {
+		page := filepath.Join("image", f.Name)
+		if err := writeImagePage(filepath.Join(*out, page), f); err != nil {
+			return fmt.Errorf("could not write page for %q: %w", f.Name, err)
+		}
+
+		index = append(index, searchEntry{
+			Name:      f.Name,
+			Page:      "/" + filepath.ToSlash(page) + "/",
+			Thumbnail: f.ThumbnailURL,
+			Author:    f.Author,
+		})
+	}
+
+	if err := writeJSON(filepath.Join(*out, "search.json"), index); err != nil {
+		return err
+	}
+
+	log.Infow("exported site", "dir", *out, "images", len(files))
+	return nil
+}
+
+// copyAssets writes the server's embedded static files, including the gallery
+// index, to dir.
+func copyAssets(dir string) error {
+	return fs.WalkDir(static.Assets, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if filepath.Ext(path) == ".go" {
+			return nil
+		}
+
+		target := filepath.Join(dir, path)
+		if d.IsDir() {
+			return os.MkdirAll(target, 0750)
+		}
+
+		dat, err := fs.ReadFile(static.Assets, path)
+		if err != nil {
+			return err
+		}
+
+		return os.WriteFile(target, dat, 0600)
+	})
+}
+
+func writeImagePage(dir string, f *wallpapers.File) error {
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return err
+	}
+
+	out, err := os.Create(filepath.Join(dir, "index.html"))
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	if err := imagePage.Execute(out, templates.ImagePage{File: f, Root: "../../"}); err != nil {
+		return err
+	}
+
+	return out.Close()
+}
+
+func writeJSON(path string, v any) error {
+	dat, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(path, dat, 0600); err != nil {
+		return fmt.Errorf("could not write %q: %w", path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"sync/atomic"
+
+	"github.com/icco/wallpapers"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+)
+
+// fsck downloads every file and checks it against its stored size and
+// checksums.
+func fsck(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("fsck", flag.ExitOnError)
+	concurrency := fs.Int("concurrency", 4, "how many files to check at once")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var checked, corrupt, failed atomic.Int64
+	var g errgroup.Group
+	g.SetLimit(max(*concurrency, 1))
+	for f, err := range wallpapers.Files(ctx) {
+		if err != nil {
+			return err
+		}
+
+		g.Go(func() error {
+			checked.Add(1)
+			err := checkFile(ctx, f)
+			switch {
+			case errors.Is(err, wallpapers.ErrCorrupt):
+				corrupt.Add(1)
+				log.Errorw("corrupt", "file", f.Name, zap.Error(err))
+			case err != nil:
+				failed.Add(1)
+				log.Errorw("could not check", "file", f.Name, zap.Error(err))
+			default:
+				log.Debugw("ok", "file", f.Name)
+			}
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return err
+	}
+
+	log.Infow("checked files", "checked", checked.Load(), "corrupt", corrupt.Load(), "failed", failed.Load())
+	if corrupt.Load() > 0 || failed.Load() > 0 {
+		return fmt.Errorf("%d corrupt and %d unreadable files", corrupt.Load(), failed.Load())
+	}
+
+	return nil
+}
+
+func checkFile(ctx context.Context, f *wallpapers.File) error {
+	rc, err := wallpapers.OpenFile(ctx, f.Name)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	content, err := io.ReadAll(rc)
+	if err != nil {
+		return fmt.Errorf("failed read: %w", err)
+	}
+
+	return wallpapers.Verify(f, content)
+}
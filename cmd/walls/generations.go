@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/icco/wallpapers"
+)
+
+// generations lists the stored generations of a file or restores one of
+// them, and turns bucket versioning on or off.
+func generations(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("generations", flag.ExitOnError)
+	enable := fs.Bool("enable", false, "keep previous generations of overwritten and deleted files")
+	disable := fs.Bool("disable", false, "stop keeping previous generations")
+	restore := fs.Int64("restore", 0, "make this generation of the file the live one")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *enable || *disable {
+		if *enable && *disable {
+			return errors.New("only one of -enable and -disable may be set")
+		}
+		if err := wallpapers.SetVersioning(ctx, *enable); err != nil {
+			return err
+		}
+		log.Infow("updated versioning", "enabled", *enable)
+		return nil
+	}
+
+	// Without a file, report whether versioning is on.
+	if fs.NArg() == 0 && *restore == 0 {
+		on, err := wallpapers.Versioning(ctx)
+		if err != nil {
+			return err
+		}
+		log.Infow("versioning", "enabled", on)
+		return nil
+	}
+	if fs.NArg() != 1 {
+		return errors.New("usage: walls generations [-restore <generation>] <file>")
+	}
+	name := fs.Arg(0)
+
+	if *restore != 0 {
+		if err := wallpapers.RestoreGeneration(ctx, name, *restore); err != nil {
+			return err
+		}
+		log.Infow("restored", "file", name, "generation", *restore)
+		return nil
+	}
+
+	gens, err := wallpapers.ListGenerations(ctx, name)
+	if err != nil {
+		return err
+	}
+	for _, f := range gens {
+		state := "live"
+		if !f.Deleted.IsZero() {
+			state = "replaced " + f.Deleted.Format(time.RFC3339)
+		}
+		fmt.Printf("%d\t%d\t%08x\t%s\t%s\n", f.Generation, f.Size, f.CRC32C, f.Created.Format(time.RFC3339), state)
+	}
+
+	return nil
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/icco/wallpapers"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+)
+
+// group links lower resolution copies of the same artwork to the highest
+// resolution one, so listings show each artwork once.
+func group(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("group", flag.ExitOnError)
+	concurrency := fs.Int("concurrency", 4, "how many files to read at once")
+	distance := fs.Int("distance", wallpapers.DefaultVariantDistance, "largest perceptual hash distance treated as the same artwork")
+	dryRun := fs.Bool("n", false, "report groups without recording them")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var (
+		hashes []wallpapers.ImageHash
+		linked = map[string]string{}
+	)
+	failed, err := hashImages(ctx, *concurrency, func(f *wallpapers.File, h wallpapers.ImageHash) {
+		hashes = append(hashes, h)
+		if f.VariantOf != "" {
+			linked[f.Name] = f.VariantOf
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	// Files that were linked but no longer match anything become
+	// wallpapers of their own again.
+	want := map[string]string{}
+	for _, grp := range wallpapers.GroupVariants(hashes, *distance) {
+		names := make([]string, 0, len(grp))
+		for _, h := range grp {
+			names = append(names, fmt.Sprintf("%s (%dx%d)", h.Name, h.Width, h.Height))
+		}
+		log.Infow("variants", "primary", grp[0].Name, "files", names)
+
+		for _, h := range grp[1:] {
+			want[h.Name] = grp[0].Name
+		}
+	}
+	for _, h := range hashes {
+		if want[h.Name] == linked[h.Name] || *dryRun {
+			continue
+		}
+
+		if err := wallpapers.SetVariantOf(ctx, h.Name, want[h.Name]); err != nil {
+			failed++
+			log.Errorw("could not record variant", "file", h.Name, zap.Error(err))
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d files failed", failed)
+	}
+
+	return nil
+}
+
+// hashImages downloads every image in the collection, concurrency at a
+// time, and calls found with its perceptual hash. Calls to found do not
+// overlap. It returns how many images could not be hashed.
+func hashImages(ctx context.Context, concurrency int, found func(f *wallpapers.File, h wallpapers.ImageHash)) (int64, error) {
+	var (
+		mu     sync.Mutex
+		failed atomic.Int64
+	)
+	var g errgroup.Group
+	g.SetLimit(max(concurrency, 1))
+	for f, err := range wallpapers.Files(ctx) {
+		if err != nil {
+			return 0, err
+		}
+		if f.Type != wallpapers.TypeImage {
+			continue
+		}
+
+		g.Go(func() error {
+			content, err := wallpapers.DownloadFile(ctx, f.Name)
+			if err != nil {
+				failed.Add(1)
+				log.Errorw("could not download", "file", f.Name, zap.Error(err))
+				return nil
+			}
+
+			hash, w, h, err := wallpapers.PerceptualHash(content)
+			if err != nil {
+				failed.Add(1)
+				log.Errorw("could not hash", "file", f.Name, zap.Error(err))
+				return nil
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			found(f, wallpapers.ImageHash{Name: f.Name, Hash: hash, Width: w, Height: h, Size: f.Size})
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return 0, err
+	}
+
+	return failed.Load(), nil
+}
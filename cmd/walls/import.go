@@ -0,0 +1,367 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/icco/wallpapers"
+	"github.com/icco/wallpapers/config"
+	"github.com/icco/wallpapers/notify"
+	"go.uber.org/zap"
+)
+
+const redditUserAgent = "walls-importer/1.0 (+https://github.com/icco/wallpapers)"
+
+// redditListing is the subset of a reddit listing response we use.
+type redditListing struct {
+	Data struct {
+		Children []struct {
+			Data struct {
+				Title     string `json:"title"`
+				Author    string `json:"author"`
+				URL       string `json:"url"`
+				Permalink string `json:"permalink"`
+				PostHint  string `json:"post_hint"`
+				Over18    bool   `json:"over_18"`
+			} `json:"data"`
+		} `json:"children"`
+	} `json:"data"`
+}
+
+// unsplashPhoto is the subset of an Unsplash photo we use.
+type unsplashPhoto struct {
+	Width       int    `json:"width"`
+	Height      int    `json:"height"`
+	Description string `json:"alt_description"`
+	URLs        struct {
+		Raw string `json:"raw"`
+	} `json:"urls"`
+	Links struct {
+		HTML             string `json:"html"`
+		DownloadLocation string `json:"download_location"`
+	} `json:"links"`
+	User struct {
+		Name string `json:"name"`
+	} `json:"user"`
+}
+
+func importCmd(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return errors.New("import requires a source")
+	}
+
+	switch args[0] {
+	case "reddit":
+		return importReddit(ctx, args[1:])
+	case "unsplash":
+		return importUnsplash(ctx, args[1:])
+	default:
+		return fmt.Errorf("unsupported import source %q", args[0])
+	}
+}
+
+// importer adds images from another site to the collection, skipping ones
+// that are too small or that the collection already has.
+type importer struct {
+	minWidth, minHeight int
+	dryRun              bool
+	similar             bool
+	distance            int
+	concurrency         int
+
+	notifier *notify.Notifier
+	// known are the collection's files by the CRC32C of their original
+	// content.
+	known map[uint32][]*wallpapers.File
+	// hashes are the perceptual hashes of the images seen in this run and,
+	// with -similar, of the whole collection.
+	hashes []wallpapers.ImageHash
+}
+
+// newImporter adds the flags shared by every import source to fs.
+func newImporter(fs *flag.FlagSet) *importer {
+	im := &importer{}
+	fs.IntVar(&im.minWidth, "min-width", 1920, "skip images narrower than this")
+	fs.IntVar(&im.minHeight, "min-height", 1080, "skip images shorter than this")
+	fs.BoolVar(&im.dryRun, "dry-run", false, "only list what would be imported")
+	fs.BoolVar(&im.similar, "similar", false, "also skip images that look like one in the collection, which downloads every wallpaper first")
+	fs.IntVar(&im.distance, "distance", wallpapers.DefaultVariantDistance, "largest perceptual hash distance treated as the same artwork")
+	fs.IntVar(&im.concurrency, "concurrency", 4, "how many files to read at once with -similar")
+	return im
+}
+
+// load indexes the collection. Call it after the flags are parsed.
+func (im *importer) load(ctx context.Context) error {
+	im.notifier = cfg.Notifier()
+	known, err := wallpapers.IndexByCRC(ctx)
+	if err != nil {
+		return err
+	}
+	im.known = known
+
+	if !im.similar {
+		return nil
+	}
+	failed, err := hashImages(ctx, im.concurrency, func(_ *wallpapers.File, h wallpapers.ImageHash) {
+		im.hashes = append(im.hashes, h)
+	})
+	if err != nil {
+		return err
+	}
+	if failed > 0 {
+		log.Warnw("some wallpapers could not be hashed and may be imported again", "failed", failed)
+	}
+	return nil
+}
+
+// add downloads the image at u and uploads it with attr, unless it is too
+// small, has the same content as a wallpaper, or looks like an image at
+// least as large that is in the collection or was seen earlier in this run.
+// It returns the name of the new wallpaper, or "" if it was skipped.
+func (im *importer) add(ctx context.Context, u string, attr wallpapers.Attribution, title string) (string, error) {
+	d, err := wallpapers.FetchURL(ctx, u)
+	if err != nil {
+		log.Warnw("could not fetch", "url", u, zap.Error(err))
+		return "", nil
+	}
+
+	if d.Width < im.minWidth || d.Height < im.minHeight {
+		log.Infow("too small, skipping", "url", u, "width", d.Width, "height", d.Height)
+		return "", nil
+	}
+
+	for _, f := range im.known[wallpapers.GetFileCRC(d.Content)] {
+		if f.SameContent(d.Content) {
+			log.Infow("already have, skipping", "url", u, "file", f.Name)
+			return "", nil
+		}
+	}
+
+	hash, w, h, err := wallpapers.PerceptualHash(d.Content)
+	if err != nil {
+		log.Warnw("could not hash", "url", u, zap.Error(err))
+		return "", nil
+	}
+	for _, seen := range im.hashes {
+		if wallpapers.HashDistance(seen.Hash, hash) <= im.distance && seen.Width*seen.Height >= w*h {
+			log.Infow("looks like one we have, skipping", "url", u, "file", seen.Name)
+			return "", nil
+		}
+	}
+	im.hashes = append(im.hashes, wallpapers.ImageHash{Name: d.Name, Hash: hash, Width: w, Height: h, Size: int64(len(d.Content))})
+
+	if im.dryRun {
+		log.Infow("would import", "url", u, "file", d.Name, "title", title)
+		return "", nil
+	}
+
+	// Content seen earlier in this run has the same perceptual hash, so
+	// the index does not need to include it.
+	name, err := wallpapers.UploadNew(ctx, d.Name, d.Content, wallpapers.WithAttribution(attr), wallpapers.WithIndex(im.known))
+	var dup *wallpapers.DuplicateError
+	if errors.As(err, &dup) {
+		log.Infow("already have, skipping", "url", u, "file", dup.Existing)
+		return "", nil
+	}
+	var rejected *wallpapers.RejectedError
+	if errors.As(err, &rejected) {
+		log.Warnw("quarantined", "url", u, "file", d.Name, "reason", rejected.Reason)
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("could not upload %q: %w", d.Name, err)
+	}
+	im.hashes[len(im.hashes)-1].Name = name
+	log.Infow("imported", "url", u, "file", name, "title", title)
+
+	if err := im.notifier.NewWallpaper(ctx, name); err != nil {
+		log.Warnw("could not send notification", "file", name, zap.Error(err))
+	}
+	if err := wallpapers.Warm(ctx, name, cfg.WarmSizes); err != nil {
+		log.Warnw("could not warm renditions", "file", name, zap.Error(err))
+	}
+	return name, nil
+}
+
+func importReddit(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("import reddit", flag.ExitOnError)
+	top := fs.String("top", "week", "time range for top posts: hour, day, week, month, year or all")
+	limit := fs.Int("limit", 25, "number of posts to look at")
+	im := newImporter(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if fs.NArg() != 1 {
+		return errors.New("usage: walls import reddit [flags] r/<subreddit>")
+	}
+	sub := strings.TrimPrefix(strings.TrimPrefix(fs.Arg(0), "/"), "r/")
+
+	if err := im.load(ctx); err != nil {
+		return err
+	}
+
+	listing, err := fetchReddit(ctx, sub, *top, *limit)
+	if err != nil {
+		return err
+	}
+
+	for _, child := range listing.Data.Children {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		post := child.Data
+		if post.Over18 || post.PostHint != "image" {
+			continue
+		}
+
+		attr := wallpapers.Attribution{
+			SourceURL: "https://www.reddit.com" + post.Permalink,
+			Author:    "u/" + post.Author,
+		}
+		if _, err := im.add(ctx, post.URL, attr, post.Title); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func fetchReddit(ctx context.Context, sub, top string, limit int) (*redditListing, error) {
+	q := url.Values{}
+	q.Set("t", top)
+	q.Set("limit", fmt.Sprint(limit))
+	u := fmt.Sprintf("https://www.reddit.com/r/%s/top.json?%s", url.PathEscape(sub), q.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", redditUserAgent)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch listing: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("could not fetch listing: %s", resp.Status)
+	}
+
+	var listing redditListing
+	if err := json.NewDecoder(resp.Body).Decode(&listing); err != nil {
+		return nil, fmt.Errorf("could not decode listing: %w", err)
+	}
+
+	return &listing, nil
+}
+
+func importUnsplash(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("import unsplash", flag.ExitOnError)
+	limit := fs.Int("limit", 25, "number of photos to look at, at most 30")
+	im := newImporter(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if fs.NArg() != 1 {
+		return errors.New("usage: walls import unsplash [flags] <query>")
+	}
+	if cfg.UnsplashAccessKey == "" {
+		return fmt.Errorf("importing from Unsplash needs unsplash_access_key or $%s", config.UnsplashAccessKeyEnv)
+	}
+
+	if err := im.load(ctx); err != nil {
+		return err
+	}
+
+	photos, err := searchUnsplash(ctx, fs.Arg(0), *limit)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range photos {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		// The size is known up front, so small photos are not downloaded.
+		if p.Width < im.minWidth || p.Height < im.minHeight {
+			log.Infow("too small, skipping", "photo", p.Links.HTML, "width", p.Width, "height", p.Height)
+			continue
+		}
+
+		attr := wallpapers.Attribution{
+			SourceURL: p.Links.HTML,
+			Author:    p.User.Name,
+			License:   "Unsplash License",
+		}
+		name, err := im.add(ctx, p.URLs.Raw, attr, p.Description)
+		if err != nil {
+			return err
+		}
+
+		// Unsplash's API guidelines ask for every photo used to be reported
+		// as downloaded.
+		if name != "" {
+			if err := unsplashGet(ctx, p.Links.DownloadLocation, nil); err != nil {
+				log.Warnw("could not report download", "photo", p.Links.HTML, zap.Error(err))
+			}
+		}
+	}
+
+	return nil
+}
+
+func searchUnsplash(ctx context.Context, query string, limit int) ([]unsplashPhoto, error) {
+	q := url.Values{}
+	q.Set("query", query)
+	q.Set("orientation", "landscape")
+	q.Set("per_page", fmt.Sprint(min(max(limit, 1), 30)))
+
+	var result struct {
+		Results []unsplashPhoto `json:"results"`
+	}
+	if err := unsplashGet(ctx, "https://api.unsplash.com/search/photos?"+q.Encode(), &result); err != nil {
+		return nil, fmt.Errorf("could not search photos: %w", err)
+	}
+
+	return result.Results, nil
+}
+
+// unsplashGet calls the Unsplash API and decodes the response into v, if v
+// is not nil.
+func unsplashGet(ctx context.Context, u string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept-Version", "v1")
+	req.Header.Set("Authorization", "Client-ID "+cfg.UnsplashAccessKey)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.New(resp.Status)
+	}
+	if v == nil {
+		return nil
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("could not decode response: %w", err)
+	}
+	return nil
+}
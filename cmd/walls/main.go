@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
+
+	"github.com/icco/wallpapers"
+	"github.com/icco/wallpapers/config"
+	"go.uber.org/zap"
+)
+
+var (
+	log *zap.SugaredLogger
+	cfg = config.New()
+)
+
+// command is a walls subcommand. It receives the arguments after its name.
+type command struct {
+	usage string
+	run   func(ctx context.Context, args []string) error
+}
+
+var commands = map[string]command{
+	"add":         {"add <url>: download an image and add it to the collection", add},
+	"alt":         {"alt <file> [text]: set a wallpaper's alt text, or clear it", alt},
+	"attribute":   {"attribute <file>: set the source, author and license of a wallpaper", attribute},
+	"audit":       {"audit [-name <file>] [-since <duration>]: print the audit log", audit},
+	"doctor":      {"doctor [-dir <dir>]: report names that collide once formatted", doctor},
+	"edit":        {"edit -query q [-source s] [-author a] [-license l] [-n]: set the attribution of matching wallpapers", edit},
+	"export":      {"export -out <dir>: render a static copy of the gallery", export},
+	"fsck":        {"fsck: check every file against its stored checksums", fsck},
+	"generations": {"generations [-enable|-disable] [[-restore <generation>] <file>]: list or restore previous versions of a file", generations},
+	"group":       {"group [-n] [-distance d]: link lower resolution copies of the same artwork", group},
+	"import":      {"import reddit r/<subreddit> | unsplash <query>: import top images from a subreddit or an Unsplash search", importCmd},
+	"mirror":      {"mirror status|sync: compare the mirror with the collection, or copy what it is missing", mirrorCmd},
+	"profiles":    {"profiles [-n]: record the color profile of images that have none", profiles},
+	"quarantine":  {"quarantine [-release <file>|-delete <file>]: list, release or discard rejected uploads", quarantine},
+	"set":         {"set [-random|-daily] [-query q]: set a wallpaper as the desktop background", set},
+	"stats":       {"stats [-json]: print counts by format and the total size of the collection", stats},
+	"storage":     {"storage class|lifecycle: move old originals to colder storage, or set bucket rules that do", storageCmd},
+}
+
+func main() {
+	flag.Usage = usage
+	cfg.RegisterFlags(flag.CommandLine)
+	flag.Parse()
+
+	l, err := zap.NewDevelopment(zap.AddStacktrace(zap.FatalLevel))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "could not create logger: %+v\n", err)
+		os.Exit(1)
+	}
+	log = l.Sugar()
+	wallpapers.SetLogger(log)
+	defer func() { _ = log.Sync() }()
+
+	if flag.NArg() < 1 {
+		usage()
+		os.Exit(2)
+	}
+
+	cmd, ok := commands[flag.Arg(0)]
+	if !ok {
+		usage()
+		os.Exit(2)
+	}
+
+	if err := cfg.Load(); err != nil {
+		log.Errorw("could not load config", zap.Error(err))
+		_ = log.Sync()
+		os.Exit(1)
+	}
+
+	store, err := cfg.DefaultStore()
+	if err != nil {
+		log.Errorw("could not configure store", zap.Error(err))
+		_ = log.Sync()
+		os.Exit(1)
+	}
+	wallpapers.SetStore(store)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	err = cmd.run(ctx, flag.Args()[1:])
+	stop()
+	if err != nil {
+		log.Errorw("command failed", "command", flag.Arg(0), zap.Error(err))
+		_ = log.Sync()
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	var lines []string
+	for _, cmd := range commands {
+		lines = append(lines, "  "+cmd.usage)
+	}
+	slices.Sort(lines)
+
+	fmt.Fprintf(flag.CommandLine.Output(), "usage: walls <command> [flags] [args]\n\n%s\n", strings.Join(lines, "\n"))
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+
+	"github.com/icco/wallpapers"
+	"github.com/icco/wallpapers/config"
+	"go.uber.org/zap"
+)
+
+// mirrorCmd reports how far the mirror of the default collection is behind,
+// or copies what it is missing.
+func mirrorCmd(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: walls mirror status|sync [flags]")
+	}
+
+	dst, err := cfg.MirrorStore()
+	if err != nil {
+		return err
+	}
+	if dst == nil {
+		return fmt.Errorf("no mirror configured, set mirror in the config or %s", config.MirrorEnv)
+	}
+
+	switch args[0] {
+	case "status":
+		return mirrorStatus(ctx, dst, args[1:])
+	case "sync":
+		return mirrorSync(ctx, dst, args[1:])
+	default:
+		return fmt.Errorf("unknown mirror command %q", args[0])
+	}
+}
+
+func mirrorStatus(ctx context.Context, dst wallpapers.Store, args []string) error {
+	fs := flag.NewFlagSet("mirror status", flag.ExitOnError)
+	all := fs.Bool("all", false, "also list files that are mirrored")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	entries, err := wallpapers.CompareMirror(ctx, wallpapers.StoreFor(ctx), dst)
+	if err != nil {
+		return err
+	}
+
+	counts := map[string]int{}
+	for _, e := range entries {
+		counts[e.State]++
+		if e.State != wallpapers.MirrorOK || *all {
+			fmt.Printf("%-8s %s\n", e.State, e.Name)
+		}
+	}
+	fmt.Printf("%d ok, %d missing, %d stale, %d only in mirror\n",
+		counts[wallpapers.MirrorOK], counts[wallpapers.MirrorMissing], counts[wallpapers.MirrorStale], counts[wallpapers.MirrorExtra])
+
+	return nil
+}
+
+func mirrorSync(ctx context.Context, dst wallpapers.Store, args []string) error {
+	fs := flag.NewFlagSet("mirror sync", flag.ExitOnError)
+	dryRun := fs.Bool("n", false, "report files without copying them")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	src := wallpapers.StoreFor(ctx)
+	entries, err := wallpapers.CompareMirror(ctx, src, dst)
+	if err != nil {
+		return err
+	}
+
+	var copied, failed int
+	for _, e := range entries {
+		if e.State != wallpapers.MirrorMissing && e.State != wallpapers.MirrorStale {
+			continue
+		}
+
+		log.Infow("mirroring", "file", e.Name, "state", e.State)
+		if *dryRun {
+			copied++
+			continue
+		}
+		if err := wallpapers.MirrorFile(ctx, src, dst, e.Name); err != nil {
+			failed++
+			log.Errorw("could not mirror", "file", e.Name, zap.Error(err))
+			continue
+		}
+		copied++
+	}
+
+	log.Infow("mirrored files", "files", copied, "failed", failed)
+	if failed > 0 {
+		return fmt.Errorf("%d files failed", failed)
+	}
+
+	return nil
+}
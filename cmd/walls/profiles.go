@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/icco/wallpapers"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+)
+
+// profiles records the color profile of images uploaded before the
+// uploader started doing so. Images without an embedded profile have
+// nothing to record, so they are read again on every run.
+func profiles(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("profiles", flag.ExitOnError)
+	concurrency := fs.Int("concurrency", 4, "how many files to read at once")
+	dryRun := fs.Bool("n", false, "report profiles without recording them")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var recorded, failed atomic.Int64
+	var g errgroup.Group
+	g.SetLimit(max(*concurrency, 1))
+	for f, err := range wallpapers.Files(ctx) {
+		if err != nil {
+			return err
+		}
+		if f.Type != wallpapers.TypeImage || f.ColorProfile != "" {
+			continue
+		}
+
+		g.Go(func() error {
+			profile, err := readProfile(ctx, f)
+			if err != nil {
+				failed.Add(1)
+				log.Errorw("could not read color profile", "file", f.Name, zap.Error(err))
+				return nil
+			}
+			if profile == "" {
+				return nil
+			}
+
+			log.Infow("color profile", "file", f.Name, "profile", profile)
+			if *dryRun {
+				return nil
+			}
+
+			if err := wallpapers.SetColorProfile(ctx, f.Name, profile); err != nil {
+				failed.Add(1)
+				log.Errorw("could not record color profile", "file", f.Name, zap.Error(err))
+				return nil
+			}
+			recorded.Add(1)
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return err
+	}
+
+	log.Infow("recorded color profiles", "recorded", recorded.Load(), "failed", failed.Load())
+	if failed.Load() > 0 {
+		return fmt.Errorf("%d files failed", failed.Load())
+	}
+
+	return nil
+}
+
+func readProfile(ctx context.Context, f *wallpapers.File) (string, error) {
+	content, err := wallpapers.DownloadFile(ctx, f.Name)
+	if err != nil {
+		return "", err
+	}
+
+	return wallpapers.ColorProfile(content)
+}
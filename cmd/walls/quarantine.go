@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+
+	"github.com/icco/wallpapers"
+)
+
+// quarantine lists uploads that failed validation, or releases or discards
+// one of them.
+func quarantine(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("quarantine", flag.ExitOnError)
+	release := fs.String("release", "", "move this file into the collection without validating it")
+	discard := fs.String("delete", "", "delete this file from quarantine")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	switch {
+	case *release != "" && *discard != "":
+		return errors.New("only one of -release and -delete may be set")
+	case *release != "":
+		if err := wallpapers.ReleaseQuarantined(ctx, *release); err != nil {
+			return err
+		}
+		log.Infow("released", "file", *release)
+		return nil
+	case *discard != "":
+		if err := wallpapers.DeleteQuarantined(ctx, *discard); err != nil {
+			return err
+		}
+		log.Infow("deleted", "file", *discard)
+		return nil
+	}
+
+	for f, err := range wallpapers.QuarantinedFiles(ctx) {
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s\t%d\t%s\n", f.Name, f.Size, f.Metadata[wallpapers.MetadataQuarantineReason])
+	}
+
+	return nil
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/icco/wallpapers"
+	"github.com/icco/wallpapers/client"
+	"go.uber.org/zap"
+)
+
+// Used when the display size cannot be detected.
+const (
+	defaultDisplayWidth  = 3840
+	defaultDisplayHeight = 2160
+)
+
+// set picks a wallpaper from the server, downloads it cropped to the
+// display and makes it the desktop background. Downloads are cached, so
+// setting the same wallpaper again does not download it.
+func set(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("set", flag.ExitOnError)
+	server := fs.String("server", client.DefaultBaseURL, "wallpapers server to use")
+	random := fs.Bool("random", false, "pick a random wallpaper (the default)")
+	daily := fs.Bool("daily", false, "pick the wallpaper of the day, the same one all day")
+	query := fs.String("query", "", "only pick wallpapers whose name or author contains this")
+	width := fs.Int("w", 0, "display width in pixels, detected if not set")
+	height := fs.Int("h", 0, "display height in pixels, detected if not set")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *random && *daily {
+		return errors.New("only one of -random and -daily may be set")
+	}
+
+	w, h := *width, *height
+	if w <= 0 || h <= 0 {
+		var err error
+		w, h, err = displaySize(ctx)
+		if err != nil {
+			log.Warnw("could not detect display size, using default", "w", defaultDisplayWidth, "h", defaultDisplayHeight, zap.Error(err))
+			w, h = defaultDisplayWidth, defaultDisplayHeight
+		}
+	}
+
+	c, err := client.New(*server, "")
+	if err != nil {
+		return err
+	}
+	files, err := c.ListImages(ctx)
+	if err != nil {
+		return fmt.Errorf("could not list wallpapers: %w", err)
+	}
+	files = slices.DeleteFunc(files, func(f *client.Image) bool {
+		return f.Type != wallpapers.TypeImage || !matchesQuery(f.Key, f.Author, *query)
+	})
+	if len(files) == 0 {
+		return errors.New("no wallpapers match")
+	}
+
+	var f *client.Image
+	if *daily {
+		// Sort by name so the pick only changes when the day does, not
+		// when wallpapers are added.
+		slices.SortFunc(files, func(a, b *client.Image) int { return strings.Compare(a.Key, b.Key) })
+		day := time.Now().Unix() / int64((24 * time.Hour).Seconds())
+		f = files[day%int64(len(files))]
+	} else {
+		f = files[rand.IntN(len(files))]
+	}
+
+	path, err := cachedFit(ctx, c, f.Key, w, h)
+	if err != nil {
+		return err
+	}
+
+	if err := setDesktop(ctx, path); err != nil {
+		return fmt.Errorf("could not set desktop background: %w", err)
+	}
+	log.Infow("set wallpaper", "file", f.Key, "w", w, "h", h, "path", path)
+
+	return nil
+}
+
+// matchesQuery reports whether q appears in a wallpaper's name or author.
+func matchesQuery(name, author, q string) bool {
+	q = strings.ToLower(q)
+	return strings.Contains(strings.ToLower(name), q) || strings.Contains(strings.ToLower(author), q)
+}
+
+// cachedFit returns the path of name cropped to w by h in the user's cache
+// directory, downloading it first if needed.
+func cachedFit(ctx context.Context, c *client.Client, name string, w, h int) (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	dir = filepath.Join(dir, "walls")
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return "", err
+	}
+
+	ext := filepath.Ext(name)
+	path := filepath.Join(dir, fmt.Sprintf("%s-%dx%d%s", strings.TrimSuffix(name, ext), w, h, ext))
+	if _, err := os.Stat(path); err == nil {
+		return path, nil
+	}
+
+	u, err := c.Fit(ctx, name, w, h)
+	if err != nil {
+		return "", fmt.Errorf("could not get %q: %w", name, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("could not download %q: %w", name, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("could not download %q: %s", name, resp.Status)
+	}
+
+	// Download next to the final path so a partial download is never
+	// used.
+	tmp, err := os.CreateTemp(dir, ".download-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, resp.Body); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("could not download %q: %w", name, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", err
+	}
+
+	return path, nil
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"text/tabwriter"
+
+	"github.com/icco/wallpapers"
+)
+
+// stats prints the same summary of the collection as /stats.json.
+func stats(ctx context.Context, args []string) error {
+	fset := flag.NewFlagSet("stats", flag.ExitOnError)
+	asJSON := fset.Bool("json", false, "print the summary as JSON")
+	if err := fset.Parse(args); err != nil {
+		return err
+	}
+
+	files, err := wallpapers.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+	s := wallpapers.Summarize(files)
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(s)
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "count\t%d\n", s.Count)
+	fmt.Fprintf(tw, "total size\t%d\n", s.TotalSize)
+	fmt.Fprintln(tw)
+	fmt.Fprintln(tw, "FORMAT\tCOUNT")
+	for _, format := range slices.Sorted(maps.Keys(s.Formats)) {
+		fmt.Fprintf(tw, "%s\t%d\n", format, s.Formats[format])
+	}
+	return tw.Flush()
+}
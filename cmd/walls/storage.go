@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"github.com/icco/wallpapers"
+	"go.uber.org/zap"
+)
+
+// storageCmd moves old originals to a colder storage class, or shows and
+// sets the bucket lifecycle rules that do so automatically.
+func storageCmd(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: walls storage class|lifecycle [flags]")
+	}
+
+	switch args[0] {
+	case "class":
+		return storageClass(ctx, args[1:])
+	case "lifecycle":
+		return storageLifecycle(ctx, args[1:])
+	default:
+		return fmt.Errorf("unknown storage command %q", args[0])
+	}
+}
+
+func storageClass(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("storage class", flag.ExitOnError)
+	class := fs.String("class", wallpapers.StorageNearline, "storage class to move files to")
+	olderThan := fs.Duration("older-than", 365*24*time.Hour, "only move files added longer ago than this")
+	name := fs.String("name", "", "move only this file, regardless of age")
+	dryRun := fs.Bool("n", false, "report files without moving them")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *name != "" {
+		if *dryRun {
+			log.Infow("would move", "file", *name, "class", *class)
+			return nil
+		}
+		return wallpapers.SetStorageClass(ctx, *name, *class)
+	}
+
+	cutoff := time.Now().Add(-*olderThan)
+	var moved, bytes, failed int64
+	for f, err := range wallpapers.Files(ctx) {
+		if err != nil {
+			return err
+		}
+		if f.StorageClass == *class || f.Added().After(cutoff) {
+			continue
+		}
+
+		log.Infow("moving", "file", f.Name, "from", f.StorageClass, "to", *class, "added", f.Added())
+		if *dryRun {
+			moved++
+			bytes += f.Size
+			continue
+		}
+		if err := wallpapers.SetStorageClass(ctx, f.Name, *class); err != nil {
+			failed++
+			log.Errorw("could not move", "file", f.Name, zap.Error(err))
+			continue
+		}
+		moved++
+		bytes += f.Size
+	}
+
+	log.Infow("moved files", "class", *class, "files", moved, "bytes", bytes, "failed", failed)
+	if failed > 0 {
+		return fmt.Errorf("%d files failed", failed)
+	}
+
+	return nil
+}
+
+func storageLifecycle(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("storage lifecycle", flag.ExitOnError)
+	nearline := fs.Int64("nearline-after", 0, "move originals to Nearline this many days after they were added, 0 to leave unset")
+	coldline := fs.Int64("coldline-after", 0, "move originals to Coldline this many days after they were added, 0 to leave unset")
+	clearRules := fs.Bool("clear", false, "remove every lifecycle rule")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *clearRules || *nearline > 0 || *coldline > 0 {
+		l := &storage.Lifecycle{}
+		if *nearline > 0 {
+			l.Rules = append(l.Rules, wallpapers.ArchiveRule(wallpapers.StorageNearline, *nearline))
+		}
+		if *coldline > 0 {
+			l.Rules = append(l.Rules, wallpapers.ArchiveRule(wallpapers.StorageColdline, *coldline))
+		}
+		if err := wallpapers.SetLifecycle(ctx, l); err != nil {
+			return err
+		}
+	}
+
+	l, err := wallpapers.Lifecycle(ctx)
+	if err != nil {
+		return err
+	}
+	if len(l.Rules) == 0 {
+		fmt.Println("no lifecycle rules")
+	}
+	for _, r := range l.Rules {
+		fmt.Printf("%s %s\tage=%d days_since_custom_time=%d classes=%v\n",
+			r.Action.Type, r.Action.StorageClass, r.Condition.AgeInDays, r.Condition.DaysSinceCustomTime, r.Condition.MatchesStorageClasses)
+	}
+
+	return nil
+}
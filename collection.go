@@ -0,0 +1,127 @@
+package wallpapers
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ImgixHost serves the images in the default bucket.
+const ImgixHost = "icco-walls.imgix.net"
+
+// Collection is a set of wallpapers kept in its own bucket. Public
+// collections are world readable and served through imgix; private ones are
+// only readable with credentials.
+type Collection struct {
+	Name      string `yaml:"name"`
+	Bucket    string `yaml:"bucket"`
+	ImgixHost string `yaml:"imgix_host"`
+}
+
+// Public reports whether the collection's images are world readable.
+func (c Collection) Public() bool {
+	return c.ImgixHost != ""
+}
+
+// CollectionsEnv lists the collections, as comma separated
+// name:bucket[:imgix host] entries. Collections without an imgix host are
+// private.
+const CollectionsEnv = "WALLPAPERS_COLLECTIONS"
+
+// DefaultCollection is the collection used when none is configured.
+var DefaultCollection = Collection{Name: "public", Bucket: Bucket, ImgixHost: ImgixHost}
+
+// CollectionsFromEnv returns the collections configured in
+// WALLPAPERS_COLLECTIONS, or just DefaultCollection. The first one is the
+// default.
+func CollectionsFromEnv() ([]Collection, error) {
+	v := os.Getenv(CollectionsEnv)
+	if v == "" {
+		return []Collection{DefaultCollection}, nil
+	}
+
+	return ParseCollections(v)
+}
+
+// ParseCollections parses collections in the format of
+// WALLPAPERS_COLLECTIONS.
+func ParseCollections(v string) ([]Collection, error) {
+	var ret []Collection
+	seen := map[string]bool{}
+	for _, entry := range strings.Split(v, ",") {
+		parts := strings.Split(strings.TrimSpace(entry), ":")
+		if len(parts) < 2 || len(parts) > 3 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("invalid collection %q, expected name:bucket[:imgix host]", entry)
+		}
+		if seen[parts[0]] {
+			return nil, fmt.Errorf("duplicate collection %q", parts[0])
+		}
+		seen[parts[0]] = true
+
+		c := Collection{Name: parts[0], Bucket: parts[1]}
+		if len(parts) == 3 {
+			c.ImgixHost = parts[2]
+		}
+		ret = append(ret, c)
+	}
+
+	return ret, nil
+}
+
+// OpenCollection returns the store holding a collection, using the backend
+// selected by WALLPAPERS_BACKEND.
+func OpenCollection(c Collection, first bool) (Store, error) {
+	return BackendFromEnv().Open(c, first)
+}
+
+// Backend selects where collections are kept: "gcs" (the default) or
+// "local", which keeps files in Dir so the tools can run without cloud
+// credentials.
+type Backend struct {
+	Name string
+	Dir  string
+}
+
+// BackendFromEnv returns the backend selected by WALLPAPERS_BACKEND and
+// WALLPAPERS_DIR.
+func BackendFromEnv() Backend {
+	return Backend{Name: os.Getenv(BackendEnv), Dir: os.Getenv(LocalDirEnv)}
+}
+
+// Open returns the store holding a collection. The local backend keeps each
+// collection other than the first in a subdirectory named after it.
+func (b Backend) Open(c Collection, first bool) (Store, error) {
+	switch b.Name {
+	case "", "gcs":
+		return NewGCSStore(c), nil
+	case "local":
+		dir := b.Dir
+		if dir == "" {
+			dir = DefaultLocalDir
+		}
+		if !first {
+			dir = filepath.Join(dir, c.Name)
+		}
+		return NewLocalStore(dir)
+	default:
+		return nil, fmt.Errorf("unknown backend %q", b.Name)
+	}
+}
+
+type storeKey struct{}
+
+// ContextWithStore returns a context that makes the package level functions
+// use s instead of the default store.
+func ContextWithStore(ctx context.Context, s Store) context.Context {
+	return context.WithValue(ctx, storeKey{}, s)
+}
+
+// StoreFor returns the store set on ctx, or the default store.
+func StoreFor(ctx context.Context) Store {
+	if s, ok := ctx.Value(storeKey{}).(Store); ok {
+		return s
+	}
+	return DefaultStore()
+}
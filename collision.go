@@ -0,0 +1,65 @@
+package wallpapers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"cloud.google.com/go/storage"
+)
+
+// HashedName adds a short hash of content to a formatted name, to tell apart
+// different pictures whose names format to the same thing.
+func HashedName(name string, content []byte) string {
+	ext := filepath.Ext(name)
+	return fmt.Sprintf("%s%08x%s", strings.TrimSuffix(name, ext), GetFileCRC(content), ext)
+}
+
+// UploadNew uploads content as a new wallpaper named name, which must
+// already be formatted, and returns the name it was uploaded under. If the
+// collection already has the same content, nothing is uploaded and a
+// *DuplicateError is returned. If a different picture has the name, a hash
+// of the content is added to it.
+//
+// Duplicates are found by listing the collection, unless an index is given
+// with WithIndex.
+func UploadNew(ctx context.Context, name string, content []byte, opts ...UploadOption) (string, error) {
+	o := &uploadOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	var existing string
+	if o.index != nil {
+		existing = findIndexed(o.index, content)
+	} else {
+		var err error
+		existing, err = FindDuplicate(ctx, content)
+		if err != nil {
+			return "", err
+		}
+	}
+	if existing != "" {
+		return "", &DuplicateError{Name: name, Existing: existing}
+	}
+
+	_, err := GetFile(ctx, name)
+	switch {
+	case err == nil:
+		hashed := HashedName(name, content)
+		LoggerFor(ctx).Infow("name taken by a different picture", "name", name, "using", hashed)
+		name = hashed
+	case errors.Is(err, storage.ErrObjectNotExist):
+		opts = append(opts, WithPrevious(nil))
+	default:
+		return "", err
+	}
+
+	if err := UploadFile(ctx, name, content, opts...); err != nil {
+		return "", err
+	}
+
+	return name, nil
+}
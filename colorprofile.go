@@ -0,0 +1,198 @@
+package wallpapers
+
+import (
+	"bytes"
+	"compress/zlib"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"strings"
+	"unicode/utf16"
+)
+
+// MetadataColorProfile is the object metadata key holding the description
+// of an image's embedded ICC profile, such as "Display P3".
+const MetadataColorProfile = "color_profile"
+
+// ProfileSRGB is reported for PNGs that declare sRGB without embedding a
+// profile.
+const ProfileSRGB = "sRGB"
+
+// maxProfileSize bounds how much a compressed PNG profile may inflate to.
+const maxProfileSize = 4 << 20
+
+// ColorProfile returns the description of the ICC profile embedded in a PNG
+// or JPEG, or "" if it has none, in which case it should be treated as sRGB.
+func ColorProfile(content []byte) (string, error) {
+	var icc []byte
+	var err error
+	switch {
+	case bytes.HasPrefix(content, pngSignature):
+		icc, err = pngProfile(content)
+		if err == nil && icc == nil && hasPNGChunk(content, "sRGB") {
+			return ProfileSRGB, nil
+		}
+	case bytes.HasPrefix(content, []byte{0xff, 0xd8}):
+		icc, err = jpegProfile(content)
+	default:
+		return "", nil
+	}
+	if err != nil || icc == nil {
+		return "", err
+	}
+
+	return iccDescription(icc)
+}
+
+// WithColorProfile records an image's color profile as object metadata.
+func WithColorProfile(profile string) UploadOption {
+	return WithMetadata(map[string]string{MetadataColorProfile: profile})
+}
+
+// SetColorProfile records the color profile of an existing image.
+func SetColorProfile(ctx context.Context, filename, profile string) error {
+	return updateFile(ctx, filename, ObjectUpdate{Metadata: map[string]string{MetadataColorProfile: profile}})
+}
+
+func hasPNGChunk(content []byte, typ string) bool {
+	chunks, err := pngChunks(content)
+	if err != nil {
+		return false
+	}
+	for _, c := range chunks {
+		if c == typ {
+			return true
+		}
+	}
+	return false
+}
+
+// pngProfile returns the decompressed profile from a PNG's iCCP chunk.
+func pngProfile(content []byte) ([]byte, error) {
+	rest := content[len(pngSignature):]
+	for len(rest) >= 12 {
+		n := binary.BigEndian.Uint32(rest[:4])
+		if uint64(n)+12 > uint64(len(rest)) {
+			return nil, errors.New("truncated png chunk")
+		}
+
+		if string(rest[4:8]) == "iCCP" {
+			// The profile name, a null separator and the compression
+			// method come before the compressed profile.
+			data := rest[8 : 8+n]
+			i := bytes.IndexByte(data, 0)
+			if i < 0 || i+2 > len(data) {
+				return nil, errors.New("invalid iCCP chunk")
+			}
+
+			zr, err := zlib.NewReader(bytes.NewReader(data[i+2:]))
+			if err != nil {
+				return nil, err
+			}
+			defer zr.Close()
+
+			return io.ReadAll(io.LimitReader(zr, maxProfileSize))
+		}
+		rest = rest[12+n:]
+	}
+
+	return nil, nil
+}
+
+// jpegProfile reassembles the profile from a JPEG's APP2 ICC_PROFILE
+// segments, which may split it into several numbered parts.
+func jpegProfile(content []byte) ([]byte, error) {
+	const marker = "ICC_PROFILE\x00"
+	parts := map[byte][]byte{}
+	total := 0
+
+	rest := content[2:]
+	for len(rest) >= 4 && rest[0] == 0xff {
+		typ := rest[1]
+		// Start of scan: the headers are over.
+		if typ == 0xda {
+			break
+		}
+
+		n := int(binary.BigEndian.Uint16(rest[2:4]))
+		if n < 2 || n+2 > len(rest) {
+			return nil, errors.New("truncated jpeg segment")
+		}
+
+		seg := rest[4 : 2+n]
+		if typ == 0xe2 && len(seg) > len(marker)+2 && string(seg[:len(marker)]) == marker {
+			seq := seg[len(marker)]
+			total = int(seg[len(marker)+1])
+			parts[seq] = seg[len(marker)+2:]
+		}
+		rest = rest[2+n:]
+	}
+
+	if total == 0 {
+		return nil, nil
+	}
+
+	var icc []byte
+	for i := 1; i <= total; i++ {
+		p, ok := parts[byte(i)]
+		if !ok {
+			return nil, errors.New("incomplete jpeg icc profile")
+		}
+		icc = append(icc, p...)
+	}
+
+	return icc, nil
+}
+
+// iccDescription reads the profile description tag of an ICC profile. It
+// understands both the version 2 desc and version 4 mluc tag types.
+func iccDescription(icc []byte) (string, error) {
+	if len(icc) < 132 {
+		return "", errors.New("icc profile too short")
+	}
+
+	count := int(binary.BigEndian.Uint32(icc[128:132]))
+	for i := range count {
+		entry := 132 + i*12
+		if entry+12 > len(icc) {
+			break
+		}
+		if string(icc[entry:entry+4]) != "desc" {
+			continue
+		}
+
+		off := int(binary.BigEndian.Uint32(icc[entry+4 : entry+8]))
+		size := int(binary.BigEndian.Uint32(icc[entry+8 : entry+12]))
+		if off < 0 || size < 12 || off+size > len(icc) {
+			return "", errors.New("invalid icc desc tag")
+		}
+		tag := icc[off : off+size]
+
+		switch string(tag[:4]) {
+		case "desc":
+			n := int(binary.BigEndian.Uint32(tag[8:12]))
+			if 12+n > len(tag) {
+				return "", errors.New("invalid icc desc tag")
+			}
+			return strings.TrimRight(string(tag[12:12+n]), "\x00"), nil
+		case "mluc":
+			// Use the first localized record.
+			if len(tag) < 28 {
+				return "", errors.New("invalid icc mluc tag")
+			}
+			n := int(binary.BigEndian.Uint32(tag[20:24]))
+			start := int(binary.BigEndian.Uint32(tag[24:28]))
+			if start+n > len(tag) || n%2 != 0 {
+				return "", errors.New("invalid icc mluc tag")
+			}
+			u := make([]uint16, n/2)
+			for j := range u {
+				u[j] = binary.BigEndian.Uint16(tag[start+2*j:])
+			}
+			return strings.TrimRight(string(utf16.Decode(u)), "\x00"), nil
+		}
+	}
+
+	return "", errors.New("icc profile has no description")
+}
@@ -0,0 +1,432 @@
+// Package config loads the settings shared by the wallpapers commands from a
+// YAML file, the environment and command line flags, in increasing order of
+// precedence.
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/icco/wallpapers"
+	"github.com/icco/wallpapers/notify"
+	"gopkg.in/yaml.v3"
+)
+
+// Environment variables read by Load, besides those of the wallpapers and
+// notify packages.
+const (
+	// FileEnv is the path of the config file, if the -config flag is not
+	// set.
+	FileEnv = "WALLPAPERS_CONFIG"
+	// LogSamplingEnv holds the server's request log sampling rules.
+	LogSamplingEnv = "WALLPAPERS_LOG_SAMPLING"
+	// JobEnvPrefix is prepended to a job's upper cased name, with dashes
+	// replaced by underscores, to override its schedule, e.g.
+	// WALLPAPERS_JOB_CACHE_REFRESH.
+	JobEnvPrefix = "WALLPAPERS_JOB_"
+	// RedisEnv is the URL of a Redis server shared by the server's
+	// replicas.
+	RedisEnv = "WALLPAPERS_REDIS_URL"
+	// WarmSizesEnv is a comma separated list of WIDTHxHEIGHT renditions
+	// to request from imgix after each upload.
+	WarmSizesEnv = "WALLPAPERS_WARM_SIZES"
+	// ServeImagesEnv turns on serving originals and thumbnails from the
+	// server's own domain.
+	ServeImagesEnv = "WALLPAPERS_SERVE_IMAGES"
+	// MirrorEnv is the collection uploads are copied to, as
+	// name:bucket.
+	MirrorEnv = "WALLPAPERS_MIRROR"
+	// CursorSecretEnv is the key pagination cursors are signed with.
+	CursorSecretEnv = "WALLPAPERS_CURSOR_SECRET"
+	// APITokenEnv is the bearer token required by the server's private
+	// endpoints.
+	APITokenEnv = "WALLPAPERS_API_TOKEN"
+	// GRPCPortEnv is the port the server's wallpapers.v1 gRPC service
+	// listens on.
+	GRPCPortEnv = "WALLPAPERS_GRPC_PORT"
+	// GRPCTLSCertEnv and GRPCTLSKeyEnv are the PEM files of the
+	// certificate the gRPC service is served with.
+	GRPCTLSCertEnv = "WALLPAPERS_GRPC_TLS_CERT"
+	GRPCTLSKeyEnv  = "WALLPAPERS_GRPC_TLS_KEY"
+	// UnsplashAccessKeyEnv is the Unsplash API access key used by walls
+	// import unsplash.
+	UnsplashAccessKeyEnv = "WALLPAPERS_UNSPLASH_ACCESS_KEY"
+)
+
+// Config is the configuration of the wallpapers commands.
+type Config struct {
+	// Backend is "gcs" or "local".
+	Backend string `yaml:"backend"`
+	// Dir is where the local backend keeps files.
+	Dir string `yaml:"dir"`
+	// Collections are the collections to use. The first is the default.
+	Collections []wallpapers.Collection `yaml:"collections"`
+	// Webhooks are notified of new wallpapers.
+	Webhooks []string `yaml:"webhooks"`
+	// WarmSizes are cropped renditions requested from imgix after each
+	// upload, besides the thumbnail and full resolution ones.
+	WarmSizes []wallpapers.Size `yaml:"warm_sizes"`
+	// Mirror, if set, is a second copy of the default collection, ideally
+	// in another account, that uploads are copied to.
+	Mirror *wallpapers.Collection `yaml:"mirror"`
+	// UnsplashAccessKey is the access key of an Unsplash API application,
+	// needed to import from Unsplash.
+	UnsplashAccessKey string `yaml:"unsplash_access_key"`
+
+	Server Server `yaml:"server"`
+
+	flags *flags
+}
+
+// Server holds the settings only used by cmd/server.
+type Server struct {
+	Port string `yaml:"port"`
+	// LogSampling is a comma separated list of /prefix=rate rules.
+	LogSampling string `yaml:"log_sampling"`
+	// Jobs overrides the schedules of background jobs by name. "off"
+	// disables a job.
+	Jobs map[string]string `yaml:"jobs"`
+	// Redis is a redis:// or rediss:// URL. If set, caches are shared
+	// between replicas through it instead of kept in each process.
+	Redis string `yaml:"redis"`
+	// ServeImages points file URLs at the server's /img routes, which a
+	// CDN in front of the server can cache forever.
+	ServeImages bool `yaml:"serve_images"`
+	// CursorSecret signs pagination cursors. Every replica behind one
+	// address must use the same secret, so the server refuses to start
+	// without one on Cloud Run or when Redis is set. A single local
+	// process picks a random key, and its cursors stop working when it
+	// restarts.
+	CursorSecret string `yaml:"cursor_secret"`
+	// APIToken is the bearer token required by private endpoints such as
+	// /audit. Without one they are refused.
+	APIToken string `yaml:"api_token"`
+	// GRPCPort, if set, serves the wallpapers.v1 gRPC service on this
+	// port next to the HTTP API.
+	GRPCPort string `yaml:"grpc_port"`
+	// GRPCTLSCert and GRPCTLSKey are the PEM files of the gRPC service's
+	// certificate. Without them the API token would be sent in the clear,
+	// so the service only listens on localhost.
+	GRPCTLSCert string `yaml:"grpc_tls_cert"`
+	GRPCTLSKey  string `yaml:"grpc_tls_key"`
+}
+
+type flags struct {
+	fs                *flag.FlagSet
+	file              string
+	backend           string
+	dir               string
+	collections       string
+	webhooks          string
+	warmSizes         string
+	mirror            string
+	unsplashAccessKey string
+	port              string
+	logSampling       string
+	jobs              string
+	redis             string
+	serveImages       bool
+	cursorSecret      string
+	apiToken          string
+	grpcPort          string
+	grpcTLSCert       string
+	grpcTLSKey        string
+}
+
+// New returns a Config with the defaults.
+func New() *Config {
+	return &Config{
+		Collections: []wallpapers.Collection{wallpapers.DefaultCollection},
+		Server: Server{
+			Port: "8080",
+			Jobs: map[string]string{},
+		},
+	}
+}
+
+// RegisterFlags adds flags for the settings to fs, one for each setting
+// that can be set from the environment. Flags that are set override the
+// file and the environment when Load is called.
+func (c *Config) RegisterFlags(fs *flag.FlagSet) {
+	f := &flags{fs: fs}
+	fs.StringVar(&f.file, "config", "", "path of a YAML config file, defaults to $"+FileEnv)
+	fs.StringVar(&f.backend, "backend", "", "where wallpapers are kept: gcs or local")
+	fs.StringVar(&f.dir, "backend-dir", "", "directory used by the local backend")
+	fs.StringVar(&f.collections, "collections", "", "collections as comma separated name:bucket[:imgix host] entries")
+	fs.StringVar(&f.webhooks, "webhooks", "", "comma separated webhook URLs to notify of new wallpapers")
+	fs.StringVar(&f.warmSizes, "warm-sizes", "", "comma separated WIDTHxHEIGHT renditions to request from imgix after each upload")
+	fs.StringVar(&f.mirror, "mirror", "", "collection uploads are copied to, as name:bucket")
+	fs.StringVar(&f.unsplashAccessKey, "unsplash-access-key", "", "Unsplash API access key for walls import unsplash")
+	fs.StringVar(&f.port, "port", "", "port the server listens on")
+	fs.StringVar(&f.logSampling, "log-sampling", "", "comma separated /prefix=rate request log sampling rules")
+	fs.StringVar(&f.jobs, "jobs", "", "comma separated name=schedule overrides of background jobs, \"off\" disables one")
+	fs.StringVar(&f.redis, "redis", "", "redis:// URL of a Redis server shared by the server's replicas")
+	fs.BoolVar(&f.serveImages, "serve-images", false, "serve originals and thumbnails from the server's own domain")
+	fs.StringVar(&f.cursorSecret, "cursor-secret", "", "key pagination cursors are signed with")
+	fs.StringVar(&f.apiToken, "api-token", "", "bearer token required by the server's private endpoints")
+	fs.StringVar(&f.grpcPort, "grpc-port", "", "port to serve the wallpapers.v1 gRPC service on")
+	fs.StringVar(&f.grpcTLSCert, "grpc-tls-cert", "", "PEM certificate file of the gRPC service")
+	fs.StringVar(&f.grpcTLSKey, "grpc-tls-key", "", "PEM key file of the gRPC service")
+	c.flags = f
+}
+
+// Load reads the config file, then the environment, then any flags
+// registered with RegisterFlags. Call it after the flags are parsed.
+func (c *Config) Load() error {
+	path := os.Getenv(FileEnv)
+	if c.flags != nil && c.flags.file != "" {
+		path = c.flags.file
+	}
+	if path != "" {
+		if err := c.loadFile(path); err != nil {
+			return err
+		}
+	}
+
+	if err := c.loadEnv(); err != nil {
+		return err
+	}
+
+	if c.flags != nil {
+		if err := c.loadFlags(); err != nil {
+			return err
+		}
+	}
+
+	if len(c.Collections) == 0 {
+		return errors.New("no collections configured")
+	}
+	if (c.Server.GRPCTLSCert == "") != (c.Server.GRPCTLSKey == "") {
+		return errors.New("the gRPC TLS certificate and key must be set together")
+	}
+
+	return nil
+}
+
+func (c *Config) loadFile(path string) error {
+	dat, err := os.ReadFile(path) // #nosec G304 -- the path is the user's own config
+	if err != nil {
+		return fmt.Errorf("could not read config: %w", err)
+	}
+
+	if err := yaml.Unmarshal(dat, c); err != nil {
+		return fmt.Errorf("could not parse config %s: %w", path, err)
+	}
+
+	return nil
+}
+
+func (c *Config) loadEnv() error {
+	if v := os.Getenv(wallpapers.BackendEnv); v != "" {
+		c.Backend = v
+	}
+	if v := os.Getenv(wallpapers.LocalDirEnv); v != "" {
+		c.Dir = v
+	}
+	if v := os.Getenv(wallpapers.CollectionsEnv); v != "" {
+		cs, err := wallpapers.ParseCollections(v)
+		if err != nil {
+			return err
+		}
+		c.Collections = cs
+	}
+	if v := os.Getenv(MirrorEnv); v != "" {
+		m, err := parseMirror(MirrorEnv, v)
+		if err != nil {
+			return err
+		}
+		c.Mirror = m
+	}
+	if v := os.Getenv(notify.EnvVar); v != "" {
+		c.Webhooks = splitList(v)
+	}
+	if v := os.Getenv(WarmSizesEnv); v != "" {
+		sizes, err := parseSizes(v)
+		if err != nil {
+			return err
+		}
+		c.WarmSizes = sizes
+	}
+	if v := os.Getenv(UnsplashAccessKeyEnv); v != "" {
+		c.UnsplashAccessKey = v
+	}
+
+	if v := os.Getenv("PORT"); v != "" {
+		c.Server.Port = v
+	}
+	if v := os.Getenv(LogSamplingEnv); v != "" {
+		c.Server.LogSampling = v
+	}
+	if v := os.Getenv(RedisEnv); v != "" {
+		c.Server.Redis = v
+	}
+	if v := os.Getenv(ServeImagesEnv); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("invalid %s %q: %w", ServeImagesEnv, v, err)
+		}
+		c.Server.ServeImages = b
+	}
+	if v := os.Getenv(CursorSecretEnv); v != "" {
+		c.Server.CursorSecret = v
+	}
+	if v := os.Getenv(APITokenEnv); v != "" {
+		c.Server.APIToken = v
+	}
+	if v := os.Getenv(GRPCPortEnv); v != "" {
+		c.Server.GRPCPort = v
+	}
+	if v := os.Getenv(GRPCTLSCertEnv); v != "" {
+		c.Server.GRPCTLSCert = v
+	}
+	if v := os.Getenv(GRPCTLSKeyEnv); v != "" {
+		c.Server.GRPCTLSKey = v
+	}
+	for _, kv := range os.Environ() {
+		k, v, _ := strings.Cut(kv, "=")
+		name, ok := strings.CutPrefix(k, JobEnvPrefix)
+		if !ok || v == "" {
+			continue
+		}
+		if c.Server.Jobs == nil {
+			c.Server.Jobs = map[string]string{}
+		}
+		c.Server.Jobs[strings.ReplaceAll(strings.ToLower(name), "_", "-")] = v
+	}
+
+	return nil
+}
+
+func (c *Config) loadFlags() error {
+	f := c.flags
+	var err error
+	f.fs.Visit(func(fl *flag.Flag) {
+		if err != nil {
+			return
+		}
+		switch fl.Name {
+		case "backend":
+			c.Backend = f.backend
+		case "backend-dir":
+			c.Dir = f.dir
+		case "collections":
+			var cs []wallpapers.Collection
+			if cs, err = wallpapers.ParseCollections(f.collections); err == nil {
+				c.Collections = cs
+			}
+		case "webhooks":
+			c.Webhooks = splitList(f.webhooks)
+		case "warm-sizes":
+			var sizes []wallpapers.Size
+			if sizes, err = parseSizes(f.warmSizes); err == nil {
+				c.WarmSizes = sizes
+			}
+		case "mirror":
+			var m *wallpapers.Collection
+			if m, err = parseMirror("-mirror", f.mirror); err == nil {
+				c.Mirror = m
+			}
+		case "unsplash-access-key":
+			c.UnsplashAccessKey = f.unsplashAccessKey
+		case "port":
+			c.Server.Port = f.port
+		case "log-sampling":
+			c.Server.LogSampling = f.logSampling
+		case "jobs":
+			if c.Server.Jobs == nil {
+				c.Server.Jobs = map[string]string{}
+			}
+			for _, job := range splitList(f.jobs) {
+				name, schedule, ok := strings.Cut(job, "=")
+				if !ok {
+					err = fmt.Errorf("invalid -jobs entry %q, expected name=schedule", job)
+					return
+				}
+				c.Server.Jobs[strings.TrimSpace(name)] = strings.TrimSpace(schedule)
+			}
+		case "redis":
+			c.Server.Redis = f.redis
+		case "serve-images":
+			c.Server.ServeImages = f.serveImages
+		case "cursor-secret":
+			c.Server.CursorSecret = f.cursorSecret
+		case "api-token":
+			c.Server.APIToken = f.apiToken
+		case "grpc-port":
+			c.Server.GRPCPort = f.grpcPort
+		case "grpc-tls-cert":
+			c.Server.GRPCTLSCert = f.grpcTLSCert
+		case "grpc-tls-key":
+			c.Server.GRPCTLSKey = f.grpcTLSKey
+		}
+	})
+
+	return err
+}
+
+// StorageBackend returns the configured storage backend.
+func (c *Config) StorageBackend() wallpapers.Backend {
+	return wallpapers.Backend{Name: c.Backend, Dir: c.Dir}
+}
+
+// Open returns the store of the i-th collection.
+func (c *Config) Open(i int) (wallpapers.Store, error) {
+	return c.StorageBackend().Open(c.Collections[i], i == 0)
+}
+
+// MirrorStore returns the store of the mirror, or nil if there is none.
+func (c *Config) MirrorStore() (wallpapers.Store, error) {
+	if c.Mirror == nil {
+		return nil, nil
+	}
+	return c.StorageBackend().Open(*c.Mirror, false)
+}
+
+// DefaultStore returns the store of the default collection.
+func (c *Config) DefaultStore() (wallpapers.Store, error) {
+	return c.Open(0)
+}
+
+// Notifier returns a notifier for the configured webhooks.
+func (c *Config) Notifier() *notify.Notifier {
+	return notify.New(c.Webhooks)
+}
+
+func splitList(v string) []string {
+	var ret []string
+	for _, s := range strings.Split(v, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			ret = append(ret, s)
+		}
+	}
+	return ret
+}
+
+// parseMirror parses the single name:bucket collection of the mirror set
+// by source.
+func parseMirror(source, v string) (*wallpapers.Collection, error) {
+	cs, err := wallpapers.ParseCollections(v)
+	if err != nil {
+		return nil, err
+	}
+	if len(cs) != 1 {
+		return nil, fmt.Errorf("invalid %s %q, expected one name:bucket", source, v)
+	}
+	return &cs[0], nil
+}
+
+func parseSizes(v string) ([]wallpapers.Size, error) {
+	var sizes []wallpapers.Size
+	for _, s := range splitList(v) {
+		size, err := wallpapers.ParseSize(s)
+		if err != nil {
+			return nil, err
+		}
+		sizes = append(sizes, size)
+	}
+	return sizes, nil
+}
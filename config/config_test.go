@@ -0,0 +1,159 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/icco/wallpapers"
+	"github.com/icco/wallpapers/notify"
+)
+
+func TestLoadPrecedence(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		// file, env and flag set the setting in each layer, and want is
+		// what get returns once each of them is applied in turn.
+		file string
+		env  string
+		val  string
+		flag string
+		get  func(*Config) string
+		want [3]string
+	}{
+		{
+			name: "backend",
+			file: "backend: gcs", env: wallpapers.BackendEnv, val: "local", flag: "-backend=gcs",
+			get:  func(c *Config) string { return c.Backend },
+			want: [3]string{"gcs", "local", "gcs"},
+		},
+		{
+			name: "backend dir",
+			file: "dir: /file", env: wallpapers.LocalDirEnv, val: "/env", flag: "-backend-dir=/flag",
+			get:  func(c *Config) string { return c.Dir },
+			want: [3]string{"/file", "/env", "/flag"},
+		},
+		{
+			name: "collections",
+			file: "collections: [{name: file, bucket: file}]", env: wallpapers.CollectionsEnv, val: "env:env", flag: "-collections=flag:flag",
+			get:  func(c *Config) string { return c.Collections[0].Bucket },
+			want: [3]string{"file", "env", "flag"},
+		},
+		{
+			name: "webhooks",
+			file: "webhooks: ['https://file']", env: notify.EnvVar, val: "https://env", flag: "-webhooks=https://flag",
+			get:  func(c *Config) string { return strings.Join(c.Webhooks, ",") },
+			want: [3]string{"https://file", "https://env", "https://flag"},
+		},
+		{
+			name: "warm sizes",
+			file: "warm_sizes: [1x1]", env: WarmSizesEnv, val: "2x2", flag: "-warm-sizes=3x3",
+			get:  func(c *Config) string { return fmt.Sprint(c.WarmSizes) },
+			want: [3]string{"[1x1]", "[2x2]", "[3x3]"},
+		},
+		{
+			name: "mirror",
+			file: "mirror: {name: file, bucket: file}", env: MirrorEnv, val: "env:env", flag: "-mirror=flag:flag",
+			get:  func(c *Config) string { return c.Mirror.Bucket },
+			want: [3]string{"file", "env", "flag"},
+		},
+		{
+			name: "unsplash access key",
+			file: "unsplash_access_key: file", env: UnsplashAccessKeyEnv, val: "env", flag: "-unsplash-access-key=flag",
+			get:  func(c *Config) string { return c.UnsplashAccessKey },
+			want: [3]string{"file", "env", "flag"},
+		},
+		{
+			name: "port",
+			file: "server: {port: '1'}", env: "PORT", val: "2", flag: "-port=3",
+			get:  func(c *Config) string { return c.Server.Port },
+			want: [3]string{"1", "2", "3"},
+		},
+		{
+			name: "log sampling",
+			file: "server: {log_sampling: /file=1}", env: LogSamplingEnv, val: "/env=1", flag: "-log-sampling=/flag=1",
+			get:  func(c *Config) string { return c.Server.LogSampling },
+			want: [3]string{"/file=1", "/env=1", "/flag=1"},
+		},
+		{
+			name: "jobs",
+			file: "server: {jobs: {cache-refresh: file}}", env: JobEnvPrefix + "CACHE_REFRESH", val: "env", flag: "-jobs=cache-refresh=off",
+			get:  func(c *Config) string { return c.Server.Jobs["cache-refresh"] },
+			want: [3]string{"file", "env", "off"},
+		},
+		{
+			name: "redis",
+			file: "server: {redis: 'redis://file'}", env: RedisEnv, val: "redis://env", flag: "-redis=redis://flag",
+			get:  func(c *Config) string { return c.Server.Redis },
+			want: [3]string{"redis://file", "redis://env", "redis://flag"},
+		},
+		{
+			name: "serve images",
+			file: "server: {serve_images: true}", env: ServeImagesEnv, val: "false", flag: "-serve-images",
+			get:  func(c *Config) string { return strconv.FormatBool(c.Server.ServeImages) },
+			want: [3]string{"true", "false", "true"},
+		},
+		{
+			name: "cursor secret",
+			file: "server: {cursor_secret: file}", env: CursorSecretEnv, val: "env", flag: "-cursor-secret=flag",
+			get:  func(c *Config) string { return c.Server.CursorSecret },
+			want: [3]string{"file", "env", "flag"},
+		},
+		{
+			name: "api token",
+			file: "server: {api_token: file}", env: APITokenEnv, val: "env", flag: "-api-token=flag",
+			get:  func(c *Config) string { return c.Server.APIToken },
+			want: [3]string{"file", "env", "flag"},
+		},
+		{
+			name: "grpc port",
+			file: "server: {grpc_port: '1'}", env: GRPCPortEnv, val: "2", flag: "-grpc-port=3",
+			get:  func(c *Config) string { return c.Server.GRPCPort },
+			want: [3]string{"1", "2", "3"},
+		},
+		{
+			name: "grpc tls",
+			file: "server: {grpc_tls_cert: file, grpc_tls_key: file}", env: GRPCTLSCertEnv, val: "env", flag: "-grpc-tls-cert=flag",
+			get:  func(c *Config) string { return c.Server.GRPCTLSCert + " " + c.Server.GRPCTLSKey },
+			want: [3]string{"file file", "env file", "flag file"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(path, []byte(tc.file), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			for i, layer := range []string{"file", "env", "flag"} {
+				// Each layer keeps those below it, so env and flag are
+				// only set from their own layer on.
+				if i >= 1 {
+					t.Setenv(tc.env, tc.val)
+				} else {
+					t.Setenv(tc.env, "")
+				}
+				var args []string
+				if i >= 2 {
+					args = []string{tc.flag}
+				}
+
+				c := New()
+				fs := flag.NewFlagSet("test", flag.ContinueOnError)
+				c.RegisterFlags(fs)
+				if err := fs.Parse(append([]string{"-config=" + path}, args...)); err != nil {
+					t.Fatal(err)
+				}
+				if err := c.Load(); err != nil {
+					t.Fatalf("%s: Load error = %v", layer, err)
+				}
+				if got := tc.get(c); got != tc.want[i] {
+					t.Errorf("%s: got %q, want %q", layer, got, tc.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package wallpapers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+)
+
+// DuplicateError is returned when content is already in the collection.
+type DuplicateError struct {
+	Name     string
+	Existing string
+}
+
+func (e *DuplicateError) Error() string {
+	return fmt.Sprintf("%q has the same content as %q", e.Name, e.Existing)
+}
+
+// SameContent reports whether f was made from content. Different content
+// can share a CRC32C, so the size and, when it is known, the MD5 must match
+// too.
+func (f *File) SameContent(content []byte) bool {
+	if f.OriginalSize() != int64(len(content)) {
+		return false
+	}
+	sums := GetChecksums(content)
+	if f.OriginalCRC32C() != sums.CRC32C {
+		return false
+	}
+	md5 := f.OriginalMD5()
+	return len(md5) == 0 || bytes.Equal(md5, sums.MD5)
+}
+
+// IndexByCRC maps the original checksum of every file to the files with
+// it, so content can be looked up regardless of what it is called. Confirm
+// a match with SameContent, as different content can share a CRC32C.
+func IndexByCRC(ctx context.Context) (map[uint32][]*File, error) {
+	var files []*File
+	for f, err := range Files(ctx) {
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, f)
+	}
+
+	return IndexFiles(files), nil
+}
+
+// IndexFiles is IndexByCRC for files that have already been listed.
+func IndexFiles(files []*File) map[uint32][]*File {
+	idx := map[uint32][]*File{}
+	for _, f := range files {
+		crc := f.OriginalCRC32C()
+		idx[crc] = append(idx[crc], f)
+	}
+
+	return idx
+}
+
+// FindDuplicate returns the name of a file with the same content, or "" if
+// there is none. It lists the whole collection, so use an index from
+// IndexByCRC to check many files.
+func FindDuplicate(ctx context.Context, content []byte) (string, error) {
+	for f, err := range Files(ctx) {
+		if err != nil {
+			return "", err
+		}
+		if f.SameContent(content) {
+			return f.Name, nil
+		}
+	}
+
+	return "", nil
+}
+
+// findIndexed returns the name of a file in idx with the same content, or
+// "" if there is none.
+func findIndexed(idx map[uint32][]*File, content []byte) string {
+	for _, f := range idx[GetFileCRC(content)] {
+		if f.SameContent(content) {
+			return f.Name
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,166 @@
+package wallpapers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif"  // Register the GIF decoder for Dither.
+	_ "image/jpeg" // Register the JPEG decoder for Dither.
+	"image/png"
+	"math"
+	"path/filepath"
+	"strings"
+)
+
+// Variants returns the store that keeps renditions derived from the
+// wallpapers in ctx's store.
+func Variants(ctx context.Context) (Store, error) {
+	return subStore(ctx, VariantsStore)
+}
+
+// SaveVariant stores a rendition in ctx's store's variants. Renditions are
+// caches, so they are not recorded in the audit log.
+func SaveVariant(ctx context.Context, name string, content []byte) error {
+	s, err := Variants(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = writeFile(ctx, s, name, content, &uploadOptions{})
+	return err
+}
+
+// LoadVariant returns a rendition from ctx's store's variants, verified
+// against its checksums.
+func LoadVariant(ctx context.Context, name string) ([]byte, error) {
+	s, err := Variants(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return DownloadFile(ContextWithStore(ctx, s), name)
+}
+
+// imgixHost returns the imgix host in front of the store in ctx, if any.
+func imgixHost(ctx context.Context) (string, bool) {
+	s, ok := StoreFor(ctx).(*gcsStore)
+	if !ok || s.imgixHost == "" {
+		return "", false
+	}
+	return s.imgixHost, true
+}
+
+// UsesImgix reports whether the store in ctx is served through imgix.
+func UsesImgix(ctx context.Context) bool {
+	_, ok := imgixHost(ctx)
+	return ok
+}
+
+// EinkURL returns the URL of a grayscale version of key hosted by imgix,
+// cropped to w by h and reduced to the given number of gray levels. imgix
+// quantizes but does not dither. It returns false if the store in ctx is
+// not served through imgix, in which case Dither should be used instead.
+func EinkURL(ctx context.Context, key string, w, h, levels int) (string, bool) {
+	host, ok := imgixHost(ctx)
+	if !ok {
+		return "", false
+	}
+
+	return fmt.Sprintf("https://%s/%s?w=%d&h=%d&fit=crop&crop=entropy&sat=-100&colorquant=%d&fm=png", host, key, w, h, levels), true
+}
+
+// EinkName returns the name the e-ink rendition of f is cached under. It
+// includes f's checksum so replacing a wallpaper invalidates its renditions.
+func EinkName(f *File, w, h, levels int) string {
+	base := strings.TrimSuffix(f.Name, filepath.Ext(f.Name))
+	return fmt.Sprintf("%s-%08x-eink-%dx%d-%d.png", base, f.CRC32C, w, h, levels)
+}
+
+// Dither decodes an image, crops it around the center to fill w by h,
+// converts it to grayscale and reduces it to the given number of evenly
+// spaced gray levels with Floyd-Steinberg dithering. The result is a PNG.
+func Dither(content []byte, w, h, levels int) ([]byte, error) {
+	if w < 1 || h < 1 {
+		return nil, fmt.Errorf("invalid size %dx%d", w, h)
+	}
+	if levels < 2 || levels > 256 {
+		return nil, fmt.Errorf("levels must be between 2 and 256, got %d", levels)
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(content))
+	if err != nil {
+		return nil, fmt.Errorf("could not decode image: %w", err)
+	}
+
+	lum := grayscale(src, w, h)
+	out := image.NewGray(image.Rect(0, 0, w, h))
+	step := 255 / float32(levels-1)
+	for y := range h {
+		for x := range w {
+			i := y*w + x
+			old := lum[i]
+			v := min(max(float32(math.Round(float64(old/step)))*step, 0), 255)
+			out.SetGray(x, y, color.Gray{Y: uint8(v + 0.5)})
+
+			e := old - v
+			if x+1 < w {
+				lum[i+1] += e * 7 / 16
+			}
+			if y+1 < h {
+				if x > 0 {
+					lum[i+w-1] += e * 3 / 16
+				}
+				lum[i+w] += e * 5 / 16
+				if x+1 < w {
+					lum[i+w+1] += e * 1 / 16
+				}
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	enc := png.Encoder{CompressionLevel: png.BestCompression}
+	if err := enc.Encode(&buf, out); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// grayscale returns the luminance of the center of src that has the aspect
+// ratio of w by h, scaled to w by h by averaging the source pixels that fall
+// in each output pixel.
+func grayscale(src image.Image, w, h int) []float32 {
+	b := src.Bounds()
+	cw, ch := b.Dx(), b.Dy()
+	if cw*h > ch*w {
+		cw = ch * w / h
+	} else {
+		ch = cw * h / w
+	}
+	cx := b.Min.X + (b.Dx()-cw)/2
+	cy := b.Min.Y + (b.Dy()-ch)/2
+
+	lum := make([]float32, w*h)
+	for y := range h {
+		y0 := cy + y*ch/h
+		y1 := max(cy+(y+1)*ch/h, y0+1)
+		for x := range w {
+			x0 := cx + x*cw/w
+			x1 := max(cx+(x+1)*cw/w, x0+1)
+
+			var sum float32
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					r, g, b, _ := src.At(sx, sy).RGBA()
+					sum += (0.299*float32(r) + 0.587*float32(g) + 0.114*float32(b)) / 257
+				}
+			}
+			lum[y*w+x] = sum / float32((x1-x0)*(y1-y0))
+		}
+	}
+
+	return lum
+}
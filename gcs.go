@@ -0,0 +1,474 @@
+package wallpapers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"strings"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"google.golang.org/api/iterator"
+)
+
+// OperationTimeout bounds each GCS call that does not transfer file
+// content, such as reading attributes or listing a page. Transfers are only
+// bounded by the caller's context, since large files on slow links can take
+// a long time.
+var OperationTimeout = 30 * time.Second
+
+// gcsStore keeps files in a GCS bucket. Public buckets are served through
+// imgix.
+//
+// Wallpapers live at the top of the bucket. A store with a prefix keeps its
+// files under that "directory" instead, where listings of the top level do
+// not see them.
+type gcsStore struct {
+	bucket    string
+	imgixHost string
+	prefix    string
+}
+
+// NewGCSStore returns a store for a collection's bucket.
+func NewGCSStore(c Collection) Store {
+	return &gcsStore{bucket: c.Bucket, imgixHost: c.ImgixHost}
+}
+
+// Sub returns a private store under the name/ prefix of the bucket.
+func (s *gcsStore) Sub(name string) (Store, error) {
+	return &gcsStore{bucket: s.bucket, prefix: s.prefix + name + "/"}, nil
+}
+
+// object returns the handle of a file in the store.
+func (s *gcsStore) object(client *storage.Client, name string) *storage.ObjectHandle {
+	return client.Bucket(s.bucket).Object(s.prefix + name)
+}
+
+// query lists the store's own files, leaving out anything under a prefix.
+func (s *gcsStore) query() *storage.Query {
+	return &storage.Query{
+		Prefix:     s.prefix,
+		Delimiter:  "/",
+		Projection: storage.ProjectionNoACL,
+	}
+}
+
+// gcsClient returns the process's GCS client. It is created on first use
+// and shared by every store, since each client holds its own connection
+// pool and credentials.
+var gcsClient = sync.OnceValues(func() (*storage.Client, error) {
+	return storage.NewClient(context.Background())
+})
+
+// withTimeout applies OperationTimeout to ctx.
+func withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if OperationTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, OperationTimeout)
+}
+
+func (s *gcsStore) Attrs(ctx context.Context, name string) (*File, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	client, err := gcsClient()
+	if err != nil {
+		return nil, err
+	}
+
+	attrs, err := s.object(client, name).Attrs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not get attrs: %w", err)
+	}
+
+	return s.newFile(attrs), nil
+}
+
+func (s *gcsStore) NewReader(ctx context.Context, name string) (io.ReadCloser, error) {
+	return s.NewRangeReader(ctx, name, 0, -1)
+}
+
+func (s *gcsStore) NewRangeReader(ctx context.Context, name string, offset, length int64) (io.ReadCloser, error) {
+	client, err := gcsClient()
+	if err != nil {
+		return nil, err
+	}
+
+	rc, err := s.object(client, name).NewRangeReader(ctx, offset, length)
+	if err != nil {
+		return nil, fmt.Errorf("could not open reader: %w", err)
+	}
+
+	return rc, nil
+}
+
+func (s *gcsStore) NewWriter(ctx context.Context, name string, sums Checksums, u ObjectUpdate) (io.WriteCloser, error) {
+	client, err := gcsClient()
+	if err != nil {
+		return nil, err
+	}
+
+	wc := s.object(client, name).NewWriter(ctx)
+	wc.CRC32C = sums.CRC32C
+	wc.SendCRC32C = true
+	wc.MD5 = sums.MD5
+	if s.imgixHost != "" {
+		wc.ACL = []storage.ACLRule{{Entity: storage.AllUsers, Role: storage.RoleReader}}
+	}
+	wc.ContentType = u.ContentType
+	if !u.CustomTime.IsZero() {
+		wc.CustomTime = u.CustomTime
+	}
+	if len(u.Metadata) > 0 {
+		wc.Metadata = u.Metadata
+	}
+
+	return &gcsWriter{Writer: wc, store: s}, nil
+}
+
+// gcsWriter returns the attributes GCS reports once a write is done.
+type gcsWriter struct {
+	*storage.Writer
+	store *gcsStore
+}
+
+// Written returns the attributes of the written file, or nil before Close.
+func (w *gcsWriter) Written() *File {
+	attrs := w.Attrs()
+	if attrs == nil {
+		return nil
+	}
+	return w.store.newFile(attrs)
+}
+
+func (s *gcsStore) Update(ctx context.Context, name string, u ObjectUpdate) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	client, err := gcsClient()
+	if err != nil {
+		return err
+	}
+
+	var update storage.ObjectAttrsToUpdate
+	if u.ContentType != "" {
+		update.ContentType = u.ContentType
+	}
+	if !u.CustomTime.IsZero() {
+		update.CustomTime = u.CustomTime
+	}
+	if len(u.Metadata) > 0 {
+		update.Metadata = u.Metadata
+	}
+
+	_, err = s.object(client, name).Update(ctx, update)
+	return err
+}
+
+func (s *gcsStore) Delete(ctx context.Context, name string) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	client, err := gcsClient()
+	if err != nil {
+		return err
+	}
+
+	return s.object(client, name).Delete(ctx)
+}
+
+func (s *gcsStore) Rename(ctx context.Context, from, to string) error {
+	client, err := gcsClient()
+	if err != nil {
+		return err
+	}
+
+	c := s.object(client, to).CopierFrom(s.object(client, from))
+	if s.imgixHost != "" {
+		c.PredefinedACL = "publicRead"
+	}
+	if _, err := c.Run(ctx); err != nil {
+		return fmt.Errorf("could not copy: %w", err)
+	}
+
+	if err := s.object(client, from).Delete(ctx); err != nil {
+		return fmt.Errorf("could not delete: %w", err)
+	}
+
+	return nil
+}
+
+// SetStorageClass rewrites an object in place with a new storage class,
+// keeping its metadata and ACL.
+func (s *gcsStore) SetStorageClass(ctx context.Context, name, class string) error {
+	client, err := gcsClient()
+	if err != nil {
+		return err
+	}
+
+	o := s.object(client, name)
+	attrs, err := o.Attrs(ctx)
+	if err != nil {
+		return err
+	}
+
+	c := o.CopierFrom(o.If(storage.Conditions{GenerationMatch: attrs.Generation}))
+	c.StorageClass = class
+	c.ContentType = attrs.ContentType
+	c.CacheControl = attrs.CacheControl
+	c.CustomTime = attrs.CustomTime
+	c.Metadata = attrs.Metadata
+	if s.imgixHost != "" {
+		c.PredefinedACL = "publicRead"
+	}
+	if _, err := c.Run(ctx); err != nil {
+		return fmt.Errorf("could not rewrite: %w", err)
+	}
+
+	return nil
+}
+
+func (s *gcsStore) Lifecycle(ctx context.Context) (*storage.Lifecycle, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	client, err := gcsClient()
+	if err != nil {
+		return nil, err
+	}
+
+	attrs, err := client.Bucket(s.bucket).Attrs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not get bucket attrs: %w", err)
+	}
+
+	return &attrs.Lifecycle, nil
+}
+
+func (s *gcsStore) SetLifecycle(ctx context.Context, l *storage.Lifecycle) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	client, err := gcsClient()
+	if err != nil {
+		return err
+	}
+
+	if _, err := client.Bucket(s.bucket).Update(ctx, storage.BucketAttrsToUpdate{Lifecycle: l}); err != nil {
+		return fmt.Errorf("could not update bucket lifecycle: %w", err)
+	}
+
+	return nil
+}
+
+func (s *gcsStore) Versioning(ctx context.Context) (bool, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	client, err := gcsClient()
+	if err != nil {
+		return false, err
+	}
+
+	attrs, err := client.Bucket(s.bucket).Attrs(ctx)
+	if err != nil {
+		return false, fmt.Errorf("could not get bucket attrs: %w", err)
+	}
+
+	return attrs.VersioningEnabled, nil
+}
+
+func (s *gcsStore) SetVersioning(ctx context.Context, enabled bool) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	client, err := gcsClient()
+	if err != nil {
+		return err
+	}
+
+	if _, err := client.Bucket(s.bucket).Update(ctx, storage.BucketAttrsToUpdate{VersioningEnabled: enabled}); err != nil {
+		return fmt.Errorf("could not update bucket versioning: %w", err)
+	}
+
+	return nil
+}
+
+func (s *gcsStore) Generations(ctx context.Context, name string) ([]*File, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	client, err := gcsClient()
+	if err != nil {
+		return nil, err
+	}
+
+	// The prefix also matches longer names, which are skipped.
+	it := client.Bucket(s.bucket).Objects(ctx, &storage.Query{
+		Prefix:     s.prefix + name,
+		Versions:   true,
+		Projection: storage.ProjectionNoACL,
+	})
+	var files []*File
+	for {
+		objAttrs, err := it.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error on iterating: %w", err)
+		}
+		if objAttrs.Name != s.prefix+name {
+			continue
+		}
+		files = append(files, s.newFile(objAttrs))
+	}
+	if len(files) == 0 {
+		return nil, storage.ErrObjectNotExist
+	}
+
+	return files, nil
+}
+
+// RestoreGeneration copies a generation over the live object, keeping the
+// generation's metadata and the store's ACL.
+func (s *gcsStore) RestoreGeneration(ctx context.Context, name string, generation int64) error {
+	client, err := gcsClient()
+	if err != nil {
+		return err
+	}
+
+	o := s.object(client, name)
+	c := o.CopierFrom(o.Generation(generation))
+	if s.imgixHost != "" {
+		c.PredefinedACL = "publicRead"
+	}
+	if _, err := c.Run(ctx); err != nil {
+		return fmt.Errorf("could not restore generation %d: %w", generation, err)
+	}
+
+	return nil
+}
+
+func (s *gcsStore) List(ctx context.Context) iter.Seq2[*File, error] {
+	return func(yield func(*File, error) bool) {
+		client, err := gcsClient()
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+
+		it := client.Bucket(s.bucket).Objects(ctx, s.query())
+		for {
+			// The iterator only notices cancellation when it fetches the
+			// next page.
+			if err := ctx.Err(); err != nil {
+				yield(nil, err)
+				return
+			}
+
+			objAttrs, err := it.Next()
+			if errors.Is(err, iterator.Done) {
+				return
+			}
+			if err != nil {
+				yield(nil, fmt.Errorf("error on iterating: %w", err))
+				return
+			}
+
+			// Sub-prefixes are returned as entries without a name.
+			if objAttrs.Name == "" {
+				continue
+			}
+
+			if !yield(s.newFile(objAttrs), nil) {
+				return
+			}
+		}
+	}
+}
+
+func (s *gcsStore) Page(ctx context.Context, pageToken string, size int) ([]*File, string, error) {
+	if err := checkPageSize(size); err != nil {
+		return nil, "", err
+	}
+
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	client, err := gcsClient()
+	if err != nil {
+		return nil, "", err
+	}
+
+	var attrs []*storage.ObjectAttrs
+	it := client.Bucket(s.bucket).Objects(ctx, s.query())
+	next, err := iterator.NewPager(it, size, pageToken).NextPage(&attrs)
+	if err != nil {
+		return nil, "", fmt.Errorf("error on paging: %w", err)
+	}
+
+	ret := make([]*File, 0, len(attrs))
+	for _, objAttrs := range attrs {
+		if objAttrs.Name == "" {
+			continue
+		}
+		ret = append(ret, s.newFile(objAttrs))
+	}
+
+	return ret, next, nil
+}
+
+func (s *gcsStore) Check(ctx context.Context) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	client, err := gcsClient()
+	if err != nil {
+		return err
+	}
+
+	if _, err := client.Bucket(s.bucket).Attrs(ctx); err != nil {
+		return fmt.Errorf("could not get bucket attrs: %w", err)
+	}
+
+	return nil
+}
+
+// newFile converts GCS attributes to a File. Files in private buckets have
+// no public URLs.
+func (s *gcsStore) newFile(objAttrs *storage.ObjectAttrs) *File {
+	name := strings.TrimPrefix(objAttrs.Name, s.prefix)
+	f := &File{
+		CRC32C:       objAttrs.CRC32C,
+		MD5:          objAttrs.MD5,
+		Etag:         objAttrs.Etag,
+		Generation:   objAttrs.Generation,
+		StorageClass: objAttrs.StorageClass,
+		Name:         name,
+		Bucket:       objAttrs.Bucket,
+		Type:         MediaType(name),
+		Size:         objAttrs.Size,
+		Created:      objAttrs.Created,
+		Updated:      objAttrs.Updated,
+		CustomTime:   objAttrs.CustomTime,
+		Deleted:      objAttrs.Deleted,
+		FileURL:      objAttrs.MediaLink,
+		Metadata:     objAttrs.Metadata,
+		Video:        videoFromMetadata(name, objAttrs.Metadata),
+		ColorProfile: objAttrs.Metadata[MetadataColorProfile],
+		VariantOf:    objAttrs.Metadata[MetadataVariantOf],
+		AltText:      objAttrs.Metadata[MetadataAltText],
+		Attribution:  attributionFromMetadata(objAttrs.Metadata),
+	}
+	if s.imgixHost != "" {
+		f.ThumbnailURL = thumbURL(s.imgixHost, s.bucket, name)
+		f.FullRezURL = fullRezURL(s.imgixHost, s.bucket, name)
+	}
+
+	return f
+}
@@ -0,0 +1,120 @@
+package wallpapers
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+)
+
+// ErrNoGenerations is returned for stores that do not keep previous
+// generations of files, such as the local and memory stores.
+var ErrNoGenerations = errors.New("store does not keep previous generations")
+
+// GenerationStore is implemented by stores that can keep the previous
+// content of overwritten and deleted files.
+type GenerationStore interface {
+	Versioning(ctx context.Context) (bool, error)
+	SetVersioning(ctx context.Context, enabled bool) error
+	// Generations returns every stored generation of a file, including
+	// the live one.
+	Generations(ctx context.Context, name string) ([]*File, error)
+	// RestoreGeneration copies a generation of a file over the live one.
+	RestoreGeneration(ctx context.Context, name string, generation int64) error
+}
+
+func generationStore(ctx context.Context) (GenerationStore, error) {
+	s, ok := StoreFor(ctx).(GenerationStore)
+	if !ok {
+		return nil, ErrNoGenerations
+	}
+	return s, nil
+}
+
+// Versioning reports whether the bucket behind ctx's store keeps previous
+// generations of files.
+func Versioning(ctx context.Context) (bool, error) {
+	s, err := generationStore(ctx)
+	if err != nil {
+		return false, err
+	}
+	return s.Versioning(ctx)
+}
+
+// SetVersioning turns keeping previous generations of files on or off for
+// the bucket behind ctx's store. Generations kept before it is turned off
+// are not deleted.
+func SetVersioning(ctx context.Context, enabled bool) error {
+	s, err := generationStore(ctx)
+	if err != nil {
+		return err
+	}
+	return s.SetVersioning(ctx, enabled)
+}
+
+// ListGenerations returns the stored generations of a file, newest first.
+// Previous generations have Deleted set to when they were replaced or
+// deleted.
+func ListGenerations(ctx context.Context, filename string) ([]*File, error) {
+	s, err := generationStore(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := s.Generations(ctx, filename)
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(files, func(a, b *File) int {
+		return -cmp.Compare(a.Generation, b.Generation)
+	})
+
+	return files, nil
+}
+
+// RestoreGeneration makes a previous generation of a file the live one,
+// for example to undo an upload that overwrote a different picture with
+// the same name. The generation being replaced is kept if versioning is
+// on.
+func RestoreGeneration(ctx context.Context, filename string, generation int64) error {
+	s, err := generationStore(ctx)
+	if err != nil {
+		return err
+	}
+
+	gens, err := s.Generations(ctx, filename)
+	if err != nil {
+		return err
+	}
+	var before, restored *File
+	for _, f := range gens {
+		if f.Deleted.IsZero() {
+			before = f
+		}
+		if f.Generation == generation {
+			restored = f
+		}
+	}
+	if restored == nil {
+		return fmt.Errorf("%q has no generation %d", filename, generation)
+	}
+	if restored == before {
+		return nil
+	}
+
+	if err := s.RestoreGeneration(ctx, filename, generation); err != nil {
+		return err
+	}
+
+	after := auditAttrs(restored)
+	after["generation"] = strconv.FormatInt(generation, 10)
+	recordAudit(ctx, AuditEntry{
+		Action: AuditRestore,
+		Name:   filename,
+		Before: auditAttrs(before),
+		After:  after,
+	})
+	return nil
+}
@@ -0,0 +1,117 @@
+package wallpapers
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"math/bits"
+	"slices"
+)
+
+// MetadataVariantOf names the file a wallpaper is a lower resolution copy
+// of. Listings hide such files by default.
+const MetadataVariantOf = "variant_of"
+
+// DefaultVariantDistance is the largest Hamming distance between the
+// perceptual hashes of two images that GroupVariants treats as the same
+// artwork. Rescaling and recompression usually stay within a few bits.
+const DefaultVariantDistance = 6
+
+// ImageHash is the perceptual hash and size of an image.
+type ImageHash struct {
+	Name   string
+	Hash   uint64
+	Width  int
+	Height int
+	Size   int64
+}
+
+// PerceptualHash returns a difference hash of an image along with its size.
+// The image is reduced to 9 by 8 gray pixels and each bit records whether a
+// pixel is brighter than its right neighbour, so the same artwork at
+// different resolutions or qualities hashes to nearly the same value.
+func PerceptualHash(content []byte) (uint64, int, int, error) {
+	src, _, err := image.Decode(bytes.NewReader(content))
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("could not decode image: %w", err)
+	}
+
+	small := resample(src, src.Bounds(), 9, 8)
+	var hash uint64
+	for y := range 8 {
+		for x := range 8 {
+			l := color.GrayModel.Convert(small.At(x, y)).(color.Gray).Y
+			r := color.GrayModel.Convert(small.At(x+1, y)).(color.Gray).Y
+			hash <<= 1
+			if l > r {
+				hash |= 1
+			}
+		}
+	}
+
+	return hash, src.Bounds().Dx(), src.Bounds().Dy(), nil
+}
+
+// HashDistance is the number of bits in which two perceptual hashes differ.
+func HashDistance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// GroupVariants clusters images whose hashes are within maxDistance bits of
+// another image in the cluster. Each group is ordered with the highest
+// resolution first. Images without a near match are left out.
+func GroupVariants(hashes []ImageHash, maxDistance int) [][]ImageHash {
+	parent := make([]int, len(hashes))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	for i := range hashes {
+		for j := i + 1; j < len(hashes); j++ {
+			if HashDistance(hashes[i].Hash, hashes[j].Hash) <= maxDistance {
+				parent[find(j)] = find(i)
+			}
+		}
+	}
+
+	byRoot := map[int][]ImageHash{}
+	for i, h := range hashes {
+		byRoot[find(i)] = append(byRoot[find(i)], h)
+	}
+
+	var groups [][]ImageHash
+	for _, g := range byRoot {
+		if len(g) < 2 {
+			continue
+		}
+		slices.SortFunc(g, func(a, b ImageHash) int {
+			return cmp.Or(
+				-cmp.Compare(a.Width*a.Height, b.Width*b.Height),
+				-cmp.Compare(a.Size, b.Size),
+				cmp.Compare(a.Name, b.Name),
+			)
+		})
+		groups = append(groups, g)
+	}
+	slices.SortFunc(groups, func(a, b []ImageHash) int {
+		return cmp.Compare(a[0].Name, b[0].Name)
+	})
+
+	return groups
+}
+
+// SetVariantOf records that filename is a lower resolution copy of primary.
+// An empty primary marks it as a wallpaper of its own again.
+func SetVariantOf(ctx context.Context, filename, primary string) error {
+	return updateFile(ctx, filename, ObjectUpdate{Metadata: map[string]string{MetadataVariantOf: primary}})
+}
@@ -0,0 +1,11 @@
+package wallpapers
+
+import (
+	"context"
+)
+
+// CheckBucket verifies that the store is reachable with the current
+// credentials.
+func CheckBucket(ctx context.Context) error {
+	return StoreFor(ctx).Check(ctx)
+}
@@ -0,0 +1,376 @@
+package wallpapers
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"math"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ErrIIIF is wrapped by errors for IIIF requests that are malformed or ask
+// for something that cannot be rendered.
+var ErrIIIF = errors.New("invalid IIIF request")
+
+// IIIF qualities.
+const (
+	IIIFDefault = "default"
+	IIIFColor   = "color"
+	IIIFGray    = "gray"
+	IIIFBitonal = "bitonal"
+)
+
+// IIIFRequest is an IIIF Image API 3.0 request resolved against the size of
+// an image. Width and Height are the size of the region after scaling and
+// before rotation.
+type IIIFRequest struct {
+	Region   image.Rectangle
+	Width    int
+	Height   int
+	Rotation int
+	Mirror   bool
+	Quality  string
+	Format   string
+}
+
+// ParseIIIF parses the region, size, rotation, quality and format of an IIIF
+// image request for an image of width by height. Output larger than maxArea
+// pixels is refused, except for max, which is scaled down to fit. Only
+// rotations by multiples of 90 degrees are supported.
+func ParseIIIF(width, height int, region, size, rotation, quality, format string, maxArea int) (*IIIFRequest, error) {
+	req := &IIIFRequest{}
+
+	var err error
+	if req.Region, err = parseIIIFRegion(width, height, region); err != nil {
+		return nil, err
+	}
+	if req.Width, req.Height, err = parseIIIFSize(req.Region.Dx(), req.Region.Dy(), size, maxArea); err != nil {
+		return nil, err
+	}
+
+	rot, mirror := strings.CutPrefix(rotation, "!")
+	deg, err := strconv.ParseFloat(rot, 64)
+	if err != nil || deg < 0 || deg > 360 || math.Mod(deg, 90) != 0 {
+		return nil, fmt.Errorf("rotation %q must be 0, 90, 180 or 270: %w", rotation, ErrIIIF)
+	}
+	req.Rotation = int(deg) % 360
+	req.Mirror = mirror
+
+	switch quality {
+	case IIIFDefault, IIIFColor, IIIFGray, IIIFBitonal:
+		req.Quality = quality
+	default:
+		return nil, fmt.Errorf("unknown quality %q: %w", quality, ErrIIIF)
+	}
+
+	switch format {
+	case "jpg", "png", "gif", "webp":
+		req.Format = format
+	default:
+		return nil, fmt.Errorf("unknown format %q: %w", format, ErrIIIF)
+	}
+
+	return req, nil
+}
+
+func parseIIIFRegion(width, height int, region string) (image.Rectangle, error) {
+	bounds := image.Rect(0, 0, width, height)
+	switch region {
+	case "full":
+		return bounds, nil
+	case "square":
+		s := min(width, height)
+		x, y := (width-s)/2, (height-s)/2
+		return image.Rect(x, y, x+s, y+s), nil
+	}
+
+	spec, pct := strings.CutPrefix(region, "pct:")
+	parts := strings.Split(spec, ",")
+	if len(parts) != 4 {
+		return image.Rectangle{}, fmt.Errorf("invalid region %q: %w", region, ErrIIIF)
+	}
+
+	var v [4]float64
+	for i, p := range parts {
+		n, err := strconv.ParseFloat(p, 64)
+		if err != nil || n < 0 || (!pct && n != math.Trunc(n)) {
+			return image.Rectangle{}, fmt.Errorf("invalid region %q: %w", region, ErrIIIF)
+		}
+		v[i] = n
+	}
+	if pct {
+		v[0] = v[0] * float64(width) / 100
+		v[1] = v[1] * float64(height) / 100
+		v[2] = v[2] * float64(width) / 100
+		v[3] = v[3] * float64(height) / 100
+	}
+
+	x, y := int(math.Round(v[0])), int(math.Round(v[1]))
+	r := image.Rect(x, y, x+int(math.Round(v[2])), y+int(math.Round(v[3]))).Intersect(bounds)
+	if r.Empty() {
+		return image.Rectangle{}, fmt.Errorf("region %q is outside the image: %w", region, ErrIIIF)
+	}
+
+	return r, nil
+}
+
+func parseIIIFSize(rw, rh int, size string, maxArea int) (int, int, error) {
+	spec, upscale := strings.CutPrefix(size, "^")
+	invalid := fmt.Errorf("invalid size %q: %w", size, ErrIIIF)
+
+	var w, h int
+	switch {
+	case spec == "max":
+		w, h = rw, rh
+		scale := math.Sqrt(float64(maxArea) / float64(rw*rh))
+		if scale < 1 || upscale {
+			w = max(int(float64(rw)*scale), 1)
+			h = max(int(float64(rh)*scale), 1)
+		}
+		return w, h, nil
+	case strings.HasPrefix(spec, "pct:"):
+		n, err := strconv.ParseFloat(strings.TrimPrefix(spec, "pct:"), 64)
+		if err != nil || n <= 0 {
+			return 0, 0, invalid
+		}
+		w = int(math.Round(float64(rw) * n / 100))
+		h = int(math.Round(float64(rh) * n / 100))
+	default:
+		spec, confined := strings.CutPrefix(spec, "!")
+		ws, hs, ok := strings.Cut(spec, ",")
+		if !ok || (ws == "" && hs == "") || (confined && (ws == "" || hs == "")) {
+			return 0, 0, invalid
+		}
+
+		var err error
+		if ws != "" {
+			if w, err = strconv.Atoi(ws); err != nil || w < 1 {
+				return 0, 0, invalid
+			}
+		}
+		if hs != "" {
+			if h, err = strconv.Atoi(hs); err != nil || h < 1 {
+				return 0, 0, invalid
+			}
+		}
+
+		switch {
+		case confined:
+			scale := min(float64(w)/float64(rw), float64(h)/float64(rh))
+			if !upscale {
+				scale = min(scale, 1)
+			}
+			w = int(math.Round(float64(rw) * scale))
+			h = int(math.Round(float64(rh) * scale))
+		case hs == "":
+			h = int(math.Round(float64(rh) * float64(w) / float64(rw)))
+		case ws == "":
+			w = int(math.Round(float64(rw) * float64(h) / float64(rh)))
+		}
+	}
+
+	w, h = max(w, 1), max(h, 1)
+	if !upscale && (w > rw || h > rh) {
+		return 0, 0, fmt.Errorf("size %q is larger than the region without ^: %w", size, ErrIIIF)
+	}
+	if w*h > maxArea {
+		return 0, 0, fmt.Errorf("size %q is larger than %d pixels: %w", size, maxArea, ErrIIIF)
+	}
+
+	return w, h, nil
+}
+
+// String returns the canonical form of the request's parameters, as a path.
+func (r *IIIFRequest) String() string {
+	rot := strconv.Itoa(r.Rotation)
+	if r.Mirror {
+		rot = "!" + rot
+	}
+	return fmt.Sprintf("%d,%d,%d,%d/%d,%d/%s/%s.%s",
+		r.Region.Min.X, r.Region.Min.Y, r.Region.Dx(), r.Region.Dy(), r.Width, r.Height, rot, r.Quality, r.Format)
+}
+
+// IIIFName returns the name the IIIF rendition of f is cached under. Like
+// EinkName, it includes f's checksum.
+func IIIFName(f *File, r *IIIFRequest) string {
+	base := strings.TrimSuffix(f.Name, filepath.Ext(f.Name))
+	return fmt.Sprintf("%s-%08x-iiif-%s", base, f.CRC32C, strings.NewReplacer("/", "_", ",", "-", "!", "m").Replace(r.String()))
+}
+
+// IIIFURL returns the URL of key hosted by imgix rendered as r asks. It
+// returns false if the store in ctx is not served through imgix, in which
+// case RenderIIIF should be used instead.
+func IIIFURL(ctx context.Context, key string, r *IIIFRequest) (string, bool) {
+	host, ok := imgixHost(ctx)
+	if !ok {
+		return "", false
+	}
+
+	q := url.Values{}
+	q.Set("rect", fmt.Sprintf("%d,%d,%d,%d", r.Region.Min.X, r.Region.Min.Y, r.Region.Dx(), r.Region.Dy()))
+	q.Set("w", strconv.Itoa(r.Width))
+	q.Set("h", strconv.Itoa(r.Height))
+	q.Set("fit", "scale")
+	if r.Mirror {
+		q.Set("flip", "h")
+	}
+	if r.Rotation != 0 {
+		q.Set("rot", strconv.Itoa(r.Rotation))
+	}
+	switch r.Quality {
+	case IIIFGray:
+		q.Set("sat", "-100")
+	case IIIFBitonal:
+		q.Set("sat", "-100")
+		q.Set("colorquant", "2")
+	}
+	q.Set("fm", r.Format)
+
+	return fmt.Sprintf("https://%s/%s?%s", host, key, q.Encode()), true
+}
+
+// ImageSize returns the width and height of an image, reading only as much
+// of it as its header needs.
+func ImageSize(ctx context.Context, filename string) (int, int, error) {
+	rc, err := OpenFile(ctx, filename)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer rc.Close()
+
+	cfg, _, err := image.DecodeConfig(bufio.NewReader(rc))
+	if err != nil {
+		return 0, 0, fmt.Errorf("could not read image size: %w", err)
+	}
+
+	return cfg.Width, cfg.Height, nil
+}
+
+// RenderIIIF renders an image as r asks. WebP cannot be encoded here, so it
+// is only available through imgix.
+func RenderIIIF(content []byte, r *IIIFRequest) ([]byte, error) {
+	if r.Format == "webp" {
+		return nil, fmt.Errorf("webp is only available through imgix: %w", ErrIIIF)
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(content))
+	if err != nil {
+		return nil, fmt.Errorf("could not decode image: %w", err)
+	}
+
+	region := r.Region.Add(src.Bounds().Min).Intersect(src.Bounds())
+	if region.Empty() {
+		return nil, fmt.Errorf("region is outside the image: %w", ErrIIIF)
+	}
+
+	var out image.Image = transform(resample(src, region, r.Width, r.Height), r.Rotation, r.Mirror)
+	switch r.Quality {
+	case IIIFGray, IIIFBitonal:
+		b := out.Bounds()
+		gray := image.NewGray(b)
+		for y := b.Min.Y; y < b.Max.Y; y++ {
+			for x := b.Min.X; x < b.Max.X; x++ {
+				v := color.GrayModel.Convert(out.At(x, y)).(color.Gray)
+				if r.Quality == IIIFBitonal {
+					v.Y = uint8(255 * (int(v.Y) / 128))
+				}
+				gray.SetGray(x, y, v)
+			}
+		}
+		out = gray
+	}
+
+	var buf bytes.Buffer
+	switch r.Format {
+	case "jpg":
+		err = jpeg.Encode(&buf, out, &jpeg.Options{Quality: 90})
+	case "png":
+		err = png.Encode(&buf, out)
+	case "gif":
+		err = gif.Encode(&buf, out, nil)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// resample scales the r part of src to w by h by averaging the source
+// pixels that fall in each output pixel.
+func resample(src image.Image, r image.Rectangle, w, h int) *image.RGBA {
+	out := image.NewRGBA(image.Rect(0, 0, w, h))
+	rw, rh := r.Dx(), r.Dy()
+	for y := range h {
+		y0 := r.Min.Y + y*rh/h
+		y1 := max(r.Min.Y+(y+1)*rh/h, y0+1)
+		for x := range w {
+			x0 := r.Min.X + x*rw/w
+			x1 := max(r.Min.X+(x+1)*rw/w, x0+1)
+
+			var sr, sg, sb, sa, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := src.At(sx, sy).RGBA()
+					sr, sg, sb, sa = sr+uint64(cr), sg+uint64(cg), sb+uint64(cb), sa+uint64(ca)
+					n++
+				}
+			}
+			out.SetRGBA(x, y, color.RGBA{
+				R: uint8(sr / n >> 8),
+				G: uint8(sg / n >> 8),
+				B: uint8(sb / n >> 8),
+				A: uint8(sa / n >> 8),
+			})
+		}
+	}
+
+	return out
+}
+
+// transform mirrors src horizontally if asked and then rotates it clockwise
+// by a multiple of 90 degrees.
+func transform(src *image.RGBA, rotation int, mirror bool) *image.RGBA {
+	if rotation == 0 && !mirror {
+		return src
+	}
+
+	w, h := src.Bounds().Dx(), src.Bounds().Dy()
+	ow, oh := w, h
+	if rotation == 90 || rotation == 270 {
+		ow, oh = h, w
+	}
+
+	out := image.NewRGBA(image.Rect(0, 0, ow, oh))
+	for y := range h {
+		for x := range w {
+			sx := x
+			if mirror {
+				sx = w - 1 - x
+			}
+
+			dx, dy := x, y
+			switch rotation {
+			case 90:
+				dx, dy = h-1-y, x
+			case 180:
+				dx, dy = w-1-x, h-1-y
+			case 270:
+				dx, dy = y, w-1-x
+			}
+			out.SetRGBA(dx, dy, src.RGBAAt(sx, y))
+		}
+	}
+
+	return out
+}
@@ -0,0 +1,414 @@
+package wallpapers
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5" // #nosec G501 -- GCS uses MD5 for integrity, not security
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+	"io/fs"
+	"iter"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/storage"
+)
+
+// LocalURLPrefix is the path the server serves a LocalStore's files from.
+const LocalURLPrefix = "/files/"
+
+// LocalStore keeps files in a directory, for development without cloud
+// credentials. Attributes GCS would store on the object are kept in a JSON
+// file per image under a .meta directory.
+type LocalStore struct {
+	Dir string
+}
+
+// localMeta is what a LocalStore keeps in an image's .meta file.
+type localMeta struct {
+	CustomTime time.Time         `json:"custom_time,omitempty"`
+	Metadata   map[string]string `json:"metadata,omitempty"`
+
+	// The image's checksums, and its size and modification time when they
+	// were taken. They are taken again if the image has changed since.
+	CRC32C  uint32    `json:"crc32c,omitempty"`
+	MD5     []byte    `json:"md5,omitempty"`
+	Size    int64     `json:"size,omitempty"`
+	ModTime time.Time `json:"mod_time,omitempty"`
+}
+
+// sums returns the checksums in m if they were taken of a file as described
+// by info.
+func (m localMeta) sums(info fs.FileInfo) (Checksums, bool) {
+	if len(m.MD5) == 0 || m.Size != info.Size() || !m.ModTime.Equal(info.ModTime()) {
+		return Checksums{}, false
+	}
+	return Checksums{CRC32C: m.CRC32C, MD5: m.MD5}, true
+}
+
+// setSums records the checksums of a file as described by info.
+func (m *localMeta) setSums(sums Checksums, info fs.FileInfo) {
+	m.CRC32C = sums.CRC32C
+	m.MD5 = sums.MD5
+	m.Size = info.Size()
+	m.ModTime = info.ModTime()
+}
+
+// NewLocalStore returns a store for dir, creating it if needed.
+func NewLocalStore(dir string) (*LocalStore, error) {
+	if err := os.MkdirAll(filepath.Join(dir, ".meta"), 0750); err != nil {
+		return nil, fmt.Errorf("could not create local store: %w", err)
+	}
+
+	return &LocalStore{Dir: dir}, nil
+}
+
+// URL returns where the server serves key from.
+func (s *LocalStore) URL(key string) string {
+	return LocalURLPrefix + key
+}
+
+// Sub returns a store in a hidden directory named after name, which is left
+// out of listings like every other dotfile.
+func (s *LocalStore) Sub(name string) (Store, error) {
+	return NewLocalStore(filepath.Join(s.Dir, "."+name))
+}
+
+// path returns the path of an image, refusing names that would escape Dir.
+func (s *LocalStore) path(name string) (string, error) {
+	if !filepath.IsLocal(name) || strings.ContainsAny(name, `/\`) || strings.HasPrefix(name, ".") {
+		return "", fmt.Errorf("invalid name %q: %w", name, storage.ErrObjectNotExist)
+	}
+
+	return filepath.Join(s.Dir, name), nil
+}
+
+func (s *LocalStore) metaPath(name string) string {
+	return filepath.Join(s.Dir, ".meta", name+".json")
+}
+
+func (s *LocalStore) readMeta(name string) (localMeta, error) {
+	var m localMeta
+	dat, err := os.ReadFile(s.metaPath(name))
+	if errors.Is(err, fs.ErrNotExist) {
+		return m, nil
+	}
+	if err != nil {
+		return m, err
+	}
+
+	return m, json.Unmarshal(dat, &m)
+}
+
+func (s *LocalStore) writeMeta(name string, m localMeta) error {
+	dat, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(s.metaPath(name), dat, 0600)
+}
+
+func (s *LocalStore) Attrs(ctx context.Context, name string) (*File, error) {
+	path, err := s.path(name)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("could not get attrs: %w", storage.ErrObjectNotExist)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not get attrs: %w", err)
+	}
+
+	m, err := s.readMeta(name)
+	if err != nil {
+		return nil, fmt.Errorf("could not read meta: %w", err)
+	}
+
+	sums, ok := m.sums(info)
+	if !ok {
+		dat, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("could not read file: %w", err)
+		}
+		sums = GetChecksums(dat)
+
+		// If this fails the checksums are only taken again next time.
+		m.setSums(sums, info)
+		_ = s.writeMeta(name, m)
+	}
+
+	return &File{
+		CRC32C:       sums.CRC32C,
+		MD5:          sums.MD5,
+		Etag:         strconv.FormatUint(uint64(sums.CRC32C), 16),
+		Name:         name,
+		Type:         MediaType(name),
+		Size:         info.Size(),
+		Created:      info.ModTime(),
+		Updated:      info.ModTime(),
+		CustomTime:   m.CustomTime,
+		ThumbnailURL: s.URL(name),
+		FileURL:      s.URL(name),
+		FullRezURL:   s.URL(name),
+		Metadata:     m.Metadata,
+		Video:        videoFromMetadata(name, m.Metadata),
+		ColorProfile: m.Metadata[MetadataColorProfile],
+		VariantOf:    m.Metadata[MetadataVariantOf],
+		AltText:      m.Metadata[MetadataAltText],
+		Attribution:  attributionFromMetadata(m.Metadata),
+	}, nil
+}
+
+func (s *LocalStore) NewReader(ctx context.Context, name string) (io.ReadCloser, error) {
+	return s.NewRangeReader(ctx, name, 0, -1)
+}
+
+func (s *LocalStore) NewRangeReader(ctx context.Context, name string, offset, length int64) (io.ReadCloser, error) {
+	path, err := s.path(name)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("could not open reader: %w", storage.ErrObjectNotExist)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not open reader: %w", err)
+	}
+
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("could not open reader: %w", err)
+	}
+	if length < 0 {
+		return f, nil
+	}
+
+	return struct {
+		io.Reader
+		io.Closer
+	}{io.LimitReader(f, length), f}, nil
+}
+
+// localWriter writes to a temporary file and renames it into place on Close,
+// so readers never see a partial image.
+type localWriter struct {
+	*os.File
+	store *LocalStore
+	name  string
+	path  string
+	sums  Checksums
+	u     ObjectUpdate
+	crc   uint32
+	md5   hash.Hash
+}
+
+func (w *localWriter) Write(p []byte) (int, error) {
+	w.crc = crc32.Update(w.crc, crc32.MakeTable(crc32.Castagnoli), p)
+	w.md5.Write(p)
+	return w.File.Write(p)
+}
+
+func (w *localWriter) Close() error {
+	if err := w.File.Close(); err != nil {
+		return err
+	}
+	if w.crc != w.sums.CRC32C || (len(w.sums.MD5) > 0 && !bytes.Equal(w.md5.Sum(nil), w.sums.MD5)) {
+		_ = os.Remove(w.File.Name())
+		return fmt.Errorf("%w: upload of %q", ErrCorrupt, w.name)
+	}
+	if err := os.Rename(w.File.Name(), w.path); err != nil {
+		return err
+	}
+	info, err := os.Stat(w.path)
+	if err != nil {
+		return err
+	}
+
+	m := localMeta{CustomTime: w.u.CustomTime, Metadata: w.u.Metadata}
+	m.setSums(Checksums{CRC32C: w.crc, MD5: w.md5.Sum(nil)}, info)
+	return w.store.writeMeta(w.name, m)
+}
+
+func (s *LocalStore) NewWriter(ctx context.Context, name string, sums Checksums, u ObjectUpdate) (io.WriteCloser, error) {
+	path, err := s.path(name)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.CreateTemp(s.Dir, ".upload-*")
+	if err != nil {
+		return nil, fmt.Errorf("could not create file: %w", err)
+	}
+
+	return &localWriter{File: f, store: s, name: name, path: path, sums: sums, u: u, md5: md5.New()}, nil
+}
+
+func (s *LocalStore) Update(ctx context.Context, name string, u ObjectUpdate) error {
+	if _, err := s.Attrs(ctx, name); err != nil {
+		return err
+	}
+
+	m, err := s.readMeta(name)
+	if err != nil {
+		return err
+	}
+
+	if !u.CustomTime.IsZero() {
+		m.CustomTime = u.CustomTime
+	}
+	for k, v := range u.Metadata {
+		if m.Metadata == nil {
+			m.Metadata = map[string]string{}
+		}
+		if v == "" {
+			delete(m.Metadata, k)
+			continue
+		}
+		m.Metadata[k] = v
+	}
+
+	return s.writeMeta(name, m)
+}
+
+func (s *LocalStore) Delete(ctx context.Context, name string) error {
+	path, err := s.path(name)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return storage.ErrObjectNotExist
+		}
+		return err
+	}
+
+	if err := os.Remove(s.metaPath(name)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+func (s *LocalStore) Rename(ctx context.Context, from, to string) error {
+	src, err := s.path(from)
+	if err != nil {
+		return err
+	}
+	dst, err := s.path(to)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Rename(src, dst); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return storage.ErrObjectNotExist
+		}
+		return err
+	}
+
+	if err := os.Rename(s.metaPath(from), s.metaPath(to)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+// names returns the names of every image, sorted.
+func (s *LocalStore) names() ([]string, error) {
+	entries, err := os.ReadDir(s.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, e := range entries {
+		if e.IsDir() || strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		names = append(names, e.Name())
+	}
+
+	return names, nil
+}
+
+func (s *LocalStore) List(ctx context.Context) iter.Seq2[*File, error] {
+	return func(yield func(*File, error) bool) {
+		names, err := s.names()
+		if err != nil {
+			yield(nil, fmt.Errorf("error on iterating: %w", err))
+			return
+		}
+
+		for _, name := range names {
+			if err := ctx.Err(); err != nil {
+				yield(nil, err)
+				return
+			}
+
+			f, err := s.Attrs(ctx, name)
+			if !yield(f, err) || err != nil {
+				return
+			}
+		}
+	}
+}
+
+// Page uses the last name of the previous page as the token.
+func (s *LocalStore) Page(ctx context.Context, pageToken string, size int) ([]*File, string, error) {
+	if err := checkPageSize(size); err != nil {
+		return nil, "", err
+	}
+
+	names, err := s.names()
+	if err != nil {
+		return nil, "", fmt.Errorf("error on paging: %w", err)
+	}
+
+	var ret []*File
+	for _, name := range names {
+		if name <= pageToken {
+			continue
+		}
+
+		f, err := s.Attrs(ctx, name)
+		if err != nil {
+			return nil, "", err
+		}
+		ret = append(ret, f)
+
+		if len(ret) == size {
+			if name == names[len(names)-1] {
+				return ret, "", nil
+			}
+			return ret, name, nil
+		}
+	}
+
+	return ret, "", nil
+}
+
+func (s *LocalStore) Check(ctx context.Context) error {
+	info, err := os.Stat(s.Dir)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", s.Dir)
+	}
+
+	return nil
+}
@@ -0,0 +1,65 @@
+package wallpapers
+
+import (
+	"context"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// Logger receives the package's log lines: a message followed by
+// alternating keys and values. *zap.SugaredLogger implements it, so the
+// commands pass their own logger straight through.
+type Logger interface {
+	Debugw(msg string, keysAndValues ...any)
+	Infow(msg string, keysAndValues ...any)
+	Warnw(msg string, keysAndValues ...any)
+	Errorw(msg string, keysAndValues ...any)
+}
+
+var _ Logger = (*zap.SugaredLogger)(nil)
+
+// nopLogger discards everything. It is the default, so programs using the
+// package see no output unless they ask for it.
+type nopLogger struct{}
+
+func (nopLogger) Debugw(string, ...any) {}
+func (nopLogger) Infow(string, ...any)  {}
+func (nopLogger) Warnw(string, ...any)  {}
+func (nopLogger) Errorw(string, ...any) {}
+
+var (
+	loggerMu      sync.RWMutex
+	defaultLogger Logger = nopLogger{}
+)
+
+// SetLogger replaces the logger used when a context has none. A nil l
+// turns logging off.
+func SetLogger(l Logger) {
+	if l == nil {
+		l = nopLogger{}
+	}
+
+	loggerMu.Lock()
+	defer loggerMu.Unlock()
+	defaultLogger = l
+}
+
+type loggerKey struct{}
+
+// ContextWithLogger returns a context whose package level calls log to l,
+// for example a logger carrying a request's ID.
+func ContextWithLogger(ctx context.Context, l Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, l)
+}
+
+// LoggerFor returns the logger set on ctx, or the one set by SetLogger.
+func LoggerFor(ctx context.Context) Logger {
+	if l, ok := ctx.Value(loggerKey{}).(Logger); ok {
+		return l
+	}
+
+	loggerMu.RLock()
+	defer loggerMu.RUnlock()
+	return defaultLogger
+}
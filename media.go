@@ -0,0 +1,161 @@
+package wallpapers
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"iter"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Media types of wallpapers.
+const (
+	TypeImage = "image"
+	TypeVideo = "video"
+)
+
+// MediaTypes are the media types a wallpaper can have.
+var MediaTypes = []string{TypeImage, TypeVideo}
+
+// videoContentTypes are the looping video formats we accept, by extension.
+var videoContentTypes = map[string]string{
+	".mp4":  "video/mp4",
+	".webm": "video/webm",
+}
+
+// MediaType returns whether a file is an image or a video, based on its
+// name.
+func MediaType(name string) string {
+	if _, ok := videoContentTypes[strings.ToLower(filepath.Ext(name))]; ok {
+		return TypeVideo
+	}
+	return TypeImage
+}
+
+// Object metadata keys used to store what we know about a video.
+const (
+	MetadataWidth    = "width"
+	MetadataHeight   = "height"
+	MetadataDuration = "duration"
+)
+
+// VideoInfo describes a video wallpaper.
+type VideoInfo struct {
+	Width    int     `json:"width,omitempty"`
+	Height   int     `json:"height,omitempty"`
+	Duration float64 `json:"duration,omitempty"`
+}
+
+func videoFromMetadata(name string, md map[string]string) *VideoInfo {
+	if MediaType(name) != TypeVideo {
+		return nil
+	}
+
+	v := &VideoInfo{}
+	v.Width, _ = strconv.Atoi(md[MetadataWidth])
+	v.Height, _ = strconv.Atoi(md[MetadataHeight])
+	v.Duration, _ = strconv.ParseFloat(md[MetadataDuration], 64)
+	return v
+}
+
+// WithVideoInfo stores what we know about a video as object metadata.
+func WithVideoInfo(v *VideoInfo) UploadOption {
+	return WithMetadata(map[string]string{
+		MetadataWidth:    strconv.Itoa(v.Width),
+		MetadataHeight:   strconv.Itoa(v.Height),
+		MetadataDuration: strconv.FormatFloat(v.Duration, 'f', 3, 64),
+	})
+}
+
+// ProbeMP4 reads the duration and resolution of an MP4 from its movie
+// header and the first video track header.
+func ProbeMP4(content []byte) (*VideoInfo, error) {
+	moov, ok := findBox(content, "moov")
+	if !ok {
+		return nil, errors.New("no moov box")
+	}
+
+	v := &VideoInfo{}
+	if mvhd, ok := findBox(moov, "mvhd"); ok && len(mvhd) >= 32 {
+		var timescale uint32
+		var duration uint64
+		if mvhd[0] == 1 {
+			timescale = binary.BigEndian.Uint32(mvhd[20:24])
+			duration = binary.BigEndian.Uint64(mvhd[24:32])
+		} else {
+			timescale = binary.BigEndian.Uint32(mvhd[12:16])
+			duration = uint64(binary.BigEndian.Uint32(mvhd[16:20]))
+		}
+		if timescale > 0 {
+			v.Duration = float64(duration) / float64(timescale)
+		}
+	}
+
+	for trak := range boxes(moov, "trak") {
+		tkhd, ok := findBox(trak, "tkhd")
+		if !ok {
+			continue
+		}
+
+		// Width and height are 16.16 fixed point at the end of the box.
+		off := 76
+		if tkhd[0] == 1 {
+			off = 88
+		}
+		if len(tkhd) < off+8 {
+			continue
+		}
+
+		w := int(binary.BigEndian.Uint32(tkhd[off:]) >> 16)
+		h := int(binary.BigEndian.Uint32(tkhd[off+4:]) >> 16)
+		if w > 0 && h > 0 {
+			v.Width, v.Height = w, h
+			break
+		}
+	}
+
+	if v.Width == 0 {
+		return nil, fmt.Errorf("no video track")
+	}
+
+	return v, nil
+}
+
+// findBox returns the payload of the first box of type typ in data.
+func findBox(data []byte, typ string) ([]byte, bool) {
+	for b := range boxes(data, typ) {
+		return b, true
+	}
+	return nil, false
+}
+
+// boxes iterates over the payloads of the ISO BMFF boxes of type typ in data,
+// without descending into them.
+func boxes(data []byte, typ string) iter.Seq[[]byte] {
+	return func(yield func([]byte) bool) {
+		for len(data) >= 8 {
+			size := uint64(binary.BigEndian.Uint32(data[:4]))
+			header := uint64(8)
+			switch size {
+			case 0:
+				size = uint64(len(data))
+			case 1:
+				if len(data) < 16 {
+					return
+				}
+				size = binary.BigEndian.Uint64(data[8:16])
+				header = 16
+			}
+			if size < header || size > uint64(len(data)) {
+				return
+			}
+
+			if string(data[4:8]) == typ && !yield(data[header:size]) {
+				return
+			}
+			data = data[size:]
+		}
+	}
+}
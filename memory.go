@@ -0,0 +1,286 @@
+package wallpapers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"iter"
+	"maps"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/storage"
+)
+
+// MemoryStore keeps files in memory. It is meant for tests: install one with
+// SetStore to exercise code that uploads, lists and deletes wallpapers
+// without GCS.
+type MemoryStore struct {
+	mu      sync.RWMutex
+	objects map[string]*memoryObject
+	subs    map[string]*MemoryStore
+
+	// Now returns the time used for created and updated times. It defaults
+	// to time.Now.
+	Now func() time.Time
+}
+
+type memoryObject struct {
+	content []byte
+	file    File
+}
+
+// copyFile returns a copy of the object's attributes that callers can
+// modify.
+func (o *memoryObject) copyFile() *File {
+	f := o.file
+	f.Metadata = maps.Clone(o.file.Metadata)
+	if o.file.Video != nil {
+		v := *o.file.Video
+		f.Video = &v
+	}
+	return &f
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{objects: map[string]*memoryObject{}}
+}
+
+// Sub returns a separate MemoryStore for the given name.
+func (s *MemoryStore) Sub(name string) (Store, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.subs == nil {
+		s.subs = map[string]*MemoryStore{}
+	}
+	if s.subs[name] == nil {
+		s.subs[name] = NewMemoryStore()
+		s.subs[name].Now = s.Now
+	}
+	return s.subs[name], nil
+}
+
+func (s *MemoryStore) now() time.Time {
+	if s.Now != nil {
+		return s.Now()
+	}
+	return time.Now()
+}
+
+func (s *MemoryStore) Attrs(ctx context.Context, name string) (*File, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	o, ok := s.objects[name]
+	if !ok {
+		return nil, fmt.Errorf("could not get attrs: %w", storage.ErrObjectNotExist)
+	}
+
+	return o.copyFile(), nil
+}
+
+func (s *MemoryStore) NewReader(ctx context.Context, name string) (io.ReadCloser, error) {
+	return s.NewRangeReader(ctx, name, 0, -1)
+}
+
+func (s *MemoryStore) NewRangeReader(ctx context.Context, name string, offset, length int64) (io.ReadCloser, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	o, ok := s.objects[name]
+	if !ok {
+		return nil, fmt.Errorf("could not open reader: %w", storage.ErrObjectNotExist)
+	}
+
+	content := o.content[min(max(offset, 0), int64(len(o.content))):]
+	if length >= 0 {
+		content = content[:min(length, int64(len(content)))]
+	}
+
+	return io.NopCloser(bytes.NewReader(content)), nil
+}
+
+// memoryWriter buffers a file and stores it on Close.
+type memoryWriter struct {
+	bytes.Buffer
+	store   *MemoryStore
+	name    string
+	sums    Checksums
+	u       ObjectUpdate
+	written *File
+}
+
+// Written returns the attributes of the stored file, or nil before Close.
+func (w *memoryWriter) Written() *File {
+	return w.written
+}
+
+func (w *memoryWriter) Close() error {
+	content := bytes.Clone(w.Bytes())
+	got := GetChecksums(content)
+	if got.CRC32C != w.sums.CRC32C || (len(w.sums.MD5) > 0 && !bytes.Equal(got.MD5, w.sums.MD5)) {
+		return fmt.Errorf("%w: upload of %q", ErrCorrupt, w.name)
+	}
+
+	s := w.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	created := now
+	if old, ok := s.objects[w.name]; ok {
+		created = old.file.Created
+	}
+
+	s.objects[w.name] = &memoryObject{
+		content: content,
+		file: File{
+			CRC32C:       got.CRC32C,
+			MD5:          got.MD5,
+			Etag:         strconv.FormatUint(uint64(got.CRC32C), 16),
+			Name:         w.name,
+			Type:         MediaType(w.name),
+			Size:         int64(len(content)),
+			Created:      created,
+			Updated:      now,
+			CustomTime:   w.u.CustomTime,
+			ThumbnailURL: thumbURL(ImgixHost, Bucket, w.name),
+			FullRezURL:   fullRezURL(ImgixHost, Bucket, w.name),
+			Metadata:     maps.Clone(w.u.Metadata),
+			Video:        videoFromMetadata(w.name, w.u.Metadata),
+			ColorProfile: w.u.Metadata[MetadataColorProfile],
+			VariantOf:    w.u.Metadata[MetadataVariantOf],
+			AltText:      w.u.Metadata[MetadataAltText],
+			Attribution:  attributionFromMetadata(w.u.Metadata),
+		},
+	}
+	w.written = s.objects[w.name].copyFile()
+
+	return nil
+}
+
+func (s *MemoryStore) NewWriter(ctx context.Context, name string, sums Checksums, u ObjectUpdate) (io.WriteCloser, error) {
+	return &memoryWriter{store: s, name: name, sums: sums, u: u}, nil
+}
+
+func (s *MemoryStore) Update(ctx context.Context, name string, u ObjectUpdate) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	o, ok := s.objects[name]
+	if !ok {
+		return storage.ErrObjectNotExist
+	}
+
+	if !u.CustomTime.IsZero() {
+		o.file.CustomTime = u.CustomTime
+	}
+	for k, v := range u.Metadata {
+		if o.file.Metadata == nil {
+			o.file.Metadata = map[string]string{}
+		}
+		if v == "" {
+			delete(o.file.Metadata, k)
+			continue
+		}
+		o.file.Metadata[k] = v
+	}
+	o.file.Attribution = attributionFromMetadata(o.file.Metadata)
+	o.file.Video = videoFromMetadata(name, o.file.Metadata)
+	o.file.ColorProfile = o.file.Metadata[MetadataColorProfile]
+	o.file.VariantOf = o.file.Metadata[MetadataVariantOf]
+	o.file.AltText = o.file.Metadata[MetadataAltText]
+	o.file.Updated = s.now()
+
+	return nil
+}
+
+func (s *MemoryStore) Delete(ctx context.Context, name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.objects[name]; !ok {
+		return storage.ErrObjectNotExist
+	}
+	delete(s.objects, name)
+
+	return nil
+}
+
+func (s *MemoryStore) Rename(ctx context.Context, from, to string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	o, ok := s.objects[from]
+	if !ok {
+		return storage.ErrObjectNotExist
+	}
+
+	delete(s.objects, from)
+	o.file.Name = to
+	o.file.Type = MediaType(to)
+	o.file.ThumbnailURL = thumbURL(ImgixHost, Bucket, to)
+	o.file.FullRezURL = fullRezURL(ImgixHost, Bucket, to)
+	o.file.Video = videoFromMetadata(to, o.file.Metadata)
+	o.file.Updated = s.now()
+	s.objects[to] = o
+
+	return nil
+}
+
+// snapshot returns a copy of every file, sorted by name.
+func (s *MemoryStore) snapshot() []*File {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ret := make([]*File, 0, len(s.objects))
+	for _, name := range slices.Sorted(maps.Keys(s.objects)) {
+		ret = append(ret, s.objects[name].copyFile())
+	}
+
+	return ret
+}
+
+func (s *MemoryStore) List(ctx context.Context) iter.Seq2[*File, error] {
+	return func(yield func(*File, error) bool) {
+		for _, f := range s.snapshot() {
+			if err := ctx.Err(); err != nil {
+				yield(nil, err)
+				return
+			}
+			if !yield(f, nil) {
+				return
+			}
+		}
+	}
+}
+
+// Page uses the last name of the previous page as the token.
+func (s *MemoryStore) Page(ctx context.Context, pageToken string, size int) ([]*File, string, error) {
+	if err := checkPageSize(size); err != nil {
+		return nil, "", err
+	}
+
+	files := s.snapshot()
+	start, _ := slices.BinarySearchFunc(files, pageToken, func(f *File, t string) int {
+		if f.Name <= t {
+			return -1
+		}
+		return 1
+	})
+
+	files = files[start:]
+	if len(files) <= size {
+		return files, "", nil
+	}
+
+	return files[:size], files[size-1].Name, nil
+}
+
+func (s *MemoryStore) Check(ctx context.Context) error {
+	return nil
+}
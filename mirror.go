@@ -0,0 +1,102 @@
+package wallpapers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Mirror states reported by CompareMirror.
+const (
+	// MirrorOK means the mirror has the same content as the primary.
+	MirrorOK = "ok"
+	// MirrorMissing means the file has not been copied to the mirror.
+	MirrorMissing = "missing"
+	// MirrorStale means the mirror's copy has a different checksum, either
+	// because the primary was replaced or the copy is corrupt.
+	MirrorStale = "stale"
+	// MirrorExtra means the file is only in the mirror, for example after
+	// being deleted from the primary.
+	MirrorExtra = "extra"
+)
+
+// MirrorEntry is the state of one file in a mirror.
+type MirrorEntry struct {
+	Name  string
+	State string
+}
+
+// MirrorFile copies a file from src to dst, keeping its attributes. The
+// copy is checked against the source's checksums, so dst only keeps it if
+// it arrived intact.
+func MirrorFile(ctx context.Context, src, dst Store, name string) error {
+	f, err := src.Attrs(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	r, err := src.NewReader(ctx, name)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	u := ObjectUpdate{
+		ContentType: videoContentTypes[strings.ToLower(filepath.Ext(name))],
+		CustomTime:  f.CustomTime,
+		Metadata:    f.Metadata,
+	}
+	wc, err := dst.NewWriter(ctx, name, Checksums{CRC32C: f.CRC32C, MD5: f.MD5}, u)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(wc, r); err != nil {
+		_ = wc.Close()
+		return fmt.Errorf("could not copy %q to mirror: %w", name, err)
+	}
+	if err := wc.Close(); err != nil {
+		return fmt.Errorf("could not copy %q to mirror: %w", name, err)
+	}
+	LoggerFor(ctx).Debugw("mirrored file", "name", name, "bytes", f.Size)
+
+	return nil
+}
+
+// CompareMirror lists src and dst and returns the state of every file in
+// either, in name order. Files are compared by CRC32C.
+func CompareMirror(ctx context.Context, src, dst Store) ([]MirrorEntry, error) {
+	mirrored := map[string]uint32{}
+	for f, err := range dst.List(ctx) {
+		if err != nil {
+			return nil, err
+		}
+		mirrored[f.Name] = f.CRC32C
+	}
+
+	var ret []MirrorEntry
+	for f, err := range src.List(ctx) {
+		if err != nil {
+			return nil, err
+		}
+
+		crc, ok := mirrored[f.Name]
+		delete(mirrored, f.Name)
+		switch {
+		case !ok:
+			ret = append(ret, MirrorEntry{Name: f.Name, State: MirrorMissing})
+		case crc != f.CRC32C:
+			ret = append(ret, MirrorEntry{Name: f.Name, State: MirrorStale})
+		default:
+			ret = append(ret, MirrorEntry{Name: f.Name, State: MirrorOK})
+		}
+	}
+	for name := range mirrored {
+		ret = append(ret, MirrorEntry{Name: name, State: MirrorExtra})
+	}
+	slices.SortFunc(ret, func(a, b MirrorEntry) int { return strings.Compare(a.Name, b.Name) })
+
+	return ret, nil
+}
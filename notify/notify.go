@@ -0,0 +1,155 @@
+// Package notify posts new wallpapers to webhooks, including Slack and
+// Discord incoming webhooks.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/icco/wallpapers"
+)
+
+// EnvVar is the environment variable holding a comma separated list of
+// webhook URLs.
+const EnvVar = "WALLPAPERS_WEBHOOKS"
+
+// Notifier sends a message to every configured webhook.
+type Notifier struct {
+	URLs   []string
+	Client *http.Client
+}
+
+// New returns a Notifier for the given webhook URLs.
+func New(urls []string) *Notifier {
+	return &Notifier{
+		URLs:   urls,
+		Client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// FromEnv returns a Notifier for the webhooks listed in WALLPAPERS_WEBHOOKS.
+func FromEnv() *Notifier {
+	var urls []string
+	for _, u := range strings.Split(os.Getenv(EnvVar), ",") {
+		if u = strings.TrimSpace(u); u != "" {
+			urls = append(urls, u)
+		}
+	}
+
+	return New(urls)
+}
+
+// Enabled reports whether there are any webhooks to notify.
+func (n *Notifier) Enabled() bool {
+	return n != nil && len(n.URLs) > 0
+}
+
+// NewWallpaper announces a newly uploaded wallpaper to every webhook. All
+// webhooks are tried, and their errors are returned together.
+func (n *Notifier) NewWallpaper(ctx context.Context, name string) error {
+	if !n.Enabled() {
+		return nil
+	}
+
+	var errs []error
+	for _, u := range n.URLs {
+		if err := n.post(ctx, u, payload(ctx, u, name)); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (n *Notifier) post(ctx context.Context, u string, body any) error {
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewReader(buf))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("could not post to %s: %w", redact(u), err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("could not post to %s: %s", redact(u), resp.Status)
+	}
+
+	return nil
+}
+
+// payload builds the message body for a webhook, using the Slack or Discord
+// format when the URL belongs to one of them. Links point at the collection
+// on ctx.
+func payload(ctx context.Context, webhook, name string) any {
+	thumb := wallpapers.ThumbURL(ctx, name)
+	link := wallpapers.FullRezURL(ctx, name)
+	text := fmt.Sprintf("New wallpaper: %s", name)
+
+	host := ""
+	if pu, err := url.Parse(webhook); err == nil {
+		host = pu.Hostname()
+	}
+
+	switch {
+	case host == "hooks.slack.com":
+		return map[string]any{
+			"text": fmt.Sprintf("%s <%s>", text, link),
+			"blocks": []map[string]any{
+				{
+					"type": "section",
+					"text": map[string]string{"type": "mrkdwn", "text": fmt.Sprintf("New wallpaper: <%s|%s>", link, name)},
+				},
+				{
+					"type":      "image",
+					"image_url": thumb,
+					"alt_text":  name,
+				},
+			},
+		}
+	case host == "discord.com" || host == "discordapp.com":
+		return map[string]any{
+			"content": text,
+			"embeds": []map[string]any{
+				{
+					"title": name,
+					"url":   link,
+					"image": map[string]string{"url": thumb},
+				},
+			},
+		}
+	default:
+		return map[string]string{
+			"event":     "wallpaper.created",
+			"name":      name,
+			"thumbnail": thumb,
+			"link":      link,
+		}
+	}
+}
+
+// redact strips the path from a webhook URL, as it is usually a secret.
+func redact(u string) string {
+	pu, err := url.Parse(u)
+	if err != nil {
+		return "webhook"
+	}
+
+	return pu.Scheme + "://" + pu.Host
+}
@@ -0,0 +1,276 @@
+package wallpapers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// DeviceKind is the shape of frame a device preview is drawn in.
+type DeviceKind int
+
+// Device kinds.
+const (
+	Laptop DeviceKind = iota
+	Phone
+	Monitor
+)
+
+// Device is a screen that Preview draws a wallpaper on.
+type Device struct {
+	Kind DeviceKind
+	// Screen is the size of the display in the preview, in pixels.
+	Screen Size
+	// Bezel is the width of the frame around the screen.
+	Bezel int
+	// Radius rounds the corners of the frame.
+	Radius int
+}
+
+// Devices are the devices Preview can draw, by name.
+var Devices = map[string]Device{
+	"iphone":    {Kind: Phone, Screen: Size{Width: 390, Height: 844}, Bezel: 14, Radius: 60},
+	"macbook":   {Kind: Laptop, Screen: Size{Width: 1440, Height: 900}, Bezel: 24, Radius: 24},
+	"ultrawide": {Kind: Monitor, Screen: Size{Width: 1720, Height: 720}, Bezel: 12, Radius: 8},
+}
+
+var (
+	frameColor = color.NRGBA{R: 0x1d, G: 0x1d, B: 0x1f, A: 0xff}
+	standColor = color.NRGBA{R: 0xa1, G: 0xa1, B: 0xa6, A: 0xff}
+	barColor   = color.NRGBA{R: 0xff, G: 0xff, B: 0xff, A: 0x60}
+	iconColor  = color.NRGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xa0}
+)
+
+// PreviewName returns the name the preview of f on a device is cached
+// under. It includes f's checksum so replacing a wallpaper invalidates its
+// previews.
+func PreviewName(f *File, device string) string {
+	base := strings.TrimSuffix(f.Name, filepath.Ext(f.Name))
+	return fmt.Sprintf("%s-%08x-preview-%s.png", base, f.CRC32C, device)
+}
+
+// previewLayout returns the size of d's mockup and where its screen is.
+func previewLayout(d Device) (canvas, frame, screen image.Rectangle) {
+	sw, sh := d.Screen.Width, d.Screen.Height
+	fw, fh := sw+2*d.Bezel, sh+2*d.Bezel
+
+	// Laptops sit on a wider base, and monitors on a stand.
+	overhang, below := 0, 0
+	switch d.Kind {
+	case Laptop:
+		overhang, below = fw/12, d.Bezel
+	case Monitor:
+		below = fh / 5
+	}
+
+	canvas = image.Rect(0, 0, fw+2*overhang, fh+below)
+	frame = image.Rect(overhang, 0, overhang+fw, fh)
+	screen = image.Rect(frame.Min.X+d.Bezel, d.Bezel, frame.Min.X+d.Bezel+sw, d.Bezel+sh)
+	return canvas, frame, screen
+}
+
+// Preview decodes an image and draws its center, cropped to fill the
+// device's screen, inside a mockup of the device: a laptop or monitor with
+// a menu bar and dock, or a phone with a status bar cutout and home
+// indicator. The result is a PNG with a transparent background.
+func Preview(content []byte, d Device) ([]byte, error) {
+	src, _, err := image.Decode(bytes.NewReader(content))
+	if err != nil {
+		return nil, fmt.Errorf("could not decode image: %w", err)
+	}
+
+	return drawPreview(src, d)
+}
+
+// PreviewFrame draws d's mockup as Preview does, but with the screen left
+// transparent, for imgix to lay over a cropped wallpaper.
+func PreviewFrame(d Device) ([]byte, error) {
+	return drawPreview(nil, d)
+}
+
+// drawPreview draws src on d's mockup, or leaves the screen transparent if
+// src is nil.
+func drawPreview(src image.Image, d Device) ([]byte, error) {
+	canvas, frame, screen := previewLayout(d)
+	fw, fh := frame.Dx(), frame.Dy()
+	sw, sh := screen.Dx(), screen.Dy()
+	below := canvas.Dy() - fh
+
+	out := image.NewNRGBA(canvas)
+	fill(out, frame, d.Radius, frameColor)
+
+	mask := roundedRect{screen, max(d.Radius-d.Bezel, 0)}
+	if src == nil {
+		// Clear the screen, then fill its rounded off corners back in.
+		draw.Draw(out, screen, image.Transparent, image.Point{}, draw.Src)
+		draw.DrawMask(out, screen, image.NewUniform(frameColor), image.Point{}, outside{mask}, screen.Min, draw.Over)
+	} else {
+		b := src.Bounds()
+		cw, ch := b.Dx(), b.Dy()
+		if cw*sh > ch*sw {
+			cw = ch * sw / sh
+		} else {
+			ch = cw * sh / sw
+		}
+		cx, cy := b.Min.X+(b.Dx()-cw)/2, b.Min.Y+(b.Dy()-ch)/2
+		wallpaper := resample(src, image.Rect(cx, cy, cx+cw, cy+ch), sw, sh)
+		draw.DrawMask(out, screen, wallpaper, image.Point{}, mask, screen.Min, draw.Over)
+	}
+
+	switch d.Kind {
+	case Laptop, Monitor:
+		drawDesktop(out, screen)
+	case Phone:
+		drawPhone(out, screen)
+	}
+
+	switch d.Kind {
+	case Laptop:
+		fill(out, image.Rect(0, fh, out.Bounds().Dx(), fh+below), below/2, standColor)
+	case Monitor:
+		neck := fw / 12
+		mid := frame.Min.X + fw/2
+		fill(out, image.Rect(mid-neck/2, fh, mid+neck/2, fh+below-below/6), 0, standColor)
+		fill(out, image.Rect(mid-fw/6, fh+below-below/6, mid+fw/6, fh+below), below/12, standColor)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, out); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// PreviewFrameName returns the name frame, the PreviewFrame of a device, is
+// cached under. It includes the frame's checksum so changing how frames
+// are drawn invalidates them.
+func PreviewFrameName(device string, frame []byte) string {
+	return fmt.Sprintf("preview-frame-%s-%08x.png", device, GetFileCRC(frame))
+}
+
+// PreviewURL returns the URL of an imgix rendition of key on d's mockup:
+// key is cropped to the screen, padded out to the mockup with transparency
+// and overlaid with the frame stored in the variants as frameName. It
+// returns false if the store in ctx is not served through imgix, in which
+// case Preview should be used instead.
+func PreviewURL(ctx context.Context, key string, d Device, frameName string) (string, bool) {
+	host, ok := imgixHost(ctx)
+	if !ok {
+		return "", false
+	}
+
+	canvas, _, screen := previewLayout(d)
+	q := url.Values{}
+	q.Set("w", strconv.Itoa(screen.Dx()))
+	q.Set("h", strconv.Itoa(screen.Dy()))
+	q.Set("fit", "crop")
+	q.Set("crop", "entropy")
+	q.Set("pad-left", strconv.Itoa(screen.Min.X))
+	q.Set("pad-top", strconv.Itoa(screen.Min.Y))
+	q.Set("pad-right", strconv.Itoa(canvas.Max.X-screen.Max.X))
+	q.Set("pad-bottom", strconv.Itoa(canvas.Max.Y-screen.Max.Y))
+	q.Set("bg", "0000")
+	q.Set("mark", "/"+VariantsStore+"/"+frameName)
+	q.Set("mark-x", "0")
+	q.Set("mark-y", "0")
+	q.Set("mark-w", strconv.Itoa(canvas.Dx()))
+	q.Set("mark-h", strconv.Itoa(canvas.Dy()))
+	q.Set("fm", "png")
+	return fmt.Sprintf("https://%s/%s?%s", host, key, q.Encode()), true
+}
+
+// drawDesktop draws a menu bar across the top of screen and a dock along
+// the bottom.
+func drawDesktop(dst draw.Image, screen image.Rectangle) {
+	bar := screen.Dy() / 36
+	fill(dst, image.Rect(screen.Min.X, screen.Min.Y, screen.Max.X, screen.Min.Y+bar), 0, barColor)
+
+	const icons = 9
+	icon := screen.Dy() / 16
+	gap := icon / 4
+	width := icons*icon + (icons+1)*gap
+	dock := image.Rect(0, 0, width, icon+2*gap).Add(image.Pt(
+		screen.Min.X+(screen.Dx()-width)/2,
+		screen.Max.Y-icon-2*gap-gap,
+	))
+	fill(dst, dock, gap*2, barColor)
+	for i := range icons {
+		x := dock.Min.X + gap + i*(icon+gap)
+		fill(dst, image.Rect(x, dock.Min.Y+gap, x+icon, dock.Min.Y+gap+icon), icon/4, iconColor)
+	}
+}
+
+// drawPhone draws the camera cutout at the top of screen and the home
+// indicator at the bottom.
+func drawPhone(dst draw.Image, screen image.Rectangle) {
+	w, h := screen.Dx()*126/390, screen.Dy()*37/844
+	top := screen.Min.Y + screen.Dy()*11/844
+	mid := screen.Min.X + screen.Dx()/2
+	fill(dst, image.Rect(mid-w/2, top, mid+w/2, top+h), h/2, color.Black)
+
+	w, h = screen.Dx()*134/390, max(screen.Dy()*5/844, 2)
+	bottom := screen.Max.Y - screen.Dy()*8/844
+	fill(dst, image.Rect(mid-w/2, bottom-h, mid+w/2, bottom), h/2, iconColor)
+}
+
+// fill paints r with c, rounding its corners by radius.
+func fill(dst draw.Image, r image.Rectangle, radius int, c color.Color) {
+	draw.DrawMask(dst, r, image.NewUniform(c), image.Point{}, roundedRect{r, radius}, r.Min, draw.Over)
+}
+
+// roundedRect is a mask that is opaque inside a rectangle with rounded
+// corners.
+type roundedRect struct {
+	r      image.Rectangle
+	radius int
+}
+
+func (m roundedRect) ColorModel() color.Model { return color.AlphaModel }
+
+func (m roundedRect) Bounds() image.Rectangle { return m.r }
+
+func (m roundedRect) At(x, y int) color.Color {
+	if !image.Pt(x, y).In(m.r) {
+		return color.Transparent
+	}
+
+	rad := min(m.radius, m.r.Dx()/2, m.r.Dy()/2)
+	// Distance from the center of the nearest corner's circle, if the point
+	// is in a corner.
+	dx, dy := 0, 0
+	if x < m.r.Min.X+rad {
+		dx = m.r.Min.X + rad - x
+	} else if x >= m.r.Max.X-rad {
+		dx = x - (m.r.Max.X - rad - 1)
+	}
+	if y < m.r.Min.Y+rad {
+		dy = m.r.Min.Y + rad - y
+	} else if y >= m.r.Max.Y-rad {
+		dy = y - (m.r.Max.Y - rad - 1)
+	}
+	if dx > 0 && dy > 0 && dx*dx+dy*dy > rad*rad {
+		return color.Transparent
+	}
+	return color.Opaque
+}
+
+// outside is a mask that is opaque where the mask it wraps is not.
+type outside struct {
+	image.Image
+}
+
+func (m outside) At(x, y int) color.Color {
+	if _, _, _, a := m.Image.At(x, y).RGBA(); a == 0 {
+		return color.Opaque
+	}
+	return color.Transparent
+}
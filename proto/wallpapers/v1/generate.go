@@ -0,0 +1,6 @@
+// Package wallpapersv1 holds the types and gRPC stubs generated from
+// wallpapers.proto, the wallpapers.v1 service cmd/server serves next to its
+// HTTP API.
+package wallpapersv1
+
+//go:generate buf generate --template ../../buf.gen.yaml -o ../.. ../..
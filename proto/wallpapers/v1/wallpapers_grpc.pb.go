@@ -0,0 +1,299 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: wallpapers/v1/wallpapers.proto
+
+package wallpapersv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	WallpapersService_List_FullMethodName   = "/wallpapers.v1.WallpapersService/List"
+	WallpapersService_Get_FullMethodName    = "/wallpapers.v1.WallpapersService/Get"
+	WallpapersService_Search_FullMethodName = "/wallpapers.v1.WallpapersService/Search"
+	WallpapersService_Upload_FullMethodName = "/wallpapers.v1.WallpapersService/Upload"
+	WallpapersService_Delete_FullMethodName = "/wallpapers.v1.WallpapersService/Delete"
+)
+
+// WallpapersServiceClient is the client API for WallpapersService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// WallpapersService is the gRPC counterpart of the /v1 JSON API. Upload and
+// Delete need the server's API token as a bearer token in the
+// authorization metadata.
+type WallpapersServiceClient interface {
+	// List streams the images of a collection.
+	List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Image], error)
+	// Get returns one image.
+	Get(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*Image, error)
+	// Search streams the images whose name or author contain every word of
+	// the query.
+	Search(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Image], error)
+	// Upload adds a new image to the default collection, as the uploader
+	// does. Content already in the collection is refused, and a different
+	// picture with the same name has a hash added to its name. The first
+	// message holds the upload's info and the rest its content.
+	Upload(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[UploadRequest, Image], error)
+	// Delete removes an image from the default collection.
+	Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*DeleteResponse, error)
+}
+
+type wallpapersServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewWallpapersServiceClient(cc grpc.ClientConnInterface) WallpapersServiceClient {
+	return &wallpapersServiceClient{cc}
+}
+
+func (c *wallpapersServiceClient) List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Image], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &WallpapersService_ServiceDesc.Streams[0], WallpapersService_List_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ListRequest, Image]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type WallpapersService_ListClient = grpc.ServerStreamingClient[Image]
+
+func (c *wallpapersServiceClient) Get(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*Image, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Image)
+	err := c.cc.Invoke(ctx, WallpapersService_Get_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *wallpapersServiceClient) Search(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Image], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &WallpapersService_ServiceDesc.Streams[1], WallpapersService_Search_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SearchRequest, Image]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type WallpapersService_SearchClient = grpc.ServerStreamingClient[Image]
+
+func (c *wallpapersServiceClient) Upload(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[UploadRequest, Image], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &WallpapersService_ServiceDesc.Streams[2], WallpapersService_Upload_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[UploadRequest, Image]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type WallpapersService_UploadClient = grpc.ClientStreamingClient[UploadRequest, Image]
+
+func (c *wallpapersServiceClient) Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*DeleteResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteResponse)
+	err := c.cc.Invoke(ctx, WallpapersService_Delete_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// WallpapersServiceServer is the server API for WallpapersService service.
+// All implementations must embed UnimplementedWallpapersServiceServer
+// for forward compatibility.
+//
+// WallpapersService is the gRPC counterpart of the /v1 JSON API. Upload and
+// Delete need the server's API token as a bearer token in the
+// authorization metadata.
+type WallpapersServiceServer interface {
+	// List streams the images of a collection.
+	List(*ListRequest, grpc.ServerStreamingServer[Image]) error
+	// Get returns one image.
+	Get(context.Context, *GetRequest) (*Image, error)
+	// Search streams the images whose name or author contain every word of
+	// the query.
+	Search(*SearchRequest, grpc.ServerStreamingServer[Image]) error
+	// Upload adds a new image to the default collection, as the uploader
+	// does. Content already in the collection is refused, and a different
+	// picture with the same name has a hash added to its name. The first
+	// message holds the upload's info and the rest its content.
+	Upload(grpc.ClientStreamingServer[UploadRequest, Image]) error
+	// Delete removes an image from the default collection.
+	Delete(context.Context, *DeleteRequest) (*DeleteResponse, error)
+	mustEmbedUnimplementedWallpapersServiceServer()
+}
+
+// UnimplementedWallpapersServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedWallpapersServiceServer struct{}
+
+func (UnimplementedWallpapersServiceServer) List(*ListRequest, grpc.ServerStreamingServer[Image]) error {
+	return status.Errorf(codes.Unimplemented, "method List not implemented")
+}
+func (UnimplementedWallpapersServiceServer) Get(context.Context, *GetRequest) (*Image, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Get not implemented")
+}
+func (UnimplementedWallpapersServiceServer) Search(*SearchRequest, grpc.ServerStreamingServer[Image]) error {
+	return status.Errorf(codes.Unimplemented, "method Search not implemented")
+}
+func (UnimplementedWallpapersServiceServer) Upload(grpc.ClientStreamingServer[UploadRequest, Image]) error {
+	return status.Errorf(codes.Unimplemented, "method Upload not implemented")
+}
+func (UnimplementedWallpapersServiceServer) Delete(context.Context, *DeleteRequest) (*DeleteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Delete not implemented")
+}
+func (UnimplementedWallpapersServiceServer) mustEmbedUnimplementedWallpapersServiceServer() {}
+func (UnimplementedWallpapersServiceServer) testEmbeddedByValue()                           {}
+
+// UnsafeWallpapersServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to WallpapersServiceServer will
+// result in compilation errors.
+type UnsafeWallpapersServiceServer interface {
+	mustEmbedUnimplementedWallpapersServiceServer()
+}
+
+func RegisterWallpapersServiceServer(s grpc.ServiceRegistrar, srv WallpapersServiceServer) {
+	// If the following call pancis, it indicates UnimplementedWallpapersServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&WallpapersService_ServiceDesc, srv)
+}
+
+func _WallpapersService_List_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(WallpapersServiceServer).List(m, &grpc.GenericServerStream[ListRequest, Image]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type WallpapersService_ListServer = grpc.ServerStreamingServer[Image]
+
+func _WallpapersService_Get_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WallpapersServiceServer).Get(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WallpapersService_Get_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WallpapersServiceServer).Get(ctx, req.(*GetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WallpapersService_Search_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SearchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(WallpapersServiceServer).Search(m, &grpc.GenericServerStream[SearchRequest, Image]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type WallpapersService_SearchServer = grpc.ServerStreamingServer[Image]
+
+func _WallpapersService_Upload_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(WallpapersServiceServer).Upload(&grpc.GenericServerStream[UploadRequest, Image]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type WallpapersService_UploadServer = grpc.ClientStreamingServer[UploadRequest, Image]
+
+func _WallpapersService_Delete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WallpapersServiceServer).Delete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WallpapersService_Delete_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WallpapersServiceServer).Delete(ctx, req.(*DeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// WallpapersService_ServiceDesc is the grpc.ServiceDesc for WallpapersService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var WallpapersService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "wallpapers.v1.WallpapersService",
+	HandlerType: (*WallpapersServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Get",
+			Handler:    _WallpapersService_Get_Handler,
+		},
+		{
+			MethodName: "Delete",
+			Handler:    _WallpapersService_Delete_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "List",
+			Handler:       _WallpapersService_List_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Search",
+			Handler:       _WallpapersService_Search_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Upload",
+			Handler:       _WallpapersService_Upload_Handler,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "wallpapers/v1/wallpapers.proto",
+}
@@ -0,0 +1,128 @@
+package wallpapers
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"image/png"
+	"strconv"
+)
+
+// Object metadata keys recording the file a recompressed upload was made from.
+const (
+	MetadataOriginalSize   = "original_size"
+	MetadataOriginalCRC32C = "original_crc32c"
+	MetadataOriginalMD5    = "original_md5"
+)
+
+// pngSignature starts every PNG file.
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+// colorChunks are PNG chunks that change how pixels are displayed. Go's
+// decoder ignores them, so re-encoding an image that has one could change
+// how it looks.
+var colorChunks = map[string]bool{
+	"iCCP": true,
+	"gAMA": true,
+	"cHRM": true,
+}
+
+// RecompressPNG losslessly recompresses a PNG at the best compression level.
+// It returns nil if content is not a PNG, carries a color profile that
+// re-encoding would drop, or would not get any smaller. Other formats are
+// uploaded as they are.
+func RecompressPNG(content []byte) ([]byte, error) {
+	if !bytes.HasPrefix(content, pngSignature) {
+		return nil, nil
+	}
+
+	chunks, err := pngChunks(content)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range chunks {
+		if colorChunks[c] {
+			return nil, nil
+		}
+	}
+
+	img, err := png.Decode(bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	enc := png.Encoder{CompressionLevel: png.BestCompression}
+	if err := enc.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+
+	if buf.Len() >= len(content) {
+		return nil, nil
+	}
+
+	return buf.Bytes(), nil
+}
+
+// pngChunks returns the type of every chunk in a PNG.
+func pngChunks(content []byte) ([]string, error) {
+	var types []string
+	rest := content[len(pngSignature):]
+	for len(rest) >= 12 {
+		n := binary.BigEndian.Uint32(rest[:4])
+		if uint64(n)+12 > uint64(len(rest)) {
+			return nil, errors.New("truncated png chunk")
+		}
+
+		types = append(types, string(rest[4:8]))
+		rest = rest[12+n:]
+	}
+
+	return types, nil
+}
+
+// WithOriginal records the size and checksum of the file a recompressed upload
+// was made from, so later syncs can compare against the original.
+func WithOriginal(original []byte) UploadOption {
+	sums := GetChecksums(original)
+	return WithMetadata(map[string]string{
+		MetadataOriginalSize:   strconv.Itoa(len(original)),
+		MetadataOriginalCRC32C: strconv.FormatUint(uint64(sums.CRC32C), 10),
+		MetadataOriginalMD5:    hex.EncodeToString(sums.MD5),
+	})
+}
+
+// OriginalCRC32C returns the checksum of the file f was made from: the
+// recorded original if f was recompressed, otherwise its own.
+func (f *File) OriginalCRC32C() uint32 {
+	if v, err := strconv.ParseUint(f.Metadata[MetadataOriginalCRC32C], 10, 32); err == nil {
+		return uint32(v)
+	}
+	return f.CRC32C
+}
+
+// OriginalMD5 returns the MD5 of the file f was made from, or nil if it is
+// not known: recompressed uploads made before it was recorded, and composite
+// objects, have none.
+func (f *File) OriginalMD5() []byte {
+	if v, ok := f.Metadata[MetadataOriginalMD5]; ok {
+		sum, err := hex.DecodeString(v)
+		if err != nil {
+			return nil
+		}
+		return sum
+	}
+	if _, ok := f.Metadata[MetadataOriginalCRC32C]; ok {
+		return nil
+	}
+	return f.MD5
+}
+
+// OriginalSize returns the size of the file f was made from.
+func (f *File) OriginalSize() int64 {
+	if v, err := strconv.ParseInt(f.Metadata[MetadataOriginalSize], 10, 64); err == nil {
+		return v
+	}
+	return f.Size
+}
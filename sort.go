@@ -0,0 +1,48 @@
+package wallpapers
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// SortKeys are the fields SortFiles accepts.
+var SortKeys = []string{"added", "created", "updated", "name", "size"}
+
+// SortFiles sorts files in place by key, newest, largest or last first if
+// desc is set. Ties are broken by name so the order is stable across calls.
+func SortFiles(files []*File, key string, desc bool) error {
+	var compare func(a, b *File) int
+	switch key {
+	case "added":
+		compare = byTime((*File).Added)
+	case "created":
+		compare = byTime(func(f *File) time.Time { return f.Created })
+	case "updated":
+		compare = byTime(func(f *File) time.Time { return f.Updated })
+	case "name":
+		compare = func(a, b *File) int { return 0 }
+	case "size":
+		compare = func(a, b *File) int { return cmp.Compare(a.Size, b.Size) }
+	default:
+		return fmt.Errorf("unknown sort %q, must be one of %s", key, strings.Join(SortKeys, ", "))
+	}
+
+	slices.SortStableFunc(files, func(a, b *File) int {
+		c := cmp.Or(compare(a, b), strings.Compare(a.Name, b.Name))
+		if desc {
+			return -c
+		}
+		return c
+	})
+
+	return nil
+}
+
+func byTime(t func(*File) time.Time) func(a, b *File) int {
+	return func(a, b *File) int {
+		return t(a).Compare(t(b))
+	}
+}
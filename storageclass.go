@@ -0,0 +1,109 @@
+package wallpapers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"cloud.google.com/go/storage"
+)
+
+// GCS storage classes, from most to least expensive to store.
+const (
+	StorageStandard = "STANDARD"
+	StorageNearline = "NEARLINE"
+	StorageColdline = "COLDLINE"
+	StorageArchive  = "ARCHIVE"
+)
+
+// StorageClasses are the storage classes a file can be moved to.
+var StorageClasses = []string{StorageStandard, StorageNearline, StorageColdline, StorageArchive}
+
+// ErrNoStorageClasses is returned for stores without storage classes or
+// lifecycle rules, such as the local and memory stores.
+var ErrNoStorageClasses = errors.New("store does not have storage classes")
+
+// StorageClassStore is implemented by stores that can move files between
+// storage classes and manage the bucket's lifecycle rules.
+type StorageClassStore interface {
+	SetStorageClass(ctx context.Context, name, class string) error
+	Lifecycle(ctx context.Context) (*storage.Lifecycle, error)
+	SetLifecycle(ctx context.Context, l *storage.Lifecycle) error
+}
+
+func storageClassStore(ctx context.Context) (StorageClassStore, error) {
+	s, ok := StoreFor(ctx).(StorageClassStore)
+	if !ok {
+		return nil, ErrNoStorageClasses
+	}
+	return s, nil
+}
+
+// SetStorageClass moves a file to another storage class. The content is
+// rewritten in place, so it keeps its name and metadata.
+func SetStorageClass(ctx context.Context, filename, class string) error {
+	if !slices.Contains(StorageClasses, class) {
+		return fmt.Errorf("unknown storage class %q", class)
+	}
+
+	s, err := storageClassStore(ctx)
+	if err != nil {
+		return err
+	}
+
+	before, err := GetFile(ctx, filename)
+	if err != nil {
+		return err
+	}
+	if before.StorageClass == class {
+		return nil
+	}
+
+	if err := s.SetStorageClass(ctx, filename, class); err != nil {
+		return err
+	}
+
+	recordAudit(ctx, AuditEntry{
+		Action: AuditUpdate,
+		Name:   filename,
+		Before: map[string]string{"storage_class": before.StorageClass},
+		After:  map[string]string{"storage_class": class},
+	})
+	return nil
+}
+
+// Lifecycle returns the lifecycle rules of the bucket behind ctx's store.
+func Lifecycle(ctx context.Context) (*storage.Lifecycle, error) {
+	s, err := storageClassStore(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.Lifecycle(ctx)
+}
+
+// SetLifecycle replaces the lifecycle rules of the bucket behind ctx's
+// store.
+func SetLifecycle(ctx context.Context, l *storage.Lifecycle) error {
+	s, err := storageClassStore(ctx)
+	if err != nil {
+		return err
+	}
+	return s.SetLifecycle(ctx, l)
+}
+
+// ArchiveRule returns a lifecycle rule that moves standard storage files
+// to class days after they were added. Only originals have a custom time,
+// so renditions, audit entries and quarantined files stay where they are.
+func ArchiveRule(class string, days int64) storage.LifecycleRule {
+	return storage.LifecycleRule{
+		Action: storage.LifecycleAction{
+			Type:         storage.SetStorageClassAction,
+			StorageClass: class,
+		},
+		Condition: storage.LifecycleCondition{
+			DaysSinceCustomTime:   days,
+			MatchesStorageClasses: []string{StorageStandard},
+		},
+	}
+}
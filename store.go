@@ -0,0 +1,151 @@
+package wallpapers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"sync"
+	"time"
+)
+
+// Store is where wallpapers are kept. Lookups of missing files return an
+// error wrapping storage.ErrObjectNotExist, whatever the backend.
+type Store interface {
+	// Attrs returns the attributes of a single file.
+	Attrs(ctx context.Context, name string) (*File, error)
+	// NewReader opens a file for reading. The caller must close it.
+	NewReader(ctx context.Context, name string) (io.ReadCloser, error)
+	// NewRangeReader opens length bytes of a file starting at offset for
+	// reading. A negative length reads to the end of the file. The caller
+	// must close it.
+	NewRangeReader(ctx context.Context, name string, offset, length int64) (io.ReadCloser, error)
+	// NewWriter creates or replaces a file. The content is checked against
+	// sums, and the file is only visible once the writer is closed.
+	NewWriter(ctx context.Context, name string, sums Checksums, u ObjectUpdate) (io.WriteCloser, error)
+	// Update changes the attributes of an existing file.
+	Update(ctx context.Context, name string, u ObjectUpdate) error
+	// Delete removes a file.
+	Delete(ctx context.Context, name string) error
+	// Rename moves a file to a new name without transferring its content
+	// through the caller, keeping its attributes.
+	Rename(ctx context.Context, from, to string) error
+	// List iterates over every file in name order.
+	List(ctx context.Context) iter.Seq2[*File, error]
+	// Page returns up to size files in name order starting at pageToken,
+	// and the token of the next page, which is empty after the last page.
+	// A size below 1 returns an error wrapping ErrPageSize.
+	Page(ctx context.Context, pageToken string, size int) ([]*File, string, error)
+	// Check verifies that the store is reachable.
+	Check(ctx context.Context) error
+}
+
+// writtenFiler is implemented by the writers of stores that know the
+// attributes of the file they wrote once they are closed, which saves
+// reading them again.
+type writtenFiler interface {
+	Written() *File
+}
+
+// URLStore is implemented by stores that serve their own images instead of
+// going through imgix.
+type URLStore interface {
+	URL(key string) string
+}
+
+// SubStore is implemented by stores that can keep other kinds of files,
+// such as derived renditions or the audit log, out of the listing of the
+// wallpapers themselves.
+type SubStore interface {
+	Sub(name string) (Store, error)
+}
+
+// Names of the sub stores.
+const (
+	VariantsStore   = "variants"
+	AuditStore      = "audit"
+	QuarantineStore = "quarantine"
+	UploadsStore    = "uploads"
+)
+
+// ErrNoSubStore is returned for stores that cannot keep sub stores.
+var ErrNoSubStore = errors.New("store does not keep sub stores")
+
+// ErrPageSize is returned when a page is asked for with a size below 1.
+var ErrPageSize = errors.New("page size must be at least 1")
+
+// checkPageSize returns an error wrapping ErrPageSize if size is below 1.
+func checkPageSize(size int) error {
+	if size < 1 {
+		return fmt.Errorf("%w, got %d", ErrPageSize, size)
+	}
+	return nil
+}
+
+// subStore returns the named sub store of ctx's store.
+func subStore(ctx context.Context, name string) (Store, error) {
+	ss, ok := StoreFor(ctx).(SubStore)
+	if !ok {
+		return nil, ErrNoSubStore
+	}
+	return ss.Sub(name)
+}
+
+// PendingUploads returns the store that keeps uploads to ctx's store that
+// are still being received.
+func PendingUploads(ctx context.Context) (Store, error) {
+	return subStore(ctx, UploadsStore)
+}
+
+// ObjectUpdate holds the mutable attributes of a file. Zero fields are left
+// unchanged. A metadata key with an empty value is removed.
+type ObjectUpdate struct {
+	ContentType string
+	CustomTime  time.Time
+	Metadata    map[string]string
+}
+
+// Environment variables read by StoreFromEnv.
+const (
+	BackendEnv  = "WALLPAPERS_BACKEND"
+	LocalDirEnv = "WALLPAPERS_DIR"
+)
+
+// DefaultLocalDir is where the local backend keeps files if LocalDirEnv is
+// not set.
+const DefaultLocalDir = ".wallpapers"
+
+var (
+	storeMu      sync.RWMutex
+	defaultStore = NewGCSStore(DefaultCollection)
+)
+
+// DefaultStore returns the store used by the package level functions when
+// their context has none. It is the GCS bucket unless SetStore has been
+// called.
+func DefaultStore() Store {
+	storeMu.RLock()
+	defer storeMu.RUnlock()
+	return defaultStore
+}
+
+// SetStore replaces the store used by the package level functions.
+func SetStore(s Store) {
+	storeMu.Lock()
+	defer storeMu.Unlock()
+	defaultStore = s
+}
+
+// StoreFromEnv returns the store of the default collection, using the
+// backend selected by WALLPAPERS_BACKEND: "gcs" (the default) or "local",
+// which keeps files in WALLPAPERS_DIR so the tools can run without cloud
+// credentials.
+func StoreFromEnv() (Store, error) {
+	collections, err := CollectionsFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	return OpenCollection(collections[0], true)
+}
@@ -5,4 +5,4 @@ git add go.mod go.sum
 git ci -m 'update go deps'
 git push
 
-go run ./cmd/uploader
+go run ./cmd/uploader -delete
@@ -0,0 +1,105 @@
+package wallpapers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	// Register decoders so we can validate downloaded images.
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+)
+
+// MaxURLSize is the largest file UploadFromURL will download.
+const MaxURLSize = 200 << 20
+
+// Download is an image fetched over HTTP.
+type Download struct {
+	Name    string
+	Content []byte
+	Format  string
+	Width   int
+	Height  int
+}
+
+// FetchURL downloads an image over HTTP and checks that it decodes as an
+// image. The returned Name is a formatted version of the file name in the URL.
+func FetchURL(ctx context.Context, rawURL string) (*Download, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid url: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("unsupported scheme %q", u.Scheme)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("could not fetch: %s", resp.Status)
+	}
+
+	content, err := io.ReadAll(io.LimitReader(resp.Body, MaxURLSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed read: %w", err)
+	}
+	if len(content) > MaxURLSize {
+		return nil, fmt.Errorf("file is larger than %d bytes", MaxURLSize)
+	}
+
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(content))
+	if err != nil {
+		return nil, fmt.Errorf("not an image: %w", err)
+	}
+
+	return &Download{
+		Name:    urlFileName(u, format, content),
+		Content: content,
+		Format:  format,
+		Width:   cfg.Width,
+		Height:  cfg.Height,
+	}, nil
+}
+
+// UploadFromURL downloads an image over HTTP, checks that it decodes as an
+// image, and uploads it under a formatted version of its name. It returns the
+// name of the uploaded file. If the collection already has the same content,
+// nothing is uploaded and a *DuplicateError is returned.
+func UploadFromURL(ctx context.Context, rawURL string, opts ...UploadOption) (string, error) {
+	d, err := FetchURL(ctx, rawURL)
+	if err != nil {
+		return "", err
+	}
+
+	return UploadNew(ctx, d.Name, d.Content, opts...)
+}
+
+// urlFileName picks a file name for a downloaded image, using the decoded
+// format as the extension and falling back to the CRC if the URL path has no
+// usable name.
+func urlFileName(u *url.URL, format string, content []byte) string {
+	base := path.Base(u.Path)
+	base = strings.TrimSuffix(base, path.Ext(base))
+	base = NameRegex.ReplaceAllString(strings.ToLower(base), "")
+	if base == "" {
+		base = fmt.Sprintf("%08x", GetFileCRC(content))
+	}
+
+	return FormatName(base + "." + format)
+}
@@ -0,0 +1,189 @@
+package wallpapers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"iter"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"cloud.google.com/go/storage"
+
+	// Register decoders for the formats other than GIF, JPEG and PNG that
+	// can be checked.
+	_ "golang.org/x/image/bmp"
+	_ "golang.org/x/image/tiff"
+	_ "golang.org/x/image/webp"
+)
+
+// MetadataQuarantineReason records why a quarantined upload was rejected.
+const MetadataQuarantineReason = "quarantine_reason"
+
+// Validation is what an upload must satisfy to join the collection.
+// Zero fields are not checked.
+type Validation struct {
+	// MaxSize is the largest file accepted, in bytes.
+	MaxSize int64
+	// MinWidth and MinHeight are the smallest image accepted, in pixels.
+	// Videos are not checked.
+	MinWidth  int
+	MinHeight int
+}
+
+// DefaultValidation is applied by UploadFile unless WithValidation says
+// otherwise. It matches the size cap on downloads and asks for at least
+// 720p images.
+var DefaultValidation = Validation{
+	MaxSize:   MaxURLSize,
+	MinWidth:  1280,
+	MinHeight: 720,
+}
+
+// RejectedError is returned when an upload fails validation. The content
+// was put in quarantine instead.
+type RejectedError struct {
+	Name   string
+	Reason string
+}
+
+func (e *RejectedError) Error() string {
+	return fmt.Sprintf("%q was quarantined: %s", e.Name, e.Reason)
+}
+
+// WithValidation replaces DefaultValidation for an upload. The zero
+// Validation checks nothing beyond images decoding.
+func WithValidation(v Validation) UploadOption {
+	return func(o *uploadOptions) {
+		o.validation = &v
+	}
+}
+
+// WithoutValidation uploads the content as is, for files released from
+// quarantine by hand.
+func WithoutValidation() UploadOption {
+	return func(o *uploadOptions) {
+		o.skipValidation = true
+	}
+}
+
+// decodableExts are the image extensions whose content must decode.
+var decodableExts = []string{".bmp", ".gif", ".jpeg", ".jpg", ".png", ".tif", ".tiff", ".webp"}
+
+// undecodableExts are the image extensions we accept without a decoder, so
+// only their size is checked.
+var undecodableExts = []string{".avif", ".heic"}
+
+// Validate reports why content should not be uploaded as filename, or ""
+// if it may be. Files must fit the size cap and be a video or an image we
+// know, and images that can be decoded must decode and be large enough.
+func (v Validation) Validate(filename string, content []byte) string {
+	if v.MaxSize > 0 && int64(len(content)) > v.MaxSize {
+		return fmt.Sprintf("%d bytes is over the %d byte limit", len(content), v.MaxSize)
+	}
+
+	ext := strings.ToLower(filepath.Ext(filename))
+	switch {
+	case MediaType(filename) == TypeVideo, slices.Contains(undecodableExts, ext):
+		return ""
+	case !slices.Contains(decodableExts, ext):
+		return fmt.Sprintf("%q is not an image or video extension", ext)
+	}
+
+	// Decoding the whole image also catches truncated files, which have a
+	// valid header.
+	img, _, err := image.Decode(bytes.NewReader(content))
+	if err != nil {
+		return fmt.Sprintf("does not decode as an image: %v", err)
+	}
+	if b := img.Bounds(); b.Dx() < v.MinWidth || b.Dy() < v.MinHeight {
+		return fmt.Sprintf("%dx%d is smaller than %dx%d", b.Dx(), b.Dy(), v.MinWidth, v.MinHeight)
+	}
+
+	return ""
+}
+
+// Quarantined returns the store that keeps rejected uploads for ctx's
+// store.
+func Quarantined(ctx context.Context) (Store, error) {
+	return subStore(ctx, QuarantineStore)
+}
+
+// quarantine stores rejected content with the reason it was rejected. It
+// is not written again if the same content is already there.
+func quarantine(ctx context.Context, filename string, content []byte, reason string) error {
+	s, err := Quarantined(ctx)
+	if errors.Is(err, ErrNoSubStore) {
+		LoggerFor(ctx).Debugw("store has no quarantine, dropping rejected file", "name", filename, "reason", reason)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	existing, err := s.Attrs(ctx, filename)
+	if err == nil && existing.CRC32C == GetFileCRC(content) && existing.Metadata[MetadataQuarantineReason] == reason {
+		return nil
+	}
+	if err != nil && !errors.Is(err, storage.ErrObjectNotExist) {
+		return err
+	}
+
+	o := &uploadOptions{metadata: map[string]string{MetadataQuarantineReason: reason}}
+	LoggerFor(ctx).Infow("quarantining file", "name", filename, "reason", reason)
+	if _, err := writeFile(ctx, s, filename, content, o); err != nil {
+		return fmt.Errorf("could not quarantine %q: %w", filename, err)
+	}
+
+	recordAudit(ctx, AuditEntry{
+		Action: AuditQuarantine,
+		Name:   filename,
+		After:  map[string]string{MetadataQuarantineReason: reason},
+	})
+	return nil
+}
+
+// QuarantinedFiles iterates over the rejected uploads of ctx's store. Each
+// file's Metadata holds the reason under MetadataQuarantineReason.
+func QuarantinedFiles(ctx context.Context) iter.Seq2[*File, error] {
+	s, err := Quarantined(ctx)
+	if err != nil {
+		return func(yield func(*File, error) bool) {
+			yield(nil, err)
+		}
+	}
+	return s.List(ctx)
+}
+
+// ReleaseQuarantined moves a quarantined file into the collection without
+// validating it.
+func ReleaseQuarantined(ctx context.Context, filename string) error {
+	s, err := Quarantined(ctx)
+	if err != nil {
+		return err
+	}
+
+	content, err := DownloadFile(ContextWithStore(ctx, s), filename)
+	if err != nil {
+		return err
+	}
+
+	if err := UploadFile(ctx, filename, content, WithoutValidation()); err != nil {
+		return err
+	}
+
+	return s.Delete(ctx, filename)
+}
+
+// DeleteQuarantined discards a quarantined file.
+func DeleteQuarantined(ctx context.Context, filename string) error {
+	s, err := Quarantined(ctx)
+	if err != nil {
+		return err
+	}
+
+	return s.Delete(ctx, filename)
+}
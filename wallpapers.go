@@ -1,19 +1,19 @@
 package wallpapers
 
 import (
-	"cmp"
 	"context"
 	"errors"
 	"fmt"
 	"hash/crc32"
+	"io"
+	"iter"
 	"path/filepath"
 	"regexp"
-	"slices"
 	"strings"
 	"time"
 
 	"cloud.google.com/go/storage"
-	"google.golang.org/api/iterator"
+	"golang.org/x/time/rate"
 )
 
 const (
@@ -39,12 +39,7 @@ func FormatName(in string) string {
 }
 
 func GetGoogleCRC(ctx context.Context, filename string) (uint32, error) {
-	client, err := storage.NewClient(ctx)
-	if err != nil {
-		return 0, err
-	}
-
-	attr, err := client.Bucket(Bucket).Object(filename).Attrs(ctx)
+	f, err := StoreFor(ctx).Attrs(ctx, filename)
 	if err != nil {
 		if !errors.Is(err, storage.ErrObjectNotExist) {
 			return 0, fmt.Errorf("could not get attrs: %w", err)
@@ -55,7 +50,7 @@ func GetGoogleCRC(ctx context.Context, filename string) (uint32, error) {
 		}
 	}
 
-	return attr.CRC32C, nil
+	return f.CRC32C, nil
 }
 
 func GetFileCRC(content []byte) uint32 {
@@ -63,102 +58,467 @@ func GetFileCRC(content []byte) uint32 {
 }
 
 func DeleteFile(ctx context.Context, filename string) error {
-	client, err := storage.NewClient(ctx)
+	before, err := attrsIfExists(ctx, filename)
 	if err != nil {
 		return err
 	}
 
-	return client.Bucket(Bucket).Object(filename).Delete(ctx)
+	if err := StoreFor(ctx).Delete(ctx, filename); err != nil {
+		return err
+	}
+
+	recordAudit(ctx, AuditEntry{Action: AuditDelete, Name: filename, Before: auditAttrs(before)})
+	return nil
+}
+
+// RenameFile moves a file to a new name. The content is copied inside the
+// store rather than downloaded and uploaded again.
+func RenameFile(ctx context.Context, from, to string) error {
+	if err := StoreFor(ctx).Rename(ctx, from, to); err != nil {
+		return err
+	}
+
+	recordAudit(ctx, AuditEntry{
+		Action: AuditRename,
+		Name:   to,
+		Before: map[string]string{"name": from},
+		After:  map[string]string{"name": to},
+	})
+	return nil
+}
+
+// OpenFile returns a reader for the content of a file in GoogleCloud. The
+// caller must close it.
+func OpenFile(ctx context.Context, filename string) (io.ReadCloser, error) {
+	return StoreFor(ctx).NewReader(ctx, filename)
+}
+
+// OpenRange returns a reader for length bytes of a file starting at offset.
+// A negative length reads to the end. The caller must close it.
+func OpenRange(ctx context.Context, filename string, offset, length int64) (io.ReadCloser, error) {
+	return StoreFor(ctx).NewRangeReader(ctx, filename, offset, length)
+}
+
+// DownloadFile returns the content of a file in GoogleCloud. The content is
+// verified against the file's stored size and checksums, and an error
+// wrapping ErrCorrupt is returned if they do not match.
+func DownloadFile(ctx context.Context, filename string) ([]byte, error) {
+	f, err := GetFile(ctx, filename)
+	if err != nil {
+		return nil, err
+	}
+
+	rc, err := OpenFile(ctx, filename)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	content, err := io.ReadAll(rc)
+	if err != nil {
+		return nil, fmt.Errorf("failed read: %w", err)
+	}
+
+	if err := Verify(f, content); err != nil {
+		return nil, err
+	}
+
+	return content, nil
+}
+
+// UploadOption configures an upload.
+type UploadOption func(*uploadOptions)
+
+type uploadOptions struct {
+	customTime     time.Time
+	index          map[uint32][]*File
+	previous       *File
+	previousKnown  bool
+	limiter        *rate.Limiter
+	metadata       map[string]string
+	validation     *Validation
+	skipValidation bool
+}
+
+// WithCustomTime sets the object's CustomTime, which we use to record when a
+// wallpaper was originally added to the collection.
+func WithCustomTime(t time.Time) UploadOption {
+	return func(o *uploadOptions) {
+		o.customTime = t
+	}
+}
+
+// WithMetadata adds custom metadata key/value pairs to the object.
+func WithMetadata(md map[string]string) UploadOption {
+	return func(o *uploadOptions) {
+		if o.metadata == nil {
+			o.metadata = map[string]string{}
+		}
+		for k, v := range md {
+			o.metadata[k] = v
+		}
+	}
+}
+
+// WithPrevious gives the attributes of the file being replaced, such as
+// from an earlier listing, so they are not read again for the audit log.
+// f is nil if there is no such file.
+func WithPrevious(f *File) UploadOption {
+	return func(o *uploadOptions) {
+		o.previous = f
+		o.previousKnown = true
+	}
+}
+
+// WithIndex makes UploadNew look for duplicates in idx, as built by
+// IndexByCRC or IndexFiles, instead of listing the collection. idx is not
+// updated with the new file.
+func WithIndex(idx map[uint32][]*File) UploadOption {
+	return func(o *uploadOptions) {
+		o.index = idx
+	}
+}
+
+// WithMaxBandwidth limits how many bytes per second are written to GCS.
+func WithMaxBandwidth(bytesPerSecond int) UploadOption {
+	return func(o *uploadOptions) {
+		if bytesPerSecond > 0 {
+			o.limiter = rate.NewLimiter(rate.Limit(bytesPerSecond), bytesPerSecond)
+		}
+	}
+}
+
+// WithRateLimiter limits bytes written to GCS using a shared limiter, so the
+// limit can span several uploads.
+func WithRateLimiter(l *rate.Limiter) UploadOption {
+	return func(o *uploadOptions) {
+		o.limiter = l
+	}
 }
 
 // UploadFile takes a file name and content and uploads it to GoogleCloud.
-func UploadFile(ctx context.Context, filename string, content []byte) error {
-	client, err := storage.NewClient(ctx)
+// Content that fails validation is quarantined instead, and a
+// *RejectedError is returned.
+func UploadFile(ctx context.Context, filename string, content []byte, opts ...UploadOption) error {
+	o := &uploadOptions{validation: &DefaultValidation}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if !o.skipValidation {
+		if reason := o.validation.Validate(filename, content); reason != "" {
+			if err := quarantine(ctx, filename, content, reason); err != nil {
+				return err
+			}
+			return &RejectedError{Name: filename, Reason: reason}
+		}
+	}
+
+	before := o.previous
+	if !o.previousKnown {
+		var err error
+		if before, err = attrsIfExists(ctx, filename); err != nil {
+			return err
+		}
+	}
+
+	after, err := writeFile(ctx, StoreFor(ctx), filename, content, o)
 	if err != nil {
 		return err
 	}
+	if after == nil {
+		if after, err = GetFile(ctx, filename); err != nil {
+			return err
+		}
+	}
 
-	wc := client.Bucket(Bucket).Object(filename).NewWriter(ctx)
-	wc.CRC32C = GetFileCRC(content)
-	wc.SendCRC32C = true
-	wc.ACL = []storage.ACLRule{{Entity: storage.AllUsers, Role: storage.RoleReader}}
+	b, a := changed(auditAttrs(before), auditAttrs(after))
+	recordAudit(ctx, AuditEntry{Action: AuditUpload, Name: filename, Before: b, After: a})
+	return nil
+}
 
-	if _, err := wc.Write(content); err != nil {
-		return fmt.Errorf("failed write: %w", err)
+// writeFile writes content to s. It returns the attributes of the new file
+// if the store reports them.
+func writeFile(ctx context.Context, s Store, filename string, content []byte, o *uploadOptions) (*File, error) {
+	u := ObjectUpdate{
+		ContentType: videoContentTypes[strings.ToLower(filepath.Ext(filename))],
+		CustomTime:  o.customTime,
+		Metadata:    o.metadata,
+	}
+	wc, err := s.NewWriter(ctx, filename, GetChecksums(content), u)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := writeLimited(ctx, wc, content, o.limiter); err != nil {
+		return nil, fmt.Errorf("failed write: %w", err)
 	}
 	if err := wc.Close(); err != nil {
-		return fmt.Errorf("failed close: %w", err)
+		return nil, fmt.Errorf("failed close: %w", err)
+	}
+
+	if w, ok := wc.(writtenFiler); ok {
+		return w.Written(), nil
+	}
+	return nil, nil
+}
+
+// writeLimited writes content in chunks no larger than the limiter's burst,
+// waiting on the limiter before each one. A nil limiter writes everything at
+// once.
+func writeLimited(ctx context.Context, w io.Writer, content []byte, l *rate.Limiter) error {
+	if l == nil {
+		_, err := w.Write(content)
+		return err
+	}
+
+	chunk := min(l.Burst(), 256*1024)
+	for len(content) > 0 {
+		n := min(chunk, len(content))
+		if err := l.WaitN(ctx, n); err != nil {
+			return err
+		}
+		if _, err := w.Write(content[:n]); err != nil {
+			return err
+		}
+		content = content[n:]
 	}
 
 	return nil
 }
 
+// SetCustomTime sets the CustomTime of an existing object. GCS only allows
+// CustomTime to move forward, so this should only be used on objects that do
+// not have one yet.
+func SetCustomTime(ctx context.Context, filename string, t time.Time) error {
+	return updateFile(ctx, filename, ObjectUpdate{CustomTime: t})
+}
+
+// storeURL returns the URL of key if the store on ctx serves its own
+// images.
+func storeURL(ctx context.Context, key string) (string, bool) {
+	if us, ok := StoreFor(ctx).(URLStore); ok {
+		return us.URL(key), true
+	}
+	return "", false
+}
+
+// imgixFor returns the imgix host and bucket of the store on ctx. Stores
+// that are not GCS buckets use the default collection's.
+func imgixFor(ctx context.Context) (host, bucket string) {
+	if s, ok := StoreFor(ctx).(*gcsStore); ok {
+		return s.imgixHost, s.bucket
+	}
+	return ImgixHost, Bucket
+}
+
+// PublicURL returns the URL of the original file in GCS.
+func PublicURL(ctx context.Context, key string) string {
+	_, bucket := imgixFor(ctx)
+	return publicURL(bucket, key)
+}
+
+func publicURL(bucket, key string) string {
+	return fmt.Sprintf("https://storage.googleapis.com/%s/%s", bucket, key)
+}
+
+// The size of the image at FullRezURL.
+const (
+	FullRezWidth  = 3840
+	FullRezHeight = 2160
+)
+
 // FullRezURL returns the URL a cropped version hosted by imgix.
-func FullRezURL(key string) string {
-	w := 3840
-	h := 2160
-	return fmt.Sprintf("https://icco-walls.imgix.net/%s?auto=compress&w=%d&h=%d&crop=entropy&fm=png", key, w, h)
+func FullRezURL(ctx context.Context, key string) string {
+	if u, ok := storeURL(ctx, key); ok {
+		return u
+	}
+
+	host, bucket := imgixFor(ctx)
+	return fullRezURL(host, bucket, key)
+}
+
+func fullRezURL(host, bucket, key string) string {
+	if host == "" || MediaType(key) == TypeVideo {
+		return publicURL(bucket, key)
+	}
+
+	return fmt.Sprintf("https://%s/%s?auto=compress&w=%d&h=%d&crop=entropy&fm=png", host, key, FullRezWidth, FullRezHeight)
 }
 
 // ThumbUrl returns the URL a small cropped version hosted by imgix.
-func ThumbURL(key string) string {
+func ThumbURL(ctx context.Context, key string) string {
+	if u, ok := storeURL(ctx, key); ok {
+		return u
+	}
+
+	host, bucket := imgixFor(ctx)
+	return thumbURL(host, bucket, key)
+}
+
+func thumbURL(host, bucket, key string) string {
+	if host == "" || MediaType(key) == TypeVideo {
+		return publicURL(bucket, key)
+	}
+
 	w := 800
 	h := 450
-	return fmt.Sprintf("https://icco-walls.imgix.net/%s?w=%d&h=%d&fit=crop&auto=compress&auto=format", key, w, h)
+	return fmt.Sprintf("https://%s/%s?w=%d&h=%d&fit=crop&auto=compress&auto=format", host, key, w, h)
+}
+
+// FitURL returns the URL of a version hosted by imgix cropped to exactly fit
+// a w by h display at the given device pixel ratio. The imgix host is the
+// one of the collection on ctx.
+func FitURL(ctx context.Context, key string, w, h int, dpr float64) string {
+	if u, ok := storeURL(ctx, key); ok {
+		return u
+	}
+	host, bucket := imgixFor(ctx)
+	if host == "" || MediaType(key) == TypeVideo {
+		return publicURL(bucket, key)
+	}
+
+	return fmt.Sprintf("https://%s/%s?w=%d&h=%d&dpr=%g&fit=crop&crop=entropy&auto=compress&auto=format", host, key, w, h, dpr)
 }
 
 // File is a subset of storage.ObjectAttrs that we need.
 type File struct {
 	CRC32C       uint32    `json:"-"`
+	MD5          []byte    `json:"-"`
 	Etag         string    `json:"etag"`
+	Generation   int64     `json:"-"`
+	StorageClass string    `json:"-"`
 	FileURL      string    `json:"-"`
 	FullRezURL   string    `json:"cdn"`
 	Name         string    `json:"key"`
+	Bucket       string    `json:"bucket,omitempty"`
+	Type         string    `json:"type"`
 	Size         int64     `json:"-"`
 	ThumbnailURL string    `json:"thumbnail"`
 	Created      time.Time `json:"created_at"`
 	Updated      time.Time `json:"updated_at"`
+	CustomTime   time.Time `json:"-"`
+
+	// Deleted is when a previous generation was replaced or deleted. It is
+	// zero for live files.
+	Deleted time.Time `json:"-"`
+
+	// Metadata is the object's custom metadata, which holds attribution
+	// and other fields set by the tools.
+	Metadata map[string]string `json:"-"`
+
+	// Video is set for video wallpapers.
+	Video *VideoInfo `json:"video,omitempty"`
+
+	// ColorProfile describes the image's embedded ICC profile. Images
+	// without one are sRGB.
+	ColorProfile string `json:"color_profile,omitempty"`
+
+	// VariantOf is the name of the higher resolution copy of the same
+	// artwork, if this is a lower resolution one.
+	VariantOf string `json:"variant_of,omitempty"`
+
+	// AltText describes the wallpaper for screen readers. Use Alt to fall
+	// back to the name when it is not set.
+	AltText string `json:"alt_text,omitempty"`
+
+	Attribution
+}
+
+// Added returns when the file was added to the collection. This is the
+// CustomTime set by the uploader if there is one, otherwise when the object
+// was created.
+func (f *File) Added() time.Time {
+	if !f.CustomTime.IsZero() {
+		return f.CustomTime
+	}
+	return f.Created
+}
+
+// GetFile returns the attributes for a single file. It returns an error
+// wrapping storage.ErrObjectNotExist if the file does not exist.
+func GetFile(ctx context.Context, filename string) (*File, error) {
+	return StoreFor(ctx).Attrs(ctx, filename)
+}
+
+// Files lazily iterates over the attributes of every file, in name order.
+// Iteration stops after the first error.
+func Files(ctx context.Context) iter.Seq2[*File, error] {
+	return StoreFor(ctx).List(ctx)
+}
+
+// GetPage returns up to size files in name order starting at pageToken, and
+// the token for the next page, which is empty after the last page. A size
+// below 1 returns an error wrapping ErrPageSize.
+func GetPage(ctx context.Context, pageToken string, size int) ([]*File, string, error) {
+	return StoreFor(ctx).Page(ctx, pageToken, size)
 }
 
-// GetAll returns all of the attributes for files in GCS.
+// GetAll returns all of the attributes for files, most recently added first.
+// GCS can only list by name, so the whole bucket is read before sorting; use
+// Files or GetPage when order does not matter.
 func GetAll(ctx context.Context) ([]*File, error) {
-	client, err := storage.NewClient(ctx)
+	var ret []*File
+	for f, err := range Files(ctx) {
+		if err != nil {
+			return nil, err
+		}
+
+		ret = append(ret, f)
+	}
+
+	if err := SortFiles(ret, "added", true); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+// Stats is a summary of the files in GCS.
+type Stats struct {
+	Count     int            `json:"count"`
+	TotalSize int64          `json:"total_size"`
+	Formats   map[string]int `json:"formats"`
+}
+
+// GetStats returns counts by file format and the total size of the bucket.
+func GetStats(ctx context.Context) (*Stats, error) {
+	files, err := GetAll(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	var ret []*File
+	return Summarize(files), nil
+}
 
-	query := &storage.Query{
-		Projection: storage.ProjectionNoACL,
+// Summarize returns counts by file format and the total size of files.
+func Summarize(files []*File) *Stats {
+	stats := &Stats{
+		Formats: map[string]int{},
 	}
+	for _, f := range files {
+		stats.Count++
+		stats.TotalSize += f.Size
 
-	it := client.Bucket(Bucket).Objects(ctx, query)
-	for {
-		objAttrs, err := it.Next()
-		if errors.Is(err, iterator.Done) {
-			break
+		format := strings.TrimPrefix(strings.ToLower(filepath.Ext(f.Name)), ".")
+		if format == "" {
+			format = "unknown"
 		}
-		if err != nil {
-			return nil, fmt.Errorf("error on iterating: %w", err)
+		stats.Formats[format]++
+	}
+
+	return stats
+}
+
+// LastModified returns the most recent Updated time of files, which acts as
+// a version for the collection as a whole.
+func LastModified(files []*File) time.Time {
+	var last time.Time
+	for _, f := range files {
+		if f.Updated.After(last) {
+			last = f.Updated
 		}
+	}
 
-		ret = append(ret, &File{
-			CRC32C:       objAttrs.CRC32C,
-			Etag:         objAttrs.Etag,
-			Name:         objAttrs.Name,
-			Size:         objAttrs.Size,
-			Created:      objAttrs.Created,
-			Updated:      objAttrs.Updated,
-			ThumbnailURL: ThumbURL(objAttrs.Name),
-			FileURL:      objAttrs.MediaLink,
-			FullRezURL:   FullRezURL(objAttrs.Name),
-		})
-	}
-
-	// Sort by created date
-	slices.SortStableFunc(ret, func(b, a *File) int {
-		return cmp.Compare(a.Created.String(), b.Created.String())
-	})
-	return ret, nil
+	return last
 }
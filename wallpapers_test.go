@@ -0,0 +1,851 @@
+package wallpapers
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"image"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"golang.org/x/image/bmp"
+	"golang.org/x/image/tiff"
+	"google.golang.org/api/option"
+)
+
+// useMemoryStore makes the package level functions use an empty
+// MemoryStore for the length of a test.
+func useMemoryStore(t *testing.T) *MemoryStore {
+	t.Helper()
+	old := DefaultStore()
+	s := NewMemoryStore()
+	SetStore(s)
+	t.Cleanup(func() { SetStore(old) })
+	return s
+}
+
+// testPNG returns a w by h PNG whose content depends on shade.
+func testPNG(t *testing.T, w, h int, shade uint8) []byte {
+	t.Helper()
+	img := image.NewGray(image.Rect(0, 0, w, h))
+	for i := range img.Pix {
+		img.Pix[i] = shade
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestUploadFile(t *testing.T) {
+	ctx := context.Background()
+	valid := testPNG(t, DefaultValidation.MinWidth, DefaultValidation.MinHeight, 1)
+	other := testPNG(t, DefaultValidation.MinWidth, DefaultValidation.MinHeight, 2)
+	small := testPNG(t, 640, 480, 1)
+
+	for _, tc := range []struct {
+		name            string
+		filename        string
+		existing        []byte
+		content         []byte
+		opts            []UploadOption
+		wantRejected    bool
+		wantStored      []byte
+		wantQuarantined bool
+		wantAudit       string
+	}{
+		{
+			name:       "new file",
+			content:    valid,
+			wantStored: valid,
+			wantAudit:  AuditUpload,
+		},
+		{
+			name:       "replaces existing file",
+			existing:   other,
+			content:    valid,
+			wantStored: valid,
+			wantAudit:  AuditUpload,
+		},
+		{
+			name:            "too small is quarantined",
+			content:         small,
+			wantRejected:    true,
+			wantQuarantined: true,
+			wantAudit:       AuditQuarantine,
+		},
+		{
+			name:            "not an image is quarantined",
+			content:         []byte("not a png"),
+			wantRejected:    true,
+			wantQuarantined: true,
+			wantAudit:       AuditQuarantine,
+		},
+		{
+			name:            "text file is quarantined",
+			filename:        "notes.txt",
+			content:         []byte("not a wallpaper"),
+			wantRejected:    true,
+			wantQuarantined: true,
+			wantAudit:       AuditQuarantine,
+		},
+		{
+			name:            "rejected upload keeps existing file",
+			existing:        other,
+			content:         small,
+			wantRejected:    true,
+			wantStored:      other,
+			wantQuarantined: true,
+			wantAudit:       AuditQuarantine,
+		},
+		{
+			name:       "without validation",
+			content:    small,
+			opts:       []UploadOption{WithoutValidation()},
+			wantStored: small,
+			wantAudit:  AuditUpload,
+		},
+		{
+			name:       "custom validation",
+			content:    small,
+			opts:       []UploadOption{WithValidation(Validation{MinWidth: 640, MinHeight: 480})},
+			wantStored: small,
+			wantAudit:  AuditUpload,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s := useMemoryStore(t)
+			name := cmp.Or(tc.filename, "a.png")
+			if tc.existing != nil {
+				if err := UploadFile(ctx, name, tc.existing); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			err := UploadFile(ctx, name, tc.content, tc.opts...)
+			var rejected *RejectedError
+			if got := errors.As(err, &rejected); got != tc.wantRejected {
+				t.Fatalf("UploadFile error = %v, want rejected %v", err, tc.wantRejected)
+			}
+			if err != nil && !tc.wantRejected {
+				t.Fatalf("UploadFile error = %v", err)
+			}
+
+			f, err := s.Attrs(ctx, name)
+			switch {
+			case tc.wantStored == nil && !errors.Is(err, storage.ErrObjectNotExist):
+				t.Errorf("Attrs error = %v, want ErrObjectNotExist", err)
+			case tc.wantStored != nil && err != nil:
+				t.Errorf("Attrs error = %v", err)
+			case tc.wantStored != nil && f.CRC32C != GetFileCRC(tc.wantStored):
+				t.Errorf("stored CRC = %d, want %d", f.CRC32C, GetFileCRC(tc.wantStored))
+			}
+
+			q, err := Quarantined(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := q.Attrs(ctx, name); (err == nil) != tc.wantQuarantined {
+				t.Errorf("quarantined = %v, want %v", err == nil, tc.wantQuarantined)
+			}
+
+			entries, err := AuditEntries(ctx, AuditQuery{Name: name, Limit: 1})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(entries) != 1 || entries[0].Action != tc.wantAudit {
+				t.Errorf("last audit entry = %+v, want %s", entries, tc.wantAudit)
+			}
+		})
+	}
+}
+
+// countingStore counts the attribute reads of a MemoryStore.
+type countingStore struct {
+	*MemoryStore
+	attrs int
+}
+
+func (s *countingStore) Attrs(ctx context.Context, name string) (*File, error) {
+	s.attrs++
+	return s.MemoryStore.Attrs(ctx, name)
+}
+
+func TestUploadFileAttrsReads(t *testing.T) {
+	content := testPNG(t, 1, 1, 1)
+
+	for _, tc := range []struct {
+		name     string
+		existing bool
+		previous bool
+		want     int
+	}{
+		{
+			name: "new file",
+			want: 1,
+		},
+		{
+			name:     "new file with previous",
+			previous: true,
+			want:     0,
+		},
+		{
+			name:     "replaced file with previous",
+			existing: true,
+			previous: true,
+			want:     0,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s := &countingStore{MemoryStore: NewMemoryStore()}
+			ctx := ContextWithStore(context.Background(), s)
+
+			var prev *File
+			if tc.existing {
+				if _, err := writeFile(ctx, s, "a.png", testPNG(t, 1, 1, 2), &uploadOptions{}); err != nil {
+					t.Fatal(err)
+				}
+				var err error
+				if prev, err = s.MemoryStore.Attrs(ctx, "a.png"); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			opts := []UploadOption{WithoutValidation()}
+			if tc.previous {
+				opts = append(opts, WithPrevious(prev))
+			}
+			if err := UploadFile(ctx, "a.png", content, opts...); err != nil {
+				t.Fatal(err)
+			}
+			if s.attrs != tc.want {
+				t.Errorf("UploadFile read attributes %d times, want %d", s.attrs, tc.want)
+			}
+
+			entries, err := AuditEntries(ctx, AuditQuery{Name: "a.png"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(entries) != 1 || entries[0].Action != AuditUpload {
+				t.Fatalf("audit entries = %+v, want one upload", entries)
+			}
+			if got, want := entries[0].After["crc32c"], strconv.FormatUint(uint64(GetFileCRC(content)), 10); got != want {
+				t.Errorf("audit crc32c after = %q, want %q", got, want)
+			}
+			if _, ok := entries[0].Before["crc32c"]; ok != tc.existing {
+				t.Errorf("audit before = %v, want crc32c recorded %v", entries[0].Before, tc.existing)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	encode := func(enc func(*bytes.Buffer, image.Image) error, w, h int) []byte {
+		t.Helper()
+		var buf bytes.Buffer
+		if err := enc(&buf, image.NewGray(image.Rect(0, 0, w, h))); err != nil {
+			t.Fatal(err)
+		}
+		return buf.Bytes()
+	}
+	tiffEnc := func(b *bytes.Buffer, img image.Image) error { return tiff.Encode(b, img, nil) }
+	bmpEnc := func(b *bytes.Buffer, img image.Image) error { return bmp.Encode(b, img) }
+	// A 1x1 lossless WebP, as x/image has no WebP encoder.
+	webp, err := base64.StdEncoding.DecodeString("UklGRhoAAABXRUJQVlA4TA0AAAAvAAAAEAcQERGIiP4HAA==")
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, h := DefaultValidation.MinWidth, DefaultValidation.MinHeight
+
+	for _, tc := range []struct {
+		name     string
+		filename string
+		content  []byte
+		maxSize  int64
+		want     string
+	}{
+		{name: "tiff", filename: "a.tiff", content: encode(tiffEnc, w, h)},
+		{name: "small tiff", filename: "a.tif", content: encode(tiffEnc, 640, 480), want: "640x480 is smaller"},
+		{name: "bmp", filename: "a.bmp", content: encode(bmpEnc, w, h)},
+		{name: "small webp", filename: "a.webp", content: webp, want: "1x1 is smaller"},
+		{name: "heic has no decoder", filename: "a.heic", content: []byte("\x00\x00\x00\x18ftypheic")},
+		{name: "avif has no decoder", filename: "a.AVIF", content: []byte("\x00\x00\x00\x1cftypavif")},
+		{name: "corrupt png", filename: "a.png", content: []byte("not a png"), want: "does not decode"},
+		{name: "corrupt webp", filename: "a.webp", content: webp[:20], want: "does not decode"},
+		{name: "too big heic", filename: "a.heic", content: make([]byte, 11), maxSize: 10, want: "over the 10 byte limit"},
+		{name: "text", filename: "notes.txt", content: []byte("hello"), want: "not an image or video"},
+		{name: "pdf", filename: "a.pdf", content: []byte("%PDF-1.7"), want: "not an image or video"},
+		{name: "desktop.ini", filename: "desktop.ini", content: []byte("[.ShellClassInfo]"), want: "not an image or video"},
+		{name: "no extension", filename: "README", content: []byte("hello"), want: "not an image or video"},
+		{name: "webm", filename: "a.webm", content: []byte("\x1a\x45\xdf\xa3")},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			v := DefaultValidation
+			if tc.maxSize > 0 {
+				v.MaxSize = tc.maxSize
+			}
+			got := v.Validate(tc.filename, tc.content)
+			if (tc.want == "") != (got == "") || !strings.Contains(got, tc.want) {
+				t.Errorf("Validate(%q) = %q, want %q", tc.filename, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestGetAll(t *testing.T) {
+	ctx := context.Background()
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, tc := range []struct {
+		name string
+		// files are uploaded in order, a day apart.
+		files  []string
+		custom map[string]time.Time
+		want   []string
+	}{
+		{
+			name: "empty",
+			want: nil,
+		},
+		{
+			name:  "newest first",
+			files: []string{"a.png", "b.png", "c.png"},
+			want:  []string{"c.png", "b.png", "a.png"},
+		},
+		{
+			name:   "custom time is when a file was added",
+			files:  []string{"a.png", "b.png", "c.png"},
+			custom: map[string]time.Time{"a.png": base.AddDate(0, 0, 10)},
+			want:   []string{"a.png", "c.png", "b.png"},
+		},
+		{
+			name:   "older custom time",
+			files:  []string{"a.png", "b.png"},
+			custom: map[string]time.Time{"b.png": base.AddDate(-1, 0, 0)},
+			want:   []string{"a.png", "b.png"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s := useMemoryStore(t)
+			now := base
+			s.Now = func() time.Time { return now }
+			for i, name := range tc.files {
+				now = base.AddDate(0, 0, i)
+				opts := []UploadOption{WithoutValidation()}
+				if c, ok := tc.custom[name]; ok {
+					opts = append(opts, WithCustomTime(c))
+				}
+				if err := UploadFile(ctx, name, testPNG(t, 1, 1, uint8(i)), opts...); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			files, err := GetAll(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, f := range files {
+				got = append(got, f.Name)
+			}
+			if !slices.Equal(got, tc.want) {
+				t.Errorf("GetAll = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestDeleteFile(t *testing.T) {
+	ctx := context.Background()
+
+	for _, tc := range []struct {
+		name    string
+		files   []string
+		delete  string
+		want    []string
+		wantErr error
+	}{
+		{
+			name:   "deletes file",
+			files:  []string{"a.png", "b.png"},
+			delete: "a.png",
+			want:   []string{"b.png"},
+		},
+		{
+			name:    "missing file",
+			files:   []string{"a.png"},
+			delete:  "b.png",
+			want:    []string{"a.png"},
+			wantErr: storage.ErrObjectNotExist,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			useMemoryStore(t)
+			for i, name := range tc.files {
+				if err := UploadFile(ctx, name, testPNG(t, 1, 1, uint8(i)), WithoutValidation()); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			err := DeleteFile(ctx, tc.delete)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("DeleteFile error = %v, want %v", err, tc.wantErr)
+			}
+
+			var got []string
+			for f, err := range Files(ctx) {
+				if err != nil {
+					t.Fatal(err)
+				}
+				got = append(got, f.Name)
+			}
+			if !slices.Equal(got, tc.want) {
+				t.Errorf("files = %q, want %q", got, tc.want)
+			}
+
+			entries, err := AuditEntries(ctx, AuditQuery{Name: tc.delete})
+			if err != nil {
+				t.Fatal(err)
+			}
+			deleted := len(entries) > 0 && entries[0].Action == AuditDelete
+			if deleted != (tc.wantErr == nil) {
+				t.Errorf("audit entries = %+v, want delete recorded %v", entries, tc.wantErr == nil)
+			}
+		})
+	}
+}
+
+// fakeGCS serves the listing part of the GCS JSON API for a bucket holding
+// names, and points the GCS stores at it for the length of a test.
+func fakeGCS(t *testing.T, bucket string, names []string) Store {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/storage/v1/b/"+bucket+"/o" {
+			http.NotFound(w, r)
+			return
+		}
+
+		q := r.URL.Query()
+		size, err := strconv.Atoi(q.Get("maxResults"))
+		if err != nil || size < 1 {
+			size = len(names)
+		}
+		type object struct {
+			Name   string `json:"name"`
+			Bucket string `json:"bucket"`
+		}
+		resp := struct {
+			Items         []object `json:"items"`
+			NextPageToken string   `json:"nextPageToken,omitempty"`
+		}{}
+		for _, name := range names {
+			if name <= q.Get("pageToken") {
+				continue
+			}
+			if len(resp.Items) == size {
+				resp.NextPageToken = resp.Items[size-1].Name
+				break
+			}
+			resp.Items = append(resp.Items, object{Name: name, Bucket: bucket})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			t.Error(err)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	old := gcsClient
+	gcsClient = func() (*storage.Client, error) {
+		return storage.NewClient(context.Background(),
+			option.WithEndpoint(srv.URL+"/storage/v1/"),
+			option.WithoutAuthentication())
+	}
+	t.Cleanup(func() { gcsClient = old })
+
+	return NewGCSStore(Collection{Bucket: bucket})
+}
+
+func TestGetPage(t *testing.T) {
+	ctx := context.Background()
+	names := []string{"a.png", "b.png", "c.png"}
+
+	stores := map[string]func(t *testing.T) Store{
+		"memory": func(t *testing.T) Store {
+			s := NewMemoryStore()
+			for i, name := range names {
+				if _, err := writeFile(ctx, s, name, testPNG(t, 1, 1, uint8(i)), &uploadOptions{}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			return s
+		},
+		"local": func(t *testing.T) Store {
+			s, err := NewLocalStore(t.TempDir())
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i, name := range names {
+				if _, err := writeFile(ctx, s, name, testPNG(t, 1, 1, uint8(i)), &uploadOptions{}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			return s
+		},
+		"gcs": func(t *testing.T) Store {
+			return fakeGCS(t, "test", names)
+		},
+	}
+
+	for _, tc := range []struct {
+		name    string
+		size    int
+		want    [][]string
+		wantErr error
+	}{
+		{
+			name:    "zero",
+			size:    0,
+			wantErr: ErrPageSize,
+		},
+		{
+			name:    "negative",
+			size:    -1,
+			wantErr: ErrPageSize,
+		},
+		{
+			name: "one",
+			size: 1,
+			want: [][]string{{"a.png"}, {"b.png"}, {"c.png"}},
+		},
+		{
+			name: "all",
+			size: len(names),
+			want: [][]string{names},
+		},
+	} {
+		for store, newStore := range stores {
+			t.Run(tc.name+"/"+store, func(t *testing.T) {
+				s := newStore(t)
+				sctx := ContextWithStore(ctx, s)
+
+				var got [][]string
+				token := ""
+				for {
+					files, next, err := GetPage(sctx, token, tc.size)
+					if !errors.Is(err, tc.wantErr) {
+						t.Fatalf("GetPage error = %v, want %v", err, tc.wantErr)
+					}
+					if err != nil {
+						return
+					}
+
+					var page []string
+					for _, f := range files {
+						page = append(page, f.Name)
+					}
+					got = append(got, page)
+
+					if next == "" {
+						break
+					}
+					if len(got) > len(names) {
+						t.Fatalf("GetPage did not stop after %d pages", len(got))
+					}
+					token = next
+				}
+
+				if !slices.EqualFunc(got, tc.want, slices.Equal) {
+					t.Errorf("pages = %q, want %q", got, tc.want)
+				}
+			})
+		}
+	}
+}
+
+func TestUploadNew(t *testing.T) {
+	ctx := context.Background()
+	content := testPNG(t, 1, 1, 1)
+
+	for _, tc := range []struct {
+		name     string
+		existing map[string][]byte
+		// index, if set, lists the files of existing passed to WithIndex.
+		index   []string
+		want    string
+		wantDup string
+	}{
+		{
+			name: "new content",
+			want: "a.png",
+		},
+		{
+			name:     "duplicate found by listing",
+			existing: map[string][]byte{"b.png": content},
+			wantDup:  "b.png",
+		},
+		{
+			name:     "duplicate found in index",
+			existing: map[string][]byte{"b.png": content},
+			index:    []string{"b.png"},
+			wantDup:  "b.png",
+		},
+		{
+			name:     "index is used instead of listing",
+			existing: map[string][]byte{"b.png": content},
+			index:    []string{},
+			want:     "a.png",
+		},
+		{
+			name:     "name taken by different content",
+			existing: map[string][]byte{"a.png": testPNG(t, 1, 1, 2)},
+			want:     HashedName("a.png", content),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			useMemoryStore(t)
+			for name, c := range tc.existing {
+				if err := UploadFile(ctx, name, c, WithoutValidation()); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			opts := []UploadOption{WithoutValidation()}
+			if tc.index != nil {
+				var files []*File
+				for _, name := range tc.index {
+					f, err := GetFile(ctx, name)
+					if err != nil {
+						t.Fatal(err)
+					}
+					files = append(files, f)
+				}
+				opts = append(opts, WithIndex(IndexFiles(files)))
+			}
+
+			got, err := UploadNew(ctx, "a.png", content, opts...)
+			var dup *DuplicateError
+			if errors.As(err, &dup) {
+				if dup.Existing != tc.wantDup {
+					t.Errorf("UploadNew duplicate of %q, want %q", dup.Existing, tc.wantDup)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tc.wantDup != "" {
+				t.Fatalf("UploadNew = %q, want duplicate of %q", got, tc.wantDup)
+			}
+			if got != tc.want {
+				t.Errorf("UploadNew = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestLocalStoreChecksums(t *testing.T) {
+	ctx := context.Background()
+	content := testPNG(t, 1, 1, 1)
+	sameSize := slices.Clone(content)
+	sameSize[len(sameSize)-1] ^= 0xff
+
+	for _, tc := range []struct {
+		name string
+		// change replaces the file behind the store's back.
+		change []byte
+		// keepTime restores the modification time after the change.
+		keepTime bool
+		want     []byte
+	}{
+		{
+			name: "unchanged",
+			want: content,
+		},
+		{
+			name:   "changed",
+			change: testPNG(t, 2, 2, 2),
+			want:   testPNG(t, 2, 2, 2),
+		},
+		{
+			// Same size and time, so the recorded checksums are trusted
+			// without reading the file.
+			name:     "same size and time",
+			change:   sameSize,
+			keepTime: true,
+			want:     content,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s, err := NewLocalStore(t.TempDir())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := writeFile(ctx, s, "a.png", content, &uploadOptions{}); err != nil {
+				t.Fatal(err)
+			}
+
+			if tc.change != nil {
+				path := filepath.Join(s.Dir, "a.png")
+				info, err := os.Stat(path)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, tc.change, 0600); err != nil {
+					t.Fatal(err)
+				}
+				mtime := info.ModTime().Add(time.Second)
+				if tc.keepTime {
+					mtime = info.ModTime()
+				}
+				if err := os.Chtimes(path, mtime, mtime); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			f, err := s.Attrs(ctx, "a.png")
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := GetChecksums(tc.want)
+			if f.CRC32C != want.CRC32C || !bytes.Equal(f.MD5, want.MD5) {
+				t.Errorf("Attrs checksums = %08x %x, want %08x %x", f.CRC32C, f.MD5, want.CRC32C, want.MD5)
+			}
+		})
+	}
+}
+
+func TestGetGoogleCRC(t *testing.T) {
+	ctx := context.Background()
+	content := testPNG(t, 1, 1, 1)
+
+	for _, tc := range []struct {
+		name  string
+		files map[string][]byte
+		get   string
+		want  uint32
+	}{
+		{
+			name:  "existing file",
+			files: map[string][]byte{"a.png": content},
+			get:   "a.png",
+			want:  GetFileCRC(content),
+		},
+		{
+			name:  "missing file is zero",
+			files: map[string][]byte{"a.png": content},
+			get:   "b.png",
+			want:  0,
+		},
+		{
+			name: "empty store",
+			get:  "a.png",
+			want: 0,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			useMemoryStore(t)
+			for name, content := range tc.files {
+				if err := UploadFile(ctx, name, content, WithoutValidation()); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			got, err := GetGoogleCRC(ctx, tc.get)
+			if err != nil {
+				t.Fatalf("GetGoogleCRC error = %v", err)
+			}
+			if got != tc.want {
+				t.Errorf("GetGoogleCRC(%q) = %d, want %d", tc.get, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestPreviewFrame(t *testing.T) {
+	// The phone's screen has rounded corners.
+	d := Devices["iphone"]
+	buf, err := PreviewFrame(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(bytes.NewReader(buf))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, frame, screen := previewLayout(d)
+
+	alpha := func(p image.Point) uint32 {
+		_, _, _, a := img.At(p.X, p.Y).RGBA()
+		return a
+	}
+	for _, tc := range []struct {
+		name   string
+		p      image.Point
+		opaque bool
+	}{
+		{name: "bezel", p: image.Pt(frame.Min.X+d.Bezel/2, frame.Min.Y+frame.Dy()/2), opaque: true},
+		{name: "screen", p: image.Pt(screen.Min.X+screen.Dx()/2, screen.Min.Y+screen.Dy()/2)},
+		{name: "rounded off screen corner", p: screen.Min, opaque: true},
+		{name: "outside the frame", p: image.Pt(0, 0)},
+	} {
+		if got := alpha(tc.p) == 0xffff; got != tc.opaque {
+			t.Errorf("%s at %v opaque = %v, want %v", tc.name, tc.p, got, tc.opaque)
+		}
+	}
+}
+
+func TestPreviewURL(t *testing.T) {
+	d := Devices["iphone"]
+	if _, ok := PreviewURL(ContextWithStore(context.Background(), NewMemoryStore()), "a.jpg", d, "frame.png"); ok {
+		t.Error("PreviewURL without imgix = true, want false")
+	}
+
+	ctx := ContextWithStore(context.Background(), &gcsStore{bucket: "walls", imgixHost: "walls.imgix.net"})
+	got, ok := PreviewURL(ctx, "a.jpg", d, "frame.png")
+	if !ok {
+		t.Fatal("PreviewURL with imgix = false, want true")
+	}
+	u, err := url.Parse(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Host != "walls.imgix.net" || u.Path != "/a.jpg" {
+		t.Errorf("PreviewURL = %q, want a.jpg on walls.imgix.net", got)
+	}
+	canvas, _, screen := previewLayout(d)
+	q := u.Query()
+	for param, want := range map[string]int{
+		"w":          d.Screen.Width,
+		"h":          d.Screen.Height,
+		"pad-left":   screen.Min.X,
+		"pad-top":    screen.Min.Y,
+		"pad-right":  canvas.Max.X - screen.Max.X,
+		"pad-bottom": canvas.Max.Y - screen.Max.Y,
+		"mark-w":     canvas.Dx(),
+		"mark-h":     canvas.Dy(),
+	} {
+		if got := q.Get(param); got != strconv.Itoa(want) {
+			t.Errorf("%s = %q, want %d", param, got, want)
+		}
+	}
+	if got := q.Get("mark"); got != "/variants/frame.png" {
+		t.Errorf("mark = %q, want the frame in the variants", got)
+	}
+}
@@ -0,0 +1,8032 @@
+// Package wallpapersclient provides primitives to interact with the openapi HTTP API.
+//
+// Code generated by github.com/oapi-codegen/oapi-codegen/v2 version v2.4.1 DO NOT EDIT.
+package wallpapersclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/oapi-codegen/runtime"
+)
+
+const (
+	BearerAuthScopes = "bearerAuth.Scopes"
+)
+
+// Defines values for AuditEntryAction.
+const (
+	AuditEntryActionDelete AuditEntryAction = "delete"
+	AuditEntryActionRename AuditEntryAction = "rename"
+	AuditEntryActionUpdate AuditEntryAction = "update"
+	AuditEntryActionUpload AuditEntryAction = "upload"
+)
+
+// Defines values for EventType.
+const (
+	EventTypeAdd    EventType = "add"
+	EventTypeDelete EventType = "delete"
+	EventTypeUpdate EventType = "update"
+)
+
+// Defines values for FileType.
+const (
+	FileTypeImage FileType = "image"
+	FileTypeVideo FileType = "video"
+)
+
+// Defines values for JobResult.
+const (
+	JobResultError JobResult = "error"
+	JobResultOk    JobResult = "ok"
+)
+
+// Defines values for OEmbedType.
+const (
+	Photo OEmbedType = "photo"
+)
+
+// Defines values for ReadinessChecksStatus.
+const (
+	ReadinessChecksStatusError ReadinessChecksStatus = "error"
+	ReadinessChecksStatusOk    ReadinessChecksStatus = "ok"
+)
+
+// Defines values for ReadinessStatus.
+const (
+	ReadinessStatusError ReadinessStatus = "error"
+	ReadinessStatusOk    ReadinessStatus = "ok"
+)
+
+// Defines values for Order.
+const (
+	OrderAsc  Order = "asc"
+	OrderDesc Order = "desc"
+)
+
+// Defines values for Sort.
+const (
+	SortAdded   Sort = "added"
+	SortCreated Sort = "created"
+	SortName    Sort = "name"
+	SortPopular Sort = "popular"
+	SortSize    Sort = "size"
+	SortUpdated Sort = "updated"
+)
+
+// Defines values for Type.
+const (
+	TypeImage Type = "image"
+	TypeVideo Type = "video"
+)
+
+// Defines values for Variants.
+const (
+	VariantsAll Variants = "all"
+)
+
+// Defines values for ListImagesParamsSort.
+const (
+	ListImagesParamsSortAdded   ListImagesParamsSort = "added"
+	ListImagesParamsSortCreated ListImagesParamsSort = "created"
+	ListImagesParamsSortName    ListImagesParamsSort = "name"
+	ListImagesParamsSortPopular ListImagesParamsSort = "popular"
+	ListImagesParamsSortSize    ListImagesParamsSort = "size"
+	ListImagesParamsSortUpdated ListImagesParamsSort = "updated"
+)
+
+// Defines values for ListImagesParamsOrder.
+const (
+	ListImagesParamsOrderAsc  ListImagesParamsOrder = "asc"
+	ListImagesParamsOrderDesc ListImagesParamsOrder = "desc"
+)
+
+// Defines values for ListImagesParamsType.
+const (
+	ListImagesParamsTypeImage ListImagesParamsType = "image"
+	ListImagesParamsTypeVideo ListImagesParamsType = "video"
+)
+
+// Defines values for ListImagesParamsVariants.
+const (
+	ListImagesParamsVariantsAll ListImagesParamsVariants = "all"
+)
+
+// Defines values for ListImagesParamsFields.
+const (
+	ListImagesParamsFieldsAltText      ListImagesParamsFields = "alt_text"
+	ListImagesParamsFieldsAuthor       ListImagesParamsFields = "author"
+	ListImagesParamsFieldsCdn          ListImagesParamsFields = "cdn"
+	ListImagesParamsFieldsColorProfile ListImagesParamsFields = "color_profile"
+	ListImagesParamsFieldsCreatedAt    ListImagesParamsFields = "created_at"
+	ListImagesParamsFieldsEtag         ListImagesParamsFields = "etag"
+	ListImagesParamsFieldsKey          ListImagesParamsFields = "key"
+	ListImagesParamsFieldsLicense      ListImagesParamsFields = "license"
+	ListImagesParamsFieldsSourceUrl    ListImagesParamsFields = "source_url"
+	ListImagesParamsFieldsThumbnail    ListImagesParamsFields = "thumbnail"
+	ListImagesParamsFieldsType         ListImagesParamsFields = "type"
+	ListImagesParamsFieldsUpdatedAt    ListImagesParamsFields = "updated_at"
+	ListImagesParamsFieldsVariantOf    ListImagesParamsFields = "variant_of"
+	ListImagesParamsFieldsVideo        ListImagesParamsFields = "video"
+)
+
+// Defines values for WallhavenSearchParamsSorting.
+const (
+	DateAdded WallhavenSearchParamsSorting = "date_added"
+	Favorites WallhavenSearchParamsSorting = "favorites"
+	Random    WallhavenSearchParamsSorting = "random"
+	Relevance WallhavenSearchParamsSorting = "relevance"
+	Toplist   WallhavenSearchParamsSorting = "toplist"
+	Views     WallhavenSearchParamsSorting = "views"
+)
+
+// Defines values for WallhavenSearchParamsOrder.
+const (
+	WallhavenSearchParamsOrderAsc  WallhavenSearchParamsOrder = "asc"
+	WallhavenSearchParamsOrderDesc WallhavenSearchParamsOrder = "desc"
+)
+
+// Defines values for WallhavenSearchParamsVariants.
+const (
+	WallhavenSearchParamsVariantsAll WallhavenSearchParamsVariants = "all"
+)
+
+// Defines values for IiifImageParamsQuality.
+const (
+	Bitonal IiifImageParamsQuality = "bitonal"
+	Color   IiifImageParamsQuality = "color"
+	Default IiifImageParamsQuality = "default"
+	Gray    IiifImageParamsQuality = "gray"
+)
+
+// Defines values for IiifImageParamsFormat.
+const (
+	Gif  IiifImageParamsFormat = "gif"
+	Jpg  IiifImageParamsFormat = "jpg"
+	Png  IiifImageParamsFormat = "png"
+	Webp IiifImageParamsFormat = "webp"
+)
+
+// Defines values for OembedParamsFormat.
+const (
+	OembedParamsFormatJson OembedParamsFormat = "json"
+)
+
+// Defines values for PlaylistParamsFormat.
+const (
+	PlaylistParamsFormatJson PlaylistParamsFormat = "json"
+	PlaylistParamsFormatM3u  PlaylistParamsFormat = "m3u"
+	PlaylistParamsFormatTxt  PlaylistParamsFormat = "txt"
+)
+
+// Defines values for PlaylistParamsSort.
+const (
+	PlaylistParamsSortAdded   PlaylistParamsSort = "added"
+	PlaylistParamsSortCreated PlaylistParamsSort = "created"
+	PlaylistParamsSortName    PlaylistParamsSort = "name"
+	PlaylistParamsSortPopular PlaylistParamsSort = "popular"
+	PlaylistParamsSortSize    PlaylistParamsSort = "size"
+	PlaylistParamsSortUpdated PlaylistParamsSort = "updated"
+)
+
+// Defines values for PlaylistParamsOrder.
+const (
+	PlaylistParamsOrderAsc  PlaylistParamsOrder = "asc"
+	PlaylistParamsOrderDesc PlaylistParamsOrder = "desc"
+)
+
+// Defines values for PlaylistParamsVariants.
+const (
+	PlaylistParamsVariantsAll PlaylistParamsVariants = "all"
+)
+
+// Defines values for PreviewImageParamsDevice.
+const (
+	Iphone    PreviewImageParamsDevice = "iphone"
+	Macbook   PreviewImageParamsDevice = "macbook"
+	Ultrawide PreviewImageParamsDevice = "ultrawide"
+)
+
+// Defines values for CreateUploadParamsTusResumable.
+const (
+	CreateUploadParamsTusResumableN100 CreateUploadParamsTusResumable = "1.0.0"
+)
+
+// Defines values for DeleteUploadParamsTusResumable.
+const (
+	DeleteUploadParamsTusResumableN100 DeleteUploadParamsTusResumable = "1.0.0"
+)
+
+// Defines values for UploadOffsetParamsTusResumable.
+const (
+	UploadOffsetParamsTusResumableN100 UploadOffsetParamsTusResumable = "1.0.0"
+)
+
+// Defines values for AppendUploadParamsTusResumable.
+const (
+	AppendUploadParamsTusResumableN100 AppendUploadParamsTusResumable = "1.0.0"
+)
+
+// Defines values for V1ListImagesParamsSort.
+const (
+	V1ListImagesParamsSortAdded   V1ListImagesParamsSort = "added"
+	V1ListImagesParamsSortCreated V1ListImagesParamsSort = "created"
+	V1ListImagesParamsSortName    V1ListImagesParamsSort = "name"
+	V1ListImagesParamsSortPopular V1ListImagesParamsSort = "popular"
+	V1ListImagesParamsSortSize    V1ListImagesParamsSort = "size"
+	V1ListImagesParamsSortUpdated V1ListImagesParamsSort = "updated"
+)
+
+// Defines values for V1ListImagesParamsOrder.
+const (
+	Asc  V1ListImagesParamsOrder = "asc"
+	Desc V1ListImagesParamsOrder = "desc"
+)
+
+// Defines values for V1ListImagesParamsType.
+const (
+	V1ListImagesParamsTypeImage V1ListImagesParamsType = "image"
+	V1ListImagesParamsTypeVideo V1ListImagesParamsType = "video"
+)
+
+// Defines values for V1ListImagesParamsVariants.
+const (
+	All V1ListImagesParamsVariants = "all"
+)
+
+// Defines values for V1ListImagesParamsFields.
+const (
+	V1ListImagesParamsFieldsAltText      V1ListImagesParamsFields = "alt_text"
+	V1ListImagesParamsFieldsAuthor       V1ListImagesParamsFields = "author"
+	V1ListImagesParamsFieldsCdn          V1ListImagesParamsFields = "cdn"
+	V1ListImagesParamsFieldsColorProfile V1ListImagesParamsFields = "color_profile"
+	V1ListImagesParamsFieldsCreatedAt    V1ListImagesParamsFields = "created_at"
+	V1ListImagesParamsFieldsEtag         V1ListImagesParamsFields = "etag"
+	V1ListImagesParamsFieldsKey          V1ListImagesParamsFields = "key"
+	V1ListImagesParamsFieldsLicense      V1ListImagesParamsFields = "license"
+	V1ListImagesParamsFieldsSourceUrl    V1ListImagesParamsFields = "source_url"
+	V1ListImagesParamsFieldsThumbnail    V1ListImagesParamsFields = "thumbnail"
+	V1ListImagesParamsFieldsType         V1ListImagesParamsFields = "type"
+	V1ListImagesParamsFieldsUpdatedAt    V1ListImagesParamsFields = "updated_at"
+	V1ListImagesParamsFieldsVariantOf    V1ListImagesParamsFields = "variant_of"
+	V1ListImagesParamsFieldsVideo        V1ListImagesParamsFields = "video"
+)
+
+// APIError defines model for APIError.
+type APIError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+
+	// Param For 400 responses, the query parameter that failed validation.
+	Param *string `json:"param,omitempty"`
+
+	// RequestId Identifies the request in the server's logs.
+	RequestId *string `json:"request_id,omitempty"`
+}
+
+// AuditEntry defines model for AuditEntry.
+type AuditEntry struct {
+	Action *AuditEntryAction `json:"action,omitempty"`
+
+	// Actor Who made the change.
+	Actor *string `json:"actor,omitempty"`
+
+	// After Changed attributes as they are now.
+	After *map[string]string `json:"after,omitempty"`
+
+	// Before Changed attributes as they were.
+	Before *map[string]string `json:"before,omitempty"`
+	Name   *string            `json:"name,omitempty"`
+	Time   *time.Time         `json:"time,omitempty"`
+}
+
+// AuditEntryAction defines model for AuditEntry.Action.
+type AuditEntryAction string
+
+// Error defines model for Error.
+type Error struct {
+	Code string `json:"code"`
+
+	// Error Same as message, kept for older clients.
+	Error   string `json:"error"`
+	Message string `json:"message"`
+
+	// Param For 400 responses, the query parameter that failed validation.
+	Param *string `json:"param,omitempty"`
+
+	// RequestId Identifies the request in the server's logs.
+	RequestId *string `json:"request_id,omitempty"`
+}
+
+// ErrorEnvelope defines model for ErrorEnvelope.
+type ErrorEnvelope struct {
+	Data  *interface{} `json:"data"`
+	Error APIError     `json:"error"`
+}
+
+// Event defines model for Event.
+type Event struct {
+	File *File      `json:"file,omitempty"`
+	Type *EventType `json:"type,omitempty"`
+}
+
+// EventType defines model for Event.Type.
+type EventType string
+
+// File defines model for File.
+type File struct {
+	// AltText Description of the wallpaper for screen readers. Falls back to the key when not set.
+	AltText *string `json:"alt_text,omitempty"`
+	Author  *string `json:"author,omitempty"`
+	Bucket  *string `json:"bucket,omitempty"`
+	Cdn     string  `json:"cdn"`
+
+	// ColorProfile Description of the embedded ICC profile, e.g. Display P3. Absent for sRGB images without a profile.
+	ColorProfile *string   `json:"color_profile,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+	Etag         string    `json:"etag"`
+	Key          string    `json:"key"`
+	License      *string   `json:"license,omitempty"`
+	SourceUrl    *string   `json:"source_url,omitempty"`
+	Thumbnail    string    `json:"thumbnail"`
+	Type         FileType  `json:"type"`
+	UpdatedAt    time.Time `json:"updated_at"`
+
+	// VariantOf Key of the higher resolution copy of the same artwork, if this is a lower resolution one.
+	VariantOf *string `json:"variant_of,omitempty"`
+
+	// Video Set for video wallpapers. Fields are absent if the video could not be probed.
+	Video *VideoInfo `json:"video,omitempty"`
+}
+
+// FileType defines model for File.Type.
+type FileType string
+
+// GraphQLError defines model for GraphQLError.
+type GraphQLError struct {
+	Message *string `json:"message,omitempty"`
+
+	// Path The response keys and list indexes of the field that failed.
+	Path *[]GraphQLError_Path_Item `json:"path,omitempty"`
+}
+
+// GraphQLErrorPath0 defines model for .
+type GraphQLErrorPath0 = string
+
+// GraphQLErrorPath1 defines model for .
+type GraphQLErrorPath1 = int
+
+// GraphQLError_Path_Item defines model for GraphQLError.path.Item.
+type GraphQLError_Path_Item struct {
+	union json.RawMessage
+}
+
+// GraphQLRequest defines model for GraphQLRequest.
+type GraphQLRequest struct {
+	// OperationName The operation to run, if the document has several.
+	OperationName *string `json:"operationName,omitempty"`
+
+	// Query A GraphQL query document.
+	Query string `json:"query"`
+
+	// Variables Values of the operation's variables.
+	Variables *map[string]interface{} `json:"variables,omitempty"`
+}
+
+// GraphQLResponse defines model for GraphQLResponse.
+type GraphQLResponse struct {
+	// Data The selected fields, absent if the request could not be run and null if a non-null field failed.
+	Data   *map[string]interface{} `json:"data"`
+	Errors *[]GraphQLError         `json:"errors,omitempty"`
+}
+
+// ImagesEnvelope defines model for ImagesEnvelope.
+type ImagesEnvelope struct {
+	Data []File `json:"data"`
+
+	// NextCursor Pass as cursor to get the next page. Absent on the last page.
+	NextCursor *string `json:"next_cursor,omitempty"`
+}
+
+// Job defines model for Job.
+type Job struct {
+	Duration *string    `json:"duration,omitempty"`
+	Enabled  *bool      `json:"enabled,omitempty"`
+	Error    *string    `json:"error,omitempty"`
+	LastRun  *time.Time `json:"last_run,omitempty"`
+	Name     *string    `json:"name,omitempty"`
+	NextRun  *time.Time `json:"next_run,omitempty"`
+	Result   *JobResult `json:"result,omitempty"`
+	Running  *bool      `json:"running,omitempty"`
+	Runs     *int       `json:"runs,omitempty"`
+
+	// Schedule Cron expression, or off if the job is disabled.
+	Schedule *string `json:"schedule,omitempty"`
+}
+
+// JobResult defines model for Job.Result.
+type JobResult string
+
+// OEmbed defines model for OEmbed.
+type OEmbed struct {
+	AuthorName   *string    `json:"author_name,omitempty"`
+	AuthorUrl    *string    `json:"author_url,omitempty"`
+	Height       int        `json:"height"`
+	ProviderName *string    `json:"provider_name,omitempty"`
+	ProviderUrl  *string    `json:"provider_url,omitempty"`
+	Title        *string    `json:"title,omitempty"`
+	Type         OEmbedType `json:"type"`
+	Url          string     `json:"url"`
+	Version      string     `json:"version"`
+	Width        int        `json:"width"`
+}
+
+// OEmbedType defines model for OEmbed.Type.
+type OEmbedType string
+
+// Readiness defines model for Readiness.
+type Readiness struct {
+	Checks *map[string]struct {
+		Error   *string                `json:"error,omitempty"`
+		Latency *string                `json:"latency,omitempty"`
+		Status  *ReadinessChecksStatus `json:"status,omitempty"`
+	} `json:"checks,omitempty"`
+	Status *ReadinessStatus `json:"status,omitempty"`
+}
+
+// ReadinessChecksStatus defines model for Readiness.Checks.Status.
+type ReadinessChecksStatus string
+
+// ReadinessStatus defines model for Readiness.Status.
+type ReadinessStatus string
+
+// Stats defines model for Stats.
+type Stats struct {
+	Count     *int            `json:"count,omitempty"`
+	Formats   *map[string]int `json:"formats,omitempty"`
+	TotalSize *int64          `json:"total_size,omitempty"`
+}
+
+// StatsEnvelope defines model for StatsEnvelope.
+type StatsEnvelope struct {
+	Data Stats `json:"data"`
+}
+
+// VideoInfo Set for video wallpapers. Fields are absent if the video could not be probed.
+type VideoInfo struct {
+	// Duration Length in seconds.
+	Duration *float32 `json:"duration,omitempty"`
+	Height   *int     `json:"height,omitempty"`
+	Width    *int     `json:"width,omitempty"`
+}
+
+// WallhavenWallpaper defines model for WallhavenWallpaper.
+type WallhavenWallpaper struct {
+	Category   *string   `json:"category,omitempty"`
+	Colors     *[]string `json:"colors,omitempty"`
+	CreatedAt  *string   `json:"created_at,omitempty"`
+	DimensionX *int      `json:"dimension_x,omitempty"`
+	DimensionY *int      `json:"dimension_y,omitempty"`
+	Favorites  *int      `json:"favorites,omitempty"`
+	FileSize   *int      `json:"file_size,omitempty"`
+	FileType   *string   `json:"file_type,omitempty"`
+	Id         *string   `json:"id,omitempty"`
+	Path       *string   `json:"path,omitempty"`
+	Purity     *string   `json:"purity,omitempty"`
+	Ratio      *string   `json:"ratio,omitempty"`
+	Resolution *string   `json:"resolution,omitempty"`
+	ShortUrl   *string   `json:"short_url,omitempty"`
+	Source     *string   `json:"source,omitempty"`
+	Tags       *[]string `json:"tags,omitempty"`
+	Thumbs     *struct {
+		Large    *string `json:"large,omitempty"`
+		Original *string `json:"original,omitempty"`
+		Small    *string `json:"small,omitempty"`
+	} `json:"thumbs,omitempty"`
+	Url   *string `json:"url,omitempty"`
+	Views *int    `json:"views,omitempty"`
+}
+
+// Collection defines model for Collection.
+type Collection = string
+
+// Cursor defines model for Cursor.
+type Cursor = string
+
+// DPR defines model for DPR.
+type DPR = float32
+
+// Fields defines model for Fields.
+type Fields = []string
+
+// Height defines model for Height.
+type Height = int
+
+// Limit defines model for Limit.
+type Limit = int
+
+// Name defines model for Name.
+type Name = string
+
+// Order defines model for Order.
+type Order string
+
+// Sort defines model for Sort.
+type Sort string
+
+// Type defines model for Type.
+type Type string
+
+// Variants defines model for Variants.
+type Variants string
+
+// Width defines model for Width.
+type Width = int
+
+// V1Error defines model for V1Error.
+type V1Error = ErrorEnvelope
+
+// ListImagesParams defines parameters for ListImages.
+type ListImagesParams struct {
+	Sort *ListImagesParamsSort `form:"sort,omitempty" json:"sort,omitempty"`
+
+	// Order Defaults to asc when sorting by name and desc otherwise.
+	Order *ListImagesParamsOrder `form:"order,omitempty" json:"order,omitempty"`
+
+	// Type Only return wallpapers of this media type.
+	Type *ListImagesParamsType `form:"type,omitempty" json:"type,omitempty"`
+
+	// Variants Lower resolution copies of the same artwork are hidden unless this is all.
+	Variants *ListImagesParamsVariants `form:"variants,omitempty" json:"variants,omitempty"`
+
+	// Collection Name of a public collection. Defaults to the main collection; unknown names return 404.
+	Collection *Collection `form:"collection,omitempty" json:"collection,omitempty"`
+
+	// Fields Comma separated File fields to return.
+	Fields *Fields `form:"fields,omitempty" json:"fields,omitempty"`
+}
+
+// ListImagesParamsSort defines parameters for ListImages.
+type ListImagesParamsSort string
+
+// ListImagesParamsOrder defines parameters for ListImages.
+type ListImagesParamsOrder string
+
+// ListImagesParamsType defines parameters for ListImages.
+type ListImagesParamsType string
+
+// ListImagesParamsVariants defines parameters for ListImages.
+type ListImagesParamsVariants string
+
+// ListImagesParamsFields defines parameters for ListImages.
+type ListImagesParamsFields string
+
+// WallhavenSearchParams defines parameters for WallhavenSearch.
+type WallhavenSearchParams struct {
+	Q           *string                       `form:"q,omitempty" json:"q,omitempty"`
+	Sorting     *WallhavenSearchParamsSorting `form:"sorting,omitempty" json:"sorting,omitempty"`
+	Order       *WallhavenSearchParamsOrder   `form:"order,omitempty" json:"order,omitempty"`
+	Seed        *string                       `form:"seed,omitempty" json:"seed,omitempty"`
+	Atleast     *string                       `form:"atleast,omitempty" json:"atleast,omitempty"`
+	Resolutions *string                       `form:"resolutions,omitempty" json:"resolutions,omitempty"`
+	Ratios      *string                       `form:"ratios,omitempty" json:"ratios,omitempty"`
+	Page        *int                          `form:"page,omitempty" json:"page,omitempty"`
+
+	// Variants Lower resolution copies of the same artwork are hidden unless this is all.
+	Variants *WallhavenSearchParamsVariants `form:"variants,omitempty" json:"variants,omitempty"`
+}
+
+// WallhavenSearchParamsSorting defines parameters for WallhavenSearch.
+type WallhavenSearchParamsSorting string
+
+// WallhavenSearchParamsOrder defines parameters for WallhavenSearch.
+type WallhavenSearchParamsOrder string
+
+// WallhavenSearchParamsVariants defines parameters for WallhavenSearch.
+type WallhavenSearchParamsVariants string
+
+// GetArchiveParams defines parameters for GetArchive.
+type GetArchiveParams struct {
+	Name []string `form:"name" json:"name"`
+}
+
+// PostArchiveJSONBody defines parameters for PostArchive.
+type PostArchiveJSONBody struct {
+	Names []string `json:"names"`
+}
+
+// AuditParams defines parameters for Audit.
+type AuditParams struct {
+	// Name Only changes to this file.
+	Name *string `form:"name,omitempty" json:"name,omitempty"`
+
+	// Since Only changes made at or after this time.
+	Since *time.Time `form:"since,omitempty" json:"since,omitempty"`
+
+	// Limit Most entries to return.
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// DownloadImageParams defines parameters for DownloadImage.
+type DownloadImageParams struct {
+	// Collection Name of a public collection. Defaults to the main collection; unknown names return 404.
+	Collection *Collection `form:"collection,omitempty" json:"collection,omitempty"`
+}
+
+// DownloadImageHeadParams defines parameters for DownloadImageHead.
+type DownloadImageHeadParams struct {
+	// Collection Name of a public collection. Defaults to the main collection; unknown names return 404.
+	Collection *Collection `form:"collection,omitempty" json:"collection,omitempty"`
+}
+
+// EinkImageParams defines parameters for EinkImage.
+type EinkImageParams struct {
+	W int `form:"w" json:"w"`
+	H int `form:"h" json:"h"`
+
+	// Levels Number of gray levels.
+	Levels *int `form:"levels,omitempty" json:"levels,omitempty"`
+}
+
+// StreamEventsParams defines parameters for StreamEvents.
+type StreamEventsParams struct {
+	// Collection Name of a public collection. Defaults to the main collection; unknown names return 404.
+	Collection *Collection `form:"collection,omitempty" json:"collection,omitempty"`
+}
+
+// FitDailyImageParams defines parameters for FitDailyImage.
+type FitDailyImageParams struct {
+	W   Width  `form:"w" json:"w"`
+	H   Height `form:"h" json:"h"`
+	Dpr *DPR   `form:"dpr,omitempty" json:"dpr,omitempty"`
+}
+
+// FitRandomImageParams defines parameters for FitRandomImage.
+type FitRandomImageParams struct {
+	W   Width  `form:"w" json:"w"`
+	H   Height `form:"h" json:"h"`
+	Dpr *DPR   `form:"dpr,omitempty" json:"dpr,omitempty"`
+}
+
+// FitImageParams defines parameters for FitImage.
+type FitImageParams struct {
+	W   Width  `form:"w" json:"w"`
+	H   Height `form:"h" json:"h"`
+	Dpr *DPR   `form:"dpr,omitempty" json:"dpr,omitempty"`
+}
+
+// GraphqlQueryParams defines parameters for GraphqlQuery.
+type GraphqlQueryParams struct {
+	Query         string  `form:"query" json:"query"`
+	OperationName *string `form:"operationName,omitempty" json:"operationName,omitempty"`
+
+	// Variables Variables as a JSON object.
+	Variables *string `form:"variables,omitempty" json:"variables,omitempty"`
+}
+
+// IiifImageParamsQuality defines parameters for IiifImage.
+type IiifImageParamsQuality string
+
+// IiifImageParamsFormat defines parameters for IiifImage.
+type IiifImageParamsFormat string
+
+// ImagePageParams defines parameters for ImagePage.
+type ImagePageParams struct {
+	// Collection Name of a public collection. Defaults to the main collection; unknown names return 404.
+	Collection *Collection `form:"collection,omitempty" json:"collection,omitempty"`
+}
+
+// GetImageThumbnailParams defines parameters for GetImageThumbnail.
+type GetImageThumbnailParams struct {
+	// Collection Name of a public collection. Defaults to the main collection; unknown names return 404.
+	Collection *Collection `form:"collection,omitempty" json:"collection,omitempty"`
+}
+
+// GetImageOriginalParams defines parameters for GetImageOriginal.
+type GetImageOriginalParams struct {
+	// Collection Name of a public collection. Defaults to the main collection; unknown names return 404.
+	Collection *Collection `form:"collection,omitempty" json:"collection,omitempty"`
+}
+
+// GetImageOriginalHeadParams defines parameters for GetImageOriginalHead.
+type GetImageOriginalHeadParams struct {
+	// Collection Name of a public collection. Defaults to the main collection; unknown names return 404.
+	Collection *Collection `form:"collection,omitempty" json:"collection,omitempty"`
+}
+
+// OembedParams defines parameters for Oembed.
+type OembedParams struct {
+	Url       string              `form:"url" json:"url"`
+	Maxwidth  *int                `form:"maxwidth,omitempty" json:"maxwidth,omitempty"`
+	Maxheight *int                `form:"maxheight,omitempty" json:"maxheight,omitempty"`
+	Format    *OembedParamsFormat `form:"format,omitempty" json:"format,omitempty"`
+}
+
+// OembedParamsFormat defines parameters for Oembed.
+type OembedParamsFormat string
+
+// PlaylistParams defines parameters for Playlist.
+type PlaylistParams struct {
+	// Q Words that must all appear in the name or author.
+	Q      *string               `form:"q,omitempty" json:"q,omitempty"`
+	Format *PlaylistParamsFormat `form:"format,omitempty" json:"format,omitempty"`
+
+	// W Width in CSS pixels. Requires h.
+	W *int `form:"w,omitempty" json:"w,omitempty"`
+
+	// H Height in CSS pixels. Requires w.
+	H    *int                `form:"h,omitempty" json:"h,omitempty"`
+	Dpr  *DPR                `form:"dpr,omitempty" json:"dpr,omitempty"`
+	Sort *PlaylistParamsSort `form:"sort,omitempty" json:"sort,omitempty"`
+
+	// Order Defaults to asc when sorting by name and desc otherwise.
+	Order *PlaylistParamsOrder `form:"order,omitempty" json:"order,omitempty"`
+
+	// Variants Lower resolution copies of the same artwork are hidden unless this is all.
+	Variants *PlaylistParamsVariants `form:"variants,omitempty" json:"variants,omitempty"`
+
+	// Collection Name of a public collection. Defaults to the main collection; unknown names return 404.
+	Collection *Collection `form:"collection,omitempty" json:"collection,omitempty"`
+}
+
+// PlaylistParamsFormat defines parameters for Playlist.
+type PlaylistParamsFormat string
+
+// PlaylistParamsSort defines parameters for Playlist.
+type PlaylistParamsSort string
+
+// PlaylistParamsOrder defines parameters for Playlist.
+type PlaylistParamsOrder string
+
+// PlaylistParamsVariants defines parameters for Playlist.
+type PlaylistParamsVariants string
+
+// PreviewImageParams defines parameters for PreviewImage.
+type PreviewImageParams struct {
+	Device *PreviewImageParamsDevice `form:"device,omitempty" json:"device,omitempty"`
+}
+
+// PreviewImageParamsDevice defines parameters for PreviewImage.
+type PreviewImageParamsDevice string
+
+// SitemapParams defines parameters for Sitemap.
+type SitemapParams struct {
+	// Collection Name of a public collection. Defaults to the main collection; unknown names return 404.
+	Collection *Collection `form:"collection,omitempty" json:"collection,omitempty"`
+}
+
+// GetStatsParams defines parameters for GetStats.
+type GetStatsParams struct {
+	// Collection Name of a public collection. Defaults to the main collection; unknown names return 404.
+	Collection *Collection `form:"collection,omitempty" json:"collection,omitempty"`
+}
+
+// CreateUploadParams defines parameters for CreateUpload.
+type CreateUploadParams struct {
+	// TusResumable The tus protocol version, which must be 1.0.0.
+	TusResumable CreateUploadParamsTusResumable `json:"Tus-Resumable"`
+
+	// UploadLength Size of the whole upload in bytes.
+	UploadLength int64 `json:"Upload-Length"`
+
+	// UploadMetadata Comma separated keys and base64 values; filename names the wallpaper.
+	UploadMetadata string `json:"Upload-Metadata"`
+}
+
+// CreateUploadParamsTusResumable defines parameters for CreateUpload.
+type CreateUploadParamsTusResumable string
+
+// DeleteUploadParams defines parameters for DeleteUpload.
+type DeleteUploadParams struct {
+	// TusResumable The tus protocol version, which must be 1.0.0.
+	TusResumable DeleteUploadParamsTusResumable `json:"Tus-Resumable"`
+}
+
+// DeleteUploadParamsTusResumable defines parameters for DeleteUpload.
+type DeleteUploadParamsTusResumable string
+
+// UploadOffsetParams defines parameters for UploadOffset.
+type UploadOffsetParams struct {
+	// TusResumable The tus protocol version, which must be 1.0.0.
+	TusResumable UploadOffsetParamsTusResumable `json:"Tus-Resumable"`
+}
+
+// UploadOffsetParamsTusResumable defines parameters for UploadOffset.
+type UploadOffsetParamsTusResumable string
+
+// AppendUploadParams defines parameters for AppendUpload.
+type AppendUploadParams struct {
+	// TusResumable The tus protocol version, which must be 1.0.0.
+	TusResumable AppendUploadParamsTusResumable `json:"Tus-Resumable"`
+
+	// UploadOffset Where the body starts, which must be the upload's current offset.
+	UploadOffset int64 `json:"Upload-Offset"`
+}
+
+// AppendUploadParamsTusResumable defines parameters for AppendUpload.
+type AppendUploadParamsTusResumable string
+
+// V1GetArchiveParams defines parameters for V1GetArchive.
+type V1GetArchiveParams struct {
+	Name []string `form:"name" json:"name"`
+}
+
+// V1PostArchiveJSONBody defines parameters for V1PostArchive.
+type V1PostArchiveJSONBody struct {
+	Names []string `json:"names"`
+}
+
+// V1StreamEventsParams defines parameters for V1StreamEvents.
+type V1StreamEventsParams struct {
+	// Collection Name of a public collection. Defaults to the main collection; unknown names return 404.
+	Collection *Collection `form:"collection,omitempty" json:"collection,omitempty"`
+}
+
+// V1FitDailyImageParams defines parameters for V1FitDailyImage.
+type V1FitDailyImageParams struct {
+	W   Width  `form:"w" json:"w"`
+	H   Height `form:"h" json:"h"`
+	Dpr *DPR   `form:"dpr,omitempty" json:"dpr,omitempty"`
+}
+
+// V1FitRandomImageParams defines parameters for V1FitRandomImage.
+type V1FitRandomImageParams struct {
+	W   Width  `form:"w" json:"w"`
+	H   Height `form:"h" json:"h"`
+	Dpr *DPR   `form:"dpr,omitempty" json:"dpr,omitempty"`
+}
+
+// V1FitImageParams defines parameters for V1FitImage.
+type V1FitImageParams struct {
+	W   Width  `form:"w" json:"w"`
+	H   Height `form:"h" json:"h"`
+	Dpr *DPR   `form:"dpr,omitempty" json:"dpr,omitempty"`
+}
+
+// V1ListImagesParams defines parameters for V1ListImages.
+type V1ListImagesParams struct {
+	Limit  *Limit                  `form:"limit,omitempty" json:"limit,omitempty"`
+	Cursor *Cursor                 `form:"cursor,omitempty" json:"cursor,omitempty"`
+	Sort   *V1ListImagesParamsSort `form:"sort,omitempty" json:"sort,omitempty"`
+
+	// Order Defaults to asc when sorting by name and desc otherwise.
+	Order *V1ListImagesParamsOrder `form:"order,omitempty" json:"order,omitempty"`
+
+	// Type Only return wallpapers of this media type.
+	Type *V1ListImagesParamsType `form:"type,omitempty" json:"type,omitempty"`
+
+	// Variants Lower resolution copies of the same artwork are hidden unless this is all.
+	Variants *V1ListImagesParamsVariants `form:"variants,omitempty" json:"variants,omitempty"`
+
+	// Q Only return wallpapers matching this search, as GraphQL and gRPC search do.
+	Q *string `form:"q,omitempty" json:"q,omitempty"`
+
+	// Collection Name of a public collection. Defaults to the main collection; unknown names return 404.
+	Collection *Collection `form:"collection,omitempty" json:"collection,omitempty"`
+
+	// Fields Comma separated File fields to return.
+	Fields *Fields `form:"fields,omitempty" json:"fields,omitempty"`
+}
+
+// V1ListImagesParamsSort defines parameters for V1ListImages.
+type V1ListImagesParamsSort string
+
+// V1ListImagesParamsOrder defines parameters for V1ListImages.
+type V1ListImagesParamsOrder string
+
+// V1ListImagesParamsType defines parameters for V1ListImages.
+type V1ListImagesParamsType string
+
+// V1ListImagesParamsVariants defines parameters for V1ListImages.
+type V1ListImagesParamsVariants string
+
+// V1ListImagesParamsFields defines parameters for V1ListImages.
+type V1ListImagesParamsFields string
+
+// V1GetStatsParams defines parameters for V1GetStats.
+type V1GetStatsParams struct {
+	// Collection Name of a public collection. Defaults to the main collection; unknown names return 404.
+	Collection *Collection `form:"collection,omitempty" json:"collection,omitempty"`
+}
+
+// PostArchiveJSONRequestBody defines body for PostArchive for application/json ContentType.
+type PostArchiveJSONRequestBody PostArchiveJSONBody
+
+// GraphqlPostJSONRequestBody defines body for GraphqlPost for application/json ContentType.
+type GraphqlPostJSONRequestBody = GraphQLRequest
+
+// V1PostArchiveJSONRequestBody defines body for V1PostArchive for application/json ContentType.
+type V1PostArchiveJSONRequestBody V1PostArchiveJSONBody
+
+// AsGraphQLErrorPath0 returns the union data inside the GraphQLError_Path_Item as a GraphQLErrorPath0
+func (t GraphQLError_Path_Item) AsGraphQLErrorPath0() (GraphQLErrorPath0, error) {
+	var body GraphQLErrorPath0
+	err := json.Unmarshal(t.union, &body)
+	return body, err
+}
+
+// FromGraphQLErrorPath0 overwrites any union data inside the GraphQLError_Path_Item as the provided GraphQLErrorPath0
+func (t *GraphQLError_Path_Item) FromGraphQLErrorPath0(v GraphQLErrorPath0) error {
+	b, err := json.Marshal(v)
+	t.union = b
+	return err
+}
+
+// MergeGraphQLErrorPath0 performs a merge with any union data inside the GraphQLError_Path_Item, using the provided GraphQLErrorPath0
+func (t *GraphQLError_Path_Item) MergeGraphQLErrorPath0(v GraphQLErrorPath0) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	merged, err := runtime.JSONMerge(t.union, b)
+	t.union = merged
+	return err
+}
+
+// AsGraphQLErrorPath1 returns the union data inside the GraphQLError_Path_Item as a GraphQLErrorPath1
+func (t GraphQLError_Path_Item) AsGraphQLErrorPath1() (GraphQLErrorPath1, error) {
+	var body GraphQLErrorPath1
+	err := json.Unmarshal(t.union, &body)
+	return body, err
+}
+
+// FromGraphQLErrorPath1 overwrites any union data inside the GraphQLError_Path_Item as the provided GraphQLErrorPath1
+func (t *GraphQLError_Path_Item) FromGraphQLErrorPath1(v GraphQLErrorPath1) error {
+	b, err := json.Marshal(v)
+	t.union = b
+	return err
+}
+
+// MergeGraphQLErrorPath1 performs a merge with any union data inside the GraphQLError_Path_Item, using the provided GraphQLErrorPath1
+func (t *GraphQLError_Path_Item) MergeGraphQLErrorPath1(v GraphQLErrorPath1) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	merged, err := runtime.JSONMerge(t.union, b)
+	t.union = merged
+	return err
+}
+
+func (t GraphQLError_Path_Item) MarshalJSON() ([]byte, error) {
+	b, err := t.union.MarshalJSON()
+	return b, err
+}
+
+func (t *GraphQLError_Path_Item) UnmarshalJSON(b []byte) error {
+	err := t.union.UnmarshalJSON(b)
+	return err
+}
+
+// RequestEditorFn  is the function signature for the RequestEditor callback function
+type RequestEditorFn func(ctx context.Context, req *http.Request) error
+
+// Doer performs HTTP requests.
+//
+// The standard http.Client implements this interface.
+type HttpRequestDoer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Client which conforms to the OpenAPI3 specification for this service.
+type Client struct {
+	// The endpoint of the server conforming to this interface, with scheme,
+	// https://api.deepmap.com for example. This can contain a path relative
+	// to the server, such as https://api.deepmap.com/dev-test, and all the
+	// paths in the swagger spec will be appended to the server.
+	Server string
+
+	// Doer for performing requests, typically a *http.Client with any
+	// customized settings, such as certificate chains.
+	Client HttpRequestDoer
+
+	// A list of callbacks for modifying requests which are generated before sending over
+	// the network.
+	RequestEditors []RequestEditorFn
+}
+
+// ClientOption allows setting custom parameters during construction
+type ClientOption func(*Client) error
+
+// Creates a new Client, with reasonable defaults
+func NewClient(server string, opts ...ClientOption) (*Client, error) {
+	// create a client with sane default values
+	client := Client{
+		Server: server,
+	}
+	// mutate client and add all optional params
+	for _, o := range opts {
+		if err := o(&client); err != nil {
+			return nil, err
+		}
+	}
+	// ensure the server URL always has a trailing slash
+	if !strings.HasSuffix(client.Server, "/") {
+		client.Server += "/"
+	}
+	// create httpClient, if not already present
+	if client.Client == nil {
+		client.Client = &http.Client{}
+	}
+	return &client, nil
+}
+
+// WithHTTPClient allows overriding the default Doer, which is
+// automatically created using http.Client. This is useful for tests.
+func WithHTTPClient(doer HttpRequestDoer) ClientOption {
+	return func(c *Client) error {
+		c.Client = doer
+		return nil
+	}
+}
+
+// WithRequestEditorFn allows setting up a callback function, which will be
+// called right before sending the request. This can be used to mutate the request.
+func WithRequestEditorFn(fn RequestEditorFn) ClientOption {
+	return func(c *Client) error {
+		c.RequestEditors = append(c.RequestEditors, fn)
+		return nil
+	}
+}
+
+// The interface specification for the client above.
+type ClientInterface interface {
+	// ListImages request
+	ListImages(ctx context.Context, params *ListImagesParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// WallhavenSearch request
+	WallhavenSearch(ctx context.Context, params *WallhavenSearchParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// WallhavenWallpaper request
+	WallhavenWallpaper(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetArchive request
+	GetArchive(ctx context.Context, params *GetArchiveParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// PostArchiveWithBody request with any body
+	PostArchiveWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	PostArchive(ctx context.Context, body PostArchiveJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// Audit request
+	Audit(ctx context.Context, params *AuditParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// DownloadImage request
+	DownloadImage(ctx context.Context, name Name, params *DownloadImageParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// DownloadImageHead request
+	DownloadImageHead(ctx context.Context, name Name, params *DownloadImageHeadParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// Downloads request
+	Downloads(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// EinkImage request
+	EinkImage(ctx context.Context, name Name, params *EinkImageParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// StreamEvents request
+	StreamEvents(ctx context.Context, params *StreamEventsParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// FitDailyImage request
+	FitDailyImage(ctx context.Context, params *FitDailyImageParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// FitRandomImage request
+	FitRandomImage(ctx context.Context, params *FitRandomImageParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// FitImage request
+	FitImage(ctx context.Context, name Name, params *FitImageParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GraphqlQuery request
+	GraphqlQuery(ctx context.Context, params *GraphqlQueryParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GraphqlPostWithBody request with any body
+	GraphqlPostWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	GraphqlPost(ctx context.Context, body GraphqlPostJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// Healthz request
+	Healthz(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// IiifBase request
+	IiifBase(ctx context.Context, name Name, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// IiifInfo request
+	IiifInfo(ctx context.Context, name Name, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// IiifImage request
+	IiifImage(ctx context.Context, name Name, region string, size string, rotation string, quality IiifImageParamsQuality, format IiifImageParamsFormat, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// ImagePage request
+	ImagePage(ctx context.Context, name Name, params *ImagePageParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetImageThumbnail request
+	GetImageThumbnail(ctx context.Context, hash string, name Name, params *GetImageThumbnailParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetImageOriginal request
+	GetImageOriginal(ctx context.Context, hash string, name Name, params *GetImageOriginalParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetImageOriginalHead request
+	GetImageOriginalHead(ctx context.Context, hash string, name Name, params *GetImageOriginalHeadParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// Jobs request
+	Jobs(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// Oembed request
+	Oembed(ctx context.Context, params *OembedParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// Playlist request
+	Playlist(ctx context.Context, params *PlaylistParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// PreviewImage request
+	PreviewImage(ctx context.Context, name Name, params *PreviewImageParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// Readyz request
+	Readyz(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// Sitemap request
+	Sitemap(ctx context.Context, params *SitemapParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// GetStats request
+	GetStats(ctx context.Context, params *GetStatsParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// Usage request
+	Usage(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// UploadOptions request
+	UploadOptions(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// CreateUpload request
+	CreateUpload(ctx context.Context, params *CreateUploadParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// DeleteUpload request
+	DeleteUpload(ctx context.Context, id string, params *DeleteUploadParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// UploadOffset request
+	UploadOffset(ctx context.Context, id string, params *UploadOffsetParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// AppendUploadWithBody request with any body
+	AppendUploadWithBody(ctx context.Context, id string, params *AppendUploadParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// V1GetArchive request
+	V1GetArchive(ctx context.Context, params *V1GetArchiveParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// V1PostArchiveWithBody request with any body
+	V1PostArchiveWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	V1PostArchive(ctx context.Context, body V1PostArchiveJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// V1StreamEvents request
+	V1StreamEvents(ctx context.Context, params *V1StreamEventsParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// V1FitDailyImage request
+	V1FitDailyImage(ctx context.Context, params *V1FitDailyImageParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// V1FitRandomImage request
+	V1FitRandomImage(ctx context.Context, params *V1FitRandomImageParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// V1FitImage request
+	V1FitImage(ctx context.Context, name Name, params *V1FitImageParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// V1ListImages request
+	V1ListImages(ctx context.Context, params *V1ListImagesParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// V1DeleteImage request
+	V1DeleteImage(ctx context.Context, name Name, reqEditors ...RequestEditorFn) (*http.Response, error)
+
+	// V1GetStats request
+	V1GetStats(ctx context.Context, params *V1GetStatsParams, reqEditors ...RequestEditorFn) (*http.Response, error)
+}
+
+func (c *Client) ListImages(ctx context.Context, params *ListImagesParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewListImagesRequest(c.Server, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) WallhavenSearch(ctx context.Context, params *WallhavenSearchParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewWallhavenSearchRequest(c.Server, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) WallhavenWallpaper(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewWallhavenWallpaperRequest(c.Server, id)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetArchive(ctx context.Context, params *GetArchiveParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetArchiveRequest(c.Server, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PostArchiveWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPostArchiveRequestWithBody(c.Server, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PostArchive(ctx context.Context, body PostArchiveJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPostArchiveRequest(c.Server, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) Audit(ctx context.Context, params *AuditParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewAuditRequest(c.Server, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) DownloadImage(ctx context.Context, name Name, params *DownloadImageParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewDownloadImageRequest(c.Server, name, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) DownloadImageHead(ctx context.Context, name Name, params *DownloadImageHeadParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewDownloadImageHeadRequest(c.Server, name, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) Downloads(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewDownloadsRequest(c.Server)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) EinkImage(ctx context.Context, name Name, params *EinkImageParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewEinkImageRequest(c.Server, name, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) StreamEvents(ctx context.Context, params *StreamEventsParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewStreamEventsRequest(c.Server, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) FitDailyImage(ctx context.Context, params *FitDailyImageParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewFitDailyImageRequest(c.Server, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) FitRandomImage(ctx context.Context, params *FitRandomImageParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewFitRandomImageRequest(c.Server, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) FitImage(ctx context.Context, name Name, params *FitImageParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewFitImageRequest(c.Server, name, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GraphqlQuery(ctx context.Context, params *GraphqlQueryParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGraphqlQueryRequest(c.Server, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GraphqlPostWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGraphqlPostRequestWithBody(c.Server, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GraphqlPost(ctx context.Context, body GraphqlPostJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGraphqlPostRequest(c.Server, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) Healthz(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewHealthzRequest(c.Server)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) IiifBase(ctx context.Context, name Name, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewIiifBaseRequest(c.Server, name)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) IiifInfo(ctx context.Context, name Name, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewIiifInfoRequest(c.Server, name)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) IiifImage(ctx context.Context, name Name, region string, size string, rotation string, quality IiifImageParamsQuality, format IiifImageParamsFormat, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewIiifImageRequest(c.Server, name, region, size, rotation, quality, format)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) ImagePage(ctx context.Context, name Name, params *ImagePageParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewImagePageRequest(c.Server, name, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetImageThumbnail(ctx context.Context, hash string, name Name, params *GetImageThumbnailParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetImageThumbnailRequest(c.Server, hash, name, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetImageOriginal(ctx context.Context, hash string, name Name, params *GetImageOriginalParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetImageOriginalRequest(c.Server, hash, name, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetImageOriginalHead(ctx context.Context, hash string, name Name, params *GetImageOriginalHeadParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetImageOriginalHeadRequest(c.Server, hash, name, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) Jobs(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewJobsRequest(c.Server)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) Oembed(ctx context.Context, params *OembedParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewOembedRequest(c.Server, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) Playlist(ctx context.Context, params *PlaylistParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPlaylistRequest(c.Server, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) PreviewImage(ctx context.Context, name Name, params *PreviewImageParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewPreviewImageRequest(c.Server, name, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) Readyz(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewReadyzRequest(c.Server)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) Sitemap(ctx context.Context, params *SitemapParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewSitemapRequest(c.Server, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) GetStats(ctx context.Context, params *GetStatsParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewGetStatsRequest(c.Server, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) Usage(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewUsageRequest(c.Server)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) UploadOptions(ctx context.Context, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewUploadOptionsRequest(c.Server)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) CreateUpload(ctx context.Context, params *CreateUploadParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewCreateUploadRequest(c.Server, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) DeleteUpload(ctx context.Context, id string, params *DeleteUploadParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewDeleteUploadRequest(c.Server, id, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) UploadOffset(ctx context.Context, id string, params *UploadOffsetParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewUploadOffsetRequest(c.Server, id, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) AppendUploadWithBody(ctx context.Context, id string, params *AppendUploadParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewAppendUploadRequestWithBody(c.Server, id, params, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) V1GetArchive(ctx context.Context, params *V1GetArchiveParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewV1GetArchiveRequest(c.Server, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) V1PostArchiveWithBody(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewV1PostArchiveRequestWithBody(c.Server, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) V1PostArchive(ctx context.Context, body V1PostArchiveJSONRequestBody, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewV1PostArchiveRequest(c.Server, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) V1StreamEvents(ctx context.Context, params *V1StreamEventsParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewV1StreamEventsRequest(c.Server, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) V1FitDailyImage(ctx context.Context, params *V1FitDailyImageParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewV1FitDailyImageRequest(c.Server, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) V1FitRandomImage(ctx context.Context, params *V1FitRandomImageParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewV1FitRandomImageRequest(c.Server, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) V1FitImage(ctx context.Context, name Name, params *V1FitImageParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewV1FitImageRequest(c.Server, name, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) V1ListImages(ctx context.Context, params *V1ListImagesParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewV1ListImagesRequest(c.Server, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) V1DeleteImage(ctx context.Context, name Name, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewV1DeleteImageRequest(c.Server, name)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+func (c *Client) V1GetStats(ctx context.Context, params *V1GetStatsParams, reqEditors ...RequestEditorFn) (*http.Response, error) {
+	req, err := NewV1GetStatsRequest(c.Server, params)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if err := c.applyEditors(ctx, req, reqEditors); err != nil {
+		return nil, err
+	}
+	return c.Client.Do(req)
+}
+
+// NewListImagesRequest generates requests for ListImages
+func NewListImagesRequest(server string, params *ListImagesParams) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/all.json")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if params.Sort != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "sort", runtime.ParamLocationQuery, *params.Sort); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Order != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "order", runtime.ParamLocationQuery, *params.Order); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Type != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "type", runtime.ParamLocationQuery, *params.Type); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Variants != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "variants", runtime.ParamLocationQuery, *params.Variants); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Collection != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "collection", runtime.ParamLocationQuery, *params.Collection); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Fields != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", false, "fields", runtime.ParamLocationQuery, *params.Fields); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewWallhavenSearchRequest generates requests for WallhavenSearch
+func NewWallhavenSearchRequest(server string, params *WallhavenSearchParams) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/api/v1/search")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if params.Q != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "q", runtime.ParamLocationQuery, *params.Q); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Sorting != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "sorting", runtime.ParamLocationQuery, *params.Sorting); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Order != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "order", runtime.ParamLocationQuery, *params.Order); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Seed != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "seed", runtime.ParamLocationQuery, *params.Seed); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Atleast != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "atleast", runtime.ParamLocationQuery, *params.Atleast); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Resolutions != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "resolutions", runtime.ParamLocationQuery, *params.Resolutions); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Ratios != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "ratios", runtime.ParamLocationQuery, *params.Ratios); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Page != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "page", runtime.ParamLocationQuery, *params.Page); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Variants != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "variants", runtime.ParamLocationQuery, *params.Variants); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewWallhavenWallpaperRequest generates requests for WallhavenWallpaper
+func NewWallhavenWallpaperRequest(server string, id string) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/api/v1/w/%s", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewGetArchiveRequest generates requests for GetArchive
+func NewGetArchiveRequest(server string, params *GetArchiveParams) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/archive")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if queryFrag, err := runtime.StyleParamWithLocation("form", true, "name", runtime.ParamLocationQuery, params.Name); err != nil {
+			return nil, err
+		} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+			return nil, err
+		} else {
+			for k, v := range parsed {
+				for _, v2 := range v {
+					queryValues.Add(k, v2)
+				}
+			}
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewPostArchiveRequest calls the generic PostArchive builder with application/json body
+func NewPostArchiveRequest(server string, body PostArchiveJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewPostArchiveRequestWithBody(server, "application/json", bodyReader)
+}
+
+// NewPostArchiveRequestWithBody generates requests for PostArchive with any type of body
+func NewPostArchiveRequestWithBody(server string, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/archive")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
+}
+
+// NewAuditRequest generates requests for Audit
+func NewAuditRequest(server string, params *AuditParams) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/audit")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if params.Name != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "name", runtime.ParamLocationQuery, *params.Name); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Since != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "since", runtime.ParamLocationQuery, *params.Since); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Limit != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "limit", runtime.ParamLocationQuery, *params.Limit); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewDownloadImageRequest generates requests for DownloadImage
+func NewDownloadImageRequest(server string, name Name, params *DownloadImageParams) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "name", runtime.ParamLocationPath, name)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/download/%s", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if params.Collection != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "collection", runtime.ParamLocationQuery, *params.Collection); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewDownloadImageHeadRequest generates requests for DownloadImageHead
+func NewDownloadImageHeadRequest(server string, name Name, params *DownloadImageHeadParams) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "name", runtime.ParamLocationPath, name)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/download/%s", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if params.Collection != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "collection", runtime.ParamLocationQuery, *params.Collection); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("HEAD", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewDownloadsRequest generates requests for Downloads
+func NewDownloadsRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/downloads.json")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewEinkImageRequest generates requests for EinkImage
+func NewEinkImageRequest(server string, name Name, params *EinkImageParams) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "name", runtime.ParamLocationPath, name)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/eink/%s", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if queryFrag, err := runtime.StyleParamWithLocation("form", true, "w", runtime.ParamLocationQuery, params.W); err != nil {
+			return nil, err
+		} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+			return nil, err
+		} else {
+			for k, v := range parsed {
+				for _, v2 := range v {
+					queryValues.Add(k, v2)
+				}
+			}
+		}
+
+		if queryFrag, err := runtime.StyleParamWithLocation("form", true, "h", runtime.ParamLocationQuery, params.H); err != nil {
+			return nil, err
+		} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+			return nil, err
+		} else {
+			for k, v := range parsed {
+				for _, v2 := range v {
+					queryValues.Add(k, v2)
+				}
+			}
+		}
+
+		if params.Levels != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "levels", runtime.ParamLocationQuery, *params.Levels); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewStreamEventsRequest generates requests for StreamEvents
+func NewStreamEventsRequest(server string, params *StreamEventsParams) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/events")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if params.Collection != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "collection", runtime.ParamLocationQuery, *params.Collection); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewFitDailyImageRequest generates requests for FitDailyImage
+func NewFitDailyImageRequest(server string, params *FitDailyImageParams) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/fit/daily")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if queryFrag, err := runtime.StyleParamWithLocation("form", true, "w", runtime.ParamLocationQuery, params.W); err != nil {
+			return nil, err
+		} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+			return nil, err
+		} else {
+			for k, v := range parsed {
+				for _, v2 := range v {
+					queryValues.Add(k, v2)
+				}
+			}
+		}
+
+		if queryFrag, err := runtime.StyleParamWithLocation("form", true, "h", runtime.ParamLocationQuery, params.H); err != nil {
+			return nil, err
+		} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+			return nil, err
+		} else {
+			for k, v := range parsed {
+				for _, v2 := range v {
+					queryValues.Add(k, v2)
+				}
+			}
+		}
+
+		if params.Dpr != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "dpr", runtime.ParamLocationQuery, *params.Dpr); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewFitRandomImageRequest generates requests for FitRandomImage
+func NewFitRandomImageRequest(server string, params *FitRandomImageParams) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/fit/random")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if queryFrag, err := runtime.StyleParamWithLocation("form", true, "w", runtime.ParamLocationQuery, params.W); err != nil {
+			return nil, err
+		} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+			return nil, err
+		} else {
+			for k, v := range parsed {
+				for _, v2 := range v {
+					queryValues.Add(k, v2)
+				}
+			}
+		}
+
+		if queryFrag, err := runtime.StyleParamWithLocation("form", true, "h", runtime.ParamLocationQuery, params.H); err != nil {
+			return nil, err
+		} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+			return nil, err
+		} else {
+			for k, v := range parsed {
+				for _, v2 := range v {
+					queryValues.Add(k, v2)
+				}
+			}
+		}
+
+		if params.Dpr != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "dpr", runtime.ParamLocationQuery, *params.Dpr); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewFitImageRequest generates requests for FitImage
+func NewFitImageRequest(server string, name Name, params *FitImageParams) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "name", runtime.ParamLocationPath, name)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/fit/%s", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if queryFrag, err := runtime.StyleParamWithLocation("form", true, "w", runtime.ParamLocationQuery, params.W); err != nil {
+			return nil, err
+		} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+			return nil, err
+		} else {
+			for k, v := range parsed {
+				for _, v2 := range v {
+					queryValues.Add(k, v2)
+				}
+			}
+		}
+
+		if queryFrag, err := runtime.StyleParamWithLocation("form", true, "h", runtime.ParamLocationQuery, params.H); err != nil {
+			return nil, err
+		} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+			return nil, err
+		} else {
+			for k, v := range parsed {
+				for _, v2 := range v {
+					queryValues.Add(k, v2)
+				}
+			}
+		}
+
+		if params.Dpr != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "dpr", runtime.ParamLocationQuery, *params.Dpr); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewGraphqlQueryRequest generates requests for GraphqlQuery
+func NewGraphqlQueryRequest(server string, params *GraphqlQueryParams) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/graphql")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if queryFrag, err := runtime.StyleParamWithLocation("form", true, "query", runtime.ParamLocationQuery, params.Query); err != nil {
+			return nil, err
+		} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+			return nil, err
+		} else {
+			for k, v := range parsed {
+				for _, v2 := range v {
+					queryValues.Add(k, v2)
+				}
+			}
+		}
+
+		if params.OperationName != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "operationName", runtime.ParamLocationQuery, *params.OperationName); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Variables != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "variables", runtime.ParamLocationQuery, *params.Variables); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewGraphqlPostRequest calls the generic GraphqlPost builder with application/json body
+func NewGraphqlPostRequest(server string, body GraphqlPostJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewGraphqlPostRequestWithBody(server, "application/json", bodyReader)
+}
+
+// NewGraphqlPostRequestWithBody generates requests for GraphqlPost with any type of body
+func NewGraphqlPostRequestWithBody(server string, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/graphql")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
+}
+
+// NewHealthzRequest generates requests for Healthz
+func NewHealthzRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/healthz")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewIiifBaseRequest generates requests for IiifBase
+func NewIiifBaseRequest(server string, name Name) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "name", runtime.ParamLocationPath, name)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/iiif/%s", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewIiifInfoRequest generates requests for IiifInfo
+func NewIiifInfoRequest(server string, name Name) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "name", runtime.ParamLocationPath, name)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/iiif/%s/info.json", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewIiifImageRequest generates requests for IiifImage
+func NewIiifImageRequest(server string, name Name, region string, size string, rotation string, quality IiifImageParamsQuality, format IiifImageParamsFormat) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "name", runtime.ParamLocationPath, name)
+	if err != nil {
+		return nil, err
+	}
+
+	var pathParam1 string
+
+	pathParam1, err = runtime.StyleParamWithLocation("simple", false, "region", runtime.ParamLocationPath, region)
+	if err != nil {
+		return nil, err
+	}
+
+	var pathParam2 string
+
+	pathParam2, err = runtime.StyleParamWithLocation("simple", false, "size", runtime.ParamLocationPath, size)
+	if err != nil {
+		return nil, err
+	}
+
+	var pathParam3 string
+
+	pathParam3, err = runtime.StyleParamWithLocation("simple", false, "rotation", runtime.ParamLocationPath, rotation)
+	if err != nil {
+		return nil, err
+	}
+
+	var pathParam4 string
+
+	pathParam4, err = runtime.StyleParamWithLocation("simple", false, "quality", runtime.ParamLocationPath, quality)
+	if err != nil {
+		return nil, err
+	}
+
+	var pathParam5 string
+
+	pathParam5, err = runtime.StyleParamWithLocation("simple", false, "format", runtime.ParamLocationPath, format)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/iiif/%s/%s/%s/%s/%s.%s", pathParam0, pathParam1, pathParam2, pathParam3, pathParam4, pathParam5)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewImagePageRequest generates requests for ImagePage
+func NewImagePageRequest(server string, name Name, params *ImagePageParams) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "name", runtime.ParamLocationPath, name)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/image/%s", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if params.Collection != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "collection", runtime.ParamLocationQuery, *params.Collection); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewGetImageThumbnailRequest generates requests for GetImageThumbnail
+func NewGetImageThumbnailRequest(server string, hash string, name Name, params *GetImageThumbnailParams) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "hash", runtime.ParamLocationPath, hash)
+	if err != nil {
+		return nil, err
+	}
+
+	var pathParam1 string
+
+	pathParam1, err = runtime.StyleParamWithLocation("simple", false, "name", runtime.ParamLocationPath, name)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/img/%s/thumb/%s", pathParam0, pathParam1)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if params.Collection != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "collection", runtime.ParamLocationQuery, *params.Collection); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewGetImageOriginalRequest generates requests for GetImageOriginal
+func NewGetImageOriginalRequest(server string, hash string, name Name, params *GetImageOriginalParams) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "hash", runtime.ParamLocationPath, hash)
+	if err != nil {
+		return nil, err
+	}
+
+	var pathParam1 string
+
+	pathParam1, err = runtime.StyleParamWithLocation("simple", false, "name", runtime.ParamLocationPath, name)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/img/%s/%s", pathParam0, pathParam1)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if params.Collection != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "collection", runtime.ParamLocationQuery, *params.Collection); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewGetImageOriginalHeadRequest generates requests for GetImageOriginalHead
+func NewGetImageOriginalHeadRequest(server string, hash string, name Name, params *GetImageOriginalHeadParams) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "hash", runtime.ParamLocationPath, hash)
+	if err != nil {
+		return nil, err
+	}
+
+	var pathParam1 string
+
+	pathParam1, err = runtime.StyleParamWithLocation("simple", false, "name", runtime.ParamLocationPath, name)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/img/%s/%s", pathParam0, pathParam1)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if params.Collection != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "collection", runtime.ParamLocationQuery, *params.Collection); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("HEAD", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewJobsRequest generates requests for Jobs
+func NewJobsRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/jobs")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewOembedRequest generates requests for Oembed
+func NewOembedRequest(server string, params *OembedParams) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/oembed")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if queryFrag, err := runtime.StyleParamWithLocation("form", true, "url", runtime.ParamLocationQuery, params.Url); err != nil {
+			return nil, err
+		} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+			return nil, err
+		} else {
+			for k, v := range parsed {
+				for _, v2 := range v {
+					queryValues.Add(k, v2)
+				}
+			}
+		}
+
+		if params.Maxwidth != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "maxwidth", runtime.ParamLocationQuery, *params.Maxwidth); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Maxheight != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "maxheight", runtime.ParamLocationQuery, *params.Maxheight); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Format != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "format", runtime.ParamLocationQuery, *params.Format); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewPlaylistRequest generates requests for Playlist
+func NewPlaylistRequest(server string, params *PlaylistParams) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/playlist")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if params.Q != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "q", runtime.ParamLocationQuery, *params.Q); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Format != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "format", runtime.ParamLocationQuery, *params.Format); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.W != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "w", runtime.ParamLocationQuery, *params.W); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.H != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "h", runtime.ParamLocationQuery, *params.H); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Dpr != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "dpr", runtime.ParamLocationQuery, *params.Dpr); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Sort != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "sort", runtime.ParamLocationQuery, *params.Sort); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Order != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "order", runtime.ParamLocationQuery, *params.Order); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Variants != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "variants", runtime.ParamLocationQuery, *params.Variants); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Collection != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "collection", runtime.ParamLocationQuery, *params.Collection); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewPreviewImageRequest generates requests for PreviewImage
+func NewPreviewImageRequest(server string, name Name, params *PreviewImageParams) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "name", runtime.ParamLocationPath, name)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/preview/%s", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if params.Device != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "device", runtime.ParamLocationQuery, *params.Device); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewReadyzRequest generates requests for Readyz
+func NewReadyzRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/readyz")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewSitemapRequest generates requests for Sitemap
+func NewSitemapRequest(server string, params *SitemapParams) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/sitemap.xml")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if params.Collection != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "collection", runtime.ParamLocationQuery, *params.Collection); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewGetStatsRequest generates requests for GetStats
+func NewGetStatsRequest(server string, params *GetStatsParams) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/stats.json")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if params.Collection != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "collection", runtime.ParamLocationQuery, *params.Collection); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewUsageRequest generates requests for Usage
+func NewUsageRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/stats/usage.json")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewUploadOptionsRequest generates requests for UploadOptions
+func NewUploadOptionsRequest(server string) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/upload")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("OPTIONS", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewCreateUploadRequest generates requests for CreateUpload
+func NewCreateUploadRequest(server string, params *CreateUploadParams) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/upload")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+
+		var headerParam0 string
+
+		headerParam0, err = runtime.StyleParamWithLocation("simple", false, "Tus-Resumable", runtime.ParamLocationHeader, params.TusResumable)
+		if err != nil {
+			return nil, err
+		}
+
+		req.Header.Set("Tus-Resumable", headerParam0)
+
+		var headerParam1 string
+
+		headerParam1, err = runtime.StyleParamWithLocation("simple", false, "Upload-Length", runtime.ParamLocationHeader, params.UploadLength)
+		if err != nil {
+			return nil, err
+		}
+
+		req.Header.Set("Upload-Length", headerParam1)
+
+		var headerParam2 string
+
+		headerParam2, err = runtime.StyleParamWithLocation("simple", false, "Upload-Metadata", runtime.ParamLocationHeader, params.UploadMetadata)
+		if err != nil {
+			return nil, err
+		}
+
+		req.Header.Set("Upload-Metadata", headerParam2)
+
+	}
+
+	return req, nil
+}
+
+// NewDeleteUploadRequest generates requests for DeleteUpload
+func NewDeleteUploadRequest(server string, id string, params *DeleteUploadParams) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/upload/%s", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("DELETE", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+
+		var headerParam0 string
+
+		headerParam0, err = runtime.StyleParamWithLocation("simple", false, "Tus-Resumable", runtime.ParamLocationHeader, params.TusResumable)
+		if err != nil {
+			return nil, err
+		}
+
+		req.Header.Set("Tus-Resumable", headerParam0)
+
+	}
+
+	return req, nil
+}
+
+// NewUploadOffsetRequest generates requests for UploadOffset
+func NewUploadOffsetRequest(server string, id string, params *UploadOffsetParams) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/upload/%s", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("HEAD", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+
+		var headerParam0 string
+
+		headerParam0, err = runtime.StyleParamWithLocation("simple", false, "Tus-Resumable", runtime.ParamLocationHeader, params.TusResumable)
+		if err != nil {
+			return nil, err
+		}
+
+		req.Header.Set("Tus-Resumable", headerParam0)
+
+	}
+
+	return req, nil
+}
+
+// NewAppendUploadRequestWithBody generates requests for AppendUpload with any type of body
+func NewAppendUploadRequestWithBody(server string, id string, params *AppendUploadParams, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "id", runtime.ParamLocationPath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/upload/%s", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("PATCH", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	if params != nil {
+
+		var headerParam0 string
+
+		headerParam0, err = runtime.StyleParamWithLocation("simple", false, "Tus-Resumable", runtime.ParamLocationHeader, params.TusResumable)
+		if err != nil {
+			return nil, err
+		}
+
+		req.Header.Set("Tus-Resumable", headerParam0)
+
+		var headerParam1 string
+
+		headerParam1, err = runtime.StyleParamWithLocation("simple", false, "Upload-Offset", runtime.ParamLocationHeader, params.UploadOffset)
+		if err != nil {
+			return nil, err
+		}
+
+		req.Header.Set("Upload-Offset", headerParam1)
+
+	}
+
+	return req, nil
+}
+
+// NewV1GetArchiveRequest generates requests for V1GetArchive
+func NewV1GetArchiveRequest(server string, params *V1GetArchiveParams) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v1/archive")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if queryFrag, err := runtime.StyleParamWithLocation("form", true, "name", runtime.ParamLocationQuery, params.Name); err != nil {
+			return nil, err
+		} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+			return nil, err
+		} else {
+			for k, v := range parsed {
+				for _, v2 := range v {
+					queryValues.Add(k, v2)
+				}
+			}
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewV1PostArchiveRequest calls the generic V1PostArchive builder with application/json body
+func NewV1PostArchiveRequest(server string, body V1PostArchiveJSONRequestBody) (*http.Request, error) {
+	var bodyReader io.Reader
+	buf, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	bodyReader = bytes.NewReader(buf)
+	return NewV1PostArchiveRequestWithBody(server, "application/json", bodyReader)
+}
+
+// NewV1PostArchiveRequestWithBody generates requests for V1PostArchive with any type of body
+func NewV1PostArchiveRequestWithBody(server string, contentType string, body io.Reader) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v1/archive")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", queryURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	return req, nil
+}
+
+// NewV1StreamEventsRequest generates requests for V1StreamEvents
+func NewV1StreamEventsRequest(server string, params *V1StreamEventsParams) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v1/events")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if params.Collection != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "collection", runtime.ParamLocationQuery, *params.Collection); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewV1FitDailyImageRequest generates requests for V1FitDailyImage
+func NewV1FitDailyImageRequest(server string, params *V1FitDailyImageParams) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v1/fit/daily")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if queryFrag, err := runtime.StyleParamWithLocation("form", true, "w", runtime.ParamLocationQuery, params.W); err != nil {
+			return nil, err
+		} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+			return nil, err
+		} else {
+			for k, v := range parsed {
+				for _, v2 := range v {
+					queryValues.Add(k, v2)
+				}
+			}
+		}
+
+		if queryFrag, err := runtime.StyleParamWithLocation("form", true, "h", runtime.ParamLocationQuery, params.H); err != nil {
+			return nil, err
+		} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+			return nil, err
+		} else {
+			for k, v := range parsed {
+				for _, v2 := range v {
+					queryValues.Add(k, v2)
+				}
+			}
+		}
+
+		if params.Dpr != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "dpr", runtime.ParamLocationQuery, *params.Dpr); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewV1FitRandomImageRequest generates requests for V1FitRandomImage
+func NewV1FitRandomImageRequest(server string, params *V1FitRandomImageParams) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v1/fit/random")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if queryFrag, err := runtime.StyleParamWithLocation("form", true, "w", runtime.ParamLocationQuery, params.W); err != nil {
+			return nil, err
+		} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+			return nil, err
+		} else {
+			for k, v := range parsed {
+				for _, v2 := range v {
+					queryValues.Add(k, v2)
+				}
+			}
+		}
+
+		if queryFrag, err := runtime.StyleParamWithLocation("form", true, "h", runtime.ParamLocationQuery, params.H); err != nil {
+			return nil, err
+		} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+			return nil, err
+		} else {
+			for k, v := range parsed {
+				for _, v2 := range v {
+					queryValues.Add(k, v2)
+				}
+			}
+		}
+
+		if params.Dpr != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "dpr", runtime.ParamLocationQuery, *params.Dpr); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewV1FitImageRequest generates requests for V1FitImage
+func NewV1FitImageRequest(server string, name Name, params *V1FitImageParams) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "name", runtime.ParamLocationPath, name)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v1/fit/%s", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if queryFrag, err := runtime.StyleParamWithLocation("form", true, "w", runtime.ParamLocationQuery, params.W); err != nil {
+			return nil, err
+		} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+			return nil, err
+		} else {
+			for k, v := range parsed {
+				for _, v2 := range v {
+					queryValues.Add(k, v2)
+				}
+			}
+		}
+
+		if queryFrag, err := runtime.StyleParamWithLocation("form", true, "h", runtime.ParamLocationQuery, params.H); err != nil {
+			return nil, err
+		} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+			return nil, err
+		} else {
+			for k, v := range parsed {
+				for _, v2 := range v {
+					queryValues.Add(k, v2)
+				}
+			}
+		}
+
+		if params.Dpr != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "dpr", runtime.ParamLocationQuery, *params.Dpr); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewV1ListImagesRequest generates requests for V1ListImages
+func NewV1ListImagesRequest(server string, params *V1ListImagesParams) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v1/images")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if params.Limit != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "limit", runtime.ParamLocationQuery, *params.Limit); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Cursor != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "cursor", runtime.ParamLocationQuery, *params.Cursor); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Sort != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "sort", runtime.ParamLocationQuery, *params.Sort); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Order != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "order", runtime.ParamLocationQuery, *params.Order); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Type != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "type", runtime.ParamLocationQuery, *params.Type); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Variants != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "variants", runtime.ParamLocationQuery, *params.Variants); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Q != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "q", runtime.ParamLocationQuery, *params.Q); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Collection != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "collection", runtime.ParamLocationQuery, *params.Collection); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		if params.Fields != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", false, "fields", runtime.ParamLocationQuery, *params.Fields); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewV1DeleteImageRequest generates requests for V1DeleteImage
+func NewV1DeleteImageRequest(server string, name Name) (*http.Request, error) {
+	var err error
+
+	var pathParam0 string
+
+	pathParam0, err = runtime.StyleParamWithLocation("simple", false, "name", runtime.ParamLocationPath, name)
+	if err != nil {
+		return nil, err
+	}
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v1/images/%s", pathParam0)
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("DELETE", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+// NewV1GetStatsRequest generates requests for V1GetStats
+func NewV1GetStatsRequest(server string, params *V1GetStatsParams) (*http.Request, error) {
+	var err error
+
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+
+	operationPath := fmt.Sprintf("/v1/stats")
+	if operationPath[0] == '/' {
+		operationPath = "." + operationPath
+	}
+
+	queryURL, err := serverURL.Parse(operationPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if params != nil {
+		queryValues := queryURL.Query()
+
+		if params.Collection != nil {
+
+			if queryFrag, err := runtime.StyleParamWithLocation("form", true, "collection", runtime.ParamLocationQuery, *params.Collection); err != nil {
+				return nil, err
+			} else if parsed, err := url.ParseQuery(queryFrag); err != nil {
+				return nil, err
+			} else {
+				for k, v := range parsed {
+					for _, v2 := range v {
+						queryValues.Add(k, v2)
+					}
+				}
+			}
+
+		}
+
+		queryURL.RawQuery = queryValues.Encode()
+	}
+
+	req, err := http.NewRequest("GET", queryURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+func (c *Client) applyEditors(ctx context.Context, req *http.Request, additionalEditors []RequestEditorFn) error {
+	for _, r := range c.RequestEditors {
+		if err := r(ctx, req); err != nil {
+			return err
+		}
+	}
+	for _, r := range additionalEditors {
+		if err := r(ctx, req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ClientWithResponses builds on ClientInterface to offer response payloads
+type ClientWithResponses struct {
+	ClientInterface
+}
+
+// NewClientWithResponses creates a new ClientWithResponses, which wraps
+// Client with return type handling
+func NewClientWithResponses(server string, opts ...ClientOption) (*ClientWithResponses, error) {
+	client, err := NewClient(server, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &ClientWithResponses{client}, nil
+}
+
+// WithBaseURL overrides the baseURL.
+func WithBaseURL(baseURL string) ClientOption {
+	return func(c *Client) error {
+		newBaseURL, err := url.Parse(baseURL)
+		if err != nil {
+			return err
+		}
+		c.Server = newBaseURL.String()
+		return nil
+	}
+}
+
+// ClientWithResponsesInterface is the interface specification for the client with responses above.
+type ClientWithResponsesInterface interface {
+	// ListImagesWithResponse request
+	ListImagesWithResponse(ctx context.Context, params *ListImagesParams, reqEditors ...RequestEditorFn) (*ListImagesResponse, error)
+
+	// WallhavenSearchWithResponse request
+	WallhavenSearchWithResponse(ctx context.Context, params *WallhavenSearchParams, reqEditors ...RequestEditorFn) (*WallhavenSearchResponse, error)
+
+	// WallhavenWallpaperWithResponse request
+	WallhavenWallpaperWithResponse(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*WallhavenWallpaperResponse, error)
+
+	// GetArchiveWithResponse request
+	GetArchiveWithResponse(ctx context.Context, params *GetArchiveParams, reqEditors ...RequestEditorFn) (*GetArchiveResponse, error)
+
+	// PostArchiveWithBodyWithResponse request with any body
+	PostArchiveWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostArchiveResponse, error)
+
+	PostArchiveWithResponse(ctx context.Context, body PostArchiveJSONRequestBody, reqEditors ...RequestEditorFn) (*PostArchiveResponse, error)
+
+	// AuditWithResponse request
+	AuditWithResponse(ctx context.Context, params *AuditParams, reqEditors ...RequestEditorFn) (*AuditResponse, error)
+
+	// DownloadImageWithResponse request
+	DownloadImageWithResponse(ctx context.Context, name Name, params *DownloadImageParams, reqEditors ...RequestEditorFn) (*DownloadImageResponse, error)
+
+	// DownloadImageHeadWithResponse request
+	DownloadImageHeadWithResponse(ctx context.Context, name Name, params *DownloadImageHeadParams, reqEditors ...RequestEditorFn) (*DownloadImageHeadResponse, error)
+
+	// DownloadsWithResponse request
+	DownloadsWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*DownloadsResponse, error)
+
+	// EinkImageWithResponse request
+	EinkImageWithResponse(ctx context.Context, name Name, params *EinkImageParams, reqEditors ...RequestEditorFn) (*EinkImageResponse, error)
+
+	// StreamEventsWithResponse request
+	StreamEventsWithResponse(ctx context.Context, params *StreamEventsParams, reqEditors ...RequestEditorFn) (*StreamEventsResponse, error)
+
+	// FitDailyImageWithResponse request
+	FitDailyImageWithResponse(ctx context.Context, params *FitDailyImageParams, reqEditors ...RequestEditorFn) (*FitDailyImageResponse, error)
+
+	// FitRandomImageWithResponse request
+	FitRandomImageWithResponse(ctx context.Context, params *FitRandomImageParams, reqEditors ...RequestEditorFn) (*FitRandomImageResponse, error)
+
+	// FitImageWithResponse request
+	FitImageWithResponse(ctx context.Context, name Name, params *FitImageParams, reqEditors ...RequestEditorFn) (*FitImageResponse, error)
+
+	// GraphqlQueryWithResponse request
+	GraphqlQueryWithResponse(ctx context.Context, params *GraphqlQueryParams, reqEditors ...RequestEditorFn) (*GraphqlQueryResponse, error)
+
+	// GraphqlPostWithBodyWithResponse request with any body
+	GraphqlPostWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*GraphqlPostResponse, error)
+
+	GraphqlPostWithResponse(ctx context.Context, body GraphqlPostJSONRequestBody, reqEditors ...RequestEditorFn) (*GraphqlPostResponse, error)
+
+	// HealthzWithResponse request
+	HealthzWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*HealthzResponse, error)
+
+	// IiifBaseWithResponse request
+	IiifBaseWithResponse(ctx context.Context, name Name, reqEditors ...RequestEditorFn) (*IiifBaseResponse, error)
+
+	// IiifInfoWithResponse request
+	IiifInfoWithResponse(ctx context.Context, name Name, reqEditors ...RequestEditorFn) (*IiifInfoResponse, error)
+
+	// IiifImageWithResponse request
+	IiifImageWithResponse(ctx context.Context, name Name, region string, size string, rotation string, quality IiifImageParamsQuality, format IiifImageParamsFormat, reqEditors ...RequestEditorFn) (*IiifImageResponse, error)
+
+	// ImagePageWithResponse request
+	ImagePageWithResponse(ctx context.Context, name Name, params *ImagePageParams, reqEditors ...RequestEditorFn) (*ImagePageResponse, error)
+
+	// GetImageThumbnailWithResponse request
+	GetImageThumbnailWithResponse(ctx context.Context, hash string, name Name, params *GetImageThumbnailParams, reqEditors ...RequestEditorFn) (*GetImageThumbnailResponse, error)
+
+	// GetImageOriginalWithResponse request
+	GetImageOriginalWithResponse(ctx context.Context, hash string, name Name, params *GetImageOriginalParams, reqEditors ...RequestEditorFn) (*GetImageOriginalResponse, error)
+
+	// GetImageOriginalHeadWithResponse request
+	GetImageOriginalHeadWithResponse(ctx context.Context, hash string, name Name, params *GetImageOriginalHeadParams, reqEditors ...RequestEditorFn) (*GetImageOriginalHeadResponse, error)
+
+	// JobsWithResponse request
+	JobsWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*JobsResponse, error)
+
+	// OembedWithResponse request
+	OembedWithResponse(ctx context.Context, params *OembedParams, reqEditors ...RequestEditorFn) (*OembedResponse, error)
+
+	// PlaylistWithResponse request
+	PlaylistWithResponse(ctx context.Context, params *PlaylistParams, reqEditors ...RequestEditorFn) (*PlaylistResponse, error)
+
+	// PreviewImageWithResponse request
+	PreviewImageWithResponse(ctx context.Context, name Name, params *PreviewImageParams, reqEditors ...RequestEditorFn) (*PreviewImageResponse, error)
+
+	// ReadyzWithResponse request
+	ReadyzWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*ReadyzResponse, error)
+
+	// SitemapWithResponse request
+	SitemapWithResponse(ctx context.Context, params *SitemapParams, reqEditors ...RequestEditorFn) (*SitemapResponse, error)
+
+	// GetStatsWithResponse request
+	GetStatsWithResponse(ctx context.Context, params *GetStatsParams, reqEditors ...RequestEditorFn) (*GetStatsResponse, error)
+
+	// UsageWithResponse request
+	UsageWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*UsageResponse, error)
+
+	// UploadOptionsWithResponse request
+	UploadOptionsWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*UploadOptionsResponse, error)
+
+	// CreateUploadWithResponse request
+	CreateUploadWithResponse(ctx context.Context, params *CreateUploadParams, reqEditors ...RequestEditorFn) (*CreateUploadResponse, error)
+
+	// DeleteUploadWithResponse request
+	DeleteUploadWithResponse(ctx context.Context, id string, params *DeleteUploadParams, reqEditors ...RequestEditorFn) (*DeleteUploadResponse, error)
+
+	// UploadOffsetWithResponse request
+	UploadOffsetWithResponse(ctx context.Context, id string, params *UploadOffsetParams, reqEditors ...RequestEditorFn) (*UploadOffsetResponse, error)
+
+	// AppendUploadWithBodyWithResponse request with any body
+	AppendUploadWithBodyWithResponse(ctx context.Context, id string, params *AppendUploadParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*AppendUploadResponse, error)
+
+	// V1GetArchiveWithResponse request
+	V1GetArchiveWithResponse(ctx context.Context, params *V1GetArchiveParams, reqEditors ...RequestEditorFn) (*V1GetArchiveResponse, error)
+
+	// V1PostArchiveWithBodyWithResponse request with any body
+	V1PostArchiveWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*V1PostArchiveResponse, error)
+
+	V1PostArchiveWithResponse(ctx context.Context, body V1PostArchiveJSONRequestBody, reqEditors ...RequestEditorFn) (*V1PostArchiveResponse, error)
+
+	// V1StreamEventsWithResponse request
+	V1StreamEventsWithResponse(ctx context.Context, params *V1StreamEventsParams, reqEditors ...RequestEditorFn) (*V1StreamEventsResponse, error)
+
+	// V1FitDailyImageWithResponse request
+	V1FitDailyImageWithResponse(ctx context.Context, params *V1FitDailyImageParams, reqEditors ...RequestEditorFn) (*V1FitDailyImageResponse, error)
+
+	// V1FitRandomImageWithResponse request
+	V1FitRandomImageWithResponse(ctx context.Context, params *V1FitRandomImageParams, reqEditors ...RequestEditorFn) (*V1FitRandomImageResponse, error)
+
+	// V1FitImageWithResponse request
+	V1FitImageWithResponse(ctx context.Context, name Name, params *V1FitImageParams, reqEditors ...RequestEditorFn) (*V1FitImageResponse, error)
+
+	// V1ListImagesWithResponse request
+	V1ListImagesWithResponse(ctx context.Context, params *V1ListImagesParams, reqEditors ...RequestEditorFn) (*V1ListImagesResponse, error)
+
+	// V1DeleteImageWithResponse request
+	V1DeleteImageWithResponse(ctx context.Context, name Name, reqEditors ...RequestEditorFn) (*V1DeleteImageResponse, error)
+
+	// V1GetStatsWithResponse request
+	V1GetStatsWithResponse(ctx context.Context, params *V1GetStatsParams, reqEditors ...RequestEditorFn) (*V1GetStatsResponse, error)
+}
+
+type ListImagesResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *[]File
+	JSON400      *Error
+	JSON500      *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r ListImagesResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r ListImagesResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type WallhavenSearchResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *struct {
+		Data *[]WallhavenWallpaper `json:"data,omitempty"`
+		Meta *struct {
+			CurrentPage *int    `json:"current_page,omitempty"`
+			LastPage    *int    `json:"last_page,omitempty"`
+			PerPage     *int    `json:"per_page,omitempty"`
+			Query       *string `json:"query,omitempty"`
+			Seed        *string `json:"seed,omitempty"`
+			Total       *int    `json:"total,omitempty"`
+		} `json:"meta,omitempty"`
+	}
+	JSON400 *Error
+	JSON500 *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r WallhavenSearchResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r WallhavenSearchResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type WallhavenWallpaperResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *struct {
+		Data *WallhavenWallpaper `json:"data,omitempty"`
+	}
+	JSON404 *Error
+	JSON500 *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r WallhavenWallpaperResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r WallhavenWallpaperResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetArchiveResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON400      *Error
+	JSON404      *Error
+	JSON413      *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r GetArchiveResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetArchiveResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type PostArchiveResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON400      *Error
+	JSON404      *Error
+	JSON413      *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r PostArchiveResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r PostArchiveResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type AuditResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *[]AuditEntry
+	JSON400      *Error
+	JSON401      *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r AuditResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r AuditResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type DownloadImageResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON404      *Error
+	JSON500      *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r DownloadImageResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r DownloadImageResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type DownloadImageHeadResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON404      *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r DownloadImageHeadResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r DownloadImageHeadResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type DownloadsResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *map[string]int
+}
+
+// Status returns HTTPResponse.Status
+func (r DownloadsResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r DownloadsResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type EinkImageResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON400      *Error
+	JSON404      *Error
+	JSON500      *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r EinkImageResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r EinkImageResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type StreamEventsResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+}
+
+// Status returns HTTPResponse.Status
+func (r StreamEventsResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r StreamEventsResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type FitDailyImageResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON400      *Error
+	JSON404      *Error
+	JSON500      *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r FitDailyImageResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r FitDailyImageResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type FitRandomImageResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON400      *Error
+	JSON404      *Error
+	JSON500      *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r FitRandomImageResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r FitRandomImageResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type FitImageResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON400      *Error
+	JSON404      *Error
+	JSON500      *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r FitImageResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r FitImageResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GraphqlQueryResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *GraphQLResponse
+	JSON400      *struct {
+		union json.RawMessage
+	}
+}
+
+// Status returns HTTPResponse.Status
+func (r GraphqlQueryResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GraphqlQueryResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GraphqlPostResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *GraphQLResponse
+	JSON400      *struct {
+		union json.RawMessage
+	}
+}
+
+// Status returns HTTPResponse.Status
+func (r GraphqlPostResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GraphqlPostResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type HealthzResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+}
+
+// Status returns HTTPResponse.Status
+func (r HealthzResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r HealthzResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type IiifBaseResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+}
+
+// Status returns HTTPResponse.Status
+func (r IiifBaseResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r IiifBaseResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type IiifInfoResponse struct {
+	Body                 []byte
+	HTTPResponse         *http.Response
+	ApplicationldJSON200 *map[string]interface{}
+	JSON404              *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r IiifInfoResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r IiifInfoResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type IiifImageResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON400      *Error
+	JSON404      *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r IiifImageResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r IiifImageResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type ImagePageResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+}
+
+// Status returns HTTPResponse.Status
+func (r ImagePageResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r ImagePageResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetImageThumbnailResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON404      *Error
+	JSON500      *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r GetImageThumbnailResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetImageThumbnailResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetImageOriginalResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON404      *Error
+	JSON500      *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r GetImageOriginalResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetImageOriginalResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetImageOriginalHeadResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON404      *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r GetImageOriginalHeadResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetImageOriginalHeadResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type JobsResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *[]Job
+}
+
+// Status returns HTTPResponse.Status
+func (r JobsResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r JobsResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type OembedResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *OEmbed
+	JSON400      *Error
+	JSON404      *Error
+	JSON501      *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r OembedResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r OembedResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type PlaylistResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *[]string
+	JSON400      *Error
+	JSON500      *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r PlaylistResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r PlaylistResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type PreviewImageResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON400      *Error
+	JSON404      *Error
+	JSON500      *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r PreviewImageResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r PreviewImageResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type ReadyzResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *Readiness
+	JSON503      *Readiness
+}
+
+// Status returns HTTPResponse.Status
+func (r ReadyzResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r ReadyzResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type SitemapResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	XML200       *string
+}
+
+// Status returns HTTPResponse.Status
+func (r SitemapResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r SitemapResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type GetStatsResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *Stats
+	JSON500      *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r GetStatsResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r GetStatsResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type UsageResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *struct {
+		Agents    *map[string]int `json:"agents,omitempty"`
+		Downloads *map[string]int `json:"downloads,omitempty"`
+		Endpoints *map[string]int `json:"endpoints,omitempty"`
+		Images    *map[string]int `json:"images,omitempty"`
+		Referers  *map[string]int `json:"referers,omitempty"`
+		Requests  *int            `json:"requests,omitempty"`
+		Since     *time.Time      `json:"since,omitempty"`
+		Views     *map[string]int `json:"views,omitempty"`
+	}
+}
+
+// Status returns HTTPResponse.Status
+func (r UsageResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r UsageResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type UploadOptionsResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+}
+
+// Status returns HTTPResponse.Status
+func (r UploadOptionsResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r UploadOptionsResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type CreateUploadResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON400      *Error
+	JSON401      *Error
+	JSON412      *Error
+	JSON413      *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r CreateUploadResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r CreateUploadResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type DeleteUploadResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON401      *Error
+	JSON404      *Error
+	JSON412      *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r DeleteUploadResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r DeleteUploadResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type UploadOffsetResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON401      *Error
+	JSON404      *Error
+	JSON412      *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r UploadOffsetResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r UploadOffsetResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type AppendUploadResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON400      *Error
+	JSON401      *Error
+	JSON404      *Error
+	JSON409      *Error
+	JSON412      *Error
+	JSON413      *Error
+	JSON415      *Error
+	JSON422      *Error
+}
+
+// Status returns HTTPResponse.Status
+func (r AppendUploadResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r AppendUploadResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type V1GetArchiveResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON400      *V1Error
+	JSON404      *V1Error
+	JSON413      *V1Error
+}
+
+// Status returns HTTPResponse.Status
+func (r V1GetArchiveResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r V1GetArchiveResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type V1PostArchiveResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON400      *V1Error
+	JSON404      *V1Error
+	JSON413      *V1Error
+}
+
+// Status returns HTTPResponse.Status
+func (r V1PostArchiveResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r V1PostArchiveResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type V1StreamEventsResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+}
+
+// Status returns HTTPResponse.Status
+func (r V1StreamEventsResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r V1StreamEventsResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type V1FitDailyImageResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON400      *V1Error
+	JSON404      *Error
+	JSON500      *V1Error
+}
+
+// Status returns HTTPResponse.Status
+func (r V1FitDailyImageResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r V1FitDailyImageResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type V1FitRandomImageResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON400      *V1Error
+	JSON404      *Error
+	JSON500      *V1Error
+}
+
+// Status returns HTTPResponse.Status
+func (r V1FitRandomImageResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r V1FitRandomImageResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type V1FitImageResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON400      *V1Error
+	JSON404      *V1Error
+	JSON500      *V1Error
+}
+
+// Status returns HTTPResponse.Status
+func (r V1FitImageResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r V1FitImageResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type V1ListImagesResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *ImagesEnvelope
+	JSON400      *V1Error
+	JSON500      *V1Error
+}
+
+// Status returns HTTPResponse.Status
+func (r V1ListImagesResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r V1ListImagesResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type V1DeleteImageResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON400      *V1Error
+	JSON401      *V1Error
+	JSON404      *V1Error
+	JSON500      *V1Error
+}
+
+// Status returns HTTPResponse.Status
+func (r V1DeleteImageResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r V1DeleteImageResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+type V1GetStatsResponse struct {
+	Body         []byte
+	HTTPResponse *http.Response
+	JSON200      *StatsEnvelope
+	JSON500      *V1Error
+}
+
+// Status returns HTTPResponse.Status
+func (r V1GetStatsResponse) Status() string {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Status
+	}
+	return http.StatusText(0)
+}
+
+// StatusCode returns HTTPResponse.StatusCode
+func (r V1GetStatsResponse) StatusCode() int {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.StatusCode
+	}
+	return 0
+}
+
+// ListImagesWithResponse request returning *ListImagesResponse
+func (c *ClientWithResponses) ListImagesWithResponse(ctx context.Context, params *ListImagesParams, reqEditors ...RequestEditorFn) (*ListImagesResponse, error) {
+	rsp, err := c.ListImages(ctx, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseListImagesResponse(rsp)
+}
+
+// WallhavenSearchWithResponse request returning *WallhavenSearchResponse
+func (c *ClientWithResponses) WallhavenSearchWithResponse(ctx context.Context, params *WallhavenSearchParams, reqEditors ...RequestEditorFn) (*WallhavenSearchResponse, error) {
+	rsp, err := c.WallhavenSearch(ctx, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseWallhavenSearchResponse(rsp)
+}
+
+// WallhavenWallpaperWithResponse request returning *WallhavenWallpaperResponse
+func (c *ClientWithResponses) WallhavenWallpaperWithResponse(ctx context.Context, id string, reqEditors ...RequestEditorFn) (*WallhavenWallpaperResponse, error) {
+	rsp, err := c.WallhavenWallpaper(ctx, id, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseWallhavenWallpaperResponse(rsp)
+}
+
+// GetArchiveWithResponse request returning *GetArchiveResponse
+func (c *ClientWithResponses) GetArchiveWithResponse(ctx context.Context, params *GetArchiveParams, reqEditors ...RequestEditorFn) (*GetArchiveResponse, error) {
+	rsp, err := c.GetArchive(ctx, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetArchiveResponse(rsp)
+}
+
+// PostArchiveWithBodyWithResponse request with arbitrary body returning *PostArchiveResponse
+func (c *ClientWithResponses) PostArchiveWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*PostArchiveResponse, error) {
+	rsp, err := c.PostArchiveWithBody(ctx, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePostArchiveResponse(rsp)
+}
+
+func (c *ClientWithResponses) PostArchiveWithResponse(ctx context.Context, body PostArchiveJSONRequestBody, reqEditors ...RequestEditorFn) (*PostArchiveResponse, error) {
+	rsp, err := c.PostArchive(ctx, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePostArchiveResponse(rsp)
+}
+
+// AuditWithResponse request returning *AuditResponse
+func (c *ClientWithResponses) AuditWithResponse(ctx context.Context, params *AuditParams, reqEditors ...RequestEditorFn) (*AuditResponse, error) {
+	rsp, err := c.Audit(ctx, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseAuditResponse(rsp)
+}
+
+// DownloadImageWithResponse request returning *DownloadImageResponse
+func (c *ClientWithResponses) DownloadImageWithResponse(ctx context.Context, name Name, params *DownloadImageParams, reqEditors ...RequestEditorFn) (*DownloadImageResponse, error) {
+	rsp, err := c.DownloadImage(ctx, name, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseDownloadImageResponse(rsp)
+}
+
+// DownloadImageHeadWithResponse request returning *DownloadImageHeadResponse
+func (c *ClientWithResponses) DownloadImageHeadWithResponse(ctx context.Context, name Name, params *DownloadImageHeadParams, reqEditors ...RequestEditorFn) (*DownloadImageHeadResponse, error) {
+	rsp, err := c.DownloadImageHead(ctx, name, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseDownloadImageHeadResponse(rsp)
+}
+
+// DownloadsWithResponse request returning *DownloadsResponse
+func (c *ClientWithResponses) DownloadsWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*DownloadsResponse, error) {
+	rsp, err := c.Downloads(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseDownloadsResponse(rsp)
+}
+
+// EinkImageWithResponse request returning *EinkImageResponse
+func (c *ClientWithResponses) EinkImageWithResponse(ctx context.Context, name Name, params *EinkImageParams, reqEditors ...RequestEditorFn) (*EinkImageResponse, error) {
+	rsp, err := c.EinkImage(ctx, name, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseEinkImageResponse(rsp)
+}
+
+// StreamEventsWithResponse request returning *StreamEventsResponse
+func (c *ClientWithResponses) StreamEventsWithResponse(ctx context.Context, params *StreamEventsParams, reqEditors ...RequestEditorFn) (*StreamEventsResponse, error) {
+	rsp, err := c.StreamEvents(ctx, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseStreamEventsResponse(rsp)
+}
+
+// FitDailyImageWithResponse request returning *FitDailyImageResponse
+func (c *ClientWithResponses) FitDailyImageWithResponse(ctx context.Context, params *FitDailyImageParams, reqEditors ...RequestEditorFn) (*FitDailyImageResponse, error) {
+	rsp, err := c.FitDailyImage(ctx, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseFitDailyImageResponse(rsp)
+}
+
+// FitRandomImageWithResponse request returning *FitRandomImageResponse
+func (c *ClientWithResponses) FitRandomImageWithResponse(ctx context.Context, params *FitRandomImageParams, reqEditors ...RequestEditorFn) (*FitRandomImageResponse, error) {
+	rsp, err := c.FitRandomImage(ctx, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseFitRandomImageResponse(rsp)
+}
+
+// FitImageWithResponse request returning *FitImageResponse
+func (c *ClientWithResponses) FitImageWithResponse(ctx context.Context, name Name, params *FitImageParams, reqEditors ...RequestEditorFn) (*FitImageResponse, error) {
+	rsp, err := c.FitImage(ctx, name, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseFitImageResponse(rsp)
+}
+
+// GraphqlQueryWithResponse request returning *GraphqlQueryResponse
+func (c *ClientWithResponses) GraphqlQueryWithResponse(ctx context.Context, params *GraphqlQueryParams, reqEditors ...RequestEditorFn) (*GraphqlQueryResponse, error) {
+	rsp, err := c.GraphqlQuery(ctx, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGraphqlQueryResponse(rsp)
+}
+
+// GraphqlPostWithBodyWithResponse request with arbitrary body returning *GraphqlPostResponse
+func (c *ClientWithResponses) GraphqlPostWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*GraphqlPostResponse, error) {
+	rsp, err := c.GraphqlPostWithBody(ctx, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGraphqlPostResponse(rsp)
+}
+
+func (c *ClientWithResponses) GraphqlPostWithResponse(ctx context.Context, body GraphqlPostJSONRequestBody, reqEditors ...RequestEditorFn) (*GraphqlPostResponse, error) {
+	rsp, err := c.GraphqlPost(ctx, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGraphqlPostResponse(rsp)
+}
+
+// HealthzWithResponse request returning *HealthzResponse
+func (c *ClientWithResponses) HealthzWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*HealthzResponse, error) {
+	rsp, err := c.Healthz(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseHealthzResponse(rsp)
+}
+
+// IiifBaseWithResponse request returning *IiifBaseResponse
+func (c *ClientWithResponses) IiifBaseWithResponse(ctx context.Context, name Name, reqEditors ...RequestEditorFn) (*IiifBaseResponse, error) {
+	rsp, err := c.IiifBase(ctx, name, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseIiifBaseResponse(rsp)
+}
+
+// IiifInfoWithResponse request returning *IiifInfoResponse
+func (c *ClientWithResponses) IiifInfoWithResponse(ctx context.Context, name Name, reqEditors ...RequestEditorFn) (*IiifInfoResponse, error) {
+	rsp, err := c.IiifInfo(ctx, name, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseIiifInfoResponse(rsp)
+}
+
+// IiifImageWithResponse request returning *IiifImageResponse
+func (c *ClientWithResponses) IiifImageWithResponse(ctx context.Context, name Name, region string, size string, rotation string, quality IiifImageParamsQuality, format IiifImageParamsFormat, reqEditors ...RequestEditorFn) (*IiifImageResponse, error) {
+	rsp, err := c.IiifImage(ctx, name, region, size, rotation, quality, format, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseIiifImageResponse(rsp)
+}
+
+// ImagePageWithResponse request returning *ImagePageResponse
+func (c *ClientWithResponses) ImagePageWithResponse(ctx context.Context, name Name, params *ImagePageParams, reqEditors ...RequestEditorFn) (*ImagePageResponse, error) {
+	rsp, err := c.ImagePage(ctx, name, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseImagePageResponse(rsp)
+}
+
+// GetImageThumbnailWithResponse request returning *GetImageThumbnailResponse
+func (c *ClientWithResponses) GetImageThumbnailWithResponse(ctx context.Context, hash string, name Name, params *GetImageThumbnailParams, reqEditors ...RequestEditorFn) (*GetImageThumbnailResponse, error) {
+	rsp, err := c.GetImageThumbnail(ctx, hash, name, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetImageThumbnailResponse(rsp)
+}
+
+// GetImageOriginalWithResponse request returning *GetImageOriginalResponse
+func (c *ClientWithResponses) GetImageOriginalWithResponse(ctx context.Context, hash string, name Name, params *GetImageOriginalParams, reqEditors ...RequestEditorFn) (*GetImageOriginalResponse, error) {
+	rsp, err := c.GetImageOriginal(ctx, hash, name, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetImageOriginalResponse(rsp)
+}
+
+// GetImageOriginalHeadWithResponse request returning *GetImageOriginalHeadResponse
+func (c *ClientWithResponses) GetImageOriginalHeadWithResponse(ctx context.Context, hash string, name Name, params *GetImageOriginalHeadParams, reqEditors ...RequestEditorFn) (*GetImageOriginalHeadResponse, error) {
+	rsp, err := c.GetImageOriginalHead(ctx, hash, name, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetImageOriginalHeadResponse(rsp)
+}
+
+// JobsWithResponse request returning *JobsResponse
+func (c *ClientWithResponses) JobsWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*JobsResponse, error) {
+	rsp, err := c.Jobs(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseJobsResponse(rsp)
+}
+
+// OembedWithResponse request returning *OembedResponse
+func (c *ClientWithResponses) OembedWithResponse(ctx context.Context, params *OembedParams, reqEditors ...RequestEditorFn) (*OembedResponse, error) {
+	rsp, err := c.Oembed(ctx, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseOembedResponse(rsp)
+}
+
+// PlaylistWithResponse request returning *PlaylistResponse
+func (c *ClientWithResponses) PlaylistWithResponse(ctx context.Context, params *PlaylistParams, reqEditors ...RequestEditorFn) (*PlaylistResponse, error) {
+	rsp, err := c.Playlist(ctx, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePlaylistResponse(rsp)
+}
+
+// PreviewImageWithResponse request returning *PreviewImageResponse
+func (c *ClientWithResponses) PreviewImageWithResponse(ctx context.Context, name Name, params *PreviewImageParams, reqEditors ...RequestEditorFn) (*PreviewImageResponse, error) {
+	rsp, err := c.PreviewImage(ctx, name, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePreviewImageResponse(rsp)
+}
+
+// ReadyzWithResponse request returning *ReadyzResponse
+func (c *ClientWithResponses) ReadyzWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*ReadyzResponse, error) {
+	rsp, err := c.Readyz(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseReadyzResponse(rsp)
+}
+
+// SitemapWithResponse request returning *SitemapResponse
+func (c *ClientWithResponses) SitemapWithResponse(ctx context.Context, params *SitemapParams, reqEditors ...RequestEditorFn) (*SitemapResponse, error) {
+	rsp, err := c.Sitemap(ctx, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseSitemapResponse(rsp)
+}
+
+// GetStatsWithResponse request returning *GetStatsResponse
+func (c *ClientWithResponses) GetStatsWithResponse(ctx context.Context, params *GetStatsParams, reqEditors ...RequestEditorFn) (*GetStatsResponse, error) {
+	rsp, err := c.GetStats(ctx, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGetStatsResponse(rsp)
+}
+
+// UsageWithResponse request returning *UsageResponse
+func (c *ClientWithResponses) UsageWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*UsageResponse, error) {
+	rsp, err := c.Usage(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseUsageResponse(rsp)
+}
+
+// UploadOptionsWithResponse request returning *UploadOptionsResponse
+func (c *ClientWithResponses) UploadOptionsWithResponse(ctx context.Context, reqEditors ...RequestEditorFn) (*UploadOptionsResponse, error) {
+	rsp, err := c.UploadOptions(ctx, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseUploadOptionsResponse(rsp)
+}
+
+// CreateUploadWithResponse request returning *CreateUploadResponse
+func (c *ClientWithResponses) CreateUploadWithResponse(ctx context.Context, params *CreateUploadParams, reqEditors ...RequestEditorFn) (*CreateUploadResponse, error) {
+	rsp, err := c.CreateUpload(ctx, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseCreateUploadResponse(rsp)
+}
+
+// DeleteUploadWithResponse request returning *DeleteUploadResponse
+func (c *ClientWithResponses) DeleteUploadWithResponse(ctx context.Context, id string, params *DeleteUploadParams, reqEditors ...RequestEditorFn) (*DeleteUploadResponse, error) {
+	rsp, err := c.DeleteUpload(ctx, id, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseDeleteUploadResponse(rsp)
+}
+
+// UploadOffsetWithResponse request returning *UploadOffsetResponse
+func (c *ClientWithResponses) UploadOffsetWithResponse(ctx context.Context, id string, params *UploadOffsetParams, reqEditors ...RequestEditorFn) (*UploadOffsetResponse, error) {
+	rsp, err := c.UploadOffset(ctx, id, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseUploadOffsetResponse(rsp)
+}
+
+// AppendUploadWithBodyWithResponse request with arbitrary body returning *AppendUploadResponse
+func (c *ClientWithResponses) AppendUploadWithBodyWithResponse(ctx context.Context, id string, params *AppendUploadParams, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*AppendUploadResponse, error) {
+	rsp, err := c.AppendUploadWithBody(ctx, id, params, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseAppendUploadResponse(rsp)
+}
+
+// V1GetArchiveWithResponse request returning *V1GetArchiveResponse
+func (c *ClientWithResponses) V1GetArchiveWithResponse(ctx context.Context, params *V1GetArchiveParams, reqEditors ...RequestEditorFn) (*V1GetArchiveResponse, error) {
+	rsp, err := c.V1GetArchive(ctx, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseV1GetArchiveResponse(rsp)
+}
+
+// V1PostArchiveWithBodyWithResponse request with arbitrary body returning *V1PostArchiveResponse
+func (c *ClientWithResponses) V1PostArchiveWithBodyWithResponse(ctx context.Context, contentType string, body io.Reader, reqEditors ...RequestEditorFn) (*V1PostArchiveResponse, error) {
+	rsp, err := c.V1PostArchiveWithBody(ctx, contentType, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseV1PostArchiveResponse(rsp)
+}
+
+func (c *ClientWithResponses) V1PostArchiveWithResponse(ctx context.Context, body V1PostArchiveJSONRequestBody, reqEditors ...RequestEditorFn) (*V1PostArchiveResponse, error) {
+	rsp, err := c.V1PostArchive(ctx, body, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseV1PostArchiveResponse(rsp)
+}
+
+// V1StreamEventsWithResponse request returning *V1StreamEventsResponse
+func (c *ClientWithResponses) V1StreamEventsWithResponse(ctx context.Context, params *V1StreamEventsParams, reqEditors ...RequestEditorFn) (*V1StreamEventsResponse, error) {
+	rsp, err := c.V1StreamEvents(ctx, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseV1StreamEventsResponse(rsp)
+}
+
+// V1FitDailyImageWithResponse request returning *V1FitDailyImageResponse
+func (c *ClientWithResponses) V1FitDailyImageWithResponse(ctx context.Context, params *V1FitDailyImageParams, reqEditors ...RequestEditorFn) (*V1FitDailyImageResponse, error) {
+	rsp, err := c.V1FitDailyImage(ctx, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseV1FitDailyImageResponse(rsp)
+}
+
+// V1FitRandomImageWithResponse request returning *V1FitRandomImageResponse
+func (c *ClientWithResponses) V1FitRandomImageWithResponse(ctx context.Context, params *V1FitRandomImageParams, reqEditors ...RequestEditorFn) (*V1FitRandomImageResponse, error) {
+	rsp, err := c.V1FitRandomImage(ctx, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseV1FitRandomImageResponse(rsp)
+}
+
+// V1FitImageWithResponse request returning *V1FitImageResponse
+func (c *ClientWithResponses) V1FitImageWithResponse(ctx context.Context, name Name, params *V1FitImageParams, reqEditors ...RequestEditorFn) (*V1FitImageResponse, error) {
+	rsp, err := c.V1FitImage(ctx, name, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseV1FitImageResponse(rsp)
+}
+
+// V1ListImagesWithResponse request returning *V1ListImagesResponse
+func (c *ClientWithResponses) V1ListImagesWithResponse(ctx context.Context, params *V1ListImagesParams, reqEditors ...RequestEditorFn) (*V1ListImagesResponse, error) {
+	rsp, err := c.V1ListImages(ctx, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseV1ListImagesResponse(rsp)
+}
+
+// V1DeleteImageWithResponse request returning *V1DeleteImageResponse
+func (c *ClientWithResponses) V1DeleteImageWithResponse(ctx context.Context, name Name, reqEditors ...RequestEditorFn) (*V1DeleteImageResponse, error) {
+	rsp, err := c.V1DeleteImage(ctx, name, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseV1DeleteImageResponse(rsp)
+}
+
+// V1GetStatsWithResponse request returning *V1GetStatsResponse
+func (c *ClientWithResponses) V1GetStatsWithResponse(ctx context.Context, params *V1GetStatsParams, reqEditors ...RequestEditorFn) (*V1GetStatsResponse, error) {
+	rsp, err := c.V1GetStats(ctx, params, reqEditors...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseV1GetStatsResponse(rsp)
+}
+
+// ParseListImagesResponse parses an HTTP response from a ListImagesWithResponse call
+func ParseListImagesResponse(rsp *http.Response) (*ListImagesResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ListImagesResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest []File
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseWallhavenSearchResponse parses an HTTP response from a WallhavenSearchWithResponse call
+func ParseWallhavenSearchResponse(rsp *http.Response) (*WallhavenSearchResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &WallhavenSearchResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest struct {
+			Data *[]WallhavenWallpaper `json:"data,omitempty"`
+			Meta *struct {
+				CurrentPage *int    `json:"current_page,omitempty"`
+				LastPage    *int    `json:"last_page,omitempty"`
+				PerPage     *int    `json:"per_page,omitempty"`
+				Query       *string `json:"query,omitempty"`
+				Seed        *string `json:"seed,omitempty"`
+				Total       *int    `json:"total,omitempty"`
+			} `json:"meta,omitempty"`
+		}
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseWallhavenWallpaperResponse parses an HTTP response from a WallhavenWallpaperWithResponse call
+func ParseWallhavenWallpaperResponse(rsp *http.Response) (*WallhavenWallpaperResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &WallhavenWallpaperResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest struct {
+			Data *WallhavenWallpaper `json:"data,omitempty"`
+		}
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetArchiveResponse parses an HTTP response from a GetArchiveWithResponse call
+func ParseGetArchiveResponse(rsp *http.Response) (*GetArchiveResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetArchiveResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 413:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON413 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParsePostArchiveResponse parses an HTTP response from a PostArchiveWithResponse call
+func ParsePostArchiveResponse(rsp *http.Response) (*PostArchiveResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &PostArchiveResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 413:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON413 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseAuditResponse parses an HTTP response from a AuditWithResponse call
+func ParseAuditResponse(rsp *http.Response) (*AuditResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &AuditResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest []AuditEntry
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseDownloadImageResponse parses an HTTP response from a DownloadImageWithResponse call
+func ParseDownloadImageResponse(rsp *http.Response) (*DownloadImageResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &DownloadImageResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseDownloadImageHeadResponse parses an HTTP response from a DownloadImageHeadWithResponse call
+func ParseDownloadImageHeadResponse(rsp *http.Response) (*DownloadImageHeadResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &DownloadImageHeadResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseDownloadsResponse parses an HTTP response from a DownloadsWithResponse call
+func ParseDownloadsResponse(rsp *http.Response) (*DownloadsResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &DownloadsResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest map[string]int
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseEinkImageResponse parses an HTTP response from a EinkImageWithResponse call
+func ParseEinkImageResponse(rsp *http.Response) (*EinkImageResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &EinkImageResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseStreamEventsResponse parses an HTTP response from a StreamEventsWithResponse call
+func ParseStreamEventsResponse(rsp *http.Response) (*StreamEventsResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &StreamEventsResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	return response, nil
+}
+
+// ParseFitDailyImageResponse parses an HTTP response from a FitDailyImageWithResponse call
+func ParseFitDailyImageResponse(rsp *http.Response) (*FitDailyImageResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &FitDailyImageResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseFitRandomImageResponse parses an HTTP response from a FitRandomImageWithResponse call
+func ParseFitRandomImageResponse(rsp *http.Response) (*FitRandomImageResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &FitRandomImageResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseFitImageResponse parses an HTTP response from a FitImageWithResponse call
+func ParseFitImageResponse(rsp *http.Response) (*FitImageResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &FitImageResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGraphqlQueryResponse parses an HTTP response from a GraphqlQueryWithResponse call
+func ParseGraphqlQueryResponse(rsp *http.Response) (*GraphqlQueryResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GraphqlQueryResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest GraphQLResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest struct {
+			union json.RawMessage
+		}
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGraphqlPostResponse parses an HTTP response from a GraphqlPostWithResponse call
+func ParseGraphqlPostResponse(rsp *http.Response) (*GraphqlPostResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GraphqlPostResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest GraphQLResponse
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest struct {
+			union json.RawMessage
+		}
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseHealthzResponse parses an HTTP response from a HealthzWithResponse call
+func ParseHealthzResponse(rsp *http.Response) (*HealthzResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &HealthzResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	return response, nil
+}
+
+// ParseIiifBaseResponse parses an HTTP response from a IiifBaseWithResponse call
+func ParseIiifBaseResponse(rsp *http.Response) (*IiifBaseResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &IiifBaseResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	return response, nil
+}
+
+// ParseIiifInfoResponse parses an HTTP response from a IiifInfoWithResponse call
+func ParseIiifInfoResponse(rsp *http.Response) (*IiifInfoResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &IiifInfoResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest map[string]interface{}
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.ApplicationldJSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseIiifImageResponse parses an HTTP response from a IiifImageWithResponse call
+func ParseIiifImageResponse(rsp *http.Response) (*IiifImageResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &IiifImageResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseImagePageResponse parses an HTTP response from a ImagePageWithResponse call
+func ParseImagePageResponse(rsp *http.Response) (*ImagePageResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ImagePageResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	return response, nil
+}
+
+// ParseGetImageThumbnailResponse parses an HTTP response from a GetImageThumbnailWithResponse call
+func ParseGetImageThumbnailResponse(rsp *http.Response) (*GetImageThumbnailResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetImageThumbnailResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetImageOriginalResponse parses an HTTP response from a GetImageOriginalWithResponse call
+func ParseGetImageOriginalResponse(rsp *http.Response) (*GetImageOriginalResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetImageOriginalResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetImageOriginalHeadResponse parses an HTTP response from a GetImageOriginalHeadWithResponse call
+func ParseGetImageOriginalHeadResponse(rsp *http.Response) (*GetImageOriginalHeadResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetImageOriginalHeadResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseJobsResponse parses an HTTP response from a JobsWithResponse call
+func ParseJobsResponse(rsp *http.Response) (*JobsResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &JobsResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest []Job
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseOembedResponse parses an HTTP response from a OembedWithResponse call
+func ParseOembedResponse(rsp *http.Response) (*OembedResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &OembedResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest OEmbed
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 501:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON501 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParsePlaylistResponse parses an HTTP response from a PlaylistWithResponse call
+func ParsePlaylistResponse(rsp *http.Response) (*PlaylistResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &PlaylistResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest []string
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	case rsp.StatusCode == 200:
+		// Content-type (text/plain) unsupported
+
+	}
+
+	return response, nil
+}
+
+// ParsePreviewImageResponse parses an HTTP response from a PreviewImageWithResponse call
+func ParsePreviewImageResponse(rsp *http.Response) (*PreviewImageResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &PreviewImageResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseReadyzResponse parses an HTTP response from a ReadyzWithResponse call
+func ParseReadyzResponse(rsp *http.Response) (*ReadyzResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ReadyzResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest Readiness
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 503:
+		var dest Readiness
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON503 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseSitemapResponse parses an HTTP response from a SitemapWithResponse call
+func ParseSitemapResponse(rsp *http.Response) (*SitemapResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &SitemapResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "xml") && rsp.StatusCode == 200:
+		var dest string
+		if err := xml.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.XML200 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseGetStatsResponse parses an HTTP response from a GetStatsWithResponse call
+func ParseGetStatsResponse(rsp *http.Response) (*GetStatsResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetStatsResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest Stats
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseUsageResponse parses an HTTP response from a UsageWithResponse call
+func ParseUsageResponse(rsp *http.Response) (*UsageResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &UsageResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest struct {
+			Agents    *map[string]int `json:"agents,omitempty"`
+			Downloads *map[string]int `json:"downloads,omitempty"`
+			Endpoints *map[string]int `json:"endpoints,omitempty"`
+			Images    *map[string]int `json:"images,omitempty"`
+			Referers  *map[string]int `json:"referers,omitempty"`
+			Requests  *int            `json:"requests,omitempty"`
+			Since     *time.Time      `json:"since,omitempty"`
+			Views     *map[string]int `json:"views,omitempty"`
+		}
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseUploadOptionsResponse parses an HTTP response from a UploadOptionsWithResponse call
+func ParseUploadOptionsResponse(rsp *http.Response) (*UploadOptionsResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &UploadOptionsResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	return response, nil
+}
+
+// ParseCreateUploadResponse parses an HTTP response from a CreateUploadWithResponse call
+func ParseCreateUploadResponse(rsp *http.Response) (*CreateUploadResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &CreateUploadResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 412:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON412 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 413:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON413 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseDeleteUploadResponse parses an HTTP response from a DeleteUploadWithResponse call
+func ParseDeleteUploadResponse(rsp *http.Response) (*DeleteUploadResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &DeleteUploadResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 412:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON412 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseUploadOffsetResponse parses an HTTP response from a UploadOffsetWithResponse call
+func ParseUploadOffsetResponse(rsp *http.Response) (*UploadOffsetResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &UploadOffsetResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 412:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON412 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseAppendUploadResponse parses an HTTP response from a AppendUploadWithResponse call
+func ParseAppendUploadResponse(rsp *http.Response) (*AppendUploadResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &AppendUploadResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 409:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON409 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 412:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON412 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 413:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON413 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 415:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON415 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 422:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON422 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseV1GetArchiveResponse parses an HTTP response from a V1GetArchiveWithResponse call
+func ParseV1GetArchiveResponse(rsp *http.Response) (*V1GetArchiveResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &V1GetArchiveResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest V1Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest V1Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 413:
+		var dest V1Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON413 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseV1PostArchiveResponse parses an HTTP response from a V1PostArchiveWithResponse call
+func ParseV1PostArchiveResponse(rsp *http.Response) (*V1PostArchiveResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &V1PostArchiveResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest V1Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest V1Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 413:
+		var dest V1Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON413 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseV1StreamEventsResponse parses an HTTP response from a V1StreamEventsWithResponse call
+func ParseV1StreamEventsResponse(rsp *http.Response) (*V1StreamEventsResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &V1StreamEventsResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	return response, nil
+}
+
+// ParseV1FitDailyImageResponse parses an HTTP response from a V1FitDailyImageWithResponse call
+func ParseV1FitDailyImageResponse(rsp *http.Response) (*V1FitDailyImageResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &V1FitDailyImageResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest V1Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest V1Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseV1FitRandomImageResponse parses an HTTP response from a V1FitRandomImageWithResponse call
+func ParseV1FitRandomImageResponse(rsp *http.Response) (*V1FitRandomImageResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &V1FitRandomImageResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest V1Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest V1Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseV1FitImageResponse parses an HTTP response from a V1FitImageWithResponse call
+func ParseV1FitImageResponse(rsp *http.Response) (*V1FitImageResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &V1FitImageResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest V1Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest V1Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest V1Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseV1ListImagesResponse parses an HTTP response from a V1ListImagesWithResponse call
+func ParseV1ListImagesResponse(rsp *http.Response) (*V1ListImagesResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &V1ListImagesResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest ImagesEnvelope
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest V1Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest V1Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseV1DeleteImageResponse parses an HTTP response from a V1DeleteImageWithResponse call
+func ParseV1DeleteImageResponse(rsp *http.Response) (*V1DeleteImageResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &V1DeleteImageResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 400:
+		var dest V1Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON400 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 401:
+		var dest V1Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON401 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 404:
+		var dest V1Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON404 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest V1Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	}
+
+	return response, nil
+}
+
+// ParseV1GetStatsResponse parses an HTTP response from a V1GetStatsWithResponse call
+func ParseV1GetStatsResponse(rsp *http.Response) (*V1GetStatsResponse, error) {
+	bodyBytes, err := io.ReadAll(rsp.Body)
+	defer func() { _ = rsp.Body.Close() }()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &V1GetStatsResponse{
+		Body:         bodyBytes,
+		HTTPResponse: rsp,
+	}
+
+	switch {
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 200:
+		var dest StatsEnvelope
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON200 = &dest
+
+	case strings.Contains(rsp.Header.Get("Content-Type"), "json") && rsp.StatusCode == 500:
+		var dest V1Error
+		if err := json.Unmarshal(bodyBytes, &dest); err != nil {
+			return nil, err
+		}
+		response.JSON500 = &dest
+
+	}
+
+	return response, nil
+}
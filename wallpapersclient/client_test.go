@@ -0,0 +1,143 @@
+package wallpapersclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestOperations(t *testing.T) {
+	ctx := context.Background()
+	since := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	for _, tc := range []struct {
+		name string
+		call func(*ClientWithResponses) (*http.Response, error)
+		want string
+	}{
+		{
+			name: "no parameters",
+			call: func(c *ClientWithResponses) (*http.Response, error) { return c.Healthz(ctx) },
+			want: "GET /healthz",
+		},
+		{
+			name: "path parameter",
+			call: func(c *ClientWithResponses) (*http.Response, error) {
+				return c.FitImage(ctx, "a b.png", &FitImageParams{W: 800, H: 600})
+			},
+			want: "GET /fit/a%20b.png?h=600&w=800",
+		},
+		{
+			name: "iiif region is not escaped",
+			call: func(c *ClientWithResponses) (*http.Response, error) {
+				return c.IiifImage(ctx, "a.png", "0,0,512,512", "!256,256", "0", Default, Jpg)
+			},
+			want: "GET /iiif/a.png/0,0,512,512/%21256,256/0/default.jpg",
+		},
+		{
+			name: "unset parameters are left out",
+			call: func(c *ClientWithResponses) (*http.Response, error) {
+				return c.V1ListImages(ctx, &V1ListImagesParams{Limit: ptr(10), Fields: &Fields{"key", "cdn"}})
+			},
+			want: "GET /v1/images?fields=key%2Ccdn&limit=10",
+		},
+		{
+			name: "time parameter",
+			call: func(c *ClientWithResponses) (*http.Response, error) { return c.Audit(ctx, &AuditParams{Since: &since}) },
+			want: "GET /audit?since=2024-05-01T12%3A00%3A00Z",
+		},
+		{
+			name: "request body",
+			call: func(c *ClientWithResponses) (*http.Response, error) {
+				return c.PostArchive(ctx, PostArchiveJSONRequestBody{Names: []string{"a.png"}})
+			},
+			want: `POST /archive {"names":["a.png"]}`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var got, auth string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r.Method + " " + r.URL.RequestURI()
+				auth = r.Header.Get("Authorization")
+				if b, _ := io.ReadAll(r.Body); len(b) > 0 {
+					got += " " + string(b)
+				}
+			}))
+			defer srv.Close()
+
+			c, err := New(srv.URL, "secret")
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := tc.call(c)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+			if got != tc.want {
+				t.Errorf("request = %q, want %q", got, tc.want)
+			}
+			if auth != "Bearer secret" {
+				t.Errorf("Authorization = %q, want the bearer token", auth)
+			}
+		})
+	}
+}
+
+func TestCheckResponse(t *testing.T) {
+	ctx := context.Background()
+	for _, tc := range []struct {
+		name    string
+		status  int
+		body    string
+		wantKey string
+		wantErr string
+	}{
+		{name: "ok", status: http.StatusOK, body: `{"data":[{"key":"a.png"}],"next_cursor":"x"}`, wantKey: "a.png"},
+		{name: "redirect", status: http.StatusFound},
+		{name: "v1 error", status: http.StatusBadRequest, body: `{"error":{"code":"invalid_limit","message":"limit is too large"}}`, wantErr: "wallpapers: 400 limit is too large"},
+		{name: "legacy error", status: http.StatusNotFound, body: `{"error":"not found"}`, wantErr: "wallpapers: 404 not found"},
+		{name: "no body", status: http.StatusBadGateway, wantErr: "wallpapers: 502 Bad Gateway"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tc.status)
+				_, _ = w.Write([]byte(tc.body))
+			}))
+			defer srv.Close()
+
+			c, err := New(srv.URL, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := c.V1ListImagesWithResponse(ctx, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = CheckResponse(resp.HTTPResponse, resp.Body)
+			if tc.wantErr != "" {
+				var respErr *ResponseError
+				if !errors.As(err, &respErr) || err.Error() != tc.wantErr {
+					t.Fatalf("error = %v, want %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tc.wantKey == "" {
+				return
+			}
+			if page := resp.JSON200; page == nil || len(page.Data) != 1 || page.Data[0].Key != tc.wantKey || *page.NextCursor != "x" {
+				t.Errorf("page = %+v", page)
+			}
+		})
+	}
+}
+
+func ptr[T any](v T) *T { return &v }
@@ -0,0 +1,110 @@
+package wallpapers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WarmTimeout bounds each rendition request made by Warm. imgix can take
+// several seconds to render a large original the first time.
+var WarmTimeout = 2 * time.Minute
+
+// Size is a rendition's width and height in pixels.
+type Size struct {
+	Width  int
+	Height int
+}
+
+func (s Size) String() string {
+	return fmt.Sprintf("%dx%d", s.Width, s.Height)
+}
+
+// MarshalText writes s as WIDTHxHEIGHT.
+func (s Size) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText parses a size written as WIDTHxHEIGHT.
+func (s *Size) UnmarshalText(b []byte) error {
+	v, err := ParseSize(string(b))
+	if err != nil {
+		return err
+	}
+	*s = v
+	return nil
+}
+
+// ParseSize parses a size written as WIDTHxHEIGHT, e.g. 1920x1080.
+func ParseSize(v string) (Size, error) {
+	ws, hs, ok := strings.Cut(strings.TrimSpace(v), "x")
+	w, werr := strconv.Atoi(ws)
+	h, herr := strconv.Atoi(hs)
+	if !ok || werr != nil || herr != nil || w <= 0 || h <= 0 {
+		return Size{}, fmt.Errorf("invalid size %q, expected WIDTHxHEIGHT", v)
+	}
+	return Size{Width: w, Height: h}, nil
+}
+
+// WarmURLs returns the imgix renditions of an image that Warm requests:
+// the thumbnail and full resolution URLs used by listings, and a cropped
+// rendition for each of sizes. It returns nil for videos and for stores
+// that are not served through imgix.
+func WarmURLs(ctx context.Context, key string, sizes []Size) []string {
+	s, ok := StoreFor(ctx).(*gcsStore)
+	if !ok || s.imgixHost == "" || MediaType(key) != TypeImage {
+		return nil
+	}
+
+	urls := []string{thumbURL(s.imgixHost, s.bucket, key), fullRezURL(s.imgixHost, s.bucket, key)}
+	for _, sz := range sizes {
+		urls = append(urls, fmt.Sprintf("https://%s/%s?w=%d&h=%d&dpr=1&fit=crop&crop=entropy&auto=compress&auto=format", s.imgixHost, key, sz.Width, sz.Height))
+	}
+	return urls
+}
+
+// Warm requests the renditions from WarmURLs so imgix has them cached
+// before the first visitor asks. Every URL is tried, and their errors are
+// returned together.
+func Warm(ctx context.Context, key string, sizes []Size) error {
+	var errs []error
+	for _, u := range WarmURLs(ctx, key, sizes) {
+		if err := warm(ctx, u); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		LoggerFor(ctx).Debugw("warmed rendition", "url", u)
+	}
+	return errors.Join(errs...)
+}
+
+func warm(ctx context.Context, u string) error {
+	ctx, cancel := context.WithTimeout(ctx, WarmTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("could not warm %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	// The rendition is only cached once it has been sent in full.
+	if _, err := io.Copy(io.Discard, resp.Body); err != nil {
+		return fmt.Errorf("could not warm %s: %w", u, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("could not warm %s: %s", u, resp.Status)
+	}
+
+	return nil
+}